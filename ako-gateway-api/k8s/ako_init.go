@@ -31,9 +31,11 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
 	akogatewayapinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/nodes"
+	akogatewayapiobjects "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/objects"
 	akogatewayapistatus "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/status"
 	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
@@ -90,8 +92,12 @@ func (c *GatewayController) InitController(informers k8s.K8sinformers, registere
 	numGraphWorkers := uint32(8)
 
 	graphQueueParams := utils.WorkerQueue{NumWorkers: numGraphWorkers, WorkqueueName: utils.GraphLayer}
+	graphQueueParams.RequeueFunc = func(key interface{}) {
+		lib.IncrementQueueCounter(utils.GraphLayer)
+	}
 	statusQueueParams := utils.WorkerQueue{NumWorkers: numGraphWorkers, WorkqueueName: utils.StatusQueue}
 	graphQueue = utils.SharedWorkQueue(&ingestionQueueParams, &graphQueueParams, &slowRetryQParams, &fastRetryQParams, &statusQueueParams).GetQueueByName(utils.GraphLayer)
+	graphQueue.SetMaxAttempts(lib.GetSyncMaxAttempts())
 
 	err := k8s.PopulateCache()
 	if err != nil {
@@ -217,6 +223,18 @@ func (c *GatewayController) FullSyncK8s(sync bool) error {
 		return nil
 	}
 
+	// ReferenceGrant Section
+	// The grants are indexed first, so that the cross-namespace references of
+	// the Gateways and the Routes are evaluated correctly during the full sync.
+	referenceGrantObjs, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().ReferenceGrantInformer.Lister().List(labels.Set(nil).AsSelector())
+	if err != nil {
+		utils.AviLog.Errorf("Unable to retrieve the referencegrants during full sync: %s", err)
+		return err
+	}
+	for _, referenceGrantObj := range referenceGrantObjs {
+		akogatewayapiobjects.GatewayApiLister().UpdateReferenceGrant(utils.ObjKey(referenceGrantObj), referenceGrantToStore(referenceGrantObj))
+	}
+
 	// GatewayClass Section
 	gwClassObjs, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GatewayClassInformer.Lister().List(labels.Set(nil).AsSelector())
 	if err != nil {
@@ -301,6 +319,134 @@ func (c *GatewayController) FullSyncK8s(sync bool) error {
 		akogatewayapinodes.DequeueIngestion(key, true)
 	}
 
+	// GRPCRoute Section
+	if akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer != nil {
+		var filteredGRPCRoutes []*gatewayv1alpha2.GRPCRoute
+		grpcRouteObjs, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer.Lister().GRPCRoutes(metav1.NamespaceAll).List(labels.Set(nil).AsSelector())
+		if err != nil {
+			utils.AviLog.Errorf("Unable to retrieve the grpcroutes during full sync: %s", err)
+			return err
+		}
+
+		for _, grpcRouteObj := range grpcRouteObjs {
+			key := lib.GRPCRoute + "/" + utils.ObjKey(grpcRouteObj)
+			meta, err := meta.Accessor(grpcRouteObj)
+			if err == nil {
+				resVer := meta.GetResourceVersion()
+				objects.SharedResourceVerInstanceLister().Save(key, resVer)
+			}
+			if IsGRPCRouteValid(key, grpcRouteObj) {
+				filteredGRPCRoutes = append(filteredGRPCRoutes, grpcRouteObj)
+			}
+		}
+		sort.Slice(filteredGRPCRoutes, func(i, j int) bool {
+			if filteredGRPCRoutes[i].GetCreationTimestamp().Unix() == filteredGRPCRoutes[j].GetCreationTimestamp().Unix() {
+				return filteredGRPCRoutes[i].Namespace+"/"+filteredGRPCRoutes[i].Name < filteredGRPCRoutes[j].Namespace+"/"+filteredGRPCRoutes[j].Name
+			}
+			return filteredGRPCRoutes[i].GetCreationTimestamp().Unix() < filteredGRPCRoutes[j].GetCreationTimestamp().Unix()
+		})
+		for _, filteredGRPCRoute := range filteredGRPCRoutes {
+			key := lib.GRPCRoute + "/" + utils.ObjKey(filteredGRPCRoute)
+			akogatewayapinodes.DequeueIngestion(key, true)
+		}
+	}
+
+	// TLSRoute Section
+	if akogatewayapilib.AKOControlConfig().GatewayApiInformers().TLSRouteInformer != nil {
+		var filteredTLSRoutes []*gatewayv1alpha2.TLSRoute
+		tlsRouteObjs, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().TLSRouteInformer.Lister().TLSRoutes(metav1.NamespaceAll).List(labels.Set(nil).AsSelector())
+		if err != nil {
+			utils.AviLog.Errorf("Unable to retrieve the tlsroutes during full sync: %s", err)
+			return err
+		}
+
+		for _, tlsRouteObj := range tlsRouteObjs {
+			key := lib.TLSRoute + "/" + utils.ObjKey(tlsRouteObj)
+			meta, err := meta.Accessor(tlsRouteObj)
+			if err == nil {
+				resVer := meta.GetResourceVersion()
+				objects.SharedResourceVerInstanceLister().Save(key, resVer)
+			}
+			if IsTLSRouteValid(key, tlsRouteObj) {
+				filteredTLSRoutes = append(filteredTLSRoutes, tlsRouteObj)
+			}
+		}
+		sort.Slice(filteredTLSRoutes, func(i, j int) bool {
+			if filteredTLSRoutes[i].GetCreationTimestamp().Unix() == filteredTLSRoutes[j].GetCreationTimestamp().Unix() {
+				return filteredTLSRoutes[i].Namespace+"/"+filteredTLSRoutes[i].Name < filteredTLSRoutes[j].Namespace+"/"+filteredTLSRoutes[j].Name
+			}
+			return filteredTLSRoutes[i].GetCreationTimestamp().Unix() < filteredTLSRoutes[j].GetCreationTimestamp().Unix()
+		})
+		for _, filteredTLSRoute := range filteredTLSRoutes {
+			key := lib.TLSRoute + "/" + utils.ObjKey(filteredTLSRoute)
+			akogatewayapinodes.DequeueIngestion(key, true)
+		}
+	}
+
+	// TCPRoute Section
+	if akogatewayapilib.AKOControlConfig().GatewayApiInformers().TCPRouteInformer != nil {
+		var filteredTCPRoutes []*gatewayv1alpha2.TCPRoute
+		tcpRouteObjs, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().TCPRouteInformer.Lister().TCPRoutes(metav1.NamespaceAll).List(labels.Set(nil).AsSelector())
+		if err != nil {
+			utils.AviLog.Errorf("Unable to retrieve the tcproutes during full sync: %s", err)
+			return err
+		}
+
+		for _, tcpRouteObj := range tcpRouteObjs {
+			key := lib.TCPRoute + "/" + utils.ObjKey(tcpRouteObj)
+			meta, err := meta.Accessor(tcpRouteObj)
+			if err == nil {
+				resVer := meta.GetResourceVersion()
+				objects.SharedResourceVerInstanceLister().Save(key, resVer)
+			}
+			if IsTCPRouteValid(key, tcpRouteObj) {
+				filteredTCPRoutes = append(filteredTCPRoutes, tcpRouteObj)
+			}
+		}
+		sort.Slice(filteredTCPRoutes, func(i, j int) bool {
+			if filteredTCPRoutes[i].GetCreationTimestamp().Unix() == filteredTCPRoutes[j].GetCreationTimestamp().Unix() {
+				return filteredTCPRoutes[i].Namespace+"/"+filteredTCPRoutes[i].Name < filteredTCPRoutes[j].Namespace+"/"+filteredTCPRoutes[j].Name
+			}
+			return filteredTCPRoutes[i].GetCreationTimestamp().Unix() < filteredTCPRoutes[j].GetCreationTimestamp().Unix()
+		})
+		for _, filteredTCPRoute := range filteredTCPRoutes {
+			key := lib.TCPRoute + "/" + utils.ObjKey(filteredTCPRoute)
+			akogatewayapinodes.DequeueIngestion(key, true)
+		}
+	}
+
+	// UDPRoute Section
+	if akogatewayapilib.AKOControlConfig().GatewayApiInformers().UDPRouteInformer != nil {
+		var filteredUDPRoutes []*gatewayv1alpha2.UDPRoute
+		udpRouteObjs, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().UDPRouteInformer.Lister().UDPRoutes(metav1.NamespaceAll).List(labels.Set(nil).AsSelector())
+		if err != nil {
+			utils.AviLog.Errorf("Unable to retrieve the udproutes during full sync: %s", err)
+			return err
+		}
+
+		for _, udpRouteObj := range udpRouteObjs {
+			key := lib.UDPRoute + "/" + utils.ObjKey(udpRouteObj)
+			meta, err := meta.Accessor(udpRouteObj)
+			if err == nil {
+				resVer := meta.GetResourceVersion()
+				objects.SharedResourceVerInstanceLister().Save(key, resVer)
+			}
+			if IsUDPRouteValid(key, udpRouteObj) {
+				filteredUDPRoutes = append(filteredUDPRoutes, udpRouteObj)
+			}
+		}
+		sort.Slice(filteredUDPRoutes, func(i, j int) bool {
+			if filteredUDPRoutes[i].GetCreationTimestamp().Unix() == filteredUDPRoutes[j].GetCreationTimestamp().Unix() {
+				return filteredUDPRoutes[i].Namespace+"/"+filteredUDPRoutes[i].Name < filteredUDPRoutes[j].Namespace+"/"+filteredUDPRoutes[j].Name
+			}
+			return filteredUDPRoutes[i].GetCreationTimestamp().Unix() < filteredUDPRoutes[j].GetCreationTimestamp().Unix()
+		})
+		for _, filteredUDPRoute := range filteredUDPRoutes {
+			key := lib.UDPRoute + "/" + utils.ObjKey(filteredUDPRoute)
+			akogatewayapinodes.DequeueIngestion(key, true)
+		}
+	}
+
 	// Service Section
 	svcObjs, err := utils.GetInformers().ServiceInformer.Lister().Services(metav1.NamespaceAll).List(labels.Set(nil).AsSelector())
 	if err != nil {
@@ -395,7 +541,7 @@ func SyncFromNodesLayer(key interface{}, wg *sync.WaitGroup) error {
 	aviclient := avicache.SharedAVIClients()
 	restlayer := rest.NewRestOperations(cache, aviclient)
 	restlayer.DequeueNodes(keyStr)
-	return nil
+	return restlayer.SyncError()
 }
 
 func (c *GatewayController) RefreshAuthToken() {
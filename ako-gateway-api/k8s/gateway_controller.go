@@ -21,14 +21,19 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 	gatewayclientset "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
 	gatewayexternalversions "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions"
 
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	akogatewayapiobjects "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/objects"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
@@ -58,22 +63,51 @@ func SharedGatewayController() *GatewayController {
 
 func (c *GatewayController) InitGatewayAPIInformers(cs gatewayclientset.Interface) {
 	gatewayFactory := gatewayexternalversions.NewSharedInformerFactory(cs, time.Second*30)
-	akogatewayapilib.AKOControlConfig().SetGatewayApiInformers(&akogatewayapilib.GatewayAPIInformers{
+	gatewayAPIInformers := &akogatewayapilib.GatewayAPIInformers{
 		GatewayInformer:      gatewayFactory.Gateway().V1().Gateways(),
 		GatewayClassInformer: gatewayFactory.Gateway().V1().GatewayClasses(),
 		HTTPRouteInformer:    gatewayFactory.Gateway().V1().HTTPRoutes(),
-	})
+
+		ReferenceGrantInformer: gatewayFactory.Gateway().V1beta1().ReferenceGrants(),
+	}
+	if akogatewayapilib.IsGatewayAPIResourceInstalled(cs, gatewayv1alpha2.GroupVersion.String(), "tlsroutes") {
+		gatewayAPIInformers.TLSRouteInformer = gatewayFactory.Gateway().V1alpha2().TLSRoutes()
+	} else {
+		utils.AviLog.Infof("TLSRoute CRD is not installed, TLS passthrough listeners will not be supported")
+	}
+	if akogatewayapilib.IsGatewayAPIResourceInstalled(cs, gatewayv1alpha2.GroupVersion.String(), "tcproutes") {
+		gatewayAPIInformers.TCPRouteInformer = gatewayFactory.Gateway().V1alpha2().TCPRoutes()
+	} else {
+		utils.AviLog.Infof("TCPRoute CRD is not installed, TCP listeners will not be supported")
+	}
+	if akogatewayapilib.IsGatewayAPIResourceInstalled(cs, gatewayv1alpha2.GroupVersion.String(), "udproutes") {
+		gatewayAPIInformers.UDPRouteInformer = gatewayFactory.Gateway().V1alpha2().UDPRoutes()
+	} else {
+		utils.AviLog.Infof("UDPRoute CRD is not installed, UDP listeners will not be supported")
+	}
+	if akogatewayapilib.IsGatewayAPIResourceInstalled(cs, gatewayv1alpha2.GroupVersion.String(), "grpcroutes") {
+		gatewayAPIInformers.GRPCRouteInformer = gatewayFactory.Gateway().V1alpha2().GRPCRoutes()
+	} else {
+		utils.AviLog.Infof("GRPCRoute CRD is not installed, GRPCRoutes will not be supported")
+	}
+	akogatewayapilib.AKOControlConfig().SetGatewayApiInformers(gatewayAPIInformers)
 }
 
 func (c *GatewayController) Start(stopCh <-chan struct{}) {
 	go c.informers.ServiceInformer.Informer().Run(stopCh)
-	go c.informers.EpInformer.Informer().Run(stopCh)
 
 	informersList := []cache.InformerSynced{
-		c.informers.EpInformer.Informer().HasSynced,
 		c.informers.ServiceInformer.Informer().HasSynced,
 	}
 
+	if lib.IsEndpointSliceEnabled() {
+		go c.informers.EpSlicesInformer.Informer().Run(stopCh)
+		informersList = append(informersList, c.informers.EpSlicesInformer.Informer().HasSynced)
+	} else {
+		go c.informers.EpInformer.Informer().Run(stopCh)
+		informersList = append(informersList, c.informers.EpInformer.Informer().HasSynced)
+	}
+
 	if !lib.AviSecretInitialized {
 		go c.informers.SecretInformer.Informer().Run(stopCh)
 		informersList = append(informersList, c.informers.SecretInformer.Informer().HasSynced)
@@ -90,6 +124,24 @@ func (c *GatewayController) Start(stopCh <-chan struct{}) {
 	informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().GatewayInformer.Informer().HasSynced)
 	go akogatewayapilib.AKOControlConfig().GatewayApiInformers().HTTPRouteInformer.Informer().Run(stopCh)
 	informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().HTTPRouteInformer.Informer().HasSynced)
+	go akogatewayapilib.AKOControlConfig().GatewayApiInformers().ReferenceGrantInformer.Informer().Run(stopCh)
+	informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().ReferenceGrantInformer.Informer().HasSynced)
+	if akogatewayapilib.AKOControlConfig().GatewayApiInformers().TLSRouteInformer != nil {
+		go akogatewayapilib.AKOControlConfig().GatewayApiInformers().TLSRouteInformer.Informer().Run(stopCh)
+		informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().TLSRouteInformer.Informer().HasSynced)
+	}
+	if akogatewayapilib.AKOControlConfig().GatewayApiInformers().TCPRouteInformer != nil {
+		go akogatewayapilib.AKOControlConfig().GatewayApiInformers().TCPRouteInformer.Informer().Run(stopCh)
+		informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().TCPRouteInformer.Informer().HasSynced)
+	}
+	if akogatewayapilib.AKOControlConfig().GatewayApiInformers().UDPRouteInformer != nil {
+		go akogatewayapilib.AKOControlConfig().GatewayApiInformers().UDPRouteInformer.Informer().Run(stopCh)
+		informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().UDPRouteInformer.Informer().HasSynced)
+	}
+	if akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer != nil {
+		go akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer.Informer().Run(stopCh)
+		informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer.Informer().HasSynced)
+	}
 
 	if !cache.WaitForCacheSync(stopCh, informersList...) {
 		runtime.HandleError(fmt.Errorf("timed out waiting for caches to sync"))
@@ -166,7 +218,92 @@ func (c *GatewayController) SetupEventHandlers(k8sinfo k8s.K8sinformers) {
 			}
 		},
 	}
-	c.informers.EpInformer.Informer().AddEventHandler(epEventHandler)
+	epSliceEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			epSlice := obj.(*discoveryv1.EndpointSlice)
+			svcName, ok := lib.GetServiceNameForEndpointSlice(epSlice)
+			if !ok {
+				return
+			}
+			namespace := epSlice.Namespace
+			key := utils.Endpoints + "/" + namespace + "/" + svcName
+			if lib.IsNamespaceBlocked(namespace) {
+				utils.AviLog.Debugf("key: %s, msg: EndpointSlice Add event: Namespace: %s didn't qualify filter", key, namespace)
+				return
+			}
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: ADD EndpointSlice %s", key, epSlice.Name)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			epSlice, ok := obj.(*discoveryv1.EndpointSlice)
+			if !ok {
+				// endpointslice was deleted but its final state is unrecorded.
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				epSlice, ok = tombstone.Obj.(*discoveryv1.EndpointSlice)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not an EndpointSlice: %#v", obj)
+					return
+				}
+			}
+			svcName, ok := lib.GetServiceNameForEndpointSlice(epSlice)
+			if !ok {
+				return
+			}
+			namespace := epSlice.Namespace
+			key := utils.Endpoints + "/" + namespace + "/" + svcName
+			if lib.IsNamespaceBlocked(namespace) {
+				utils.AviLog.Debugf("key: %s, msg: EndpointSlice Delete event: Namespace: %s didn't qualify filter", key, namespace)
+				return
+			}
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: DELETE EndpointSlice %s", key, epSlice.Name)
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oepSlice := old.(*discoveryv1.EndpointSlice)
+			cepSlice := cur.(*discoveryv1.EndpointSlice)
+			if reflect.DeepEqual(cepSlice.Endpoints, oepSlice.Endpoints) &&
+				reflect.DeepEqual(cepSlice.Ports, oepSlice.Ports) &&
+				reflect.DeepEqual(cepSlice.Labels, oepSlice.Labels) {
+				return
+			}
+			namespace := cepSlice.Namespace
+			if lib.IsNamespaceBlocked(namespace) {
+				utils.AviLog.Debugf("key: %s, msg: EndpointSlice Update event: Namespace: %s didn't qualify filter", utils.ObjKey(cepSlice), namespace)
+				return
+			}
+			for _, epSlice := range []*discoveryv1.EndpointSlice{oepSlice, cepSlice} {
+				svcName, ok := lib.GetServiceNameForEndpointSlice(epSlice)
+				if !ok {
+					continue
+				}
+				key := utils.Endpoints + "/" + namespace + "/" + svcName
+				bkt := utils.Bkt(namespace, numWorkers)
+				c.workqueue[bkt].AddRateLimited(key)
+				utils.AviLog.Debugf("key: %s, msg: UPDATE EndpointSlice %s", key, cepSlice.Name)
+			}
+		},
+	}
+
+	if lib.IsEndpointSliceEnabled() {
+		c.informers.EpSlicesInformer.Informer().AddEventHandler(epSliceEventHandler)
+	} else {
+		c.informers.EpInformer.Informer().AddEventHandler(epEventHandler)
+	}
 
 	svcEventHandler := cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
@@ -512,6 +649,469 @@ func (c *GatewayController) SetupGatewayApiEventHandlers(numWorkers uint32) {
 		},
 	}
 	informer.HTTPRouteInformer.Informer().AddEventHandler(httpRouteEventHandler)
+
+	c.setupReferenceGrantEventHandler(numWorkers)
+
+	if informer.TLSRouteInformer != nil {
+		c.setupTLSRouteEventHandler(numWorkers)
+	}
+	if informer.TCPRouteInformer != nil {
+		c.setupTCPRouteEventHandler(numWorkers)
+	}
+	if informer.UDPRouteInformer != nil {
+		c.setupUDPRouteEventHandler(numWorkers)
+	}
+	if informer.GRPCRouteInformer != nil {
+		c.setupGRPCRouteEventHandler(numWorkers)
+	}
+}
+
+func (c *GatewayController) setupTLSRouteEventHandler(numWorkers uint32) {
+	tlsRouteEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			tlsRoute := obj.(*gatewayv1alpha2.TLSRoute)
+			key := lib.TLSRoute + "/" + utils.ObjKey(tlsRoute)
+			ok, resVer := objects.SharedResourceVerInstanceLister().Get(key)
+			if ok && resVer.(string) == tlsRoute.ResourceVersion {
+				utils.AviLog.Debugf("key: %s, msg: same resource version returning", key)
+				return
+			}
+			if !IsTLSRouteValid(key, tlsRoute) {
+				return
+			}
+			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(tlsRoute))
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: ADD", key)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			tlsRoute, ok := obj.(*gatewayv1alpha2.TLSRoute)
+			if !ok {
+				// tlsRoute was deleted but its final state is unrecorded.
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				tlsRoute, ok = tombstone.Obj.(*gatewayv1alpha2.TLSRoute)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not a TLSRoute: %#v", obj)
+					return
+				}
+			}
+			key := lib.TLSRoute + "/" + utils.ObjKey(tlsRoute)
+			objects.SharedResourceVerInstanceLister().Delete(key)
+			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(tlsRoute))
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+		},
+		UpdateFunc: func(old, obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldTLSRoute := old.(*gatewayv1alpha2.TLSRoute)
+			newTLSRoute := obj.(*gatewayv1alpha2.TLSRoute)
+			if IsTLSRouteUpdated(oldTLSRoute, newTLSRoute) {
+				key := lib.TLSRoute + "/" + utils.ObjKey(newTLSRoute)
+				if !IsTLSRouteValid(key, newTLSRoute) {
+					return
+				}
+				namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(newTLSRoute))
+				bkt := utils.Bkt(namespace, numWorkers)
+				c.workqueue[bkt].AddRateLimited(key)
+				utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+			}
+		},
+	}
+	akogatewayapilib.AKOControlConfig().GatewayApiInformers().TLSRouteInformer.Informer().AddEventHandler(tlsRouteEventHandler)
+}
+
+func (c *GatewayController) setupTCPRouteEventHandler(numWorkers uint32) {
+	tcpRouteEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			tcpRoute := obj.(*gatewayv1alpha2.TCPRoute)
+			key := lib.TCPRoute + "/" + utils.ObjKey(tcpRoute)
+			ok, resVer := objects.SharedResourceVerInstanceLister().Get(key)
+			if ok && resVer.(string) == tcpRoute.ResourceVersion {
+				utils.AviLog.Debugf("key: %s, msg: same resource version returning", key)
+				return
+			}
+			if !IsTCPRouteValid(key, tcpRoute) {
+				return
+			}
+			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(tcpRoute))
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: ADD", key)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			tcpRoute, ok := obj.(*gatewayv1alpha2.TCPRoute)
+			if !ok {
+				// tcpRoute was deleted but its final state is unrecorded.
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				tcpRoute, ok = tombstone.Obj.(*gatewayv1alpha2.TCPRoute)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not a TCPRoute: %#v", obj)
+					return
+				}
+			}
+			key := lib.TCPRoute + "/" + utils.ObjKey(tcpRoute)
+			objects.SharedResourceVerInstanceLister().Delete(key)
+			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(tcpRoute))
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+		},
+		UpdateFunc: func(old, obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldTCPRoute := old.(*gatewayv1alpha2.TCPRoute)
+			newTCPRoute := obj.(*gatewayv1alpha2.TCPRoute)
+			if IsTCPRouteUpdated(oldTCPRoute, newTCPRoute) {
+				key := lib.TCPRoute + "/" + utils.ObjKey(newTCPRoute)
+				if !IsTCPRouteValid(key, newTCPRoute) {
+					return
+				}
+				namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(newTCPRoute))
+				bkt := utils.Bkt(namespace, numWorkers)
+				c.workqueue[bkt].AddRateLimited(key)
+				utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+			}
+		},
+	}
+	akogatewayapilib.AKOControlConfig().GatewayApiInformers().TCPRouteInformer.Informer().AddEventHandler(tcpRouteEventHandler)
+}
+
+func (c *GatewayController) setupUDPRouteEventHandler(numWorkers uint32) {
+	udpRouteEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			udpRoute := obj.(*gatewayv1alpha2.UDPRoute)
+			key := lib.UDPRoute + "/" + utils.ObjKey(udpRoute)
+			ok, resVer := objects.SharedResourceVerInstanceLister().Get(key)
+			if ok && resVer.(string) == udpRoute.ResourceVersion {
+				utils.AviLog.Debugf("key: %s, msg: same resource version returning", key)
+				return
+			}
+			if !IsUDPRouteValid(key, udpRoute) {
+				return
+			}
+			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(udpRoute))
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: ADD", key)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			udpRoute, ok := obj.(*gatewayv1alpha2.UDPRoute)
+			if !ok {
+				// udpRoute was deleted but its final state is unrecorded.
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				udpRoute, ok = tombstone.Obj.(*gatewayv1alpha2.UDPRoute)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not a UDPRoute: %#v", obj)
+					return
+				}
+			}
+			key := lib.UDPRoute + "/" + utils.ObjKey(udpRoute)
+			objects.SharedResourceVerInstanceLister().Delete(key)
+			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(udpRoute))
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+		},
+		UpdateFunc: func(old, obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldUDPRoute := old.(*gatewayv1alpha2.UDPRoute)
+			newUDPRoute := obj.(*gatewayv1alpha2.UDPRoute)
+			if IsUDPRouteUpdated(oldUDPRoute, newUDPRoute) {
+				key := lib.UDPRoute + "/" + utils.ObjKey(newUDPRoute)
+				if !IsUDPRouteValid(key, newUDPRoute) {
+					return
+				}
+				namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(newUDPRoute))
+				bkt := utils.Bkt(namespace, numWorkers)
+				c.workqueue[bkt].AddRateLimited(key)
+				utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+			}
+		},
+	}
+	akogatewayapilib.AKOControlConfig().GatewayApiInformers().UDPRouteInformer.Informer().AddEventHandler(udpRouteEventHandler)
+}
+
+func (c *GatewayController) setupGRPCRouteEventHandler(numWorkers uint32) {
+	grpcRouteEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			grpcRoute := obj.(*gatewayv1alpha2.GRPCRoute)
+			key := lib.GRPCRoute + "/" + utils.ObjKey(grpcRoute)
+			ok, resVer := objects.SharedResourceVerInstanceLister().Get(key)
+			if ok && resVer.(string) == grpcRoute.ResourceVersion {
+				utils.AviLog.Debugf("key: %s, msg: same resource version returning", key)
+				return
+			}
+			if !IsGRPCRouteValid(key, grpcRoute) {
+				return
+			}
+			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(grpcRoute))
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: ADD", key)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			grpcRoute, ok := obj.(*gatewayv1alpha2.GRPCRoute)
+			if !ok {
+				// grpcRoute was deleted but its final state is unrecorded.
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				grpcRoute, ok = tombstone.Obj.(*gatewayv1alpha2.GRPCRoute)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not a GRPCRoute: %#v", obj)
+					return
+				}
+			}
+			key := lib.GRPCRoute + "/" + utils.ObjKey(grpcRoute)
+			objects.SharedResourceVerInstanceLister().Delete(key)
+			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(grpcRoute))
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+		},
+		UpdateFunc: func(old, obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldGRPCRoute := old.(*gatewayv1alpha2.GRPCRoute)
+			newGRPCRoute := obj.(*gatewayv1alpha2.GRPCRoute)
+			if IsGRPCRouteUpdated(oldGRPCRoute, newGRPCRoute) {
+				key := lib.GRPCRoute + "/" + utils.ObjKey(newGRPCRoute)
+				if !IsGRPCRouteValid(key, newGRPCRoute) {
+					return
+				}
+				namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(newGRPCRoute))
+				bkt := utils.Bkt(namespace, numWorkers)
+				c.workqueue[bkt].AddRateLimited(key)
+				utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+			}
+		},
+	}
+	akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer.Informer().AddEventHandler(grpcRouteEventHandler)
+}
+
+func (c *GatewayController) setupReferenceGrantEventHandler(numWorkers uint32) {
+	referenceGrantEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			grant := obj.(*gatewayv1beta1.ReferenceGrant)
+			key := lib.ReferenceGrant + "/" + utils.ObjKey(grant)
+			akogatewayapiobjects.GatewayApiLister().UpdateReferenceGrant(utils.ObjKey(grant), referenceGrantToStore(grant))
+			c.requeueReferenceGrantObjects(key, grant, numWorkers)
+			utils.AviLog.Debugf("key: %s, msg: ADD", key)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			grant, ok := obj.(*gatewayv1beta1.ReferenceGrant)
+			if !ok {
+				// referenceGrant was deleted but its final state is unrecorded.
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				grant, ok = tombstone.Obj.(*gatewayv1beta1.ReferenceGrant)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not a ReferenceGrant: %#v", obj)
+					return
+				}
+			}
+			key := lib.ReferenceGrant + "/" + utils.ObjKey(grant)
+			akogatewayapiobjects.GatewayApiLister().DeleteReferenceGrant(utils.ObjKey(grant))
+			c.requeueReferenceGrantObjects(key, grant, numWorkers)
+			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+		},
+		UpdateFunc: func(old, obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldGrant := old.(*gatewayv1beta1.ReferenceGrant)
+			grant := obj.(*gatewayv1beta1.ReferenceGrant)
+			if utils.Hash(utils.Stringify(oldGrant.Spec)) == utils.Hash(utils.Stringify(grant.Spec)) {
+				return
+			}
+			key := lib.ReferenceGrant + "/" + utils.ObjKey(grant)
+			akogatewayapiobjects.GatewayApiLister().UpdateReferenceGrant(utils.ObjKey(grant), referenceGrantToStore(grant))
+			// the objects which were permitted by the older spec are requeued as well
+			c.requeueReferenceGrantObjects(key, oldGrant, numWorkers)
+			c.requeueReferenceGrantObjects(key, grant, numWorkers)
+			utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+		},
+	}
+	akogatewayapilib.AKOControlConfig().GatewayApiInformers().ReferenceGrantInformer.Informer().AddEventHandler(referenceGrantEventHandler)
+}
+
+// requeueReferenceGrantObjects requeues the Gateways and the Routes from the namespaces
+// listed in the from section of the ReferenceGrant, so that their references get re-evaluated.
+func (c *GatewayController) requeueReferenceGrantObjects(grantKey string, grant *gatewayv1beta1.ReferenceGrant, numWorkers uint32) {
+	informers := akogatewayapilib.AKOControlConfig().GatewayApiInformers()
+	enqueue := func(key, namespace string) {
+		bkt := utils.Bkt(namespace, numWorkers)
+		c.workqueue[bkt].AddRateLimited(key)
+		utils.AviLog.Debugf("key: %s, msg: requeued for ReferenceGrant %s", key, grantKey)
+	}
+	for _, from := range grant.Spec.From {
+		if string(from.Group) != gatewayv1.GroupName {
+			continue
+		}
+		namespace := string(from.Namespace)
+		switch string(from.Kind) {
+		case lib.Gateway:
+			gwList, err := informers.GatewayInformer.Lister().Gateways(namespace).List(labels.Everything())
+			if err != nil {
+				utils.AviLog.Warnf("key: %s, msg: unable to list the Gateways in namespace %s, err: %v", grantKey, namespace, err)
+				continue
+			}
+			for _, gw := range gwList {
+				key := lib.Gateway + "/" + utils.ObjKey(gw)
+				if IsValidGateway(key, gw) {
+					enqueue(key, namespace)
+				}
+			}
+		case lib.HTTPRoute:
+			routeList, err := informers.HTTPRouteInformer.Lister().HTTPRoutes(namespace).List(labels.Everything())
+			if err != nil {
+				utils.AviLog.Warnf("key: %s, msg: unable to list the HTTPRoutes in namespace %s, err: %v", grantKey, namespace, err)
+				continue
+			}
+			for _, route := range routeList {
+				key := lib.HTTPRoute + "/" + utils.ObjKey(route)
+				if IsHTTPRouteValid(key, route) {
+					enqueue(key, namespace)
+				}
+			}
+		case lib.GRPCRoute:
+			if informers.GRPCRouteInformer == nil {
+				continue
+			}
+			routeList, err := informers.GRPCRouteInformer.Lister().GRPCRoutes(namespace).List(labels.Everything())
+			if err != nil {
+				utils.AviLog.Warnf("key: %s, msg: unable to list the GRPCRoutes in namespace %s, err: %v", grantKey, namespace, err)
+				continue
+			}
+			for _, route := range routeList {
+				key := lib.GRPCRoute + "/" + utils.ObjKey(route)
+				if IsGRPCRouteValid(key, route) {
+					enqueue(key, namespace)
+				}
+			}
+		case lib.TLSRoute:
+			if informers.TLSRouteInformer == nil {
+				continue
+			}
+			routeList, err := informers.TLSRouteInformer.Lister().TLSRoutes(namespace).List(labels.Everything())
+			if err != nil {
+				utils.AviLog.Warnf("key: %s, msg: unable to list the TLSRoutes in namespace %s, err: %v", grantKey, namespace, err)
+				continue
+			}
+			for _, route := range routeList {
+				key := lib.TLSRoute + "/" + utils.ObjKey(route)
+				if IsTLSRouteValid(key, route) {
+					enqueue(key, namespace)
+				}
+			}
+		case lib.TCPRoute:
+			if informers.TCPRouteInformer == nil {
+				continue
+			}
+			routeList, err := informers.TCPRouteInformer.Lister().TCPRoutes(namespace).List(labels.Everything())
+			if err != nil {
+				utils.AviLog.Warnf("key: %s, msg: unable to list the TCPRoutes in namespace %s, err: %v", grantKey, namespace, err)
+				continue
+			}
+			for _, route := range routeList {
+				key := lib.TCPRoute + "/" + utils.ObjKey(route)
+				if IsTCPRouteValid(key, route) {
+					enqueue(key, namespace)
+				}
+			}
+		case lib.UDPRoute:
+			if informers.UDPRouteInformer == nil {
+				continue
+			}
+			routeList, err := informers.UDPRouteInformer.Lister().UDPRoutes(namespace).List(labels.Everything())
+			if err != nil {
+				utils.AviLog.Warnf("key: %s, msg: unable to list the UDPRoutes in namespace %s, err: %v", grantKey, namespace, err)
+				continue
+			}
+			for _, route := range routeList {
+				key := lib.UDPRoute + "/" + utils.ObjKey(route)
+				if IsUDPRouteValid(key, route) {
+					enqueue(key, namespace)
+				}
+			}
+		}
+	}
+}
+
+func referenceGrantToStore(grant *gatewayv1beta1.ReferenceGrant) akogatewayapiobjects.ReferenceGrantStore {
+	store := akogatewayapiobjects.ReferenceGrantStore{}
+	for _, from := range grant.Spec.From {
+		store.From = append(store.From, akogatewayapiobjects.ReferenceGrantFrom{
+			Group:     string(from.Group),
+			Kind:      string(from.Kind),
+			Namespace: string(from.Namespace),
+		})
+	}
+	for _, to := range grant.Spec.To {
+		grantTo := akogatewayapiobjects.ReferenceGrantTo{
+			Group: string(to.Group),
+			Kind:  string(to.Kind),
+		}
+		if to.Name != nil {
+			grantTo.Name = string(*to.Name)
+		}
+		store.To = append(store.To, grantTo)
+	}
+	return store
 }
 
 func IsGatewayUpdated(oldGateway, newGateway *gatewayv1.Gateway) bool {
@@ -532,6 +1132,42 @@ func IsHTTPRouteUpdated(oldHTTPRoute, newHTTPRoute *gatewayv1.HTTPRoute) bool {
 	return oldHash != newHash
 }
 
+func IsTLSRouteUpdated(oldTLSRoute, newTLSRoute *gatewayv1alpha2.TLSRoute) bool {
+	if newTLSRoute.GetDeletionTimestamp() != nil {
+		return true
+	}
+	oldHash := utils.Hash(utils.Stringify(oldTLSRoute.Spec))
+	newHash := utils.Hash(utils.Stringify(newTLSRoute.Spec))
+	return oldHash != newHash
+}
+
+func IsTCPRouteUpdated(oldTCPRoute, newTCPRoute *gatewayv1alpha2.TCPRoute) bool {
+	if newTCPRoute.GetDeletionTimestamp() != nil {
+		return true
+	}
+	oldHash := utils.Hash(utils.Stringify(oldTCPRoute.Spec))
+	newHash := utils.Hash(utils.Stringify(newTCPRoute.Spec))
+	return oldHash != newHash
+}
+
+func IsUDPRouteUpdated(oldUDPRoute, newUDPRoute *gatewayv1alpha2.UDPRoute) bool {
+	if newUDPRoute.GetDeletionTimestamp() != nil {
+		return true
+	}
+	oldHash := utils.Hash(utils.Stringify(oldUDPRoute.Spec))
+	newHash := utils.Hash(utils.Stringify(newUDPRoute.Spec))
+	return oldHash != newHash
+}
+
+func IsGRPCRouteUpdated(oldGRPCRoute, newGRPCRoute *gatewayv1alpha2.GRPCRoute) bool {
+	if newGRPCRoute.GetDeletionTimestamp() != nil {
+		return true
+	}
+	oldHash := utils.Hash(utils.Stringify(oldGRPCRoute.Spec))
+	newHash := utils.Hash(utils.Stringify(newGRPCRoute.Spec))
+	return oldHash != newHash
+}
+
 func validateAviConfigMap(obj interface{}) (*corev1.ConfigMap, bool) {
 	configMap, ok := obj.(*corev1.ConfigMap)
 	if ok && configMap.Namespace == utils.GetAKONamespace() && configMap.Name == lib.AviConfigMap {
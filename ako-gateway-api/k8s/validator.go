@@ -22,6 +22,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	"k8s.io/apimachinery/pkg/labels"
 
@@ -139,7 +140,7 @@ func isValidListener(key string, gateway *gatewayv1.Gateway, gatewayStatus *gate
 
 	listener := gateway.Spec.Listeners[index]
 	gatewayStatus.Listeners[index].Name = gateway.Spec.Listeners[index].Name
-	gatewayStatus.Listeners[index].SupportedKinds = akogatewayapilib.SupportedKinds[listener.Protocol]
+	gatewayStatus.Listeners[index].SupportedKinds = akogatewayapilib.GetSupportedKinds(listener.Protocol)
 	gatewayStatus.Listeners[index].AttachedRoutes = akogatewayapilib.ZeroAttachedRoutes
 
 	defaultCondition := akogatewayapistatus.NewCondition().
@@ -148,55 +149,99 @@ func isValidListener(key string, gateway *gatewayv1.Gateway, gatewayStatus *gate
 		Status(metav1.ConditionFalse).
 		ObservedGeneration(gateway.ObjectMeta.Generation)
 
-	// hostname is not nil or wildcard
-	if listener.Hostname == nil || *listener.Hostname == "*" {
-		utils.AviLog.Errorf("key: %s, msg: hostname is either wildcard or empty in listener %s", key, listener.Name)
+	// hostname is either empty, in which case the hostnames are taken from the attached routes,
+	// or a hostname with an optional wildcard label as prefix
+	listenerHostname := akogatewayapilib.GetListenerHostname(listener)
+	if listenerHostname == "*" || strings.Contains(strings.TrimPrefix(listenerHostname, "*."), "*") {
+		utils.AviLog.Errorf("key: %s, msg: hostname %s is not valid in listener %s", key, listenerHostname, listener.Name)
 		defaultCondition.
 			Message("Hostname not found or Hostname has invalid configuration").
 			SetIn(&gatewayStatus.Listeners[index].Conditions)
 		return false
 	}
 
-	// hostname should not overlap with hostname of an existing gateway
-	gatewayNsList, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GatewayInformer.Lister().Gateways(gateway.Namespace).List(labels.Set(nil).AsSelector())
-	if err != nil {
-		utils.AviLog.Errorf("Unable to retrieve the gateways during validation: %s", err)
-		return false
-	}
-	for _, gatewayInNamespace := range gatewayNsList {
-		if gateway.Name != gatewayInNamespace.Name {
-			for _, gwListener := range gatewayInNamespace.Spec.Listeners {
-				if *listener.Hostname == *gwListener.Hostname || isRegexMatch(string(*listener.Hostname), string(*gwListener.Hostname), key) || isRegexMatch(string(*gwListener.Hostname), string(*listener.Hostname), key) {
-					utils.AviLog.Errorf("key: %s, msg: Hostname overlaps or is same as an existing gateway %s hostname %s", key, gatewayInNamespace.Name, *gwListener.Hostname)
-					defaultCondition.
-						Message("Hostname overlaps or is same as an existing gateway hostname").
-						SetIn(&gatewayStatus.Listeners[index].Conditions)
-					return false
+	if listenerHostname != "" {
+		// hostname should not overlap with hostname of an existing gateway
+		gatewayNsList, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GatewayInformer.Lister().Gateways(gateway.Namespace).List(labels.Set(nil).AsSelector())
+		if err != nil {
+			utils.AviLog.Errorf("Unable to retrieve the gateways during validation: %s", err)
+			return false
+		}
+		for _, gatewayInNamespace := range gatewayNsList {
+			if gateway.Name != gatewayInNamespace.Name {
+				for _, gwListener := range gatewayInNamespace.Spec.Listeners {
+					gwListenerHostname := akogatewayapilib.GetListenerHostname(gwListener)
+					if gwListenerHostname == "" {
+						continue
+					}
+					if listenerHostname == gwListenerHostname || isRegexMatch(listenerHostname, gwListenerHostname, key) || isRegexMatch(gwListenerHostname, listenerHostname, key) {
+						utils.AviLog.Errorf("key: %s, msg: Hostname overlaps or is same as an existing gateway %s hostname %s", key, gatewayInNamespace.Name, gwListenerHostname)
+						defaultCondition.
+							Message("Hostname overlaps or is same as an existing gateway hostname").
+							SetIn(&gatewayStatus.Listeners[index].Conditions)
+						return false
+					}
 				}
 			}
 		}
-	}
-	if !akogatewayapilib.VerifyHostnameSubdomainMatch(string(*listener.Hostname)) {
-		defaultCondition.
-			Message(fmt.Sprintf("Didn't find match for hostname :%s in available sub-domains", string(*listener.Hostname))).
-			SetIn(&gatewayStatus.Listeners[index].Conditions)
-		return false
+		if !akogatewayapilib.VerifyHostnameSubdomainMatch(listenerHostname) {
+			defaultCondition.
+				Message(fmt.Sprintf("Didn't find match for hostname :%s in available sub-domains", listenerHostname)).
+				SetIn(&gatewayStatus.Listeners[index].Conditions)
+			return false
+		}
 	}
 
 	// protocol validation
-	if listener.Protocol != gatewayv1.HTTPProtocolType &&
-		listener.Protocol != gatewayv1.HTTPSProtocolType {
+	if !isSupportedListenerProtocol(listener.Protocol) {
 		utils.AviLog.Errorf("key: %s, msg: protocol is not supported for listener %s", key, listener.Name)
 		defaultCondition.
 			Reason(string(gatewayv1.ListenerReasonUnsupportedProtocol)).
 			Message("Unsupported protocol").
 			SetIn(&gatewayStatus.Listeners[index].Conditions)
-		gatewayStatus.Listeners[index].SupportedKinds = akogatewayapilib.SupportedKinds[gatewayv1.HTTPSProtocolType]
+		gatewayStatus.Listeners[index].SupportedKinds = akogatewayapilib.GetSupportedKinds(gatewayv1.HTTPSProtocolType)
+		return false
+	}
+
+	// TLS, TCP and UDP listeners are realised in a L4 VS, hence can not be mixed with HTTP and HTTPS listeners
+	if akogatewayapilib.IsL4Gateway(gateway) != akogatewayapilib.IsL4Protocol(string(listener.Protocol)) {
+		utils.AviLog.Errorf("key: %s, msg: protocol of listener %s conflicts with the other listeners of gateway %s", key, listener.Name, gateway.Name)
+		defaultCondition.
+			Reason(string(gatewayv1.ListenerReasonProtocolConflict)).
+			Message("TLS, TCP and UDP listeners can not be combined with HTTP or HTTPS listeners in a Gateway").
+			SetIn(&gatewayStatus.Listeners[index].Conditions)
+		return false
+	}
+	// the pool groups of TLS passthrough listeners are selected using the SNI, which is not available for TCP and UDP listeners
+	if akogatewayapilib.IsPassthroughGateway(gateway) && listener.Protocol != gatewayv1.TLSProtocolType {
+		utils.AviLog.Errorf("key: %s, msg: protocol of listener %s conflicts with the TLS listeners of gateway %s", key, listener.Name, gateway.Name)
+		defaultCondition.
+			Reason(string(gatewayv1.ListenerReasonProtocolConflict)).
+			Message("TCP and UDP listeners can not be combined with TLS listeners in a Gateway").
+			SetIn(&gatewayStatus.Listeners[index].Conditions)
 		return false
 	}
 
 	// has valid TLS config
-	if listener.TLS != nil {
+	if listener.Protocol == gatewayv1.TLSProtocolType {
+		if listener.TLS == nil || listener.TLS.Mode == nil || *listener.TLS.Mode != gatewayv1.TLSModePassthrough {
+			utils.AviLog.Errorf("key: %s, msg: tls mode not valid %+v/%+v, must be Passthrough", key, gateway.Name, listener.Name)
+			defaultCondition.
+				Reason(string(gatewayv1.ListenerReasonUnsupportedProtocol)).
+				Message("TLS mode not valid. Only Passthrough is supported for TLS protocol").
+				SetIn(&gatewayStatus.Listeners[index].Conditions)
+			return false
+		}
+	} else if listener.Protocol == gatewayv1.TCPProtocolType || listener.Protocol == gatewayv1.UDPProtocolType {
+		if listener.TLS != nil {
+			utils.AviLog.Errorf("key: %s, msg: tls config is not supported for listener %+v/%+v", key, gateway.Name, listener.Name)
+			defaultCondition.
+				Reason(string(gatewayv1.ListenerReasonUnsupportedProtocol)).
+				Message("TLS config is not supported for TCP and UDP protocol").
+				SetIn(&gatewayStatus.Listeners[index].Conditions)
+			return false
+		}
+	} else if listener.TLS != nil {
 		if (listener.TLS.Mode != nil && *listener.TLS.Mode != gatewayv1.TLSModeTerminate) || len(listener.TLS.CertificateRefs) == 0 {
 			utils.AviLog.Errorf("key: %s, msg: tls mode/ref not valid %+v/%+v", key, gateway.Name, listener.Name)
 			defaultCondition.
@@ -216,20 +261,34 @@ func isValidListener(key string, gateway *gatewayv1.Gateway, gatewayStatus *gate
 					SetIn(&gatewayStatus.Listeners[index].Conditions)
 				return false
 			}
-
+			// the secrets in the other namespaces can be used only when a ReferenceGrant permits the reference
+			if !akogatewayapilib.IsCertificateRefAllowed(gateway.Namespace, certRef) {
+				utils.AviLog.Errorf("key: %s, msg: CertificateRef %s/%s is not permitted %+v/%+v", key, *certRef.Namespace, certRef.Name, gateway.Name, listener.Name)
+				defaultCondition.
+					Type(string(gatewayv1.ListenerConditionResolvedRefs)).
+					Reason(string(gatewayv1.ListenerReasonRefNotPermitted)).
+					Message(fmt.Sprintf("Reference to the secret %s/%s is not permitted by any ReferenceGrant", *certRef.Namespace, certRef.Name)).
+					SetIn(&gatewayStatus.Listeners[index].Conditions)
+				return false
+			}
 		}
 	}
 
 	//allowedRoutes validation
 	if listener.AllowedRoutes != nil {
 		if listener.AllowedRoutes.Kinds != nil {
+			var supportedKinds []string
+			for _, kind := range akogatewayapilib.GetSupportedKinds(listener.Protocol) {
+				supportedKinds = append(supportedKinds, string(kind.Kind))
+			}
+			supportedKind := strings.Join(supportedKinds, ", ")
 			for _, kindInAllowedRoute := range listener.AllowedRoutes.Kinds {
-				if kindInAllowedRoute.Kind != "" && string(kindInAllowedRoute.Kind) != utils.HTTPRoute {
-					utils.AviLog.Errorf("key: %s, msg: AllowedRoute kind is invalid %+v/%+v. Supported AllowedRoute kind is HTTPRoute.", key, gateway.Name, listener.Name)
+				if kindInAllowedRoute.Kind != "" && !akogatewayapilib.IsRouteKindSupported(string(listener.Protocol), string(kindInAllowedRoute.Kind)) {
+					utils.AviLog.Errorf("key: %s, msg: AllowedRoute kind is invalid %+v/%+v. Supported AllowedRoute kind is %s.", key, gateway.Name, listener.Name, supportedKind)
 					defaultCondition.
 						Type(string(gatewayv1.ListenerConditionResolvedRefs)).
 						Reason(string(gatewayv1.ListenerReasonInvalidRouteKinds)).
-						Message("AllowedRoute kind is invalid. Only HTTPRoute is supported currently").
+						Message(fmt.Sprintf("AllowedRoute kind is invalid. Only %s is supported currently", supportedKind)).
 						SetIn(&gatewayStatus.Listeners[index].Conditions)
 					return false
 				}
@@ -275,15 +334,30 @@ func IsHTTPRouteValid(key string, obj *gatewayv1.HTTPRoute) bool {
 
 	httpRouteStatus := obj.Status.DeepCopy()
 	httpRouteStatus.Parents = make([]gatewayv1.RouteParentStatus, 0, len(httpRoute.Spec.ParentRefs))
+
+	err := validateHTTPRouteMatches(httpRoute)
+	if err == nil {
+		err = validateHTTPRouteFilters(httpRoute)
+	}
+	if err != nil {
+		utils.AviLog.Errorf("key: %s, msg: HTTPRoute %s is not valid, err: %v", key, httpRoute.Name, err)
+		setUnsupportedValueCondition(key, httpRoute, lib.HTTPRoute, httpRoute.Spec.ParentRefs, &httpRouteStatus.RouteStatus, err)
+		akogatewayapistatus.Record(key, httpRoute, &akogatewayapistatus.Status{HTTPRouteStatus: httpRouteStatus})
+		akogatewayapilib.AKOControlConfig().EventRecorder().Eventf(httpRoute, corev1.EventTypeWarning,
+			lib.Detached, "HTTPRoute %s is not valid, %v", httpRoute.Name, err)
+		return false
+	}
+
 	var invalidParentRefCount int
 	for index := range httpRoute.Spec.ParentRefs {
-		err := validateParentReference(key, httpRoute, httpRouteStatus, index)
+		err := validateParentReference(key, httpRoute, lib.HTTPRoute, httpRoute.Spec.ParentRefs, httpRoute.Spec.Hostnames, &httpRouteStatus.RouteStatus, index)
 		if err != nil {
 			invalidParentRefCount++
 			parentRefName := httpRoute.Spec.ParentRefs[index].Name
 			utils.AviLog.Warnf("key: %s, msg: Parent Reference %s of HTTPRoute object %s is not valid, err: %v", key, parentRefName, httpRoute.Name, err)
 		}
 	}
+	setResolvedRefsCondition(key, httpRoute, lib.HTTPRoute, getHTTPRouteBackendRefs(httpRoute), &httpRouteStatus.RouteStatus)
 	akogatewayapistatus.Record(key, httpRoute, &akogatewayapistatus.Status{HTTPRouteStatus: httpRouteStatus})
 
 	// No valid attachment, we can't proceed with this HTTPRoute object.
@@ -297,12 +371,434 @@ func IsHTTPRouteValid(key string, obj *gatewayv1.HTTPRoute) bool {
 	return true
 }
 
-func validateParentReference(key string, httpRoute *gatewayv1.HTTPRoute, httpRouteStatus *gatewayv1.HTTPRouteStatus, index int) error {
+// validateHTTPRouteMatches validates the query param matches of the HTTPRoute rules. The query
+// param is matched against the query string of the request, so a single exact query param is
+// supported in a match.
+func validateHTTPRouteMatches(httpRoute *gatewayv1.HTTPRoute) error {
+	for _, rule := range httpRoute.Spec.Rules {
+		for _, match := range rule.Matches {
+			if len(match.QueryParams) > 1 {
+				return fmt.Errorf("only one query param match is supported in a match")
+			}
+			for _, queryParam := range match.QueryParams {
+				if queryParam.Type != nil && *queryParam.Type != gatewayv1.QueryParamMatchExact {
+					return fmt.Errorf("query param match of type %s is not supported", *queryParam.Type)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateHTTPRouteFilters validates the filters of the HTTPRoute rules against what can be
+// configured on the child VS.
+func validateHTTPRouteFilters(httpRoute *gatewayv1.HTTPRoute) error {
+	for _, rule := range httpRoute.Spec.Rules {
+		var hasRedirect, hasRewrite, hasMirror bool
+		for _, filter := range rule.Filters {
+			switch filter.Type {
+			case gatewayv1.HTTPRouteFilterRequestRedirect:
+				if filter.RequestRedirect == nil {
+					continue
+				}
+				hasRedirect = true
+				redirect := filter.RequestRedirect
+				if redirect.Scheme != nil && *redirect.Scheme != "http" && *redirect.Scheme != "https" {
+					return fmt.Errorf("redirect scheme %s is not supported", *redirect.Scheme)
+				}
+				if redirect.StatusCode != nil && *redirect.StatusCode != 301 && *redirect.StatusCode != 302 && *redirect.StatusCode != 307 {
+					return fmt.Errorf("redirect status code %d is not supported", *redirect.StatusCode)
+				}
+				if err := validatePathModifier(redirect.Path, rule.Matches); err != nil {
+					return err
+				}
+			case gatewayv1.HTTPRouteFilterURLRewrite:
+				if filter.URLRewrite == nil {
+					continue
+				}
+				if hasRewrite {
+					return fmt.Errorf("only one URLRewrite filter is supported in a rule")
+				}
+				hasRewrite = true
+				if err := validatePathModifier(filter.URLRewrite.Path, rule.Matches); err != nil {
+					return err
+				}
+			case gatewayv1.HTTPRouteFilterRequestMirror:
+				if filter.RequestMirror == nil {
+					continue
+				}
+				if hasMirror {
+					return fmt.Errorf("only one RequestMirror filter is supported in a rule")
+				}
+				hasMirror = true
+				backendRef := filter.RequestMirror.BackendRef
+				if backendRef.Kind != nil && *backendRef.Kind != "Service" {
+					return fmt.Errorf("RequestMirror backend of kind %s is not supported", *backendRef.Kind)
+				}
+			case gatewayv1.HTTPRouteFilterExtensionRef:
+				return fmt.Errorf("filter of type %s is not supported", filter.Type)
+			}
+		}
+		if hasRedirect && hasRewrite {
+			return fmt.Errorf("RequestRedirect and URLRewrite filters cannot be used together in a rule")
+		}
+	}
+	return nil
+}
+
+// validatePathModifier validates the path modifier of the RequestRedirect and URLRewrite filters.
+// ReplacePrefixMatch is honoured only when all the matches of the rule are path prefix matches with
+// the same number of segments, since the prefix is replaced based on the segment count.
+func validatePathModifier(pathModifier *gatewayv1.HTTPPathModifier, matches []gatewayv1.HTTPRouteMatch) error {
+	if pathModifier == nil {
+		return nil
+	}
+	switch pathModifier.Type {
+	case gatewayv1.FullPathHTTPPathModifier:
+		return nil
+	case gatewayv1.PrefixMatchHTTPPathModifier:
+		if len(matches) == 0 {
+			return fmt.Errorf("%s requires a path prefix match", pathModifier.Type)
+		}
+		segments := -1
+		for _, match := range matches {
+			if match.Path == nil || match.Path.Type == nil || *match.Path.Type != gatewayv1.PathMatchPathPrefix || match.Path.Value == nil {
+				return fmt.Errorf("%s requires a path prefix match", pathModifier.Type)
+			}
+			count := 0
+			if prefix := strings.Trim(*match.Path.Value, "/"); prefix != "" {
+				count = len(strings.Split(prefix, "/"))
+			}
+			if segments != -1 && segments != count {
+				return fmt.Errorf("%s requires the path prefixes of a rule to have the same number of segments", pathModifier.Type)
+			}
+			segments = count
+		}
+		return nil
+	}
+	return fmt.Errorf("path modifier of type %s is not supported", pathModifier.Type)
+}
+
+// setUnsupportedValueCondition marks the route as not accepted by the parents controlled by AKO.
+func setUnsupportedValueCondition(key string, route metav1.Object, routeKind string, parentRefs []gatewayv1.ParentReference, routeStatus *gatewayv1.RouteStatus, err error) {
+	for _, parentRef := range parentRefs {
+		name := string(parentRef.Name)
+		namespace := route.GetNamespace()
+		if parentRef.Namespace != nil {
+			namespace = string(*parentRef.Namespace)
+		}
+		gateway, gwErr := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GatewayInformer.Lister().Gateways(namespace).Get(name)
+		if gwErr != nil {
+			utils.AviLog.Warnf("key: %s, msg: unable to get the gateway object. err: %s", key, gwErr)
+			continue
+		}
+		if _, isAKOCtrl := akogatewayapiobjects.GatewayApiLister().IsGatewayClassControllerAKO(string(gateway.Spec.GatewayClassName)); !isAKOCtrl {
+			continue
+		}
+		parentStatus := gatewayv1.RouteParentStatus{ControllerName: akogatewayapilib.GatewayController}
+		parentStatus.ParentRef.Name = gatewayv1.ObjectName(name)
+		parentStatus.ParentRef.Namespace = (*gatewayv1.Namespace)(&namespace)
+		parentStatus.ParentRef.SectionName = parentRef.SectionName
+		akogatewayapistatus.NewCondition().
+			Type(string(gatewayv1.RouteConditionAccepted)).
+			Reason(string(gatewayv1.RouteReasonUnsupportedValue)).
+			Status(metav1.ConditionFalse).
+			ObservedGeneration(route.GetGeneration()).
+			Message(err.Error()).
+			SetIn(&parentStatus.Conditions)
+		routeStatus.Parents = append(routeStatus.Parents, parentStatus)
+		utils.AviLog.Warnf("key: %s, msg: %s %s is not accepted by the parent reference %s, err: %v", key, routeKind, route.GetName(), name, err)
+	}
+}
+
+func IsGRPCRouteValid(key string, obj *gatewayv1alpha2.GRPCRoute) bool {
+
+	grpcRoute := obj.DeepCopy()
+	if len(grpcRoute.Spec.ParentRefs) == 0 {
+		utils.AviLog.Errorf("key: %s, msg: Parent Reference is empty for the GRPCRoute %s", key, grpcRoute.Name)
+		return false
+	}
+
+	for _, hostname := range grpcRoute.Spec.Hostnames {
+		if strings.Contains(string(hostname), "*") {
+			utils.AviLog.Errorf("key: %s, msg: Wildcard in hostname is not supported for the GRPCRoute %s", key, grpcRoute.Name)
+			akogatewayapilib.AKOControlConfig().EventRecorder().Eventf(grpcRoute, corev1.EventTypeWarning,
+				lib.Detached, "Wildcard in hostname is not supported for the GRPCRoute %s", grpcRoute.Name)
+			return false
+		}
+	}
+
+	if err := validateGRPCRouteMatches(grpcRoute); err != nil {
+		utils.AviLog.Errorf("key: %s, msg: GRPCRoute %s is not valid, err: %v", key, grpcRoute.Name, err)
+		akogatewayapilib.AKOControlConfig().EventRecorder().Eventf(grpcRoute, corev1.EventTypeWarning,
+			lib.Detached, "GRPCRoute %s is not valid, %v", grpcRoute.Name, err)
+		return false
+	}
+
+	grpcRouteStatus := obj.Status.DeepCopy()
+	grpcRouteStatus.Parents = make([]gatewayv1.RouteParentStatus, 0, len(grpcRoute.Spec.ParentRefs))
+	var invalidParentRefCount int
+	for index := range grpcRoute.Spec.ParentRefs {
+		err := validateParentReference(key, grpcRoute, lib.GRPCRoute, grpcRoute.Spec.ParentRefs, grpcRoute.Spec.Hostnames, &grpcRouteStatus.RouteStatus, index)
+		if err != nil {
+			invalidParentRefCount++
+			parentRefName := grpcRoute.Spec.ParentRefs[index].Name
+			utils.AviLog.Warnf("key: %s, msg: Parent Reference %s of GRPCRoute object %s is not valid, err: %v", key, parentRefName, grpcRoute.Name, err)
+		}
+	}
+	setResolvedRefsCondition(key, grpcRoute, lib.GRPCRoute, getGRPCRouteBackendRefs(grpcRoute), &grpcRouteStatus.RouteStatus)
+	akogatewayapistatus.Record(key, grpcRoute, &akogatewayapistatus.Status{GRPCRouteStatus: grpcRouteStatus})
+
+	// No valid attachment, we can't proceed with this GRPCRoute object.
+	if invalidParentRefCount == len(grpcRoute.Spec.ParentRefs) {
+		utils.AviLog.Errorf("key: %s, msg: GRPCRoute object %s is not valid", key, grpcRoute.Name)
+		akogatewayapilib.AKOControlConfig().EventRecorder().Eventf(grpcRoute, corev1.EventTypeWarning,
+			lib.Detached, "GRPCRoute object %s is not valid", grpcRoute.Name)
+		return false
+	}
+	utils.AviLog.Infof("key: %s, msg: GRPCRoute object %s is valid", key, grpcRoute.Name)
+	return true
+}
+
+// validateGRPCRouteMatches validates the method and header matches of the GRPCRoute. The matches are
+// translated to path and header matches on the child VS, where regular expressions are not supported.
+func validateGRPCRouteMatches(grpcRoute *gatewayv1alpha2.GRPCRoute) error {
+	for _, rule := range grpcRoute.Spec.Rules {
+		for _, match := range rule.Matches {
+			if match.Method != nil {
+				if match.Method.Type != nil && *match.Method.Type != gatewayv1alpha2.GRPCMethodMatchExact {
+					return fmt.Errorf("method match of type %s is not supported", *match.Method.Type)
+				}
+				if (match.Method.Service == nil || *match.Method.Service == "") &&
+					(match.Method.Method == nil || *match.Method.Method == "") {
+					return fmt.Errorf("method match must specify a service or a method")
+				}
+			}
+			for _, header := range match.Headers {
+				if header.Type != nil && *header.Type != gatewayv1.HeaderMatchExact {
+					return fmt.Errorf("header match of type %s is not supported", *header.Type)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func IsTLSRouteValid(key string, obj *gatewayv1alpha2.TLSRoute) bool {
+
+	tlsRoute := obj.DeepCopy()
+	if len(tlsRoute.Spec.ParentRefs) == 0 {
+		utils.AviLog.Errorf("key: %s, msg: Parent Reference is empty for the TLSRoute %s", key, tlsRoute.Name)
+		return false
+	}
+
+	// the pool group for a TLSRoute is selected using the SNI, hence the hostnames are mandatory.
+	if len(tlsRoute.Spec.Hostnames) == 0 {
+		utils.AviLog.Errorf("key: %s, msg: Hostname is empty for the TLSRoute %s", key, tlsRoute.Name)
+		akogatewayapilib.AKOControlConfig().EventRecorder().Eventf(tlsRoute, corev1.EventTypeWarning,
+			lib.Detached, "Hostname is required for the TLSRoute %s", tlsRoute.Name)
+		return false
+	}
+	for _, hostname := range tlsRoute.Spec.Hostnames {
+		if strings.Contains(string(hostname), "*") {
+			utils.AviLog.Errorf("key: %s, msg: Wildcard in hostname is not supported for the TLSRoute %s", key, tlsRoute.Name)
+			akogatewayapilib.AKOControlConfig().EventRecorder().Eventf(tlsRoute, corev1.EventTypeWarning,
+				lib.Detached, "Wildcard in hostname is not supported for the TLSRoute %s", tlsRoute.Name)
+			return false
+		}
+	}
+
+	tlsRouteStatus := obj.Status.DeepCopy()
+	tlsRouteStatus.Parents = make([]gatewayv1.RouteParentStatus, 0, len(tlsRoute.Spec.ParentRefs))
+	var invalidParentRefCount int
+	for index := range tlsRoute.Spec.ParentRefs {
+		err := validateParentReference(key, tlsRoute, lib.TLSRoute, tlsRoute.Spec.ParentRefs, tlsRoute.Spec.Hostnames, &tlsRouteStatus.RouteStatus, index)
+		if err != nil {
+			invalidParentRefCount++
+			parentRefName := tlsRoute.Spec.ParentRefs[index].Name
+			utils.AviLog.Warnf("key: %s, msg: Parent Reference %s of TLSRoute object %s is not valid, err: %v", key, parentRefName, tlsRoute.Name, err)
+		}
+	}
+	setResolvedRefsCondition(key, tlsRoute, lib.TLSRoute, getTLSRouteBackendRefs(tlsRoute), &tlsRouteStatus.RouteStatus)
+	akogatewayapistatus.Record(key, tlsRoute, &akogatewayapistatus.Status{TLSRouteStatus: tlsRouteStatus})
+
+	// No valid attachment, we can't proceed with this TLSRoute object.
+	if invalidParentRefCount == len(tlsRoute.Spec.ParentRefs) {
+		utils.AviLog.Errorf("key: %s, msg: TLSRoute object %s is not valid", key, tlsRoute.Name)
+		akogatewayapilib.AKOControlConfig().EventRecorder().Eventf(tlsRoute, corev1.EventTypeWarning,
+			lib.Detached, "TLSRoute object %s is not valid", tlsRoute.Name)
+		return false
+	}
+	utils.AviLog.Infof("key: %s, msg: TLSRoute object %s is valid", key, tlsRoute.Name)
+	return true
+}
+
+func IsTCPRouteValid(key string, obj *gatewayv1alpha2.TCPRoute) bool {
 
-	name := string(httpRoute.Spec.ParentRefs[index].Name)
-	namespace := httpRoute.Namespace
-	if httpRoute.Spec.ParentRefs[index].Namespace != nil {
-		namespace = string(*httpRoute.Spec.ParentRefs[index].Namespace)
+	tcpRoute := obj.DeepCopy()
+	if len(tcpRoute.Spec.ParentRefs) == 0 {
+		utils.AviLog.Errorf("key: %s, msg: Parent Reference is empty for the TCPRoute %s", key, tcpRoute.Name)
+		return false
+	}
+
+	tcpRouteStatus := obj.Status.DeepCopy()
+	tcpRouteStatus.Parents = make([]gatewayv1.RouteParentStatus, 0, len(tcpRoute.Spec.ParentRefs))
+	var invalidParentRefCount int
+	for index := range tcpRoute.Spec.ParentRefs {
+		err := validateParentReference(key, tcpRoute, lib.TCPRoute, tcpRoute.Spec.ParentRefs, nil, &tcpRouteStatus.RouteStatus, index)
+		if err != nil {
+			invalidParentRefCount++
+			parentRefName := tcpRoute.Spec.ParentRefs[index].Name
+			utils.AviLog.Warnf("key: %s, msg: Parent Reference %s of TCPRoute object %s is not valid, err: %v", key, parentRefName, tcpRoute.Name, err)
+		}
+	}
+	setResolvedRefsCondition(key, tcpRoute, lib.TCPRoute, getTCPRouteBackendRefs(tcpRoute), &tcpRouteStatus.RouteStatus)
+	akogatewayapistatus.Record(key, tcpRoute, &akogatewayapistatus.Status{TCPRouteStatus: tcpRouteStatus})
+
+	// No valid attachment, we can't proceed with this TCPRoute object.
+	if invalidParentRefCount == len(tcpRoute.Spec.ParentRefs) {
+		utils.AviLog.Errorf("key: %s, msg: TCPRoute object %s is not valid", key, tcpRoute.Name)
+		akogatewayapilib.AKOControlConfig().EventRecorder().Eventf(tcpRoute, corev1.EventTypeWarning,
+			lib.Detached, "TCPRoute object %s is not valid", tcpRoute.Name)
+		return false
+	}
+	utils.AviLog.Infof("key: %s, msg: TCPRoute object %s is valid", key, tcpRoute.Name)
+	return true
+}
+
+func IsUDPRouteValid(key string, obj *gatewayv1alpha2.UDPRoute) bool {
+
+	udpRoute := obj.DeepCopy()
+	if len(udpRoute.Spec.ParentRefs) == 0 {
+		utils.AviLog.Errorf("key: %s, msg: Parent Reference is empty for the UDPRoute %s", key, udpRoute.Name)
+		return false
+	}
+
+	udpRouteStatus := obj.Status.DeepCopy()
+	udpRouteStatus.Parents = make([]gatewayv1.RouteParentStatus, 0, len(udpRoute.Spec.ParentRefs))
+	var invalidParentRefCount int
+	for index := range udpRoute.Spec.ParentRefs {
+		err := validateParentReference(key, udpRoute, lib.UDPRoute, udpRoute.Spec.ParentRefs, nil, &udpRouteStatus.RouteStatus, index)
+		if err != nil {
+			invalidParentRefCount++
+			parentRefName := udpRoute.Spec.ParentRefs[index].Name
+			utils.AviLog.Warnf("key: %s, msg: Parent Reference %s of UDPRoute object %s is not valid, err: %v", key, parentRefName, udpRoute.Name, err)
+		}
+	}
+	setResolvedRefsCondition(key, udpRoute, lib.UDPRoute, getUDPRouteBackendRefs(udpRoute), &udpRouteStatus.RouteStatus)
+	akogatewayapistatus.Record(key, udpRoute, &akogatewayapistatus.Status{UDPRouteStatus: udpRouteStatus})
+
+	// No valid attachment, we can't proceed with this UDPRoute object.
+	if invalidParentRefCount == len(udpRoute.Spec.ParentRefs) {
+		utils.AviLog.Errorf("key: %s, msg: UDPRoute object %s is not valid", key, udpRoute.Name)
+		akogatewayapilib.AKOControlConfig().EventRecorder().Eventf(udpRoute, corev1.EventTypeWarning,
+			lib.Detached, "UDPRoute object %s is not valid", udpRoute.Name)
+		return false
+	}
+	utils.AviLog.Infof("key: %s, msg: UDPRoute object %s is valid", key, udpRoute.Name)
+	return true
+}
+
+// setResolvedRefsCondition sets the ResolvedRefs condition in the parent statuses of the route. The backends
+// in the other namespaces are resolved only when a ReferenceGrant permits the reference.
+func setResolvedRefsCondition(key string, route metav1.Object, routeKind string, backendRefs []gatewayv1.BackendObjectReference, routeStatus *gatewayv1.RouteStatus) {
+	condition := akogatewayapistatus.NewCondition().
+		Type(string(gatewayv1.RouteConditionResolvedRefs)).
+		Reason(string(gatewayv1.RouteReasonResolvedRefs)).
+		Status(metav1.ConditionTrue).
+		ObservedGeneration(route.GetGeneration()).
+		Message("All the references are resolved")
+	for _, backendRef := range backendRefs {
+		if !akogatewayapilib.IsBackendRefAllowed(routeKind, route.GetNamespace(), backendRef) {
+			utils.AviLog.Warnf("key: %s, msg: reference to the backend %s/%s of %s %s is not permitted", key, *backendRef.Namespace, backendRef.Name, routeKind, route.GetName())
+			condition.
+				Reason(string(gatewayv1.RouteReasonRefNotPermitted)).
+				Status(metav1.ConditionFalse).
+				Message(fmt.Sprintf("Reference to the backend %s/%s is not permitted by any ReferenceGrant", *backendRef.Namespace, backendRef.Name))
+			break
+		}
+	}
+	for i := range routeStatus.Parents {
+		condition.SetIn(&routeStatus.Parents[i].Conditions)
+	}
+}
+
+func getHTTPRouteBackendRefs(httpRoute *gatewayv1.HTTPRoute) []gatewayv1.BackendObjectReference {
+	var backendRefs []gatewayv1.BackendObjectReference
+	for _, rule := range httpRoute.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			backendRefs = append(backendRefs, backendRef.BackendObjectReference)
+		}
+		for _, filter := range rule.Filters {
+			if filter.RequestMirror != nil {
+				backendRefs = append(backendRefs, filter.RequestMirror.BackendRef)
+			}
+		}
+	}
+	return backendRefs
+}
+
+func getGRPCRouteBackendRefs(grpcRoute *gatewayv1alpha2.GRPCRoute) []gatewayv1.BackendObjectReference {
+	var backendRefs []gatewayv1.BackendObjectReference
+	for _, rule := range grpcRoute.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			backendRefs = append(backendRefs, backendRef.BackendObjectReference)
+		}
+	}
+	return backendRefs
+}
+
+func getTLSRouteBackendRefs(tlsRoute *gatewayv1alpha2.TLSRoute) []gatewayv1.BackendObjectReference {
+	var backendRefs []gatewayv1.BackendObjectReference
+	for _, rule := range tlsRoute.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			backendRefs = append(backendRefs, backendRef.BackendObjectReference)
+		}
+	}
+	return backendRefs
+}
+
+func getTCPRouteBackendRefs(tcpRoute *gatewayv1alpha2.TCPRoute) []gatewayv1.BackendObjectReference {
+	var backendRefs []gatewayv1.BackendObjectReference
+	for _, rule := range tcpRoute.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			backendRefs = append(backendRefs, backendRef.BackendObjectReference)
+		}
+	}
+	return backendRefs
+}
+
+func getUDPRouteBackendRefs(udpRoute *gatewayv1alpha2.UDPRoute) []gatewayv1.BackendObjectReference {
+	var backendRefs []gatewayv1.BackendObjectReference
+	for _, rule := range udpRoute.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			backendRefs = append(backendRefs, backendRef.BackendObjectReference)
+		}
+	}
+	return backendRefs
+}
+
+func isSupportedListenerProtocol(protocol gatewayv1.ProtocolType) bool {
+	switch protocol {
+	case gatewayv1.HTTPProtocolType, gatewayv1.HTTPSProtocolType:
+		return true
+	case gatewayv1.TLSProtocolType:
+		// TLS passthrough requires the TLSRoute CRD to be installed.
+		return akogatewayapilib.AKOControlConfig().GatewayApiInformers().TLSRouteInformer != nil
+	case gatewayv1.TCPProtocolType:
+		return akogatewayapilib.AKOControlConfig().GatewayApiInformers().TCPRouteInformer != nil
+	case gatewayv1.UDPProtocolType:
+		return akogatewayapilib.AKOControlConfig().GatewayApiInformers().UDPRouteInformer != nil
+	}
+	return false
+}
+
+func validateParentReference(key string, route metav1.Object, routeKind string, parentRefs []gatewayv1.ParentReference, hostnames []gatewayv1.Hostname, routeStatus *gatewayv1.RouteStatus, index int) error {
+
+	name := string(parentRefs[index].Name)
+	namespace := route.GetNamespace()
+	if parentRefs[index].Namespace != nil {
+		namespace = string(*parentRefs[index].Namespace)
 	}
 	gwNsName := namespace + "/" + name
 	obj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GatewayInformer.Lister().Gateways(namespace).Get(name)
@@ -315,23 +811,23 @@ func validateParentReference(key string, httpRoute *gatewayv1.HTTPRoute, httpRou
 	gwClass := string(gateway.Spec.GatewayClassName)
 	_, isAKOCtrl := akogatewayapiobjects.GatewayApiLister().IsGatewayClassControllerAKO(gwClass)
 	if !isAKOCtrl {
-		utils.AviLog.Warnf("key: %s, msg: controller for the parent reference %s of HTTPRoute object %s is not ako", key, name, httpRoute.Name)
-		return fmt.Errorf("controller for the parent reference %s of HTTPRoute object %s is not ako", name, httpRoute.Name)
+		utils.AviLog.Warnf("key: %s, msg: controller for the parent reference %s of %s object %s is not ako", key, name, routeKind, route.GetName())
+		return fmt.Errorf("controller for the parent reference %s of %s object %s is not ako", name, routeKind, route.GetName())
 	}
 	// creates the Parent status only when the AKO is the gateway controller
-	httpRouteStatus.Parents = append(httpRouteStatus.Parents, gatewayv1.RouteParentStatus{})
-	httpRouteStatus.Parents[index].ControllerName = akogatewayapilib.GatewayController
-	httpRouteStatus.Parents[index].ParentRef.Name = gatewayv1.ObjectName(name)
-	httpRouteStatus.Parents[index].ParentRef.Namespace = (*gatewayv1.Namespace)(&namespace)
-	if httpRoute.Spec.ParentRefs[index].SectionName != nil {
-		httpRouteStatus.Parents[index].ParentRef.SectionName = httpRoute.Spec.ParentRefs[index].SectionName
+	routeStatus.Parents = append(routeStatus.Parents, gatewayv1.RouteParentStatus{})
+	routeStatus.Parents[index].ControllerName = akogatewayapilib.GatewayController
+	routeStatus.Parents[index].ParentRef.Name = gatewayv1.ObjectName(name)
+	routeStatus.Parents[index].ParentRef.Namespace = (*gatewayv1.Namespace)(&namespace)
+	if parentRefs[index].SectionName != nil {
+		routeStatus.Parents[index].ParentRef.SectionName = parentRefs[index].SectionName
 	}
 
 	defaultCondition := akogatewayapistatus.NewCondition().
 		Type(string(gatewayv1.GatewayConditionAccepted)).
 		Reason(string(gatewayv1.GatewayReasonInvalid)).
 		Status(metav1.ConditionFalse).
-		ObservedGeneration(httpRoute.ObjectMeta.Generation)
+		ObservedGeneration(route.GetGeneration())
 
 	if len(gateway.Status.Conditions) == 0 {
 		// Gateway processing by AKO has not started.
@@ -339,7 +835,7 @@ func validateParentReference(key string, httpRoute *gatewayv1.HTTPRoute, httpRou
 		err := fmt.Errorf("AKO is yet to process Gateway %s for parent reference %s", gateway.Name, name)
 		defaultCondition.
 			Message(err.Error()).
-			SetIn(&httpRouteStatus.Parents[index].Conditions)
+			SetIn(&routeStatus.Parents[index].Conditions)
 		return err
 	}
 
@@ -351,14 +847,14 @@ func validateParentReference(key string, httpRoute *gatewayv1.HTTPRoute, httpRou
 		err := fmt.Errorf("Gateway %s is in Invalid State", gateway.Name)
 		defaultCondition.
 			Message(err.Error()).
-			SetIn(&httpRouteStatus.Parents[index].Conditions)
+			SetIn(&routeStatus.Parents[index].Conditions)
 		return err
 	}
 
 	//section name is optional
 	var listenersForRoute []gatewayv1.Listener
-	if httpRoute.Spec.ParentRefs[index].SectionName != nil {
-		listenerName := *httpRoute.Spec.ParentRefs[index].SectionName
+	if parentRefs[index].SectionName != nil {
+		listenerName := *parentRefs[index].SectionName
 		i := akogatewayapilib.FindListenerByName(string(listenerName), gateway.Spec.Listeners)
 		if i == -1 {
 			// listener is not present in gateway
@@ -366,7 +862,7 @@ func validateParentReference(key string, httpRoute *gatewayv1.HTTPRoute, httpRou
 			err := fmt.Errorf("Invalid listener name provided")
 			defaultCondition.
 				Message(err.Error()).
-				SetIn(&httpRouteStatus.Parents[index].Conditions)
+				SetIn(&routeStatus.Parents[index].Conditions)
 			return err
 		}
 		listenersForRoute = append(listenersForRoute, gateway.Spec.Listeners[i])
@@ -376,41 +872,43 @@ func validateParentReference(key string, httpRoute *gatewayv1.HTTPRoute, httpRou
 
 	var listenersMatchedToRoute []gatewayv1.Listener
 	for _, listenerObj := range listenersForRoute {
-		// check from store
-		hostInListener := listenerObj.Hostname
-
-		if hostInListener == nil {
-			utils.AviLog.Errorf("key: %s, msg: no hostname found in parent", key)
+		// the route can attach only to the listeners supporting its kind
+		if !akogatewayapilib.IsRouteKindSupported(string(listenerObj.Protocol), routeKind) {
+			utils.AviLog.Warnf("key: %s, msg: listener %s of Gateway %s does not support %s", key, listenerObj.Name, gateway.Name, routeKind)
 			continue
 		}
-		// replace the wildcard character with a regex
-		replacedHostname := strings.Replace(string(*hostInListener), "*", "([a-zA-Z0-9-]{1,})", 1)
-		// create the expression for pattern matching
-		pattern := fmt.Sprintf("^%s$", replacedHostname)
-		expr, err := regexp.Compile(pattern)
-		if err != nil {
-			utils.AviLog.Warnf("key: %s, msg: unable to match the hostname with listener hostname. err: %s", key, err)
+		// TCPRoute and UDPRoute attach to the listener irrespective of the hostname
+		if !akogatewayapilib.RouteHasHostnames(routeKind) {
+			listenersMatchedToRoute = append(listenersMatchedToRoute, listenerObj)
 			continue
 		}
-		var matched bool
-		for _, host := range httpRoute.Spec.Hostnames {
-			matched = matched || expr.MatchString(string(host))
+		// a route without hostnames inherits the hostname of the listener
+		hostInListener := akogatewayapilib.GetListenerHostname(listenerObj)
+		_, matched := akogatewayapilib.GetHostnameIntersection(hostInListener, "")
+		if len(hostnames) > 0 {
+			matched = false
+			for _, host := range hostnames {
+				if _, ok := akogatewayapilib.GetHostnameIntersection(hostInListener, string(host)); ok {
+					matched = true
+					break
+				}
+			}
 		}
 		if !matched {
-			utils.AviLog.Warnf("key: %s, msg: Gateway object %s don't have any listeners that matches the hostnames in HTTPRoute %s", key, gateway.Name, httpRoute.Name)
+			utils.AviLog.Warnf("key: %s, msg: Gateway object %s don't have any listeners that matches the hostnames in %s %s", key, gateway.Name, routeKind, route.GetName())
 			continue
 		}
 		listenersMatchedToRoute = append(listenersMatchedToRoute, listenerObj)
 	}
 	if len(listenersMatchedToRoute) == 0 {
-		err := fmt.Errorf("Hostname in Gateway Listener doesn't match with any of the hostnames in HTTPRoute")
+		err := fmt.Errorf("Hostname in Gateway Listener doesn't match with any of the hostnames in %s", routeKind)
 		defaultCondition.
 			Message(err.Error()).
-			SetIn(&httpRouteStatus.Parents[index].Conditions)
+			SetIn(&routeStatus.Parents[index].Conditions)
 		found, hosts := akogatewayapiobjects.GatewayApiLister().GetGatewayRouteToHostname(gwNsName)
 		if found {
-			utils.AviLog.Warnf("key: %s, msg: Hostname in Gateway Listener doesn't match with any of the hostnames in HTTPRoute", key)
-			utils.AviLog.Debugf("key: %s, msg: %d hosts mapped to the route %s/%s/%s", key, len(hosts), "HTTPRoute", httpRoute.Namespace, httpRoute.Name)
+			utils.AviLog.Warnf("key: %s, msg: Hostname in Gateway Listener doesn't match with any of the hostnames in %s", key, routeKind)
+			utils.AviLog.Debugf("key: %s, msg: %d hosts mapped to the route %s/%s/%s", key, len(hosts), routeKind, route.GetNamespace(), route.GetName())
 			return nil
 		}
 		return err
@@ -426,7 +924,7 @@ func validateParentReference(key string, httpRoute *gatewayv1.HTTPRoute, httpRou
 			err := fmt.Errorf("Couldn't find the listener %s in the Gateway status", listenerName)
 			defaultCondition.
 				Message(err.Error()).
-				SetIn(&httpRouteStatus.Parents[index].Conditions)
+				SetIn(&routeStatus.Parents[index].Conditions)
 			return err
 		}
 
@@ -438,7 +936,7 @@ func validateParentReference(key string, httpRoute *gatewayv1.HTTPRoute, httpRou
 		Reason(string(gatewayv1.GatewayReasonAccepted)).
 		Status(metav1.ConditionTrue).
 		Message("Parent reference is valid").
-		SetIn(&httpRouteStatus.Parents[index].Conditions)
-	utils.AviLog.Infof("key: %s, msg: Parent Reference %s of HTTPRoute object %s is valid", key, name, httpRoute.Name)
+		SetIn(&routeStatus.Parents[index].Conditions)
+	utils.AviLog.Infof("key: %s, msg: Parent Reference %s of %s object %s is valid", key, name, routeKind, route.GetName())
 	return nil
 }
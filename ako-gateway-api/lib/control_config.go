@@ -22,6 +22,8 @@ import (
 	"k8s.io/client-go/kubernetes"
 	gatewayclientset "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
 	gatewayinformerv1 "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions/apis/v1"
+	gatewayinformerv1alpha2 "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions/apis/v1alpha2"
+	gatewayinformerv1beta1 "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions/apis/v1beta1"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
@@ -31,6 +33,16 @@ type GatewayAPIInformers struct {
 	GatewayInformer      gatewayinformerv1.GatewayInformer
 	GatewayClassInformer gatewayinformerv1.GatewayClassInformer
 	HTTPRouteInformer    gatewayinformerv1.HTTPRouteInformer
+	// ReferenceGrantInformer watches the grants which permit the cross-namespace
+	// references to the backends and the certificates.
+	ReferenceGrantInformer gatewayinformerv1beta1.ReferenceGrantInformer
+	// TLSRouteInformer, TCPRouteInformer, UDPRouteInformer and GRPCRouteInformer
+	// are set only when the respective CRD, which is part of the experimental
+	// channel of the Gateway API, is installed in the cluster.
+	TLSRouteInformer  gatewayinformerv1alpha2.TLSRouteInformer
+	TCPRouteInformer  gatewayinformerv1alpha2.TCPRouteInformer
+	UDPRouteInformer  gatewayinformerv1alpha2.UDPRouteInformer
+	GRPCRouteInformer gatewayinformerv1alpha2.GRPCRouteInformer
 }
 
 // akoControlConfig struct is intended to store all AKO related global
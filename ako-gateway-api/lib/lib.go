@@ -20,7 +20,9 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayclientset "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
 
+	akogatewayapiobjects "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/objects"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
@@ -28,14 +30,19 @@ import (
 
 func InformersToRegister(kclient *kubernetes.Clientset) ([]string, error) {
 	// Initialize the following informers in all AKO deployments. Provide AKO the ability to watch over
-	// Services, Endpoints, Secrets, ConfigMaps.
+	// Services, Endpoints/EndpointSlices, Secrets, ConfigMaps.
 	allInformers := []string{
 		utils.ServiceInformer,
-		utils.EndpointInformer,
 		utils.SecretInformer,
 		utils.ConfigMapInformer,
 	}
 
+	if lib.IsEndpointSliceEnabled() {
+		allInformers = append(allInformers, utils.EndpointSlicesInformer)
+	} else {
+		allInformers = append(allInformers, utils.EndpointInformer)
+	}
+
 	return allInformers, nil
 }
 
@@ -46,6 +53,24 @@ func GetGatewayParentName(namespace, gwName string) string {
 	return lib.GetNamePrefix() + namespace + "-" + gwName + "-EVH"
 }
 
+// L4 vs name format - ako-gw-clustername--gatewayNs-gatewayName-L4
+func GetGatewayL4ParentName(namespace, gwName string) string {
+	// Gateways with TLS passthrough listeners are realised as a L4 VS which
+	// selects the pool group using the SNI of the client hello.
+	return lib.GetNamePrefix() + namespace + "-" + gwName + "-L4"
+}
+
+// passthrough pg name format - ako-gw-clustername--gatewayNs-gatewayName-hostname
+func GetPassthroughPGName(gwNamespace, gwName, hostname string) string {
+	return GetPassthroughPGPrefix(gwNamespace, gwName) + hostname
+}
+
+// GetPassthroughPGPrefix returns the prefix of the pool groups of a passthrough
+// gateway, the datascript appends the SNI to it to select the pool group.
+func GetPassthroughPGPrefix(gwNamespace, gwName string) string {
+	return lib.GetNamePrefix() + gwNamespace + "-" + gwName + "-"
+}
+
 // child vs name format - ako-gw-clustername--encoded value of ako-gw-clustername--parentNs-parentName-routeNs-routeName-encodedMatch
 func GetChildName(parentNs, parentName, routeNs, routeName, matchName string) string {
 	name := parentNs + "-" + parentName + "-" + routeNs + "-" + routeName + "-" + utils.Stringify(utils.Hash(matchName))
@@ -62,6 +87,71 @@ func GetPoolGroupName(parentNs, parentName, routeNs, routeName, matchName string
 	return lib.Encode(name, lib.PG)
 }
 
+// GetTrafficCloneProfileName returns the name of the traffic clone profile which mirrors
+// the requests of a child VS, one profile is created per child VS.
+func GetTrafficCloneProfileName(parentNs, parentName, routeNs, routeName, matchName string) string {
+	name := parentNs + "-" + parentName + "-" + routeNs + "-" + routeName + "-" + utils.Stringify(utils.Hash(matchName))
+	return lib.Encode(name, lib.TrafficCloneProfile)
+}
+
+// GetL4PoolGroupName returns the name of the pool group of a TCP or UDP listener. The name does not
+// depend on the route attached to the listener, so that the L4 policy rule of the listener does not change.
+func GetL4PoolGroupName(gwNamespace, gwName, listenerName string) string {
+	name := gwNamespace + "-" + gwName + "-" + listenerName
+	return lib.Encode(name, lib.PG)
+}
+
+// IsL4Protocol returns true for the listener protocols which are not handled
+// by the EVH parent VS of the gateway.
+func IsL4Protocol(proto string) bool {
+	switch gatewayv1.ProtocolType(proto) {
+	case gatewayv1.TLSProtocolType, gatewayv1.TCPProtocolType, gatewayv1.UDPProtocolType:
+		return true
+	}
+	return false
+}
+
+// IsL4Gateway returns true if the gateway has listeners which are translated to
+// a L4 VS. A gateway can not mix such listeners with HTTP and HTTPS listeners,
+// which is validated at ingestion.
+func IsL4Gateway(gateway *gatewayv1.Gateway) bool {
+	for _, listener := range gateway.Spec.Listeners {
+		if IsL4Protocol(string(listener.Protocol)) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPassthroughGateway returns true if the gateway has TLS passthrough listeners.
+// The pool groups of such gateways are selected by a datascript, using the SNI,
+// hence TCP and UDP listeners can not be combined with these listeners.
+func IsPassthroughGateway(gateway *gatewayv1.Gateway) bool {
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.Protocol == gatewayv1.TLSProtocolType {
+			return true
+		}
+	}
+	return false
+}
+
+// IsGatewayAPIResourceInstalled checks if the resource is served by the
+// kube-apiserver. The CRDs of the experimental channel are optional and an
+// informer for a missing CRD would never sync.
+func IsGatewayAPIResourceInstalled(cs gatewayclientset.Interface, groupVersion, resource string) bool {
+	resourceList, err := cs.Discovery().ServerResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		utils.AviLog.Infof("Unable to get the resources for %s, err: %v", groupVersion, err)
+		return false
+	}
+	for _, apiResource := range resourceList.APIResources {
+		if apiResource.Name == resource {
+			return true
+		}
+	}
+	return false
+}
+
 func CheckGatewayClassController(controllerName string) bool {
 	return controllerName == lib.AviIngressController
 }
@@ -141,15 +231,92 @@ func VerifyHostnameSubdomainMatch(hostname string) bool {
 	return false
 }
 
-func ProtocolToRoute(proto string) string {
-	innerMap := map[string]string{
-		"HTTP":  lib.HTTPRoute,
-		"HTTPS": lib.HTTPRoute,
-		"TCP":   lib.TCPRoute,
-		"TLS":   lib.TLSRoute,
-		"UDP":   lib.UDPRoute,
+// GetSupportedKinds returns the route kinds which can be attached to a listener of the protocol.
+// GRPCRoute is supported only when its CRD is installed in the cluster.
+func GetSupportedKinds(proto gatewayv1.ProtocolType) []gatewayv1.RouteGroupKind {
+	var kinds []gatewayv1.RouteGroupKind
+	for _, kind := range SupportedKinds[proto] {
+		if string(kind.Kind) == lib.GRPCRoute && AKOControlConfig().GatewayApiInformers().GRPCRouteInformer == nil {
+			continue
+		}
+		kinds = append(kinds, kind)
+	}
+	return kinds
+}
+
+// IsRouteKindSupported returns true if the route kind can be attached to a listener of the protocol.
+func IsRouteKindSupported(proto, routeKind string) bool {
+	for _, kind := range GetSupportedKinds(gatewayv1.ProtocolType(proto)) {
+		if string(kind.Kind) == routeKind {
+			return true
+		}
+	}
+	return false
+}
+
+// RouteHasHostnames returns false for the route kinds, which do not match the
+// traffic on hostnames. Such routes attach to a listener irrespective of its hostname.
+func RouteHasHostnames(routeKind string) bool {
+	return routeKind != lib.TCPRoute && routeKind != lib.UDPRoute
+}
+
+// IsBackendRefAllowed returns true when a route of kind routeKind in routeNamespace can reference
+// the Service in the backendRef, as per the ReferenceGrants in the namespace of the Service.
+func IsBackendRefAllowed(routeKind, routeNamespace string, backendRef gatewayv1.BackendObjectReference) bool {
+	if backendRef.Namespace == nil || string(*backendRef.Namespace) == routeNamespace {
+		return true
+	}
+	from := akogatewayapiobjects.ReferenceGrantFrom{Group: gatewayv1.GroupName, Kind: routeKind, Namespace: routeNamespace}
+	to := akogatewayapiobjects.ReferenceGrantTo{Kind: utils.Service, Name: string(backendRef.Name)}
+	return akogatewayapiobjects.GatewayApiLister().IsReferenceAllowed(from, string(*backendRef.Namespace), to)
+}
+
+// IsCertificateRefAllowed returns true when a Gateway in gatewayNamespace can reference the Secret
+// in the certRef, as per the ReferenceGrants in the namespace of the Secret.
+func IsCertificateRefAllowed(gatewayNamespace string, certRef gatewayv1.SecretObjectReference) bool {
+	if certRef.Namespace == nil || string(*certRef.Namespace) == gatewayNamespace {
+		return true
+	}
+	from := akogatewayapiobjects.ReferenceGrantFrom{Group: gatewayv1.GroupName, Kind: lib.Gateway, Namespace: gatewayNamespace}
+	to := akogatewayapiobjects.ReferenceGrantTo{Kind: utils.Secret, Name: string(certRef.Name)}
+	return akogatewayapiobjects.GatewayApiLister().IsReferenceAllowed(from, string(*certRef.Namespace), to)
+}
+
+// GetListenerHostname returns the hostname of the listener, empty when the listener matches all the hostnames.
+func GetListenerHostname(listener gatewayv1.Listener) string {
+	if listener.Hostname == nil {
+		return ""
 	}
+	return string(*listener.Hostname)
+}
 
-	return innerMap[proto]
+// GetListenerCertHostname returns the hostname used in the names of the certificates of the listener.
+// The listeners without hostname use the gateway and the listener name, so that the names are unique.
+func GetListenerCertHostname(gateway *gatewayv1.Gateway, listener gatewayv1.Listener) string {
+	if hostname := GetListenerHostname(listener); hostname != "" {
+		return hostname
+	}
+	return gateway.Namespace + "-" + gateway.Name + "-" + string(listener.Name)
+}
 
+// GetHostnameIntersection returns the hostname matched by both the listener hostname and the route hostname,
+// as per the hostname matching rules of Gateway API. An empty listener hostname matches all the route hostnames,
+// and a route without hostname inherits the hostname of the listener. A wildcard hostname matches all the
+// hostnames with the same suffix, the more specific hostname is the intersection of the two.
+func GetHostnameIntersection(listenerHostname, routeHostname string) (string, bool) {
+	if listenerHostname == "" {
+		return routeHostname, routeHostname != ""
+	}
+	if routeHostname == "" || listenerHostname == routeHostname {
+		return listenerHostname, true
+	}
+	listenerWildcard := strings.HasPrefix(listenerHostname, "*.")
+	routeWildcard := strings.HasPrefix(routeHostname, "*.")
+	if listenerWildcard && strings.HasSuffix(strings.TrimPrefix(routeHostname, "*"), listenerHostname[1:]) {
+		return routeHostname, true
+	}
+	if routeWildcard && strings.HasSuffix(strings.TrimPrefix(listenerHostname, "*"), routeHostname[1:]) {
+		return listenerHostname, true
+	}
+	return "", false
 }
@@ -15,21 +15,210 @@
 package nodes
 
 import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/vmware/alb-sdk/go/models"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	akogatewayapiobjects "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 )
 
-func (o *AviObjectGraph) ProcessL4Routes(key string, routeModel RouteModel, parentNsName string) {
-	for _, rule := range routeModel.ParseRouteRules().Rules {
-		parentNode := o.GetAviEvhVS()
+// BuildGatewayL4Vs builds the L4 VS for a gateway with TCP and UDP listeners.
+// The traffic of each listener is sent to a pool group, selected by the L4 policyset of the VS.
+func (o *AviObjectGraph) BuildGatewayL4Vs(gateway *gatewayv1.Gateway, key string) {
+	o.Lock.Lock()
+	defer o.Lock.Unlock()
+
+	vsNode := o.BuildGatewayL4Parent(gateway, key)
+
+	o.AddModelNode(vsNode)
+	utils.AviLog.Infof("key: %s, msg: checksum for AVI VS object %v", key, vsNode.GetCheckSum())
+}
+
+func (o *AviObjectGraph) BuildGatewayL4Parent(gateway *gatewayv1.Gateway, key string) *nodes.AviVsNode {
+	vsName := akogatewayapilib.GetGatewayL4ParentName(gateway.Namespace, gateway.Name)
+	vsNode := &nodes.AviVsNode{
+		Name:               vsName,
+		Tenant:             lib.GetTenant(),
+		ServiceEngineGroup: lib.GetSEGName(),
+		ApplicationProfile: utils.DEFAULT_L4_APP_PROFILE,
+		SharedVS:           true,
+		VrfContext:         lib.GetVrf(),
+		ServiceMetadata: lib.ServiceMetadataObj{
+			Gateway: gateway.Namespace + "/" + gateway.Name,
+		},
+	}
+
+	var isTCP, isUDP bool
+	for _, listener := range gateway.Spec.Listeners {
+		protocol := string(listener.Protocol)
+		if protocol == utils.TCP {
+			isTCP = true
+		} else if protocol == utils.UDP {
+			isUDP = true
+		}
+		pp := nodes.AviPortHostProtocol{Port: int32(listener.Port), Protocol: protocol}
+		if !utils.HasElem(vsNode.PortProto, pp) {
+			vsNode.PortProto = append(vsNode.PortProto, pp)
+		}
+	}
+	vsNode.NetworkProfile = nodes.GetNetworkProfile(false, isTCP, isUDP)
+
+	vsvipNode := BuildVsVipNodeForGateway(gateway, vsNode.Name)
+	vsNode.VSVIPRefs = []*nodes.AviVSVIPNode{vsvipNode}
+
+	return vsNode
+}
+
+// ProcessL4Routes rebuilds the pools, pool groups and the L4 policyset of the L4 VS of the gateway
+// from all the routes attached to the gateway.
+func (o *AviObjectGraph) ProcessL4Routes(key, parentNsName string) {
+	vsNodes := o.GetAviVS()
+	if len(vsNodes) == 0 {
+		return
+	}
+	if len(vsNodes[0].HTTPDSrefs) > 0 {
+		o.ProcessPassthroughRoutes(key, parentNsName)
+		return
+	}
+	o.BuildL4PolicySet(key, vsNodes[0], parentNsName)
+}
+
+// BuildL4PolicySet creates a pool group per TCP or UDP listener of the gateway and a L4 policyset rule
+// selecting it for the port of the listener. When multiple routes are attached to the same listener,
+// the oldest route wins.
+func (o *AviObjectGraph) BuildL4PolicySet(key string, vsNode *nodes.AviVsNode, parentNsName string) {
+	parentNs, _, parentName := lib.ExtractTypeNameNamespace(parentNsName)
 
-		// create L4 policyset per rule
-		o.BuildL4PolicySet(key, parentNode[0], routeModel, rule)
+	//reset pool, poolgroup and l4 policy references
+	vsNode.PoolRefs = nil
+	vsNode.PoolGroupRefs = nil
+	vsNode.L4PolicyRefs = nil
+
+	var portPoolSet []nodes.AviHostPathPortPoolPG
+	listenerToRoute := make(map[string]string)
+	for _, routeModel := range getL4Routes(key, parentNsName) {
+		routeTypeNsName := routeModel.GetType() + "/" + routeModel.GetNamespace() + "/" + routeModel.GetName()
+		routeConfig := routeModel.ParseRouteRules()
+		for _, listener := range akogatewayapiobjects.GatewayApiLister().GetRouteToGatewayListener(routeTypeNsName) {
+			if listener.Gateway != parentNsName || !akogatewayapilib.IsRouteKindSupported(listener.Protocol, routeModel.GetType()) {
+				continue
+			}
+			if attachedRoute, ok := listenerToRoute[listener.Name]; ok {
+				if attachedRoute != routeTypeNsName {
+					utils.AviLog.Warnf("key: %s, msg: listener %s is already used by the route %s, skipping it for route %s", key, listener.Name, attachedRoute, routeTypeNsName)
+				}
+				continue
+			}
+			listenerToRoute[listener.Name] = routeTypeNsName
+
+			PG := &nodes.AviPoolGroupNode{
+				Name:   akogatewayapilib.GetL4PoolGroupName(parentNs, parentName, listener.Name),
+				Tenant: lib.GetTenant(),
+			}
+			for _, rule := range routeConfig.Rules {
+				for _, backend := range rule.Backends {
+					poolNode := o.BuildL4Pool(key, parentNsName, routeModel, listener.Name, listener.Protocol, backend)
+					if poolNode == nil {
+						continue
+					}
+					vsNode.PoolRefs = append(vsNode.PoolRefs, poolNode)
+					poolRef := fmt.Sprintf("/api/pool?name=%s", poolNode.Name)
+					ratio := uint32(backend.Weight)
+					PG.Members = append(PG.Members, &models.PoolGroupMember{PoolRef: &poolRef, Ratio: &ratio})
+				}
+			}
+			vsNode.PoolGroupRefs = append(vsNode.PoolGroupRefs, PG)
+			portPoolSet = append(portPoolSet, nodes.AviHostPathPortPoolPG{
+				Port:      uint32(listener.Port),
+				PoolGroup: fmt.Sprintf("/api/poolgroup?name=%s", PG.Name),
+				Protocol:  listener.Protocol,
+			})
+		}
+	}
 
-		// Logic to override the app profile per port also must be taken care here.
+	if len(portPoolSet) > 0 {
+		l4policyNode := &nodes.AviL4PolicyNode{
+			Name:     vsNode.Name,
+			Tenant:   lib.GetTenant(),
+			PortPool: portPoolSet,
+		}
+		vsNode.L4PolicyRefs = []*nodes.AviL4PolicyNode{l4policyNode}
 	}
+	utils.AviLog.Infof("key: %s, msg: processed %d listeners for the L4 vs %s", key, len(listenerToRoute), vsNode.Name)
 }
 
-func (o *AviObjectGraph) BuildL4PolicySet(key string, vsNode *nodes.AviEvhVsNode, routeModel RouteModel, rule *Rule) {
+// getL4Routes returns the TCPRoutes and UDPRoutes attached to the gateway, sorted by creation timestamp and name.
+func getL4Routes(key, parentNsName string) []*l4Route {
+	var l4Routes []*l4Route
+	found, routeTypeNsNameList := akogatewayapiobjects.GatewayApiLister().GetGatewayToRoute(parentNsName)
+	if !found {
+		return l4Routes
+	}
+	for _, routeTypeNsName := range routeTypeNsNameList {
+		routeType, namespace, name := lib.ExtractTypeNameNamespace(routeTypeNsName)
+		if routeType != lib.TCPRoute && routeType != lib.UDPRoute {
+			continue
+		}
+		routeModel, err := NewRouteModel(key, routeType, name, namespace)
+		if err != nil {
+			utils.AviLog.Debugf("key: %s, msg: unable to get the %s %s/%s, err: %v", key, routeType, namespace, name, err)
+			continue
+		}
+		l4Routes = append(l4Routes, routeModel.(*l4Route))
+	}
+	sort.Slice(l4Routes, func(i, j int) bool {
+		if l4Routes[i].creationTimestamp.Equal(&l4Routes[j].creationTimestamp) {
+			return l4Routes[i].routeType+"/"+l4Routes[i].namespace+"/"+l4Routes[i].name < l4Routes[j].routeType+"/"+l4Routes[j].namespace+"/"+l4Routes[j].name
+		}
+		return l4Routes[i].creationTimestamp.Before(&l4Routes[j].creationTimestamp)
+	})
+	return l4Routes
+}
 
-	// TODO: add the l4policset code here
+// BuildL4Pool builds the pool of a backend of a TLS, TCP or UDP route. The matchName is the hostname
+// for TLSRoutes and the listener name for TCPRoutes and UDPRoutes.
+func (o *AviObjectGraph) BuildL4Pool(key, parentNsName string, routeModel RouteModel, matchName, protocol string, backend *Backend) *nodes.AviPoolNode {
+	parentNs, _, parentName := lib.ExtractTypeNameNamespace(parentNsName)
+	svcObj, err := utils.GetInformers().ServiceInformer.Lister().Services(backend.Namespace).Get(backend.Name)
+	if err != nil {
+		utils.AviLog.Debugf("key: %s, msg: there was an error in retrieving the service %s/%s", key, backend.Namespace, backend.Name)
+		return nil
+	}
+	poolNode := &nodes.AviPoolNode{
+		Name: akogatewayapilib.GetPoolName(parentNs, parentName,
+			routeModel.GetNamespace(), routeModel.GetName(), matchName,
+			backend.Namespace, backend.Name, strconv.Itoa(int(backend.Port))),
+		Tenant:     lib.GetTenant(),
+		Protocol:   protocol,
+		PortName:   akogatewayapilib.FindPortName(backend.Name, backend.Namespace, backend.Port, key),
+		TargetPort: akogatewayapilib.FindTargetPort(backend.Name, backend.Namespace, backend.Port, key),
+		Port:       backend.Port,
+		ServiceMetadata: lib.ServiceMetadataObj{
+			NamespaceServiceName: []string{backend.Namespace + "/" + backend.Name},
+		},
+		VrfContext: lib.GetVrf(),
+	}
+	poolNode.NetworkPlacementSettings = lib.GetNodeNetworkMap()
+	switch lib.GetServiceType() {
+	case lib.NodePortLocal:
+		if servers := nodes.PopulateServersForNPL(poolNode, svcObj.Namespace, svcObj.Name, false, key); servers != nil {
+			poolNode.Servers = servers
+		}
+	case lib.NodePort:
+		if servers := nodes.PopulateServersForNodePort(poolNode, svcObj.Namespace, svcObj.Name, false, key); servers != nil {
+			poolNode.Servers = servers
+		}
+	default:
+		if servers := nodes.PopulateServers(poolNode, svcObj.Namespace, svcObj.Name, false, key); servers != nil {
+			poolNode.Servers = servers
+		}
+	}
+	return poolNode
 }
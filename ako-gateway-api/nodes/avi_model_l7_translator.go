@@ -17,9 +17,11 @@ package nodes
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/vmware/alb-sdk/go/models"
 	"google.golang.org/protobuf/proto"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/objects"
@@ -74,12 +76,16 @@ func (o *AviObjectGraph) BuildChildVS(key string, routeModel RouteModel, parentN
 		Host:        hosts,
 	}
 	for _, host := range hosts {
+		// the wildcard hostnames are matched by the child VS, but can not be registered in DNS
+		if strings.HasPrefix(host, "*") {
+			continue
+		}
 		if !utils.HasElem(parentNode[0].VSVIPRefs[0].FQDNs, host) {
 			parentNode[0].VSVIPRefs[0].FQDNs = append(parentNode[0].VSVIPRefs[0].FQDNs, host)
 		}
 	}
 
-	routeTypeNsName := lib.HTTPRoute + "/" + routeModel.GetNamespace() + "/" + routeModel.GetName()
+	routeTypeNsName := routeModel.GetType() + "/" + routeModel.GetNamespace() + "/" + routeModel.GetName()
 	// create vhmatch from the match
 	o.BuildVHMatch(key, parentNsName, routeTypeNsName, childNode, rule, hosts)
 
@@ -95,6 +101,9 @@ func (o *AviObjectGraph) BuildChildVS(key string, routeModel RouteModel, parentN
 	// create the httppolicyset if the filter is present
 	o.BuildHTTPPolicySet(key, childNode, routeModel, rule)
 
+	// create the traffic clone profile if the request mirror filter is present
+	o.BuildTrafficCloneProfile(key, parentNsName, childNode, routeModel, rule)
+
 	foundEvhModel := nodes.FindAndReplaceEvhInModel(childNode, parentNode, key)
 	if !foundEvhModel {
 		parentNode[0].EvhNodes = append(parentNode[0].EvhNodes, childNode)
@@ -110,7 +119,7 @@ func (o *AviObjectGraph) BuildPGPool(key, parentNsName string, childVsNode *node
 	childVsNode.DefaultPoolGroup = ""
 	childVsNode.PoolRefs = nil
 	// create the PG from backends
-	routeTypeNsName := lib.HTTPRoute + "/" + routeModel.GetNamespace() + "/" + routeModel.GetName()
+	routeTypeNsName := routeModel.GetType() + "/" + routeModel.GetNamespace() + "/" + routeModel.GetName()
 	parentNs, _, parentName := lib.ExtractTypeNameNamespace(parentNsName)
 	allListeners := akogatewayapiobjects.GatewayApiLister().GetRouteToGatewayListener(routeTypeNsName)
 	listeners := []akogatewayapiobjects.GatewayListenerStore{}
@@ -151,6 +160,10 @@ func (o *AviObjectGraph) BuildPGPool(key, parentNsName string, childVsNode *node
 			},
 			VrfContext: lib.GetVrf(),
 		}
+		// gRPC is carried over HTTP/2 end to end.
+		if routeModel.GetType() == lib.GRPCRoute {
+			poolNode.EnableHttp2 = true
+		}
 		poolNode.NetworkPlacementSettings = lib.GetNodeNetworkMap()
 		serviceType := lib.GetServiceType()
 		if serviceType == lib.NodePort {
@@ -196,50 +209,73 @@ func (o *AviObjectGraph) BuildVHMatch(key string, parentNsName string, routeType
 		}
 
 		for i, match := range rule.Matches {
-			ruleName := fmt.Sprintf("rule-%d", i)
-			rule := &models.VHMatchRule{
-				Name:    &ruleName,
-				Matches: &models.MatchTarget{},
+			// query param match, the validator allows a single query param in a match
+			queryMatches := []*models.QueryMatch{nil}
+			for _, queryParamMatch := range match.QueryParamMatch {
+				queryMatches = getExactQueryParamMatches(queryParamMatch.Name, queryParamMatch.Value)
 			}
 
-			// path match
-			if match.PathMatch != nil {
-				rule.Matches.Path = &models.PathMatch{
-					MatchCase: proto.String("SENSITIVE"),
-					MatchStr:  []string{match.PathMatch.Path},
+			for j, queryMatch := range queryMatches {
+				ruleName := fmt.Sprintf("rule-%d", i)
+				if len(queryMatches) > 1 {
+					ruleName = fmt.Sprintf("rule-%d-%d", i, j)
 				}
-				if match.PathMatch.Type == "Exact" {
-					rule.Matches.Path.MatchCriteria = proto.String("EQUALS")
-				} else if match.PathMatch.Type == "PathPrefix" {
-					rule.Matches.Path.MatchCriteria = proto.String("BEGINS_WITH")
+				rule := &models.VHMatchRule{
+					Name:    &ruleName,
+					Matches: &models.MatchTarget{},
 				}
-			}
 
-			// header match
-			rule.Matches.Hdrs = make([]*models.HdrMatch, 0, len(match.HeaderMatch))
-			for _, headerMatch := range match.HeaderMatch {
-				headerName := headerMatch.Name
-				hdrMatch := &models.HdrMatch{
-					MatchCase:     proto.String("SENSITIVE"),
-					MatchCriteria: proto.String("HDR_EQUALS"),
-					Hdr:           &headerName,
-					Value:         []string{headerMatch.Value},
+				// path match
+				if match.PathMatch != nil {
+					rule.Matches.Path = &models.PathMatch{
+						MatchCase: proto.String("SENSITIVE"),
+						MatchStr:  []string{match.PathMatch.Path},
+					}
+					if match.PathMatch.Type == "Exact" {
+						rule.Matches.Path.MatchCriteria = proto.String("EQUALS")
+					} else if match.PathMatch.Type == "PathPrefix" {
+						rule.Matches.Path.MatchCriteria = proto.String("BEGINS_WITH")
+					} else if match.PathMatch.Type == "PathSuffix" {
+						rule.Matches.Path.MatchCriteria = proto.String("ENDS_WITH")
+					}
 				}
-				rule.Matches.Hdrs = append(rule.Matches.Hdrs, hdrMatch)
-			}
 
-			//port match from listener
-			matchCriteria := "IS_IN"
-			rule.Matches.VsPort = &models.PortMatch{
-				MatchCriteria: &matchCriteria,
-			}
-			for _, listener := range listeners {
-				rule.Matches.VsPort.Ports = append(rule.Matches.VsPort.Ports, int64(listener.Port))
-			}
-			//TODO correctly add protocol
-			//rule.Matches.Protocol.Protocols = &listeners[0].Protocol
+				// header match
+				rule.Matches.Hdrs = make([]*models.HdrMatch, 0, len(match.HeaderMatch))
+				for _, headerMatch := range match.HeaderMatch {
+					headerName := headerMatch.Name
+					hdrMatch := &models.HdrMatch{
+						MatchCase:     proto.String("SENSITIVE"),
+						MatchCriteria: proto.String("HDR_EQUALS"),
+						Hdr:           &headerName,
+						Value:         []string{headerMatch.Value},
+					}
+					rule.Matches.Hdrs = append(rule.Matches.Hdrs, hdrMatch)
+				}
 
-			vhMatch.Rules = append(vhMatch.Rules, rule)
+				rule.Matches.Query = queryMatch
+
+				// method match
+				if match.Method != "" {
+					rule.Matches.Method = &models.MethodMatch{
+						MatchCriteria: proto.String("IS_IN"),
+						Methods:       []string{"HTTP_METHOD_" + match.Method},
+					}
+				}
+
+				//port match from listener
+				matchCriteria := "IS_IN"
+				rule.Matches.VsPort = &models.PortMatch{
+					MatchCriteria: &matchCriteria,
+				}
+				for _, listener := range listeners {
+					rule.Matches.VsPort.Ports = append(rule.Matches.VsPort.Ports, int64(listener.Port))
+				}
+				//TODO correctly add protocol
+				//rule.Matches.Protocol.Protocols = &listeners[0].Protocol
+
+				vhMatch.Rules = append(vhMatch.Rules, rule)
+			}
 		}
 		vhMatches = append(vhMatches, vhMatch)
 	}
@@ -248,6 +284,56 @@ func (o *AviObjectGraph) BuildVHMatch(key string, parentNsName string, routeType
 	utils.AviLog.Infof("key: %s, msg: Attached match criteria to vs %s", key, vsNode.Name)
 }
 
+// getExactQueryParamMatches returns the query matches of an exact query param match. The query
+// string of the request carries the other query params as well, hence the param is matched on its
+// boundaries, as the whole query string or at its beginning, end or middle. The rules carrying these
+// query matches are alternatives to each other.
+func getExactQueryParamMatches(name, value string) []*models.QueryMatch {
+	param := name + "=" + value
+	boundaryMatches := [][2]string{
+		{"QUERY_MATCH_EQUALS", param},
+		{"QUERY_MATCH_BEGINS_WITH", param + "&"},
+		{"QUERY_MATCH_ENDS_WITH", "&" + param},
+		{"QUERY_MATCH_CONTAINS", "&" + param + "&"},
+	}
+	queryMatches := make([]*models.QueryMatch, 0, len(boundaryMatches))
+	for _, boundaryMatch := range boundaryMatches {
+		queryMatches = append(queryMatches, &models.QueryMatch{
+			MatchCase:     proto.String("SENSITIVE"),
+			MatchCriteria: proto.String(boundaryMatch[0]),
+			MatchStr:      []string{boundaryMatch[1]},
+		})
+	}
+	return queryMatches
+}
+
+// ProcessHTTP2OnParent enables HTTP/2 on the parent VS ports of the listeners
+// which have a GRPCRoute attached and disables it on the rest of the ports.
+func (o *AviObjectGraph) ProcessHTTP2OnParent(key, parentNsName string) {
+	parentNode := o.GetAviEvhVS()
+	if len(parentNode) == 0 {
+		return
+	}
+	http2Ports := make(map[int32]struct{})
+	_, routeTypeNsNameList := akogatewayapiobjects.GatewayApiLister().GetGatewayToRoute(parentNsName)
+	for _, routeTypeNsName := range routeTypeNsNameList {
+		routeType, _, _ := lib.ExtractTypeNameNamespace(routeTypeNsName)
+		if routeType != lib.GRPCRoute {
+			continue
+		}
+		for _, listener := range akogatewayapiobjects.GatewayApiLister().GetRouteToGatewayListener(routeTypeNsName) {
+			if listener.Gateway == parentNsName {
+				http2Ports[listener.Port] = struct{}{}
+			}
+		}
+	}
+	for i := range parentNode[0].PortProto {
+		_, enableHTTP2 := http2Ports[parentNode[0].PortProto[i].Port]
+		parentNode[0].PortProto[i].EnableHTTP2 = enableHTTP2
+	}
+	utils.AviLog.Debugf("key: %s, msg: HTTP/2 enabled on ports %v of the parent vs %s", key, utils.Stringify(http2Ports), parentNode[0].Name)
+}
+
 func (o *AviObjectGraph) BuildHTTPPolicySet(key string, vsNode *nodes.AviEvhVsNode, routeModel RouteModel, rule *Rule) {
 
 	if len(rule.Filters) == 0 {
@@ -258,21 +344,26 @@ func (o *AviObjectGraph) BuildHTTPPolicySet(key string, vsNode *nodes.AviEvhVsNo
 	policy := &nodes.AviHttpPolicySetNode{Name: vsNode.Name, Tenant: lib.GetTenant()}
 	vsNode.HttpPolicyRefs = []*nodes.AviHttpPolicySetNode{policy}
 
-	o.BuildHTTPPolicySetHTTPRequestRedirectRules(key, vsNode, routeModel, rule.Filters)
+	o.BuildHTTPPolicySetHTTPRequestRedirectRules(key, vsNode, routeModel, rule)
 	if len(vsNode.HttpPolicyRefs[0].RequestRules) == 1 {
 		// When the RedirectAction is specified the Request and Response Modify Header Action
 		// won't have any effect, hence returning.
 		utils.AviLog.Infof("key: %s, msg: Attached HTTP redirect policy to vs %s", key, vsNode.Name)
 		return
 	}
-	o.BuildHTTPPolicySetHTTPRequestRules(key, vsNode, routeModel, rule.Filters)
+	o.BuildHTTPPolicySetHTTPRequestRules(key, vsNode, routeModel, rule)
 	o.BuildHTTPPolicySetHTTPResponseRules(key, vsNode, routeModel, rule.Filters)
+	if len(vsNode.HttpPolicyRefs[0].RequestRules) == 0 && len(vsNode.HttpPolicyRefs[0].ResponseRules) == 0 {
+		// filters such as RequestMirror are not handled by the httppolicyset
+		vsNode.HttpPolicyRefs = nil
+		return
+	}
 	utils.AviLog.Infof("key: %s, msg: Attached HTTP policies to vs %s", key, vsNode.Name)
 }
 
-func (o *AviObjectGraph) BuildHTTPPolicySetHTTPRequestRules(key string, vsNode *nodes.AviEvhVsNode, routeModel RouteModel, filters []*Filter) {
+func (o *AviObjectGraph) BuildHTTPPolicySetHTTPRequestRules(key string, vsNode *nodes.AviEvhVsNode, routeModel RouteModel, rule *Rule) {
 	requestRule := &models.HTTPRequestRule{Name: &vsNode.Name, Enable: proto.Bool(true), Index: proto.Int32(1)}
-	for _, filter := range filters {
+	for _, filter := range rule.Filters {
 		if filter.RequestFilter != nil {
 			var j uint32 = 0
 			for i := range filter.RequestFilter.Add {
@@ -293,8 +384,18 @@ func (o *AviObjectGraph) BuildHTTPPolicySetHTTPRequestRules(key string, vsNode *
 				j += 1
 			}
 		}
+		// considering only the first RewriteFilter
+		if filter.RewriteFilter != nil && requestRule.RewriteURLAction == nil {
+			requestRule.RewriteURLAction = &models.HTTPRewriteURLAction{}
+			if filter.RewriteFilter.Host != "" {
+				requestRule.RewriteURLAction.HostHdr = buildStringURIParam(filter.RewriteFilter.Host)
+			}
+			if filter.RewriteFilter.Path != nil {
+				requestRule.RewriteURLAction.Path = buildPathURIParam(filter.RewriteFilter.Path, rule.Matches)
+			}
+		}
 	}
-	if len(requestRule.HdrAction) != 0 {
+	if len(requestRule.HdrAction) != 0 || requestRule.RewriteURLAction != nil {
 		vsNode.HttpPolicyRefs[0].RequestRules = []*models.HTTPRequestRule{requestRule}
 		utils.AviLog.Debugf("key: %s, msg: Attached HTTP request policies %s to vs %s", key, utils.Stringify(vsNode.HttpPolicyRefs[0].RequestRules), vsNode.Name)
 	}
@@ -344,20 +445,35 @@ func (o *AviObjectGraph) BuildHTTPPolicySetHTTPRuleHdrAction(key string, action
 	return hdrAction
 }
 
-func (o *AviObjectGraph) BuildHTTPPolicySetHTTPRequestRedirectRules(key string, vsNode *nodes.AviEvhVsNode, routeModel RouteModel, filters []*Filter) {
+func (o *AviObjectGraph) BuildHTTPPolicySetHTTPRequestRedirectRules(key string, vsNode *nodes.AviEvhVsNode, routeModel RouteModel, rule *Rule) {
 	redirectAction := &models.HTTPRedirectAction{}
-	for _, filter := range filters {
+	for _, filter := range rule.Filters {
 		// considering only the first RedirectFilter
 		if filter.RedirectFilter != nil {
-			uriParamToken := &models.URIParamToken{
-				StrValue: &filter.RedirectFilter.Host,
-				Type:     proto.String("URI_TOKEN_TYPE_STRING"),
+			// the scheme of the request is retained when the scheme is not specified
+			protocol := "HTTP"
+			if filter.RedirectFilter.Scheme != "" {
+				protocol = strings.ToUpper(filter.RedirectFilter.Scheme)
+			} else if listenerProtocol := getListenerProtocol(vsNode.ServiceMetadata.Gateway, routeModel); listenerProtocol == string(gatewayv1.HTTPSProtocolType) {
+				protocol = "HTTPS"
+			}
+			redirectAction.Protocol = &protocol
+			if filter.RedirectFilter.Host != "" {
+				redirectAction.Host = buildStringURIParam(filter.RedirectFilter.Host)
+			}
+			if filter.RedirectFilter.Path != nil {
+				redirectAction.Path = buildPathURIParam(filter.RedirectFilter.Path, rule.Matches)
+				redirectAction.KeepQuery = proto.Bool(true)
 			}
-			redirectAction.Host = &models.URIParam{
-				Tokens: []*models.URIParamToken{uriParamToken},
-				Type:   proto.String("URI_PARAM_TYPE_TOKENIZED"),
+			// the well known port of the scheme is used when only the scheme is specified,
+			// otherwise the port of the request is retained.
+			if filter.RedirectFilter.Port != 0 {
+				redirectAction.Port = proto.Uint32(uint32(filter.RedirectFilter.Port))
+			} else if filter.RedirectFilter.Scheme == "https" {
+				redirectAction.Port = proto.Uint32(443)
+			} else if filter.RedirectFilter.Scheme == "http" {
+				redirectAction.Port = proto.Uint32(80)
 			}
-			redirectAction.Protocol = proto.String("HTTP")
 			statusCode := "HTTP_REDIRECT_STATUS_CODE_302"
 			switch filter.RedirectFilter.StatusCode {
 			case 301, 302, 307:
@@ -371,3 +487,112 @@ func (o *AviObjectGraph) BuildHTTPPolicySetHTTPRequestRedirectRules(key string,
 		}
 	}
 }
+
+// buildStringURIParam returns a tokenized URI param which is replaced with the given value.
+func buildStringURIParam(value string) *models.URIParam {
+	return &models.URIParam{
+		Tokens: []*models.URIParamToken{{
+			StrValue: proto.String(value),
+			Type:     proto.String("URI_TOKEN_TYPE_STRING"),
+		}},
+		Type: proto.String("URI_PARAM_TYPE_TOKENIZED"),
+	}
+}
+
+// buildPathURIParam returns the tokenized path of a path modifier. Avi joins the path tokens
+// with a "/", hence the slashes around the values are trimmed. For ReplacePrefixMatch the
+// segments of the request path following the matched prefix are retained using a path token.
+func buildPathURIParam(pathModifier *PathModifier, matches []*Match) *models.URIParam {
+	uriParam := &models.URIParam{Type: proto.String("URI_PARAM_TYPE_TOKENIZED")}
+	value := strings.Trim(pathModifier.Value, "/")
+	if pathModifier.Type == string(gatewayv1.FullPathHTTPPathModifier) {
+		uriParam.Tokens = append(uriParam.Tokens, &models.URIParamToken{
+			StrValue: proto.String(value),
+			Type:     proto.String("URI_TOKEN_TYPE_STRING"),
+		})
+		return uriParam
+	}
+	if value != "" {
+		uriParam.Tokens = append(uriParam.Tokens, &models.URIParamToken{
+			StrValue: proto.String(value),
+			Type:     proto.String("URI_TOKEN_TYPE_STRING"),
+		})
+	}
+	// the validator ensures that all the prefixes of the rule have the same number of segments
+	var prefixSegments uint32
+	if len(matches) > 0 && matches[0].PathMatch != nil {
+		if prefix := strings.Trim(matches[0].PathMatch.Path, "/"); prefix != "" {
+			prefixSegments = uint32(len(strings.Split(prefix, "/")))
+		}
+	}
+	uriParam.Tokens = append(uriParam.Tokens, &models.URIParamToken{
+		StartIndex: proto.Uint32(prefixSegments),
+		EndIndex:   proto.Uint32(65535),
+		Type:       proto.String("URI_TOKEN_TYPE_PATH"),
+	})
+	return uriParam
+}
+
+func getListenerProtocol(parentNsName string, routeModel RouteModel) string {
+	routeTypeNsName := routeModel.GetType() + "/" + routeModel.GetNamespace() + "/" + routeModel.GetName()
+	for _, listener := range akogatewayapiobjects.GatewayApiLister().GetRouteToGatewayListener(routeTypeNsName) {
+		if listener.Gateway == parentNsName {
+			return listener.Protocol
+		}
+	}
+	return ""
+}
+
+func (o *AviObjectGraph) BuildTrafficCloneProfile(key, parentNsName string, childVsNode *nodes.AviEvhVsNode, routeModel RouteModel, rule *Rule) {
+	childVsNode.TrafficCloneRefs = nil
+	childVsNode.TrafficCloneProfileRef = nil
+	var mirror *MirrorFilter
+	for _, filter := range rule.Filters {
+		// considering only the first MirrorFilter
+		if filter.MirrorFilter != nil {
+			mirror = filter.MirrorFilter
+			break
+		}
+	}
+	if mirror == nil || mirror.Backend == nil {
+		return
+	}
+	backend := mirror.Backend
+	svcObj, err := utils.GetInformers().ServiceInformer.Lister().Services(backend.Namespace).Get(backend.Name)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to get the mirror service %s/%s, err: %v", key, backend.Namespace, backend.Name, err)
+		return
+	}
+	// the servers of the mirror backend are looked up the same way as the pool servers,
+	// the requests are cloned to the servers as is, so the backend port is not honoured.
+	poolNode := &nodes.AviPoolNode{
+		PortName:   akogatewayapilib.FindPortName(backend.Name, backend.Namespace, backend.Port, key),
+		TargetPort: akogatewayapilib.FindTargetPort(backend.Name, backend.Namespace, backend.Port, key),
+		Port:       backend.Port,
+	}
+	var servers []nodes.AviPoolMetaServer
+	if lib.GetServiceType() == lib.NodePort {
+		servers = nodes.PopulateServersForNodePort(poolNode, svcObj.ObjectMeta.Namespace, svcObj.ObjectMeta.Name, false, key)
+	} else {
+		servers = nodes.PopulateServers(poolNode, svcObj.ObjectMeta.Namespace, svcObj.ObjectMeta.Name, false, key)
+	}
+	parentNs, _, parentName := lib.ExtractTypeNameNamespace(parentNsName)
+	cloneProfile := &nodes.AviTrafficCloneProfileNode{
+		Name: akogatewayapilib.GetTrafficCloneProfileName(parentNs, parentName,
+			routeModel.GetNamespace(), routeModel.GetName(), utils.Stringify(rule.Matches)),
+		Tenant:     lib.GetTenant(),
+		AviMarkers: childVsNode.AviMarkers,
+	}
+	for _, server := range servers {
+		if server.Ip.Addr != nil && !utils.HasElem(cloneProfile.CloneServers, *server.Ip.Addr) {
+			cloneProfile.CloneServers = append(cloneProfile.CloneServers, *server.Ip.Addr)
+		}
+	}
+	if len(cloneProfile.CloneServers) == 0 {
+		utils.AviLog.Warnf("key: %s, msg: no servers found for the mirror service %s/%s", key, backend.Namespace, backend.Name)
+		return
+	}
+	childVsNode.TrafficCloneRefs = []*nodes.AviTrafficCloneProfileNode{cloneProfile}
+	childVsNode.TrafficCloneProfileRef = proto.String("/api/trafficcloneprofile?name=" + cloneProfile.Name)
+	utils.AviLog.Infof("key: %s, msg: Attached traffic clone profile %s to vs %s", key, cloneProfile.Name, childVsNode.Name)
+}
@@ -0,0 +1,205 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package nodes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vmware/alb-sdk/go/models"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	akogatewayapiobjects "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+// BuildGatewayPassthroughVs builds the L4 VS for a gateway with TLS passthrough listeners.
+// The pool groups of the VS are selected by a datascript, using the SNI of the client hello.
+func (o *AviObjectGraph) BuildGatewayPassthroughVs(gateway *gatewayv1.Gateway, key string) {
+	o.Lock.Lock()
+	defer o.Lock.Unlock()
+
+	vsNode := o.BuildGatewayPassthroughParent(gateway, key)
+
+	o.AddModelNode(vsNode)
+	utils.AviLog.Infof("key: %s, msg: checksum for AVI VS object %v", key, vsNode.GetCheckSum())
+}
+
+func (o *AviObjectGraph) BuildGatewayPassthroughParent(gateway *gatewayv1.Gateway, key string) *nodes.AviVsNode {
+	vsName := akogatewayapilib.GetGatewayL4ParentName(gateway.Namespace, gateway.Name)
+	vsNode := &nodes.AviVsNode{
+		Name:               vsName,
+		Tenant:             lib.GetTenant(),
+		ServiceEngineGroup: lib.GetSEGName(),
+		ApplicationProfile: utils.DEFAULT_L4_APP_PROFILE,
+		NetworkProfile:     utils.DEFAULT_TCP_NW_PROFILE,
+		SharedVS:           true,
+		VrfContext:         lib.GetVrf(),
+		ServiceMetadata: lib.ServiceMetadataObj{
+			Gateway: gateway.Namespace + "/" + gateway.Name,
+		},
+	}
+
+	for _, listener := range gateway.Spec.Listeners {
+		pp := nodes.AviPortHostProtocol{Port: int32(listener.Port), Protocol: utils.TCP}
+		if !utils.HasElem(vsNode.PortProto, pp) {
+			vsNode.PortProto = append(vsNode.PortProto, pp)
+		}
+	}
+
+	vsNode.HTTPDSrefs = []*nodes.AviHTTPDataScriptNode{BuildPassthroughDataScript(gateway, vsName)}
+
+	vsvipNode := BuildVsVipNodeForGateway(gateway, vsNode.Name)
+	vsNode.VSVIPRefs = []*nodes.AviVSVIPNode{vsvipNode}
+
+	return vsNode
+}
+
+func BuildPassthroughDataScript(gateway *gatewayv1.Gateway, vsName string) *nodes.AviHTTPDataScriptNode {
+	// The datascript selects the pool group by appending the SNI to the pool group prefix of the gateway.
+	script := strings.Replace(lib.PassthroughDatascript, "CLUSTER--AVIINFRA", akogatewayapilib.GetPassthroughPGPrefix(gateway.Namespace, gateway.Name), 1)
+	return &nodes.AviHTTPDataScriptNode{
+		Name:   lib.GetL7InsecureDSName(vsName),
+		Tenant: lib.GetTenant(),
+		DataScript: &nodes.DataScript{
+			Script: script,
+			Evt:    "VS_DATASCRIPT_EVT_L4_REQUEST",
+		},
+		ProtocolParsers: []string{"/api/protocolparser/?name=Default-TLS"},
+	}
+}
+
+// ProcessPassthroughRoutes rebuilds the pool groups and pools of the passthrough VS from all the
+// TLSRoutes attached to the gateway. When multiple routes claim the same hostname, the oldest
+// route wins.
+func (o *AviObjectGraph) ProcessPassthroughRoutes(key, parentNsName string) {
+	vsNodes := o.GetAviVS()
+	if len(vsNodes) == 0 {
+		return
+	}
+	vsNode := vsNodes[0]
+	parentNs, _, parentName := lib.ExtractTypeNameNamespace(parentNsName)
+
+	//reset pool, poolgroup and fqdn references
+	vsNode.PoolRefs = nil
+	vsNode.PoolGroupRefs = nil
+	if len(vsNode.HTTPDSrefs) > 0 {
+		vsNode.HTTPDSrefs[0].PoolGroupRefs = nil
+	}
+	if len(vsNode.VSVIPRefs) > 0 {
+		vsNode.VSVIPRefs[0].FQDNs = nil
+	}
+
+	hostToPG := make(map[string]*nodes.AviPoolGroupNode)
+	for _, tlsRoute := range getPassthroughRoutes(key, parentNsName) {
+		routeModel, err := GetTLSRouteModel(key, tlsRoute.Name, tlsRoute.Namespace)
+		if err != nil {
+			continue
+		}
+		routeTypeNsName := lib.TLSRoute + "/" + tlsRoute.Namespace + "/" + tlsRoute.Name
+		listenerHostnames := getRouteListenerHostnames(routeTypeNsName, parentNsName)
+		routeConfig := routeModel.ParseRouteRules()
+		for _, host := range routeConfig.Hosts {
+			if !isHostnameAllowed(host, listenerHostnames) {
+				continue
+			}
+			if _, ok := hostToPG[host]; ok {
+				utils.AviLog.Warnf("key: %s, msg: hostname %s is already used by another TLSRoute, skipping it for route %s", key, host, routeTypeNsName)
+				continue
+			}
+			PG := &nodes.AviPoolGroupNode{
+				Name:   akogatewayapilib.GetPassthroughPGName(parentNs, parentName, host),
+				Tenant: lib.GetTenant(),
+			}
+			for _, rule := range routeConfig.Rules {
+				for _, backend := range rule.Backends {
+					poolNode := o.BuildL4Pool(key, parentNsName, routeModel, host, utils.TCP, backend)
+					if poolNode == nil {
+						continue
+					}
+					vsNode.PoolRefs = append(vsNode.PoolRefs, poolNode)
+					poolRef := fmt.Sprintf("/api/pool?name=%s", poolNode.Name)
+					ratio := uint32(backend.Weight)
+					PG.Members = append(PG.Members, &models.PoolGroupMember{PoolRef: &poolRef, Ratio: &ratio})
+				}
+			}
+			hostToPG[host] = PG
+			vsNode.PoolGroupRefs = append(vsNode.PoolGroupRefs, PG)
+			if len(vsNode.HTTPDSrefs) > 0 {
+				vsNode.HTTPDSrefs[0].PoolGroupRefs = append(vsNode.HTTPDSrefs[0].PoolGroupRefs, PG.Name)
+			}
+			if len(vsNode.VSVIPRefs) > 0 && !utils.HasElem(vsNode.VSVIPRefs[0].FQDNs, host) {
+				vsNode.VSVIPRefs[0].FQDNs = append(vsNode.VSVIPRefs[0].FQDNs, host)
+			}
+		}
+	}
+	utils.AviLog.Infof("key: %s, msg: processed %d hostnames for the passthrough vs %s", key, len(hostToPG), vsNode.Name)
+}
+
+// getPassthroughRoutes returns the TLSRoutes attached to the gateway, sorted by creation timestamp and name.
+func getPassthroughRoutes(key, parentNsName string) []*gatewayv1alpha2.TLSRoute {
+	var tlsRoutes []*gatewayv1alpha2.TLSRoute
+	found, routeTypeNsNameList := akogatewayapiobjects.GatewayApiLister().GetGatewayToRoute(parentNsName)
+	if !found {
+		return tlsRoutes
+	}
+	routeLister := akogatewayapilib.AKOControlConfig().GatewayApiInformers().TLSRouteInformer.Lister()
+	for _, routeTypeNsName := range routeTypeNsNameList {
+		routeType, namespace, name := lib.ExtractTypeNameNamespace(routeTypeNsName)
+		if routeType != lib.TLSRoute {
+			continue
+		}
+		tlsRoute, err := routeLister.TLSRoutes(namespace).Get(name)
+		if err != nil {
+			utils.AviLog.Debugf("key: %s, msg: unable to get the TLSRoute %s/%s, err: %v", key, namespace, name, err)
+			continue
+		}
+		tlsRoutes = append(tlsRoutes, tlsRoute)
+	}
+	sort.Slice(tlsRoutes, func(i, j int) bool {
+		if tlsRoutes[i].CreationTimestamp.Equal(&tlsRoutes[j].CreationTimestamp) {
+			return tlsRoutes[i].Namespace+"/"+tlsRoutes[i].Name < tlsRoutes[j].Namespace+"/"+tlsRoutes[j].Name
+		}
+		return tlsRoutes[i].CreationTimestamp.Before(&tlsRoutes[j].CreationTimestamp)
+	})
+	return tlsRoutes
+}
+
+// getRouteListenerHostnames returns the hostnames of the listeners of the gateway, the route is attached to.
+func getRouteListenerHostnames(routeTypeNsName, parentNsName string) []string {
+	var hostnames []string
+	for _, listener := range akogatewayapiobjects.GatewayApiLister().GetRouteToGatewayListener(routeTypeNsName) {
+		if listener.Gateway != parentNsName {
+			continue
+		}
+		gwListenerNsName := parentNsName + "/" + listener.Name
+		hostnames = append(hostnames, akogatewayapiobjects.GatewayApiLister().GetGatewayListenerToHostname(gwListenerNsName))
+	}
+	return hostnames
+}
+
+func isHostnameAllowed(host string, listenerHostnames []string) bool {
+	for _, listenerHostname := range listenerHostnames {
+		if _, ok := akogatewayapilib.GetHostnameIntersection(listenerHostname, host); ok {
+			return true
+		}
+	}
+	return false
+}
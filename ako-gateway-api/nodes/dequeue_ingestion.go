@@ -58,41 +58,46 @@ func DequeueIngestion(key string, fullsync bool) {
 	for _, gatewayNsName := range gatewayNsNameList {
 
 		parentNs, _, parentName := lib.ExtractTypeNameNamespace(gatewayNsName)
-		modelName := lib.GetModelName(lib.GetTenant(), akogatewayapilib.GetGatewayParentName(parentNs, parentName))
-
-		modelFound, modelIntf := objects.SharedAviGraphLister().Get(modelName)
-		if !modelFound || modelIntf == nil {
+		modelName, modelIntf := getGatewayModel(parentNs, parentName)
+		if modelIntf == nil {
 			utils.AviLog.Warnf("key: %s, msg: no model found: %s", key, modelName)
 			continue
 		}
 
 		model := &AviObjectGraph{modelIntf.(*nodes.AviObjectGraph)}
-		if objType == utils.Secret {
-			handleSecrets(parentNs, parentName, key, model)
-		}
-		for _, routeTypeNsName := range routeTypeNsNameList {
-			objType, namespace, name := lib.ExtractTypeNameNamespace(routeTypeNsName)
-			utils.AviLog.Infof("key: %s, msg: processing route %s mapped to gateway %s", key, routeTypeNsName, gatewayNsName)
-
-			routeModel, err := NewRouteModel(key, objType, name, namespace)
-			if err != nil {
-				if k8serrors.IsNotFound(err) {
-					utils.AviLog.Infof("key: %s, msg: deleting configurations corresponding to route %s", key, routeTypeNsName)
-					model.ProcessRouteDeletion(key, routeModel, fullsync)
-				}
-				continue
+		if len(model.GetAviVS()) > 0 {
+			// All the routes of a L4 gateway share the pool groups of the same VS,
+			// hence the VS is rebuilt from all the routes attached to the gateway.
+			model.ProcessL4Routes(key, gatewayNsName)
+		} else {
+			if objType == utils.Secret {
+				handleSecrets(parentNs, parentName, key, model)
 			}
+			for _, routeTypeNsName := range routeTypeNsNameList {
+				objType, namespace, name := lib.ExtractTypeNameNamespace(routeTypeNsName)
+				utils.AviLog.Infof("key: %s, msg: processing route %s mapped to gateway %s", key, routeTypeNsName, gatewayNsName)
+
+				routeModel, err := NewRouteModel(key, objType, name, namespace)
+				if err != nil {
+					if k8serrors.IsNotFound(err) {
+						utils.AviLog.Infof("key: %s, msg: deleting configurations corresponding to route %s", key, routeTypeNsName)
+						model.ProcessRouteDeletion(key, routeModel, fullsync)
+					}
+					continue
+				}
 
-			childVSes := make(map[string]struct{}, 0)
+				childVSes := make(map[string]struct{}, 0)
 
-			switch objType {
-			case lib.HTTPRoute:
-				model.ProcessL7Routes(key, routeModel, gatewayNsName, childVSes, fullsync)
-			default:
-				utils.AviLog.Warnf("key: %s, msg: route of type %s not supported", key, objType)
-				continue
+				switch objType {
+				case lib.HTTPRoute, lib.GRPCRoute:
+					model.ProcessL7Routes(key, routeModel, gatewayNsName, childVSes, fullsync)
+				default:
+					utils.AviLog.Warnf("key: %s, msg: route of type %s not supported", key, objType)
+					continue
+				}
+				model.DeleteStaleChildVSes(key, routeModel, childVSes, fullsync)
 			}
-			model.DeleteStaleChildVSes(key, routeModel, childVSes, fullsync)
+			model.ProcessHTTP2OnParent(key, gatewayNsName)
 		}
 
 		// Only add this node to the list of models if the checksum has changed.
@@ -135,12 +140,7 @@ func handleGateway(namespace, name string, fullsync bool, key string) {
 	utils.AviLog.Debugf("key: %s, msg: processing gateway: %s", key, name)
 
 	modelName := lib.GetModelName(lib.GetTenant(), akogatewayapilib.GetGatewayParentName(namespace, name))
-	modelFound, _ := objects.SharedAviGraphLister().Get(modelName)
-	if modelFound {
-		utils.AviLog.Debugf("key: %s, msg: found model: %s", key, modelName)
-	} else {
-		utils.AviLog.Debugf("key: %s, msg: no model found: %s", key, modelName)
-	}
+	l4ModelName := lib.GetModelName(lib.GetTenant(), akogatewayapilib.GetGatewayL4ParentName(namespace, name))
 
 	gatewayObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GatewayInformer.Lister().Gateways(namespace).Get(name)
 	if err != nil {
@@ -149,15 +149,23 @@ func handleGateway(namespace, name string, fullsync bool, key string) {
 			return
 		}
 		utils.AviLog.Debugf("key: %s, msg: gateway not found: %s/%s", key, namespace, name)
-		if modelFound {
-			objects.SharedAviGraphLister().Save(modelName, nil)
-			if !fullsync {
-				sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
-				nodes.PublishKeyToRestLayer(modelName, key, sharedQueue)
-			}
-		}
+		deleteStaleGatewayModel(modelName, key, fullsync)
+		deleteStaleGatewayModel(l4ModelName, key, fullsync)
 		return
 	}
+
+	// the gateway is realised either as an EVH parent or as a L4 VS, the model of the other type is stale.
+	staleModelName := l4ModelName
+	if akogatewayapilib.IsL4Gateway(gatewayObj) {
+		modelName, staleModelName = l4ModelName, modelName
+	}
+	modelFound, _ := objects.SharedAviGraphLister().Get(modelName)
+	if modelFound {
+		utils.AviLog.Debugf("key: %s, msg: found model: %s", key, modelName)
+	} else {
+		utils.AviLog.Debugf("key: %s, msg: no model found: %s", key, modelName)
+	}
+
 	gwClass := string(gatewayObj.Spec.GatewayClassName)
 	utils.AviLog.Debugf("key: %s, msg: fetching gateway class %s for gateway: %s/%s", key, gwClass, namespace, name)
 	found, isAkoCtrl := akogatewayapiobjects.GatewayApiLister().IsGatewayClassControllerAKO(gwClass)
@@ -169,6 +177,7 @@ func handleGateway(namespace, name string, fullsync bool, key string) {
 			sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
 			nodes.PublishKeyToRestLayer(modelName, key, sharedQueue)
 		}
+		deleteStaleGatewayModel(staleModelName, key, fullsync)
 		return
 	}
 	utils.AviLog.Debugf("key: %s, msg: fetching gateway class found: %s", key, gwClass)
@@ -177,8 +186,16 @@ func handleGateway(namespace, name string, fullsync bool, key string) {
 		utils.AviLog.Infof("key: %s, msg: Controller is not AKO for %s, not building VS model", key, modelName)
 		return
 	}
+	deleteStaleGatewayModel(staleModelName, key, fullsync)
+
 	aviModelGraph := NewAviObjectGraph()
-	aviModelGraph.BuildGatewayVs(gatewayObj, key)
+	if akogatewayapilib.IsPassthroughGateway(gatewayObj) {
+		aviModelGraph.BuildGatewayPassthroughVs(gatewayObj, key)
+	} else if akogatewayapilib.IsL4Gateway(gatewayObj) {
+		aviModelGraph.BuildGatewayL4Vs(gatewayObj, key)
+	} else {
+		aviModelGraph.BuildGatewayVs(gatewayObj, key)
+	}
 
 	modelChanged := saveAviModel(modelName, aviModelGraph.AviObjectGraph, key)
 	if modelChanged && !fullsync {
@@ -187,6 +204,32 @@ func handleGateway(namespace, name string, fullsync bool, key string) {
 	}
 }
 
+// getGatewayModel returns the model of the gateway, which is either an EVH parent or a L4 VS.
+func getGatewayModel(namespace, name string) (string, interface{}) {
+	modelName := lib.GetModelName(lib.GetTenant(), akogatewayapilib.GetGatewayParentName(namespace, name))
+	if found, modelIntf := objects.SharedAviGraphLister().Get(modelName); found && modelIntf != nil {
+		return modelName, modelIntf
+	}
+	l4ModelName := lib.GetModelName(lib.GetTenant(), akogatewayapilib.GetGatewayL4ParentName(namespace, name))
+	if found, modelIntf := objects.SharedAviGraphLister().Get(l4ModelName); found && modelIntf != nil {
+		return l4ModelName, modelIntf
+	}
+	return modelName, nil
+}
+
+func deleteStaleGatewayModel(modelName, key string, fullsync bool) {
+	modelFound, modelIntf := objects.SharedAviGraphLister().Get(modelName)
+	if !modelFound || modelIntf == nil {
+		return
+	}
+	utils.AviLog.Infof("key: %s, msg: deleting the model: %s", key, modelName)
+	objects.SharedAviGraphLister().Save(modelName, nil)
+	if !fullsync {
+		sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
+		nodes.PublishKeyToRestLayer(modelName, key, sharedQueue)
+	}
+}
+
 func saveAviModel(modelName string, aviGraph *nodes.AviObjectGraph, key string) bool {
 	utils.AviLog.Debugf("key: %s, msg: Evaluating model :%s", key, modelName)
 	if lib.DisableSync {
@@ -106,12 +106,16 @@ func BuildTLSNodesForGateway(gateway *gatewayv1.Gateway, key string) []*nodes.Av
 					ns = string(*certRef.Namespace)
 				}
 				name = string(certRef.Name)
+				if !akogatewayapilib.IsCertificateRefAllowed(gateway.Namespace, certRef) {
+					utils.AviLog.Warnf("key: %s, msg: reference to the secret %s/%s is not permitted", key, ns, name)
+					continue
+				}
 				secretObj, err := cs.CoreV1().Secrets(ns).Get(context.TODO(), name, metav1.GetOptions{})
 				if err != nil || secretObj == nil {
 					utils.AviLog.Warnf("key: %s, msg: secret %s has been deleted, err: %s", key, name, err)
 					continue
 				}
-				tlsNode := TLSNodeFromSecret(secretObj, string(*listener.Hostname), name, key)
+				tlsNode := TLSNodeFromSecret(secretObj, akogatewayapilib.GetListenerCertHostname(gateway, listener), name, key)
 				tlsNodes = append(tlsNodes, tlsNode)
 			}
 		}
@@ -165,7 +169,7 @@ func DeleteTLSNode(key string, object *AviObjectGraph, gateway *gatewayv1.Gatewa
 		if listener.TLS != nil {
 			for _, certRef := range listener.TLS.CertificateRefs {
 				name := string(certRef.Name)
-				encodedCertName := lib.GetTLSKeyCertNodeName("", string(*listener.Hostname), name)
+				encodedCertName := lib.GetTLSKeyCertNodeName("", akogatewayapilib.GetListenerCertHostname(gateway, listener), name)
 				indexlist, exists := encodedCertNameIndexMap[encodedCertName]
 				if exists {
 					if name != secretName {
@@ -192,7 +196,7 @@ func AddTLSNode(key string, object *AviObjectGraph, gateway *gatewayv1.Gateway,
 		if listener.TLS != nil {
 			for _, certRef := range listener.TLS.CertificateRefs {
 				name := string(certRef.Name)
-				encodedCertName := lib.GetTLSKeyCertNodeName("", string(*listener.Hostname), name)
+				encodedCertName := lib.GetTLSKeyCertNodeName("", akogatewayapilib.GetListenerCertHostname(gateway, listener), name)
 				indexlist, exists := encodedCertNameIndexMap[encodedCertName]
 				if exists {
 					tlsNodes = append(tlsNodes, evhVsCertRefs[indexlist[0]])
@@ -203,7 +207,7 @@ func AddTLSNode(key string, object *AviObjectGraph, gateway *gatewayv1.Gateway,
 					}
 				} else {
 					if name == secretName {
-						tlsNode := TLSNodeFromSecret(secretObj, string(*listener.Hostname), name, key)
+						tlsNode := TLSNodeFromSecret(secretObj, akogatewayapilib.GetListenerCertHostname(gateway, listener), name, key)
 						tlsNodes = append(tlsNodes, tlsNode)
 					}
 				}
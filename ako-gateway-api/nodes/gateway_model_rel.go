@@ -15,9 +15,8 @@
 package nodes
 
 import (
-	"strings"
-
 	"k8s.io/apimachinery/pkg/api/errors"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/objects"
@@ -78,6 +77,26 @@ var (
 		GetGateways: HTTPRouteToGateway,
 		GetRoutes:   HTTPRouteChanges,
 	}
+	GRPCRoute = GraphSchema{
+		Type:        lib.GRPCRoute,
+		GetGateways: GRPCRouteToGateway,
+		GetRoutes:   GRPCRouteChanges,
+	}
+	TLSRoute = GraphSchema{
+		Type:        lib.TLSRoute,
+		GetGateways: TLSRouteToGateway,
+		GetRoutes:   TLSRouteChanges,
+	}
+	TCPRoute = GraphSchema{
+		Type:        lib.TCPRoute,
+		GetGateways: TCPRouteToGateway,
+		GetRoutes:   TCPRouteChanges,
+	}
+	UDPRoute = GraphSchema{
+		Type:        lib.UDPRoute,
+		GetGateways: UDPRouteToGateway,
+		GetRoutes:   UDPRouteChanges,
+	}
 	SupportedGraphTypes = GraphDescriptor{
 		Gateway,
 		GatewayClass,
@@ -85,6 +104,10 @@ var (
 		Service,
 		Endpoint,
 		HTTPRoute,
+		GRPCRoute,
+		TLSRoute,
+		TCPRoute,
+		UDPRoute,
 	}
 )
 
@@ -122,8 +145,8 @@ func GatewayGetGw(namespace, name, key string) ([]string, bool) {
 
 		if listenerObj.AllowedRoutes == nil {
 			gwListener.AllowedRouteNs = gwObj.Namespace
-			gwListener.AllowedRouteTypes = []objects.GatewayRouteKind{
-				{Group: akogatewayapilib.GatewayGroup, Kind: akogatewayapilib.ProtocolToRoute(gwListener.Protocol)},
+			for _, kind := range akogatewayapilib.GetSupportedKinds(listenerObj.Protocol) {
+				gwListener.AllowedRouteTypes = append(gwListener.AllowedRouteTypes, objects.GatewayRouteKind{Group: akogatewayapilib.GatewayGroup, Kind: string(kind.Kind)})
 			}
 		} else {
 			if listenerObj.AllowedRoutes.Namespaces != nil {
@@ -161,8 +184,8 @@ func GatewayGetGw(namespace, name, key string) ([]string, bool) {
 			}
 		}
 		listeners = append(listeners, gwListener)
-		hostnames[string(listenerObj.Name)] = string(*listenerObj.Hostname)
-		gwHostnames = append(gwHostnames, string(*listenerObj.Hostname))
+		hostnames[string(listenerObj.Name)] = akogatewayapilib.GetListenerHostname(listenerObj)
+		gwHostnames = append(gwHostnames, akogatewayapilib.GetListenerHostname(listenerObj))
 
 	}
 	//TODO: verify hostname overlap here or use the store updated from here
@@ -221,28 +244,76 @@ func GatewayClassGetGw(namespace, name, key string) ([]string, bool) {
 func HTTPRouteToGateway(namespace, name, key string) ([]string, bool) {
 
 	routeTypeNsName := lib.HTTPRoute + "/" + namespace + "/" + name
-	httpGroupKind := objects.GatewayRouteKind{Group: akogatewayapilib.GatewayGroup, Kind: lib.HTTPRoute}
 	hrObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().HTTPRouteInformer.Lister().HTTPRoutes(namespace).Get(name)
 	if err != nil {
-		if !errors.IsNotFound(err) {
-			utils.AviLog.Errorf("key: %s, msg: got error while getting gateway: %v", key, err)
-			return []string{}, false
-		}
-		found, gwNsNameList := akogatewayapiobjects.GatewayApiLister().GetRouteToGateway(routeTypeNsName)
-		if !found {
-			return []string{}, true
-		}
-		return gwNsNameList, true
+		return deletedRouteToGateway(key, routeTypeNsName, err)
+	}
+	return routeToGateway(key, lib.HTTPRoute, routeTypeNsName, namespace, hrObj.Spec.ParentRefs, hrObj.Spec.Hostnames), true
+}
+
+func GRPCRouteToGateway(namespace, name, key string) ([]string, bool) {
+
+	routeTypeNsName := lib.GRPCRoute + "/" + namespace + "/" + name
+	grObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer.Lister().GRPCRoutes(namespace).Get(name)
+	if err != nil {
+		return deletedRouteToGateway(key, routeTypeNsName, err)
+	}
+	return routeToGateway(key, lib.GRPCRoute, routeTypeNsName, namespace, grObj.Spec.ParentRefs, grObj.Spec.Hostnames), true
+}
+
+func TLSRouteToGateway(namespace, name, key string) ([]string, bool) {
+
+	routeTypeNsName := lib.TLSRoute + "/" + namespace + "/" + name
+	trObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().TLSRouteInformer.Lister().TLSRoutes(namespace).Get(name)
+	if err != nil {
+		return deletedRouteToGateway(key, routeTypeNsName, err)
+	}
+	return routeToGateway(key, lib.TLSRoute, routeTypeNsName, namespace, trObj.Spec.ParentRefs, trObj.Spec.Hostnames), true
+}
+
+func TCPRouteToGateway(namespace, name, key string) ([]string, bool) {
+
+	routeTypeNsName := lib.TCPRoute + "/" + namespace + "/" + name
+	trObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().TCPRouteInformer.Lister().TCPRoutes(namespace).Get(name)
+	if err != nil {
+		return deletedRouteToGateway(key, routeTypeNsName, err)
 	}
+	return routeToGateway(key, lib.TCPRoute, routeTypeNsName, namespace, trObj.Spec.ParentRefs, nil), true
+}
+
+func UDPRouteToGateway(namespace, name, key string) ([]string, bool) {
+
+	routeTypeNsName := lib.UDPRoute + "/" + namespace + "/" + name
+	urObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().UDPRouteInformer.Lister().UDPRoutes(namespace).Get(name)
+	if err != nil {
+		return deletedRouteToGateway(key, routeTypeNsName, err)
+	}
+	return routeToGateway(key, lib.UDPRoute, routeTypeNsName, namespace, urObj.Spec.ParentRefs, nil), true
+}
+
+func deletedRouteToGateway(key, routeTypeNsName string, err error) ([]string, bool) {
+	if !errors.IsNotFound(err) {
+		utils.AviLog.Errorf("key: %s, msg: got error while getting route: %v", key, err)
+		return []string{}, false
+	}
+	found, gwNsNameList := akogatewayapiobjects.GatewayApiLister().GetRouteToGateway(routeTypeNsName)
+	if !found {
+		return []string{}, true
+	}
+	return gwNsNameList, true
+}
+
+func routeToGateway(key, routeKind, routeTypeNsName, namespace string, parentRefs []gatewayv1.ParentReference, hostnames []gatewayv1.Hostname) []string {
+	routeGroupKind := objects.GatewayRouteKind{Group: akogatewayapilib.GatewayGroup, Kind: routeKind}
 	var listenerList []objects.GatewayListenerStore
 	var gatewayList []string
 	var hostnameIntersection []string
 	var gwNsNameList []string
-	for _, parentRef := range hrObj.Spec.ParentRefs {
+	for _, parentRef := range parentRefs {
 		ns := namespace
 		if parentRef.Namespace != nil {
 			ns = string(*parentRef.Namespace)
-			// if *parentRef.Namespace != gatewayv1beta1.Namespace(hrObj.Namespace) {
+			// if *parentRef.Namespace != gatewayv1beta1.Namespace(namespace) {
 			// 	//check reference grant
 			// }
 		}
@@ -250,22 +321,29 @@ func HTTPRouteToGateway(namespace, name, key string) ([]string, bool) {
 		gwNsName := ns + "/" + string(parentRef.Name)
 		listeners := akogatewayapiobjects.GatewayApiLister().GetGatewayToListeners(gwNsName)
 		for _, listener := range listeners {
-			//check if namespace is allowed
-			if (len(listener.AllowedRouteTypes) == 0 || utils.HasElem(listener.AllowedRouteTypes, httpGroupKind)) &&
-				(listener.AllowedRouteNs == akogatewayapilib.AllowedRoutesNamespaceFromAll || listener.AllowedRouteNs == hrObj.Namespace) {
+			//check if the route kind and namespace are allowed
+			if akogatewayapilib.IsRouteKindSupported(listener.Protocol, routeKind) &&
+				(len(listener.AllowedRouteTypes) == 0 || utils.HasElem(listener.AllowedRouteTypes, routeGroupKind)) &&
+				(listener.AllowedRouteNs == akogatewayapilib.AllowedRoutesNamespaceFromAll || listener.AllowedRouteNs == namespace) {
 				//if provided, check if section name and port matches
 				if (parentRef.SectionName == nil || string(*parentRef.SectionName) == listener.Name) &&
 					(parentRef.Port == nil || int32(*parentRef.Port) == listener.Port) {
 
 					gwListenerNsName := gwNsName + "/" + listener.Name
 					listenerHostname := akogatewayapiobjects.GatewayApiLister().GetGatewayListenerToHostname(gwListenerNsName)
-					if strings.HasPrefix(listenerHostname, "*") {
-						listenerHostname = listenerHostname[1:]
+					// the TCPRoutes and UDPRoutes match all the listeners of their kind
+					hostnameMatched := !akogatewayapilib.RouteHasHostnames(routeKind)
+					routeHostnames := hostnames
+					if len(routeHostnames) == 0 && !hostnameMatched {
+						// the routes without hostnames inherit the hostname of the listener
+						routeHostnames = []gatewayv1.Hostname{""}
 					}
-					hostnameMatched := false
-					for _, routeHostname := range hrObj.Spec.Hostnames {
-						if strings.HasSuffix(string(routeHostname), listenerHostname) && akogatewayapilib.VerifyHostnameSubdomainMatch(string(routeHostname)) {
-							hostnameIntersection = append(hostnameIntersection, string(routeHostname))
+					for _, routeHostname := range routeHostnames {
+						host, ok := akogatewayapilib.GetHostnameIntersection(listenerHostname, string(routeHostname))
+						if ok && akogatewayapilib.VerifyHostnameSubdomainMatch(host) {
+							if !utils.HasElem(hostnameIntersection, host) {
+								hostnameIntersection = append(hostnameIntersection, host)
+							}
 							hostnameMatched = true
 						}
 					}
@@ -294,26 +372,110 @@ func HTTPRouteToGateway(namespace, name, key string) ([]string, bool) {
 	}
 
 	utils.AviLog.Debugf("key: %s, msg: Gateways retrieved %s", key, gwNsNameList)
-	return gwNsNameList, true
+	return gwNsNameList
 }
 
 func HTTPRouteChanges(namespace, name, key string) ([]string, bool) {
 	routeTypeNsName := lib.HTTPRoute + "/" + namespace + "/" + name
 	hrObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().HTTPRouteInformer.Lister().HTTPRoutes(namespace).Get(name)
 	if err != nil {
-		if !errors.IsNotFound(err) {
-			utils.AviLog.Errorf("key: %s, msg: got error while getting gateway: %v", key, err)
-			return []string{}, false
+		return deletedRouteChanges(key, routeTypeNsName, err)
+	}
+
+	var svcNsNameList []string
+	for _, rule := range hrObj.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			svcNsNameList = append(svcNsNameList, backendRefToSvcNsName(namespace, backendRef.BackendRef))
+		}
+		// the servers of the mirror backends are configured in the traffic clone profile
+		for _, filter := range rule.Filters {
+			if filter.RequestMirror != nil {
+				svcNsNameList = append(svcNsNameList, backendRefToSvcNsName(namespace, gatewayv1.BackendRef{BackendObjectReference: filter.RequestMirror.BackendRef}))
+			}
 		}
-		// httproute must be deleted so remove mappings
+	}
+	return routeChanges(key, routeTypeNsName, parentRefsToGwNsNames(namespace, hrObj.Spec.ParentRefs), svcNsNameList), true
+}
 
-		//delete route to service must also update gateway to service (through route)
-		akogatewayapiobjects.GatewayApiLister().DeleteRouteFromStore(routeTypeNsName)
-		return []string{routeTypeNsName}, true
+func GRPCRouteChanges(namespace, name, key string) ([]string, bool) {
+	routeTypeNsName := lib.GRPCRoute + "/" + namespace + "/" + name
+	grObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer.Lister().GRPCRoutes(namespace).Get(name)
+	if err != nil {
+		return deletedRouteChanges(key, routeTypeNsName, err)
 	}
 
+	var svcNsNameList []string
+	for _, rule := range grObj.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			svcNsNameList = append(svcNsNameList, backendRefToSvcNsName(namespace, backendRef.BackendRef))
+		}
+	}
+	return routeChanges(key, routeTypeNsName, parentRefsToGwNsNames(namespace, grObj.Spec.ParentRefs), svcNsNameList), true
+}
+
+func TLSRouteChanges(namespace, name, key string) ([]string, bool) {
+	routeTypeNsName := lib.TLSRoute + "/" + namespace + "/" + name
+	trObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().TLSRouteInformer.Lister().TLSRoutes(namespace).Get(name)
+	if err != nil {
+		return deletedRouteChanges(key, routeTypeNsName, err)
+	}
+
+	var svcNsNameList []string
+	for _, rule := range trObj.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			svcNsNameList = append(svcNsNameList, backendRefToSvcNsName(namespace, backendRef))
+		}
+	}
+	return routeChanges(key, routeTypeNsName, parentRefsToGwNsNames(namespace, trObj.Spec.ParentRefs), svcNsNameList), true
+}
+
+func TCPRouteChanges(namespace, name, key string) ([]string, bool) {
+	routeTypeNsName := lib.TCPRoute + "/" + namespace + "/" + name
+	trObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().TCPRouteInformer.Lister().TCPRoutes(namespace).Get(name)
+	if err != nil {
+		return deletedRouteChanges(key, routeTypeNsName, err)
+	}
+
+	var svcNsNameList []string
+	for _, rule := range trObj.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			svcNsNameList = append(svcNsNameList, backendRefToSvcNsName(namespace, backendRef))
+		}
+	}
+	return routeChanges(key, routeTypeNsName, parentRefsToGwNsNames(namespace, trObj.Spec.ParentRefs), svcNsNameList), true
+}
+
+func UDPRouteChanges(namespace, name, key string) ([]string, bool) {
+	routeTypeNsName := lib.UDPRoute + "/" + namespace + "/" + name
+	urObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().UDPRouteInformer.Lister().UDPRoutes(namespace).Get(name)
+	if err != nil {
+		return deletedRouteChanges(key, routeTypeNsName, err)
+	}
+
+	var svcNsNameList []string
+	for _, rule := range urObj.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			svcNsNameList = append(svcNsNameList, backendRefToSvcNsName(namespace, backendRef))
+		}
+	}
+	return routeChanges(key, routeTypeNsName, parentRefsToGwNsNames(namespace, urObj.Spec.ParentRefs), svcNsNameList), true
+}
+
+func deletedRouteChanges(key, routeTypeNsName string, err error) ([]string, bool) {
+	if !errors.IsNotFound(err) {
+		utils.AviLog.Errorf("key: %s, msg: got error while getting route: %v", key, err)
+		return []string{}, false
+	}
+	// route must be deleted so remove mappings
+
+	//delete route to service must also update gateway to service (through route)
+	akogatewayapiobjects.GatewayApiLister().DeleteRouteFromStore(routeTypeNsName)
+	return []string{routeTypeNsName}, true
+}
+
+func parentRefsToGwNsNames(namespace string, parentRefs []gatewayv1.ParentReference) []string {
 	var gwNsNameList []string
-	for _, parentRef := range hrObj.Spec.ParentRefs {
+	for _, parentRef := range parentRefs {
 		ns := namespace
 		if parentRef.Namespace != nil {
 			ns = string(*parentRef.Namespace)
@@ -321,19 +483,18 @@ func HTTPRouteChanges(namespace, name, key string) ([]string, bool) {
 		gwNsName := ns + "/" + string(parentRef.Name)
 		gwNsNameList = append(gwNsNameList, gwNsName)
 	}
+	return gwNsNameList
+}
 
-	var svcNsNameList []string
-	for _, rule := range hrObj.Spec.Rules {
-		for _, backendRef := range rule.BackendRefs {
-			ns := namespace
-			if backendRef.Namespace != nil {
-				ns = string(*backendRef.Namespace)
-			}
-			svcNsName := ns + "/" + string(backendRef.Name)
-			svcNsNameList = append(svcNsNameList, svcNsName)
-		}
+func backendRefToSvcNsName(namespace string, backendRef gatewayv1.BackendRef) string {
+	ns := namespace
+	if backendRef.Namespace != nil {
+		ns = string(*backendRef.Namespace)
 	}
+	return ns + "/" + string(backendRef.Name)
+}
 
+func routeChanges(key, routeTypeNsName string, gwNsNameList, svcNsNameList []string) []string {
 	// deletes the services, which are removed, from the gateway <-> service and route <-> service mappings
 	found, oldSvcs := akogatewayapiobjects.GatewayApiLister().GetRouteToService(routeTypeNsName)
 	if found {
@@ -377,8 +538,8 @@ func HTTPRouteChanges(namespace, name, key string) ([]string, bool) {
 		}
 	}
 
-	utils.AviLog.Debugf("key: %s, msg: HTTPRoutes retrieved %s", key, []string{routeTypeNsName})
-	return []string{routeTypeNsName}, true
+	utils.AviLog.Debugf("key: %s, msg: routes retrieved %s", key, []string{routeTypeNsName})
+	return []string{routeTypeNsName}
 }
 
 func ServiceToGateways(namespace, name, key string) ([]string, bool) {
@@ -18,11 +18,14 @@ import (
 	"fmt"
 	"sort"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 )
 
 type RouteModel interface {
@@ -39,6 +42,14 @@ func NewRouteModel(key, objType, name, namespace string) (RouteModel, error) {
 	switch objType {
 	case lib.HTTPRoute:
 		return GetHTTPRouteModel(key, name, namespace)
+	case lib.GRPCRoute:
+		return GetGRPCRouteModel(key, name, namespace)
+	case lib.TLSRoute:
+		return GetTLSRouteModel(key, name, namespace)
+	case lib.TCPRoute:
+		return GetTCPRouteModel(key, name, namespace)
+	case lib.UDPRoute:
+		return GetUDPRouteModel(key, name, namespace)
 	}
 	return nil, fmt.Errorf("object of type %s not supported", objType)
 }
@@ -51,24 +62,57 @@ type HeaderMatch struct {
 
 type PathMatch struct {
 	Path string
-	//Exact, PathPrefix
+	//Exact, PathPrefix, PathSuffix
 	Type string
 }
 
+type QueryParamMatch struct {
+	Type  string
+	Name  string
+	Value string
+}
+
+// The matches are part of the child VS name, hence the fields added later
+// are omitted when empty to retain the names of the existing child VSes.
 type Match struct {
-	PathMatch   *PathMatch
-	HeaderMatch []*HeaderMatch
+	PathMatch       *PathMatch
+	HeaderMatch     []*HeaderMatch
+	QueryParamMatch []*QueryParamMatch `json:",omitempty"`
+	Method          string             `json:",omitempty"`
 }
 
 type Matches []*Match
 
 func (m Matches) Len() int      { return len(m) }
 func (m Matches) Swap(i, j int) { m[i], m[j] = m[j], m[i] }
+
+// Less orders the matches as per the precedence defined by the Gateway API:
+// exact path match, the longest path, method match, the largest number of
+// header matches and the largest number of query param matches.
 func (m Matches) Less(i, j int) bool {
-	if m[i].PathMatch != nil && m[j].PathMatch != nil {
-		return m[i].PathMatch.Path < m[j].PathMatch.Path // TODO: need to check this logic
+	pathI, pathJ := m[i].PathMatch, m[j].PathMatch
+	if pathI == nil {
+		pathI = &PathMatch{Path: "/", Type: "PathPrefix"}
+	}
+	if pathJ == nil {
+		pathJ = &PathMatch{Path: "/", Type: "PathPrefix"}
+	}
+	if (pathI.Type == "Exact") != (pathJ.Type == "Exact") {
+		return pathI.Type == "Exact"
 	}
-	return false
+	if len(pathI.Path) != len(pathJ.Path) {
+		return len(pathI.Path) > len(pathJ.Path)
+	}
+	if (m[i].Method != "") != (m[j].Method != "") {
+		return m[i].Method != ""
+	}
+	if len(m[i].HeaderMatch) != len(m[j].HeaderMatch) {
+		return len(m[i].HeaderMatch) > len(m[j].HeaderMatch)
+	}
+	if len(m[i].QueryParamMatch) != len(m[j].QueryParamMatch) {
+		return len(m[i].QueryParamMatch) > len(m[j].QueryParamMatch)
+	}
+	return pathI.Path < pathJ.Path
 }
 
 type Header struct {
@@ -88,16 +132,36 @@ type HeaderFilter struct {
 	Remove []string
 }
 
+type PathModifier struct {
+	//ReplaceFullPath, ReplacePrefixMatch
+	Type  string
+	Value string
+}
+
 type RedirectFilter struct {
+	Scheme     string
 	Host       string
+	Path       *PathModifier
+	Port       int32
 	StatusCode int32
 }
 
+type RewriteFilter struct {
+	Host string
+	Path *PathModifier
+}
+
+type MirrorFilter struct {
+	Backend *Backend
+}
+
 type Filter struct {
 	Type           string
 	RequestFilter  *HeaderFilter
 	ResponseFilter *HeaderFilter
 	RedirectFilter *RedirectFilter
+	RewriteFilter  *RewriteFilter
+	MirrorFilter   *MirrorFilter
 }
 
 type Backend struct {
@@ -202,6 +266,22 @@ func (hr *httpRoute) ParseRouteRules() *RouteConfig {
 				match.HeaderMatch = append(match.HeaderMatch, headerMatch)
 			}
 
+			// query param match
+			for _, queryParam := range ruleMatch.QueryParams {
+				queryParamMatch := &QueryParamMatch{Type: string(gatewayv1.QueryParamMatchExact)}
+				if queryParam.Type != nil {
+					queryParamMatch.Type = string(*queryParam.Type)
+				}
+				queryParamMatch.Name = string(queryParam.Name)
+				queryParamMatch.Value = queryParam.Value
+				match.QueryParamMatch = append(match.QueryParamMatch, queryParamMatch)
+			}
+
+			// method match
+			if ruleMatch.Method != nil {
+				match.Method = string(*ruleMatch.Method)
+			}
+
 			routeConfigRule.Matches = append(routeConfigRule.Matches, match)
 		}
 		sort.Sort((Matches)(routeConfigRule.Matches))
@@ -213,71 +293,70 @@ func (hr *httpRoute) ParseRouteRules() *RouteConfig {
 
 			// request header filter
 			if ruleFilter.RequestHeaderModifier != nil {
-				filter.RequestFilter = &HeaderFilter{}
-				filter.RequestFilter.Add = make([]*Header, 0, len(ruleFilter.RequestHeaderModifier.Add))
-				for _, addFilter := range ruleFilter.RequestHeaderModifier.Add {
-					addHeader := &Header{
-						Name:  string(addFilter.Name),
-						Value: addFilter.Value,
-					}
-					filter.RequestFilter.Add = append(filter.RequestFilter.Add, addHeader)
-				}
-				filter.RequestFilter.Set = make([]*Header, 0, len(ruleFilter.RequestHeaderModifier.Set))
-				for _, setFilter := range ruleFilter.RequestHeaderModifier.Set {
-					setHeader := &Header{
-						Name:  string(setFilter.Name),
-						Value: setFilter.Value,
-					}
-					filter.RequestFilter.Set = append(filter.RequestFilter.Set, setHeader)
-				}
-				filter.RequestFilter.Remove = make([]string, len(ruleFilter.RequestHeaderModifier.Remove))
-				copy(filter.RequestFilter.Remove, ruleFilter.RequestHeaderModifier.Remove)
-
-				sort.Sort((Headers)(filter.RequestFilter.Add))
-				sort.Sort((Headers)(filter.RequestFilter.Set))
-				sort.Strings(filter.RequestFilter.Remove)
+				filter.RequestFilter = parseHeaderFilter(ruleFilter.RequestHeaderModifier)
 			}
 
 			// response header filter
 			if ruleFilter.ResponseHeaderModifier != nil {
-				filter.ResponseFilter = &HeaderFilter{}
-				filter.ResponseFilter.Add = make([]*Header, 0, len(ruleFilter.ResponseHeaderModifier.Add))
-				for _, addFilter := range ruleFilter.ResponseHeaderModifier.Add {
-					addHeader := &Header{
-						Name:  string(addFilter.Name),
-						Value: addFilter.Value,
-					}
-					filter.ResponseFilter.Add = append(filter.ResponseFilter.Add, addHeader)
-				}
-				filter.ResponseFilter.Set = make([]*Header, 0, len(ruleFilter.ResponseHeaderModifier.Set))
-				for _, setFilter := range ruleFilter.ResponseHeaderModifier.Set {
-					setHeader := &Header{
-						Name:  string(setFilter.Name),
-						Value: setFilter.Value,
-					}
-					filter.ResponseFilter.Set = append(filter.ResponseFilter.Set, setHeader)
-				}
-				filter.ResponseFilter.Remove = make([]string, len(ruleFilter.ResponseHeaderModifier.Remove))
-				copy(filter.ResponseFilter.Remove, ruleFilter.ResponseHeaderModifier.Remove)
-
-				sort.Sort((Headers)(filter.ResponseFilter.Add))
-				sort.Sort((Headers)(filter.ResponseFilter.Set))
-				sort.Strings(filter.ResponseFilter.Remove)
+				filter.ResponseFilter = parseHeaderFilter(ruleFilter.ResponseHeaderModifier)
 			}
 
 			// request redirect filter
 			if ruleFilter.RequestRedirect != nil {
 				filter.RedirectFilter = &RedirectFilter{}
+				if ruleFilter.RequestRedirect.Scheme != nil {
+					filter.RedirectFilter.Scheme = *ruleFilter.RequestRedirect.Scheme
+				}
 				if ruleFilter.RequestRedirect.Hostname != nil {
 					filter.RedirectFilter.Host = string(*ruleFilter.RequestRedirect.Hostname)
 				}
+				if ruleFilter.RequestRedirect.Path != nil {
+					filter.RedirectFilter.Path = parsePathModifier(ruleFilter.RequestRedirect.Path)
+				}
+				if ruleFilter.RequestRedirect.Port != nil {
+					filter.RedirectFilter.Port = int32(*ruleFilter.RequestRedirect.Port)
+				}
 				if ruleFilter.RequestRedirect.StatusCode != nil {
 					filter.RedirectFilter.StatusCode = int32(*ruleFilter.RequestRedirect.StatusCode)
 				}
 			}
+
+			// url rewrite filter
+			if ruleFilter.URLRewrite != nil {
+				filter.RewriteFilter = &RewriteFilter{}
+				if ruleFilter.URLRewrite.Hostname != nil {
+					filter.RewriteFilter.Host = string(*ruleFilter.URLRewrite.Hostname)
+				}
+				if ruleFilter.URLRewrite.Path != nil {
+					filter.RewriteFilter.Path = parsePathModifier(ruleFilter.URLRewrite.Path)
+				}
+			}
+
+			// request mirror filter
+			if ruleFilter.RequestMirror != nil &&
+				akogatewayapilib.IsBackendRefAllowed(lib.HTTPRoute, hr.namespace, ruleFilter.RequestMirror.BackendRef) {
+				backendRef := ruleFilter.RequestMirror.BackendRef
+				backend := &Backend{
+					Name:      string(backendRef.Name),
+					Namespace: hr.namespace,
+					Weight:    1,
+				}
+				if backendRef.Namespace != nil {
+					backend.Namespace = string(*backendRef.Namespace)
+				}
+				if backendRef.Port != nil {
+					backend.Port = int32(*backendRef.Port)
+				}
+				filter.MirrorFilter = &MirrorFilter{Backend: backend}
+			}
 			routeConfigRule.Filters = append(routeConfigRule.Filters, filter)
 		}
 		for _, ruleBackend := range rule.BackendRefs {
+			// the backends in the other namespaces are honoured only when a ReferenceGrant permits them
+			if !akogatewayapilib.IsBackendRefAllowed(lib.HTTPRoute, hr.namespace, ruleBackend.BackendObjectReference) {
+				utils.AviLog.Warnf("key: %s, msg: reference to the backend %s/%s is not permitted", hr.key, *ruleBackend.Namespace, ruleBackend.Name)
+				continue
+			}
 			backend := &Backend{}
 			backend.Name = string(ruleBackend.Name)
 			if ruleBackend.Namespace != nil {
@@ -301,6 +380,48 @@ func (hr *httpRoute) ParseRouteRules() *RouteConfig {
 	return hr.routeConfig
 }
 
+func parsePathModifier(pathModifier *gatewayv1.HTTPPathModifier) *PathModifier {
+	modifier := &PathModifier{Type: string(pathModifier.Type)}
+	switch pathModifier.Type {
+	case gatewayv1.FullPathHTTPPathModifier:
+		if pathModifier.ReplaceFullPath != nil {
+			modifier.Value = *pathModifier.ReplaceFullPath
+		}
+	case gatewayv1.PrefixMatchHTTPPathModifier:
+		if pathModifier.ReplacePrefixMatch != nil {
+			modifier.Value = *pathModifier.ReplacePrefixMatch
+		}
+	}
+	return modifier
+}
+
+func parseHeaderFilter(headerModifier *gatewayv1.HTTPHeaderFilter) *HeaderFilter {
+	headerFilter := &HeaderFilter{}
+	headerFilter.Add = make([]*Header, 0, len(headerModifier.Add))
+	for _, addFilter := range headerModifier.Add {
+		addHeader := &Header{
+			Name:  string(addFilter.Name),
+			Value: addFilter.Value,
+		}
+		headerFilter.Add = append(headerFilter.Add, addHeader)
+	}
+	headerFilter.Set = make([]*Header, 0, len(headerModifier.Set))
+	for _, setFilter := range headerModifier.Set {
+		setHeader := &Header{
+			Name:  string(setFilter.Name),
+			Value: setFilter.Value,
+		}
+		headerFilter.Set = append(headerFilter.Set, setHeader)
+	}
+	headerFilter.Remove = make([]string, len(headerModifier.Remove))
+	copy(headerFilter.Remove, headerModifier.Remove)
+
+	sort.Sort((Headers)(headerFilter.Add))
+	sort.Sort((Headers)(headerFilter.Set))
+	sort.Strings(headerFilter.Remove)
+	return headerFilter
+}
+
 func (hr *httpRoute) Exists() bool {
 	return hr != nil
 }
@@ -316,3 +437,357 @@ func (hr *httpRoute) GetParents() sets.Set[string] {
 	}
 	return parents
 }
+
+type grpcRoute struct {
+	key         string
+	name        string
+	namespace   string
+	routeConfig *RouteConfig
+	spec        *gatewayv1alpha2.GRPCRouteSpec
+}
+
+func GetGRPCRouteModel(key string, name, namespace string) (RouteModel, error) {
+	gr := &grpcRoute{
+		key:       key,
+		name:      name,
+		namespace: namespace,
+	}
+
+	grObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer.Lister().GRPCRoutes(namespace).Get(name)
+	if err != nil {
+		return gr, err
+	}
+	gr.spec = grObj.Spec.DeepCopy()
+	return gr, nil
+}
+
+func (gr *grpcRoute) GetName() string {
+	return gr.name
+}
+
+func (gr *grpcRoute) GetNamespace() string {
+	return gr.namespace
+}
+
+func (gr *grpcRoute) GetType() string {
+	return lib.GRPCRoute
+}
+
+func (gr *grpcRoute) GetSpec() interface{} {
+	return gr.spec
+}
+
+// ParseRouteRules converts the GRPCRoute rules to the route config. gRPC requests
+// are HTTP/2 POSTs to /<service>/<method>, hence the method match is translated
+// to a path match, an exact one when both the service and the method are present.
+func (gr *grpcRoute) ParseRouteRules() *RouteConfig {
+	if gr.routeConfig != nil {
+		return gr.routeConfig
+	}
+	routeConfig := &RouteConfig{}
+
+	routeConfig.Hosts = make([]string, len(gr.spec.Hostnames))
+	for i := range gr.spec.Hostnames {
+		routeConfig.Hosts[i] = string(gr.spec.Hostnames[i])
+	}
+
+	routeConfig.Rules = make([]*Rule, 0, len(gr.spec.Rules))
+	for _, rule := range gr.spec.Rules {
+		routeConfigRule := &Rule{}
+		routeConfigRule.Matches = make([]*Match, 0, len(rule.Matches))
+		for _, ruleMatch := range rule.Matches {
+			match := &Match{}
+
+			// method match
+			match.PathMatch = &PathMatch{Path: "/", Type: "PathPrefix"}
+			if ruleMatch.Method != nil {
+				var service, method string
+				if ruleMatch.Method.Service != nil {
+					service = *ruleMatch.Method.Service
+				}
+				if ruleMatch.Method.Method != nil {
+					method = *ruleMatch.Method.Method
+				}
+				if service != "" && method != "" {
+					match.PathMatch.Path = "/" + service + "/" + method
+					match.PathMatch.Type = "Exact"
+				} else if service != "" {
+					match.PathMatch.Path = "/" + service + "/"
+				} else if method != "" {
+					match.PathMatch.Path = "/" + method
+					match.PathMatch.Type = "PathSuffix"
+				}
+			}
+
+			// header match
+			match.HeaderMatch = make([]*HeaderMatch, 0, len(ruleMatch.Headers))
+			for _, header := range ruleMatch.Headers {
+				headerMatch := &HeaderMatch{}
+				if header.Type != nil {
+					headerMatch.Type = string(*header.Type)
+				}
+				headerMatch.Name = string(header.Name)
+				headerMatch.Value = header.Value
+				match.HeaderMatch = append(match.HeaderMatch, headerMatch)
+			}
+
+			routeConfigRule.Matches = append(routeConfigRule.Matches, match)
+		}
+		// a rule without matches matches all the gRPC requests
+		if len(routeConfigRule.Matches) == 0 {
+			routeConfigRule.Matches = append(routeConfigRule.Matches, &Match{
+				PathMatch:   &PathMatch{Path: "/", Type: "PathPrefix"},
+				HeaderMatch: []*HeaderMatch{},
+			})
+		}
+		sort.Sort((Matches)(routeConfigRule.Matches))
+
+		routeConfigRule.Filters = make([]*Filter, 0, len(rule.Filters))
+		for _, ruleFilter := range rule.Filters {
+			filter := &Filter{}
+			filter.Type = string(ruleFilter.Type)
+			if ruleFilter.RequestHeaderModifier != nil {
+				filter.RequestFilter = parseHeaderFilter(ruleFilter.RequestHeaderModifier)
+			}
+			if ruleFilter.ResponseHeaderModifier != nil {
+				filter.ResponseFilter = parseHeaderFilter(ruleFilter.ResponseHeaderModifier)
+			}
+			routeConfigRule.Filters = append(routeConfigRule.Filters, filter)
+		}
+
+		backendRefs := make([]gatewayv1.BackendRef, 0, len(rule.BackendRefs))
+		for _, backendRef := range rule.BackendRefs {
+			backendRefs = append(backendRefs, backendRef.BackendRef)
+		}
+		routeConfigRule.Backends = parseBackendRefs(gr.key, lib.GRPCRoute, gr.namespace, backendRefs)
+		routeConfig.Rules = append(routeConfig.Rules, routeConfigRule)
+	}
+	gr.routeConfig = routeConfig
+	return gr.routeConfig
+}
+
+func (gr *grpcRoute) Exists() bool {
+	return gr != nil
+}
+
+func (gr *grpcRoute) GetParents() sets.Set[string] {
+	parents := sets.New[string]()
+	for _, ref := range gr.spec.ParentRefs {
+		namespace := gr.namespace
+		if ref.Namespace != nil {
+			namespace = string(*ref.Namespace)
+		}
+		parents.Insert(namespace + "/" + string(ref.Name))
+	}
+	return parents
+}
+
+type tlsRoute struct {
+	key         string
+	name        string
+	namespace   string
+	routeConfig *RouteConfig
+	spec        *gatewayv1alpha2.TLSRouteSpec
+}
+
+func GetTLSRouteModel(key string, name, namespace string) (RouteModel, error) {
+	tr := &tlsRoute{
+		key:       key,
+		name:      name,
+		namespace: namespace,
+	}
+
+	trObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().TLSRouteInformer.Lister().TLSRoutes(namespace).Get(name)
+	if err != nil {
+		return tr, err
+	}
+	tr.spec = trObj.Spec.DeepCopy()
+	return tr, nil
+}
+
+func (tr *tlsRoute) GetName() string {
+	return tr.name
+}
+
+func (tr *tlsRoute) GetNamespace() string {
+	return tr.namespace
+}
+
+func (tr *tlsRoute) GetType() string {
+	return lib.TLSRoute
+}
+
+func (tr *tlsRoute) GetSpec() interface{} {
+	return tr.spec
+}
+
+// ParseRouteRules for a TLSRoute returns rules without any matches, the
+// backends of the route are selected using the SNI of the request.
+func (tr *tlsRoute) ParseRouteRules() *RouteConfig {
+	if tr.routeConfig != nil {
+		return tr.routeConfig
+	}
+	routeConfig := &RouteConfig{}
+
+	routeConfig.Hosts = make([]string, len(tr.spec.Hostnames))
+	for i := range tr.spec.Hostnames {
+		routeConfig.Hosts[i] = string(tr.spec.Hostnames[i])
+	}
+
+	for _, rule := range tr.spec.Rules {
+		routeConfigRule := &Rule{}
+		routeConfigRule.Backends = parseBackendRefs(tr.key, lib.TLSRoute, tr.namespace, rule.BackendRefs)
+		routeConfig.Rules = append(routeConfig.Rules, routeConfigRule)
+	}
+	tr.routeConfig = routeConfig
+	return tr.routeConfig
+}
+
+// parseBackendRefs parses the backends of the route kinds, which do not have filters within backendRefs.
+// The backends in the other namespaces are honoured only when a ReferenceGrant permits them.
+func parseBackendRefs(key, routeType, namespace string, backendRefs []gatewayv1.BackendRef) []*Backend {
+	var backends []*Backend
+	for _, ruleBackend := range backendRefs {
+		if !akogatewayapilib.IsBackendRefAllowed(routeType, namespace, ruleBackend.BackendObjectReference) {
+			utils.AviLog.Warnf("key: %s, msg: reference to the backend %s/%s is not permitted", key, *ruleBackend.Namespace, ruleBackend.Name)
+			continue
+		}
+		backend := &Backend{}
+		backend.Name = string(ruleBackend.Name)
+		if ruleBackend.Namespace != nil {
+			backend.Namespace = string(*ruleBackend.Namespace)
+		} else {
+			backend.Namespace = namespace
+		}
+		if ruleBackend.Port != nil {
+			//Default 0
+			backend.Port = int32(*ruleBackend.Port)
+		}
+		backend.Weight = 1
+		if ruleBackend.Weight != nil {
+			backend.Weight = *ruleBackend.Weight
+		}
+		backends = append(backends, backend)
+	}
+	return backends
+}
+
+func (tr *tlsRoute) Exists() bool {
+	return tr != nil
+}
+
+func (tr *tlsRoute) GetParents() sets.Set[string] {
+	parents := sets.New[string]()
+	for _, ref := range tr.spec.ParentRefs {
+		namespace := tr.namespace
+		if ref.Namespace != nil {
+			namespace = string(*ref.Namespace)
+		}
+		parents.Insert(namespace + "/" + string(ref.Name))
+	}
+	return parents
+}
+
+// l4Route is the model for the TCPRoute and UDPRoute objects. These routes do not
+// have any matches, all the traffic of the listener is sent to the backends of the route.
+type l4Route struct {
+	key               string
+	name              string
+	namespace         string
+	routeType         string
+	creationTimestamp metav1.Time
+	parentRefs        []gatewayv1.ParentReference
+	rules             [][]gatewayv1.BackendRef
+	routeConfig       *RouteConfig
+	spec              interface{}
+}
+
+func GetTCPRouteModel(key string, name, namespace string) (RouteModel, error) {
+	tr := &l4Route{
+		key:       key,
+		name:      name,
+		namespace: namespace,
+		routeType: lib.TCPRoute,
+	}
+
+	trObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().TCPRouteInformer.Lister().TCPRoutes(namespace).Get(name)
+	if err != nil {
+		return tr, err
+	}
+	spec := trObj.Spec.DeepCopy()
+	tr.spec = spec
+	tr.creationTimestamp = trObj.CreationTimestamp
+	tr.parentRefs = spec.ParentRefs
+	for _, rule := range spec.Rules {
+		tr.rules = append(tr.rules, rule.BackendRefs)
+	}
+	return tr, nil
+}
+
+func GetUDPRouteModel(key string, name, namespace string) (RouteModel, error) {
+	ur := &l4Route{
+		key:       key,
+		name:      name,
+		namespace: namespace,
+		routeType: lib.UDPRoute,
+	}
+
+	urObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().UDPRouteInformer.Lister().UDPRoutes(namespace).Get(name)
+	if err != nil {
+		return ur, err
+	}
+	spec := urObj.Spec.DeepCopy()
+	ur.spec = spec
+	ur.creationTimestamp = urObj.CreationTimestamp
+	ur.parentRefs = spec.ParentRefs
+	for _, rule := range spec.Rules {
+		ur.rules = append(ur.rules, rule.BackendRefs)
+	}
+	return ur, nil
+}
+
+func (lr *l4Route) GetName() string {
+	return lr.name
+}
+
+func (lr *l4Route) GetNamespace() string {
+	return lr.namespace
+}
+
+func (lr *l4Route) GetType() string {
+	return lr.routeType
+}
+
+func (lr *l4Route) GetSpec() interface{} {
+	return lr.spec
+}
+
+func (lr *l4Route) ParseRouteRules() *RouteConfig {
+	if lr.routeConfig != nil {
+		return lr.routeConfig
+	}
+	routeConfig := &RouteConfig{}
+	for _, backendRefs := range lr.rules {
+		routeConfigRule := &Rule{}
+		routeConfigRule.Backends = parseBackendRefs(lr.key, lr.routeType, lr.namespace, backendRefs)
+		routeConfig.Rules = append(routeConfig.Rules, routeConfigRule)
+	}
+	lr.routeConfig = routeConfig
+	return lr.routeConfig
+}
+
+func (lr *l4Route) Exists() bool {
+	return lr != nil
+}
+
+func (lr *l4Route) GetParents() sets.Set[string] {
+	parents := sets.New[string]()
+	for _, ref := range lr.parentRefs {
+		namespace := lr.namespace
+		if ref.Namespace != nil {
+			namespace = string(*ref.Namespace)
+		}
+		parents.Insert(namespace + "/" + string(ref.Name))
+	}
+	return parents
+}
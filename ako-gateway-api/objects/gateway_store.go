@@ -44,6 +44,8 @@ func GatewayApiLister() *GWLister {
 			gatewayToHostnameStore:         objects.NewObjectMapStore(),
 			gatewayListenerToHostnameStore: objects.NewObjectMapStore(),
 			gatewayRouteToHostnameStore:    objects.NewObjectMapStore(),
+			referenceGrantStore:            objects.NewObjectMapStore(),
+			namespaceToReferenceGrant:      objects.NewObjectMapStore(),
 		}
 	})
 	return gwLister
@@ -102,6 +104,12 @@ type GWLister struct {
 	//FQDNs in parent VS
 	//gatewayns/gatewayname -> [hostname, ...]
 	gatewayRouteToHostnameStore *objects.ObjectMapStore
+
+	// grantNs/grantName -> ReferenceGrantStore
+	referenceGrantStore *objects.ObjectMapStore
+
+	// namespace -> [grantNs/grantName, ...]
+	namespaceToReferenceGrant *objects.ObjectMapStore
 }
 
 type GatewayRouteKind struct {
@@ -118,6 +126,28 @@ type GatewayListenerStore struct {
 	AllowedRouteTypes []GatewayRouteKind
 }
 
+// ReferenceGrantFrom is a from entry of a ReferenceGrant, the kind of the referencing
+// object and the namespace it belongs to.
+type ReferenceGrantFrom struct {
+	Group     string
+	Kind      string
+	Namespace string
+}
+
+// ReferenceGrantTo is a to entry of a ReferenceGrant, the kind of the referenced object
+// in the namespace of the ReferenceGrant. Name is empty when all the objects of the kind
+// can be referenced.
+type ReferenceGrantTo struct {
+	Group string
+	Kind  string
+	Name  string
+}
+
+type ReferenceGrantStore struct {
+	From []ReferenceGrantFrom
+	To   []ReferenceGrantTo
+}
+
 func (g *GWLister) IsGatewayClassControllerAKO(gwClass string) (bool, bool) {
 	g.gwLock.RLock()
 	defer g.gwLock.RUnlock()
@@ -810,3 +840,70 @@ func (g *GWLister) GetGatewayRouteToHostname(gwNsName string) (bool, []string) {
 	}
 	return false, []string{}
 }
+
+//=====All ReferenceGrant mappings go here.
+
+func (g *GWLister) UpdateReferenceGrant(grantNsName string, grant ReferenceGrantStore) {
+	g.gwLock.Lock()
+	defer g.gwLock.Unlock()
+
+	namespace, _ := utils.ExtractNamespaceObjectName(grantNsName)
+	g.referenceGrantStore.AddOrUpdate(grantNsName, grant)
+	_, grantListObj := g.namespaceToReferenceGrant.Get(namespace)
+	grantList, _ := grantListObj.([]string)
+	if !utils.HasElem(grantList, grantNsName) {
+		grantList = append(grantList, grantNsName)
+		g.namespaceToReferenceGrant.AddOrUpdate(namespace, grantList)
+	}
+}
+
+func (g *GWLister) DeleteReferenceGrant(grantNsName string) {
+	g.gwLock.Lock()
+	defer g.gwLock.Unlock()
+
+	namespace, _ := utils.ExtractNamespaceObjectName(grantNsName)
+	g.referenceGrantStore.Delete(grantNsName)
+	found, grantListObj := g.namespaceToReferenceGrant.Get(namespace)
+	if !found {
+		return
+	}
+	grantList := utils.Remove(grantListObj.([]string), grantNsName)
+	if len(grantList) == 0 {
+		g.namespaceToReferenceGrant.Delete(namespace)
+		return
+	}
+	g.namespaceToReferenceGrant.AddOrUpdate(namespace, grantList)
+}
+
+// IsReferenceAllowed returns true when the object described by from can reference the object described
+// by to in the namespace toNamespace. The references within a namespace are always allowed, the references
+// across the namespaces are allowed only when a ReferenceGrant in toNamespace permits them.
+func (g *GWLister) IsReferenceAllowed(from ReferenceGrantFrom, toNamespace string, to ReferenceGrantTo) bool {
+	if from.Namespace == toNamespace {
+		return true
+	}
+
+	g.gwLock.RLock()
+	defer g.gwLock.RUnlock()
+
+	found, grantListObj := g.namespaceToReferenceGrant.Get(toNamespace)
+	if !found {
+		return false
+	}
+	for _, grantNsName := range grantListObj.([]string) {
+		found, grantObj := g.referenceGrantStore.Get(grantNsName)
+		if !found {
+			continue
+		}
+		grant := grantObj.(ReferenceGrantStore)
+		if !utils.HasElem(grant.From, from) {
+			continue
+		}
+		for _, grantTo := range grant.To {
+			if grantTo.Group == to.Group && grantTo.Kind == to.Kind && (grantTo.Name == "" || grantTo.Name == to.Name) {
+				return true
+			}
+		}
+	}
+	return false
+}
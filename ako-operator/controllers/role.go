@@ -107,6 +107,11 @@ func BuildClusterrole(ako akov1alpha1.AKOConfig, r *AKOConfigReconciler, log log
 				Resources: []string{"ingressclasses"},
 				Verbs:     []string{"get", "watch", "list"},
 			},
+			{
+				APIGroups: []string{"discovery.k8s.io"},
+				Resources: []string{"endpointslices"},
+				Verbs:     []string{"get", "watch", "list"},
+			},
 			{
 				APIGroups: []string{""},
 				Resources: []string{"services", "services/status"},
@@ -159,7 +164,7 @@ func BuildClusterrole(ako akov1alpha1.AKOConfig, r *AKOConfigReconciler, log log
 			},
 			{
 				APIGroups: []string{"gateway.networking.k8s.io"},
-				Resources: []string{"gatewayclasses", "gatewayclasses/status", "gateways", "gateways/status", "httproutes", "httproutes/status"},
+				Resources: []string{"gatewayclasses", "gatewayclasses/status", "gateways", "gateways/status", "httproutes", "httproutes/status", "tlsroutes", "tlsroutes/status", "tcproutes", "tcproutes/status", "udproutes", "udproutes/status", "grpcroutes", "grpcroutes/status", "referencegrants"},
 				Verbs:     []string{"get", "watch", "list", "patch", "update"},
 			},
 		},
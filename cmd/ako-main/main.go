@@ -28,8 +28,10 @@ import (
 	"github.com/go-logr/logr"
 
 	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/debugapi"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/webhook"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
 	crd "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned"
@@ -69,9 +71,14 @@ func InitializeAKOApi() {
 	if lib.IsPrometheusEnabled() {
 		lib.SetPrometheusRegistry()
 	}
-	akoApi := api.NewServer(lib.GetAkoApiServerPort(), []models.ApiModel{}, lib.IsPrometheusEnabled(), lib.GetPrometheusRegistry())
+	akoApi := api.NewServer(lib.GetAkoApiServerPort(), []models.ApiModel{&debugapi.DebugModel{}}, lib.IsPrometheusEnabled(), lib.GetPrometheusRegistry())
 	akoApi.InitApi()
 	lib.SetApiServerInstance(akoApi)
+
+	if lib.IsCRDWebhookEnabled() {
+		crdWebhook := webhook.NewServer(lib.GetCRDWebhookPort(), lib.GetCRDWebhookCertDir())
+		crdWebhook.InitWebhook()
+	}
 }
 
 func InitializeAKC() {
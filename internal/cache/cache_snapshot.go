@@ -0,0 +1,201 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// CacheSnapshot is a serializable copy of the Avi object cache. A snapshot saved from a running
+// AKO can be loaded into an empty cache, so that the rest operations for a set of models can be
+// computed against it without a controller.
+type CacheSnapshot struct {
+	VirtualServices         []*AviVsCache                    `json:"virtualservices"`
+	VSVips                  []*AviVSVIPCache                 `json:"vsvips"`
+	PoolGroups              []*AviPGCache                    `json:"poolgroups"`
+	Pools                   []*AviPoolCache                  `json:"pools"`
+	HTTPPolicySets          []*AviHTTPPolicyCache            `json:"httppolicysets"`
+	DataScripts             []*AviDSCache                    `json:"datascripts"`
+	SSLKeyCerts             []*AviSSLCache                   `json:"sslkeycerts"`
+	PKIProfiles             []*AviPkiProfileCache            `json:"pkiprofiles"`
+	PersistenceProfiles     []*AviPersistenceProfileCache    `json:"persistenceprofiles"`
+	StringGroups            []*AviStringGroupCache           `json:"stringgroups"`
+	L4PolicySets            []*AviL4PolicyCache              `json:"l4policysets"`
+	TrafficCloneProfiles    []*AviTrafficCloneProfileCache   `json:"trafficcloneprofiles"`
+	NetworkSecurityPolicies []*AviNetworkSecurityPolicyCache `json:"networksecuritypolicies"`
+	VrfContexts             []*AviVrfCache                   `json:"vrfcontexts"`
+}
+
+// sortedValues returns the entries of the cache ordered by their keys, so that the snapshots
+// of the same cache are identical.
+func sortedValues(c *AviCache) []interface{} {
+	entries := c.ShallowCopy()
+	keys := make([]string, 0, len(entries))
+	values := make(map[string]interface{}, len(entries))
+	for k, v := range entries {
+		key := fmt.Sprint(k)
+		keys = append(keys, key)
+		values[key] = v
+	}
+	sort.Strings(keys)
+	sorted := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		sorted = append(sorted, values[key])
+	}
+	return sorted
+}
+
+// Snapshot returns a copy of the entries of the cache.
+func (c *AviObjCache) Snapshot() *CacheSnapshot {
+	s := &CacheSnapshot{}
+	for _, val := range sortedValues(c.VsCacheMeta) {
+		if vs, ok := val.(*AviVsCache); ok {
+			if vsCopy, ok := vs.GetVSCopy(); ok {
+				s.VirtualServices = append(s.VirtualServices, vsCopy)
+			}
+		}
+	}
+	for _, val := range sortedValues(c.VSVIPCache) {
+		if obj, ok := val.(*AviVSVIPCache); ok {
+			s.VSVips = append(s.VSVips, obj)
+		}
+	}
+	for _, val := range sortedValues(c.PgCache) {
+		if obj, ok := val.(*AviPGCache); ok {
+			s.PoolGroups = append(s.PoolGroups, obj)
+		}
+	}
+	for _, val := range sortedValues(c.PoolCache) {
+		if obj, ok := val.(*AviPoolCache); ok {
+			s.Pools = append(s.Pools, obj)
+		}
+	}
+	for _, val := range sortedValues(c.HTTPPolicyCache) {
+		if obj, ok := val.(*AviHTTPPolicyCache); ok {
+			s.HTTPPolicySets = append(s.HTTPPolicySets, obj)
+		}
+	}
+	for _, val := range sortedValues(c.DSCache) {
+		if obj, ok := val.(*AviDSCache); ok {
+			s.DataScripts = append(s.DataScripts, obj)
+		}
+	}
+	for _, val := range sortedValues(c.SSLKeyCache) {
+		if obj, ok := val.(*AviSSLCache); ok {
+			s.SSLKeyCerts = append(s.SSLKeyCerts, obj)
+		}
+	}
+	for _, val := range sortedValues(c.PKIProfileCache) {
+		if obj, ok := val.(*AviPkiProfileCache); ok {
+			s.PKIProfiles = append(s.PKIProfiles, obj)
+		}
+	}
+	for _, val := range sortedValues(c.PersistenceProfileCache) {
+		if obj, ok := val.(*AviPersistenceProfileCache); ok {
+			s.PersistenceProfiles = append(s.PersistenceProfiles, obj)
+		}
+	}
+	for _, val := range sortedValues(c.StringGroupCache) {
+		if obj, ok := val.(*AviStringGroupCache); ok {
+			s.StringGroups = append(s.StringGroups, obj)
+		}
+	}
+	for _, val := range sortedValues(c.L4PolicyCache) {
+		if obj, ok := val.(*AviL4PolicyCache); ok {
+			s.L4PolicySets = append(s.L4PolicySets, obj)
+		}
+	}
+	for _, val := range sortedValues(c.TrafficCloneProfileCache) {
+		if obj, ok := val.(*AviTrafficCloneProfileCache); ok {
+			s.TrafficCloneProfiles = append(s.TrafficCloneProfiles, obj)
+		}
+	}
+	for _, val := range sortedValues(c.NetworkSecurityPolicyCache) {
+		if obj, ok := val.(*AviNetworkSecurityPolicyCache); ok {
+			s.NetworkSecurityPolicies = append(s.NetworkSecurityPolicies, obj)
+		}
+	}
+	for _, val := range sortedValues(c.VrfCache) {
+		if obj, ok := val.(*AviVrfCache); ok {
+			s.VrfContexts = append(s.VrfContexts, obj)
+		}
+	}
+	return s
+}
+
+// LoadSnapshot adds the entries of the snapshot to the cache, with the same keys with which
+// they are added when the cache is populated from the controller.
+func (c *AviObjCache) LoadSnapshot(s *CacheSnapshot) {
+	for _, obj := range s.VirtualServices {
+		c.VsCacheMeta.AviCacheAdd(NamespaceName{Namespace: obj.Tenant, Name: obj.Name}, obj)
+	}
+	for _, obj := range s.VSVips {
+		c.VSVIPCache.AviCacheAdd(NamespaceName{Namespace: obj.Tenant, Name: obj.Name}, obj)
+	}
+	for _, obj := range s.PoolGroups {
+		c.PgCache.AviCacheAdd(NamespaceName{Namespace: obj.Tenant, Name: obj.Name}, obj)
+	}
+	for _, obj := range s.Pools {
+		c.PoolCache.AviCacheAdd(NamespaceName{Namespace: obj.Tenant, Name: obj.Name}, obj)
+	}
+	for _, obj := range s.HTTPPolicySets {
+		c.HTTPPolicyCache.AviCacheAdd(NamespaceName{Namespace: obj.Tenant, Name: obj.Name}, obj)
+	}
+	for _, obj := range s.DataScripts {
+		c.DSCache.AviCacheAdd(NamespaceName{Namespace: obj.Tenant, Name: obj.Name}, obj)
+	}
+	for _, obj := range s.SSLKeyCerts {
+		c.SSLKeyCache.AviCacheAdd(NamespaceName{Namespace: obj.Tenant, Name: obj.Name}, obj)
+	}
+	for _, obj := range s.PKIProfiles {
+		c.PKIProfileCache.AviCacheAdd(NamespaceName{Namespace: obj.Tenant, Name: obj.Name}, obj)
+	}
+	for _, obj := range s.PersistenceProfiles {
+		c.PersistenceProfileCache.AviCacheAdd(NamespaceName{Namespace: obj.Tenant, Name: obj.Name}, obj)
+	}
+	for _, obj := range s.StringGroups {
+		c.StringGroupCache.AviCacheAdd(NamespaceName{Namespace: obj.Tenant, Name: obj.Name}, obj)
+	}
+	for _, obj := range s.L4PolicySets {
+		c.L4PolicyCache.AviCacheAdd(NamespaceName{Namespace: obj.Tenant, Name: obj.Name}, obj)
+	}
+	for _, obj := range s.TrafficCloneProfiles {
+		c.TrafficCloneProfileCache.AviCacheAdd(NamespaceName{Namespace: obj.Tenant, Name: obj.Name}, obj)
+	}
+	for _, obj := range s.NetworkSecurityPolicies {
+		c.NetworkSecurityPolicyCache.AviCacheAdd(NamespaceName{Namespace: obj.Tenant, Name: obj.Name}, obj)
+	}
+	for _, obj := range s.VrfContexts {
+		c.VrfCache.AviCacheAdd(obj.Name, obj)
+	}
+}
+
+// ReadCacheSnapshot returns a new cache holding the entries of the snapshot saved in the file.
+func ReadCacheSnapshot(path string) (*AviObjCache, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s CacheSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid cache snapshot %s: %v", path, err)
+	}
+	c := NewAviObjCache()
+	c.LoadSnapshot(&s)
+	return c, nil
+}
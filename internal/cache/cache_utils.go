@@ -32,15 +32,17 @@ type NamespaceName struct {
  */
 
 type AviPoolCache struct {
-	Name                 string
-	Tenant               string
-	Uuid                 string
-	CloudConfigCksum     string
-	ServiceMetadataObj   lib.ServiceMetadataObj
-	PkiProfileCollection NamespaceName
-	LastModified         string
-	InvalidData          bool
-	HasReference         bool
+	Name                         string
+	Tenant                       string
+	Uuid                         string
+	CloudConfigCksum             string
+	FieldsCksum                  string
+	ServiceMetadataObj           lib.ServiceMetadataObj
+	PkiProfileCollection         NamespaceName
+	PersistenceProfileCollection NamespaceName
+	LastModified                 string
+	InvalidData                  bool
+	HasReference                 bool
 }
 
 type AviDSCache struct {
@@ -67,26 +69,29 @@ type AviClusterRuntimeCache struct {
 }
 
 type AviVsCache struct {
-	Name                 string
-	Tenant               string
-	Uuid                 string
-	CloudConfigCksum     string
-	PGKeyCollection      []NamespaceName
-	VSVipKeyCollection   []NamespaceName
-	PoolKeyCollection    []NamespaceName
-	DSKeyCollection      []NamespaceName
-	HTTPKeyCollection    []NamespaceName
-	SSLKeyCertCollection []NamespaceName
-	L4PolicyCollection   []NamespaceName
-	SNIChildCollection   []string
-	ParentVSRef          NamespaceName
-	PassthroughParentRef NamespaceName
-	PassthroughChildRef  NamespaceName
-	ServiceMetadataObj   lib.ServiceMetadataObj
-	LastModified         string
-	EnableRhi            bool
-	InvalidData          bool
-	VSCacheLock          sync.RWMutex
+	Name                            string
+	Tenant                          string
+	Uuid                            string
+	CloudConfigCksum                string
+	FieldsCksum                     string
+	PGKeyCollection                 []NamespaceName
+	VSVipKeyCollection              []NamespaceName
+	PoolKeyCollection               []NamespaceName
+	DSKeyCollection                 []NamespaceName
+	HTTPKeyCollection               []NamespaceName
+	SSLKeyCertCollection            []NamespaceName
+	L4PolicyCollection              []NamespaceName
+	TrafficCloneProfileCollection   []NamespaceName
+	NetworkSecurityPolicyCollection []NamespaceName
+	SNIChildCollection              []string
+	ParentVSRef                     NamespaceName
+	PassthroughParentRef            NamespaceName
+	PassthroughChildRef             NamespaceName
+	ServiceMetadataObj              lib.ServiceMetadataObj
+	LastModified                    string
+	EnableRhi                       bool
+	InvalidData                     bool
+	VSCacheLock                     sync.RWMutex
 }
 
 func (c *AviCache) AviCacheAddVS(k NamespaceName) *AviVsCache {
@@ -247,6 +252,38 @@ func (v *AviVsCache) RemoveFromL4PolicyCollection(k NamespaceName) {
 	v.L4PolicyCollection = RemoveNamespaceName(v.L4PolicyCollection, k)
 }
 
+func (v *AviVsCache) AddToTrafficCloneProfileCollection(k NamespaceName) {
+	if v.TrafficCloneProfileCollection == nil {
+		v.TrafficCloneProfileCollection = []NamespaceName{k}
+	}
+	if !utils.HasElem(v.TrafficCloneProfileCollection, k) {
+		v.TrafficCloneProfileCollection = append(v.TrafficCloneProfileCollection, k)
+	}
+}
+
+func (v *AviVsCache) RemoveFromTrafficCloneProfileCollection(k NamespaceName) {
+	if v.TrafficCloneProfileCollection == nil {
+		return
+	}
+	v.TrafficCloneProfileCollection = RemoveNamespaceName(v.TrafficCloneProfileCollection, k)
+}
+
+func (v *AviVsCache) AddToNetworkSecurityPolicyCollection(k NamespaceName) {
+	if v.NetworkSecurityPolicyCollection == nil {
+		v.NetworkSecurityPolicyCollection = []NamespaceName{k}
+	}
+	if !utils.HasElem(v.NetworkSecurityPolicyCollection, k) {
+		v.NetworkSecurityPolicyCollection = append(v.NetworkSecurityPolicyCollection, k)
+	}
+}
+
+func (v *AviVsCache) RemoveFromNetworkSecurityPolicyCollection(k NamespaceName) {
+	if v.NetworkSecurityPolicyCollection == nil {
+		return
+	}
+	v.NetworkSecurityPolicyCollection = RemoveNamespaceName(v.NetworkSecurityPolicyCollection, k)
+}
+
 func (v *AviVsCache) AddToSNIChildCollection(k string) {
 	if v.SNIChildCollection == nil {
 		v.SNIChildCollection = []string{k}
@@ -290,6 +327,22 @@ type AviPkiProfileCache struct {
 	HasReference     bool
 }
 
+type AviPersistenceProfileCache struct {
+	Name             string
+	Tenant           string
+	Uuid             string
+	CloudConfigCksum uint32
+	LastModified     string
+}
+
+type AviStringGroupCache struct {
+	Name             string
+	Tenant           string
+	Uuid             string
+	CloudConfigCksum uint32
+	LastModified     string
+}
+
 type NextPage struct {
 	NextURI    string
 	Collection interface{}
@@ -322,23 +375,42 @@ type AviVSVIPCache struct {
 }
 
 type AviHTTPPolicyCache struct {
+	Name                  string
+	Tenant                string
+	Uuid                  string
+	CloudConfigCksum      string
+	PoolGroups            []string
+	Pools                 []string
+	StringGroupCollection []NamespaceName
+	LastModified          string
+	InvalidData           bool
+	HasReference          bool
+}
+
+type AviL4PolicyCache struct {
 	Name             string
 	Tenant           string
 	Uuid             string
-	CloudConfigCksum string
-	PoolGroups       []string
+	CloudConfigCksum uint32
 	Pools            []string
 	LastModified     string
-	InvalidData      bool
 	HasReference     bool
 }
 
-type AviL4PolicyCache struct {
+type AviTrafficCloneProfileCache struct {
+	Name             string
+	Tenant           string
+	Uuid             string
+	CloudConfigCksum uint32
+	LastModified     string
+	HasReference     bool
+}
+
+type AviNetworkSecurityPolicyCache struct {
 	Name             string
 	Tenant           string
 	Uuid             string
 	CloudConfigCksum uint32
-	Pools            []string
 	LastModified     string
 	HasReference     bool
 }
@@ -482,6 +554,18 @@ func (c *AviCache) AviCacheGetNameByUuid(uuid string) (interface{}, bool) {
 			} else if value.(*AviL4PolicyCache).Uuid == uuid {
 				return value.(*AviL4PolicyCache).Name, true
 			}
+		case *AviTrafficCloneProfileCache:
+			if value.(*AviTrafficCloneProfileCache) == nil {
+				utils.AviLog.Warnf("Got nil value in cache for traffic clone profile key %v", reflect.ValueOf(key))
+			} else if value.(*AviTrafficCloneProfileCache).Uuid == uuid {
+				return value.(*AviTrafficCloneProfileCache).Name, true
+			}
+		case *AviNetworkSecurityPolicyCache:
+			if value.(*AviNetworkSecurityPolicyCache) == nil {
+				utils.AviLog.Warnf("Got nil value in cache for network security policy key %v", reflect.ValueOf(key))
+			} else if value.(*AviNetworkSecurityPolicyCache).Uuid == uuid {
+				return value.(*AviNetworkSecurityPolicyCache).Name, true
+			}
 		case *AviHTTPPolicyCache:
 			if value.(*AviHTTPPolicyCache) == nil {
 				utils.AviLog.Warnf("Got nil value in cache for http policy key %v", reflect.ValueOf(key))
@@ -494,6 +578,18 @@ func (c *AviCache) AviCacheGetNameByUuid(uuid string) (interface{}, bool) {
 			} else if value.(*AviPGCache).Uuid == uuid {
 				return value.(*AviPGCache).Name, true
 			}
+		case *AviPersistenceProfileCache:
+			if value.(*AviPersistenceProfileCache) == nil {
+				utils.AviLog.Warnf("Got nil value in cache for persistence profile key %v", reflect.ValueOf(key))
+			} else if value.(*AviPersistenceProfileCache).Uuid == uuid {
+				return value.(*AviPersistenceProfileCache).Name, true
+			}
+		case *AviStringGroupCache:
+			if value.(*AviStringGroupCache) == nil {
+				utils.AviLog.Warnf("Got nil value in cache for string group key %v", reflect.ValueOf(key))
+			} else if value.(*AviStringGroupCache).Uuid == uuid {
+				return value.(*AviStringGroupCache).Name, true
+			}
 		case *AviPkiProfileCache:
 			if value.(*AviPkiProfileCache) == nil {
 				utils.AviLog.Warnf("Got nil value in cache for pki profile key %v", reflect.ValueOf(key))
@@ -511,6 +607,18 @@ func (c *AviCache) AviCacheAdd(k interface{}, val interface{}) {
 	c.cache[k] = val
 }
 
+// AviCacheReplace sets the value of a key only if it is still old, so that an entry updated
+// concurrently by the rest layer is not overwritten. It returns whether the value was set.
+func (c *AviCache) AviCacheReplace(k interface{}, old, val interface{}) bool {
+	c.cache_lock.Lock()
+	defer c.cache_lock.Unlock()
+	if current, ok := c.cache[k]; !ok || current != old {
+		return false
+	}
+	c.cache[k] = val
+	return true
+}
+
 func (c *AviCache) AviCacheDelete(k interface{}) {
 	c.cache_lock.Lock()
 	defer c.cache_lock.Unlock()
@@ -41,19 +41,23 @@ import (
 )
 
 type AviObjCache struct {
-	PgCache            *AviCache
-	DSCache            *AviCache
-	PoolCache          *AviCache
-	CloudKeyCache      *AviCache
-	HTTPPolicyCache    *AviCache
-	L4PolicyCache      *AviCache
-	SSLKeyCache        *AviCache
-	PKIProfileCache    *AviCache
-	VSVIPCache         *AviCache
-	VrfCache           *AviCache
-	VsCacheMeta        *AviCache
-	VsCacheLocal       *AviCache
-	ClusterStatusCache *AviCache
+	PgCache                    *AviCache
+	DSCache                    *AviCache
+	PoolCache                  *AviCache
+	CloudKeyCache              *AviCache
+	HTTPPolicyCache            *AviCache
+	L4PolicyCache              *AviCache
+	TrafficCloneProfileCache   *AviCache
+	NetworkSecurityPolicyCache *AviCache
+	SSLKeyCache                *AviCache
+	PKIProfileCache            *AviCache
+	PersistenceProfileCache    *AviCache
+	StringGroupCache           *AviCache
+	VSVIPCache                 *AviCache
+	VrfCache                   *AviCache
+	VsCacheMeta                *AviCache
+	VsCacheLocal               *AviCache
+	ClusterStatusCache         *AviCache
 }
 
 func NewAviObjCache() *AviObjCache {
@@ -67,9 +71,13 @@ func NewAviObjCache() *AviObjCache {
 	c.CloudKeyCache = NewAviCache()
 	c.HTTPPolicyCache = NewAviCache()
 	c.L4PolicyCache = NewAviCache()
+	c.TrafficCloneProfileCache = NewAviCache()
+	c.NetworkSecurityPolicyCache = NewAviCache()
 	c.VSVIPCache = NewAviCache()
 	c.VrfCache = NewAviCache()
 	c.PKIProfileCache = NewAviCache()
+	c.PersistenceProfileCache = NewAviCache()
+	c.StringGroupCache = NewAviCache()
 	c.ClusterStatusCache = NewAviCache()
 	return &c
 }
@@ -97,6 +105,8 @@ func (c *AviObjCache) AviRefreshObjectCache(client []*clients.AviClient, cloud s
 		c.PopulateVsVipDataToCache(client[7], cloud)
 	}()
 	c.PopulatePkiProfilesToCache(client[0])
+	c.PopulatePersistenceProfilesToCache(client[0])
+	c.PopulateStringGroupsToCache(client[0])
 	c.PopulatePoolsToCache(client[1], cloud)
 	c.PopulatePgDataToCache(client[2], cloud)
 
@@ -112,6 +122,8 @@ func (c *AviObjCache) AviRefreshObjectCache(client []*clients.AviClient, cloud s
 	go func() {
 		defer wg.Done()
 		c.PopulateL4PolicySetToCache(client[6], cloud)
+		c.PopulateTrafficCloneProfileToCache(client[6], cloud)
+		c.PopulateNetworkSecurityPolicyToCache(client[6], cloud)
 	}()
 
 	wg.Wait()
@@ -264,6 +276,22 @@ func (c *AviObjCache) MarkReference(vsCacheObj *AviVsCache) {
 		}
 	}
 
+	for _, objKey := range vsCacheObj.TrafficCloneProfileCollection {
+		if intf, found := c.TrafficCloneProfileCache.AviCacheGet(objKey); found {
+			if obj, ok := intf.(*AviTrafficCloneProfileCache); ok {
+				obj.HasReference = true
+			}
+		}
+	}
+
+	for _, objKey := range vsCacheObj.NetworkSecurityPolicyCollection {
+		if intf, found := c.NetworkSecurityPolicyCache.AviCacheGet(objKey); found {
+			if obj, ok := intf.(*AviNetworkSecurityPolicyCache); ok {
+				obj.HasReference = true
+			}
+		}
+	}
+
 	for _, objKey := range vsCacheObj.PGKeyCollection {
 		if intf, found := c.PgCache.AviCacheGet(objKey); found {
 			if obj, ok := intf.(*AviPGCache); ok {
@@ -302,7 +330,7 @@ func (c *AviObjCache) MarkReference(vsCacheObj *AviVsCache) {
 func (c *AviObjCache) DeleteUnmarked(childCollection []string) {
 
 	var dsKeys, vsVipKeys, httpKeys, sslKeys []NamespaceName
-	var pgKeys, poolKeys, l4Keys []NamespaceName
+	var pgKeys, poolKeys, l4Keys, cloneKeys, nspKeys []NamespaceName
 	for _, objkey := range c.DSCache.AviGetAllKeys() {
 		intf, _ := c.DSCache.AviCacheGet(objkey)
 		if obj, ok := intf.(*AviDSCache); ok {
@@ -333,6 +361,26 @@ func (c *AviObjCache) DeleteUnmarked(childCollection []string) {
 		}
 	}
 
+	for _, objkey := range c.TrafficCloneProfileCache.AviGetAllKeys() {
+		intf, _ := c.TrafficCloneProfileCache.AviCacheGet(objkey)
+		if obj, ok := intf.(*AviTrafficCloneProfileCache); ok {
+			if obj.HasReference == false {
+				utils.AviLog.Infof("Reference Not found for traffic clone profile: %s", objkey)
+				cloneKeys = append(cloneKeys, objkey)
+			}
+		}
+	}
+
+	for _, objkey := range c.NetworkSecurityPolicyCache.AviGetAllKeys() {
+		intf, _ := c.NetworkSecurityPolicyCache.AviCacheGet(objkey)
+		if obj, ok := intf.(*AviNetworkSecurityPolicyCache); ok {
+			if obj.HasReference == false {
+				utils.AviLog.Infof("Reference Not found for network security policy: %s", objkey)
+				nspKeys = append(nspKeys, objkey)
+			}
+		}
+	}
+
 	for _, objkey := range c.PgCache.AviGetAllKeys() {
 		intf, _ := c.PgCache.AviCacheGet(objkey)
 		if obj, ok := intf.(*AviPGCache); ok {
@@ -385,15 +433,17 @@ func (c *AviObjCache) DeleteUnmarked(childCollection []string) {
 
 	// Only add this if we have stale data
 	vsMetaObj := AviVsCache{
-		Name:                 lib.DummyVSForStaleData,
-		VSVipKeyCollection:   vsVipKeys,
-		HTTPKeyCollection:    httpKeys,
-		DSKeyCollection:      dsKeys,
-		SSLKeyCertCollection: sslKeys,
-		PGKeyCollection:      pgKeys,
-		PoolKeyCollection:    poolKeys,
-		L4PolicyCollection:   l4Keys,
-		SNIChildCollection:   childCollection,
+		Name:                            lib.DummyVSForStaleData,
+		VSVipKeyCollection:              vsVipKeys,
+		HTTPKeyCollection:               httpKeys,
+		DSKeyCollection:                 dsKeys,
+		SSLKeyCertCollection:            sslKeys,
+		PGKeyCollection:                 pgKeys,
+		PoolKeyCollection:               poolKeys,
+		L4PolicyCollection:              l4Keys,
+		TrafficCloneProfileCollection:   cloneKeys,
+		NetworkSecurityPolicyCollection: nspKeys,
+		SNIChildCollection:              childCollection,
 	}
 	vsKey := NamespaceName{
 		Namespace: lib.GetTenant(),
@@ -591,6 +641,11 @@ func (c *AviObjCache) AviPopulateAllPools(client *clients.AviClient, cloud strin
 			utils.AviLog.Warnf("Failed to unmarshal pool data, err: %v", err)
 			continue
 		}
+		var poolFields map[string]interface{}
+		if err = json.Unmarshal(elems[i], &poolFields); err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal pool data, err: %v", err)
+			continue
+		}
 
 		if pool.Name == nil || pool.UUID == nil || pool.CloudConfigCksum == nil {
 			utils.AviLog.Warnf("Incomplete pool data unmarshalled, %s", utils.Stringify(pool))
@@ -612,13 +667,24 @@ func (c *AviObjCache) AviPopulateAllPools(client *clients.AviClient, cloud strin
 			}
 		}
 
+		var persistenceKey NamespaceName
+		if pool.ApplicationPersistenceProfileRef != nil {
+			persistenceUuid := ExtractUuid(*pool.ApplicationPersistenceProfileRef, "applicationpersistenceprofile-.*.#")
+			persistenceName, foundPersistence := c.PersistenceProfileCache.AviCacheGetNameByUuid(persistenceUuid)
+			if foundPersistence {
+				persistenceKey = NamespaceName{Namespace: lib.GetTenant(), Name: persistenceName.(string)}
+			}
+		}
+
 		poolCacheObj := AviPoolCache{
-			Name:                 *pool.Name,
-			Uuid:                 *pool.UUID,
-			CloudConfigCksum:     *pool.CloudConfigCksum,
-			PkiProfileCollection: pkiKey,
-			ServiceMetadataObj:   svc_mdata_obj,
-			LastModified:         *pool.LastModified,
+			Name:                         *pool.Name,
+			Uuid:                         *pool.UUID,
+			CloudConfigCksum:             *pool.CloudConfigCksum,
+			FieldsCksum:                  PoolDriftChecksum(poolFields),
+			PkiProfileCollection:         pkiKey,
+			PersistenceProfileCollection: persistenceKey,
+			ServiceMetadataObj:           svc_mdata_obj,
+			LastModified:                 *pool.LastModified,
 		}
 		*poolData = append(*poolData, poolCacheObj)
 	}
@@ -668,6 +734,288 @@ func (c *AviObjCache) PopulatePkiProfilesToCache(client *clients.AviClient, over
 	}
 }
 
+func (c *AviObjCache) AviPopulateAllPersistenceProfiles(client *clients.AviClient, persistenceData *[]AviPersistenceProfileCache, nextPage ...NextPage) (*[]AviPersistenceProfileCache, int, error) {
+	var uri string
+
+	// Persistence profiles do not carry created_by, hence the ones created by AKO are matched by name.
+	if len(nextPage) == 1 {
+		uri = nextPage[0].NextURI
+	} else {
+		uri = "/api/applicationpersistenceprofile/?" + "name.contains=" + lib.GetNamePrefix() + "&include_name=true" + "&page_size=100"
+	}
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for applicationpersistenceprofile %v", uri, err)
+		return nil, 0, err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal applicationpersistenceprofile data, err: %v", err)
+		return nil, 0, err
+	}
+	for i := 0; i < len(elems); i++ {
+		persistenceProfile := models.ApplicationPersistenceProfile{}
+		err = json.Unmarshal(elems[i], &persistenceProfile)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal applicationpersistenceprofile data, err: %v", err)
+			continue
+		}
+		if persistenceProfile.Name == nil || persistenceProfile.UUID == nil {
+			utils.AviLog.Warnf("Incomplete applicationpersistenceprofile data unmarshalled, %s", utils.Stringify(persistenceProfile))
+			continue
+		}
+		//Only cache a persistence profile that belongs to this AKO.
+		if !strings.HasPrefix(*persistenceProfile.Name, lib.GetNamePrefix()) {
+			continue
+		}
+		*persistenceData = append(*persistenceData, persistenceProfileCacheObj(&persistenceProfile))
+	}
+
+	if result.Next != "" {
+		// It has a next page, let's recursively call the same method.
+		next_uri := strings.Split(result.Next, "/api/applicationpersistenceprofile")
+		if len(next_uri) > 1 {
+			overrideUri := "/api/applicationpersistenceprofile" + next_uri[1]
+			nextPage := NextPage{NextURI: overrideUri}
+			_, _, err := c.AviPopulateAllPersistenceProfiles(client, persistenceData, nextPage)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	return persistenceData, result.Count, nil
+}
+
+func (c *AviObjCache) PopulatePersistenceProfilesToCache(client *clients.AviClient) {
+	var persistenceData []AviPersistenceProfileCache
+	_, count, err := c.AviPopulateAllPersistenceProfiles(client, &persistenceData)
+	if err != nil || len(persistenceData) != count {
+		return
+	}
+	persistenceCacheData := c.PersistenceProfileCache.ShallowCopy()
+	for i, persistenceCacheObj := range persistenceData {
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: persistenceCacheObj.Name}
+		utils.AviLog.Debugf("Adding key to persistence profile cache :%s", utils.Stringify(persistenceCacheObj))
+		c.PersistenceProfileCache.AviCacheAdd(k, &persistenceData[i])
+		delete(persistenceCacheData, k)
+	}
+	// The data that is left in persistenceCacheData should be explicitly removed
+	for key := range persistenceCacheData {
+		utils.AviLog.Debugf("Deleting key from persistence profile cache :%s", key)
+		c.PersistenceProfileCache.AviCacheDelete(key)
+	}
+}
+
+func (c *AviObjCache) AviPopulateOnePersistenceProfileCache(client *clients.AviClient,
+	cloud string, objName string) error {
+	uri := "/api/applicationpersistenceprofile?name=" + objName
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for applicationpersistenceprofile %v", uri, err)
+		return err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal applicationpersistenceprofile data, err: %v", err)
+		return err
+	}
+	for i := 0; i < len(elems); i++ {
+		persistenceProfile := models.ApplicationPersistenceProfile{}
+		err = json.Unmarshal(elems[i], &persistenceProfile)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal applicationpersistenceprofile data, err: %v", err)
+			continue
+		}
+		if persistenceProfile.Name == nil || persistenceProfile.UUID == nil {
+			utils.AviLog.Warnf("Incomplete applicationpersistenceprofile data unmarshalled, %s", utils.Stringify(persistenceProfile))
+			continue
+		}
+		//Only cache a persistence profile that belongs to this AKO.
+		if !strings.HasPrefix(*persistenceProfile.Name, lib.GetNamePrefix()) {
+			continue
+		}
+		persistenceCacheObj := persistenceProfileCacheObj(&persistenceProfile)
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: *persistenceProfile.Name}
+		c.PersistenceProfileCache.AviCacheAdd(k, &persistenceCacheObj)
+		utils.AviLog.Debugf("Adding persistence profile to Cache during refresh %s", k)
+	}
+	return nil
+}
+
+func persistenceProfileCacheObj(persistenceProfile *models.ApplicationPersistenceProfile) AviPersistenceProfileCache {
+	var timeout int32
+	if persistenceProfile.IPPersistenceProfile != nil && persistenceProfile.IPPersistenceProfile.IPPersistentTimeout != nil {
+		timeout = *persistenceProfile.IPPersistenceProfile.IPPersistentTimeout
+	}
+	persistenceCacheObj := AviPersistenceProfileCache{
+		Name:             *persistenceProfile.Name,
+		Tenant:           lib.GetTenant(),
+		Uuid:             *persistenceProfile.UUID,
+		CloudConfigCksum: lib.PersistenceProfileChecksum(timeout, utils.AviObjectMarkers{}, persistenceProfile.Markers, true),
+	}
+	if persistenceProfile.LastModified != nil {
+		persistenceCacheObj.LastModified = *persistenceProfile.LastModified
+	}
+	return persistenceCacheObj
+}
+
+func (c *AviObjCache) AviPopulateAllStringGroups(client *clients.AviClient, stringGroupData *[]AviStringGroupCache, nextPage ...NextPage) (*[]AviStringGroupCache, int, error) {
+	var uri string
+
+	// String groups do not carry created_by, hence the ones created by AKO are matched by name.
+	if len(nextPage) == 1 {
+		uri = nextPage[0].NextURI
+	} else {
+		uri = "/api/stringgroup/?" + "name.contains=" + lib.GetNamePrefix() + "&include_name=true" + "&page_size=100"
+	}
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for stringgroup %v", uri, err)
+		return nil, 0, err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal stringgroup data, err: %v", err)
+		return nil, 0, err
+	}
+	for i := 0; i < len(elems); i++ {
+		stringGroup := models.StringGroup{}
+		err = json.Unmarshal(elems[i], &stringGroup)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal stringgroup data, err: %v", err)
+			continue
+		}
+		if stringGroup.Name == nil || stringGroup.UUID == nil {
+			utils.AviLog.Warnf("Incomplete stringgroup data unmarshalled, %s", utils.Stringify(stringGroup))
+			continue
+		}
+		//Only cache a string group that belongs to this AKO.
+		if !strings.HasPrefix(*stringGroup.Name, lib.GetNamePrefix()) {
+			continue
+		}
+		*stringGroupData = append(*stringGroupData, stringGroupCacheObj(&stringGroup))
+	}
+
+	if result.Next != "" {
+		// It has a next page, let's recursively call the same method.
+		next_uri := strings.Split(result.Next, "/api/stringgroup")
+		if len(next_uri) > 1 {
+			overrideUri := "/api/stringgroup" + next_uri[1]
+			nextPage := NextPage{NextURI: overrideUri}
+			_, _, err := c.AviPopulateAllStringGroups(client, stringGroupData, nextPage)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	return stringGroupData, result.Count, nil
+}
+
+func (c *AviObjCache) PopulateStringGroupsToCache(client *clients.AviClient) {
+	var stringGroupData []AviStringGroupCache
+	_, count, err := c.AviPopulateAllStringGroups(client, &stringGroupData)
+	if err != nil || len(stringGroupData) != count {
+		return
+	}
+	stringGroupCacheData := c.StringGroupCache.ShallowCopy()
+	for i, stringGroupCacheObj := range stringGroupData {
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: stringGroupCacheObj.Name}
+		utils.AviLog.Debugf("Adding key to string group cache :%s", utils.Stringify(stringGroupCacheObj))
+		c.StringGroupCache.AviCacheAdd(k, &stringGroupData[i])
+		delete(stringGroupCacheData, k)
+	}
+	// The data that is left in stringGroupCacheData should be explicitly removed
+	for key := range stringGroupCacheData {
+		utils.AviLog.Debugf("Deleting key from string group cache :%s", key)
+		c.StringGroupCache.AviCacheDelete(key)
+	}
+}
+
+func (c *AviObjCache) AviPopulateOneStringGroupCache(client *clients.AviClient,
+	cloud string, objName string) error {
+	uri := "/api/stringgroup?name=" + objName
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for stringgroup %v", uri, err)
+		return err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal stringgroup data, err: %v", err)
+		return err
+	}
+	for i := 0; i < len(elems); i++ {
+		stringGroup := models.StringGroup{}
+		err = json.Unmarshal(elems[i], &stringGroup)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal stringgroup data, err: %v", err)
+			continue
+		}
+		if stringGroup.Name == nil || stringGroup.UUID == nil {
+			utils.AviLog.Warnf("Incomplete stringgroup data unmarshalled, %s", utils.Stringify(stringGroup))
+			continue
+		}
+		//Only cache a string group that belongs to this AKO.
+		if !strings.HasPrefix(*stringGroup.Name, lib.GetNamePrefix()) {
+			continue
+		}
+		stringGroupObj := stringGroupCacheObj(&stringGroup)
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: *stringGroup.Name}
+		c.StringGroupCache.AviCacheAdd(k, &stringGroupObj)
+		utils.AviLog.Debugf("Adding string group to Cache during refresh %s", k)
+	}
+	return nil
+}
+
+func stringGroupCacheObj(stringGroup *models.StringGroup) AviStringGroupCache {
+	var regex []string
+	for _, kv := range stringGroup.Kv {
+		if kv != nil && kv.Key != nil {
+			regex = append(regex, *kv.Key)
+		}
+	}
+	stringGroupCacheObj := AviStringGroupCache{
+		Name:             *stringGroup.Name,
+		Tenant:           lib.GetTenant(),
+		Uuid:             *stringGroup.UUID,
+		CloudConfigCksum: lib.StringGroupChecksum(regex, utils.AviObjectMarkers{}, stringGroup.Markers, true),
+	}
+	if stringGroup.LastModified != nil {
+		stringGroupCacheObj.LastModified = *stringGroup.LastModified
+	}
+	return stringGroupCacheObj
+}
+
+// httpPolicyStringGroups returns the cached string groups referred to by the path matches of the
+// request rules of an HTTP policyset.
+func (c *AviObjCache) httpPolicyStringGroups(httppol *models.HTTPPolicySet) []NamespaceName {
+	var stringGroups []NamespaceName
+	if httppol.HTTPRequestPolicy == nil {
+		return stringGroups
+	}
+	for _, rule := range httppol.HTTPRequestPolicy.Rules {
+		if rule.Match == nil || rule.Match.Path == nil {
+			continue
+		}
+		for _, sgRef := range rule.Match.Path.StringGroupRefs {
+			sgUuid := ExtractUuid(sgRef, "stringgroup-.*.#")
+			sgName, found := c.StringGroupCache.AviCacheGetNameByUuid(sgUuid)
+			if found {
+				stringGroups = append(stringGroups, NamespaceName{Namespace: lib.GetTenant(), Name: sgName.(string)})
+			}
+		}
+	}
+	return stringGroups
+}
+
 func (c *AviObjCache) PopulatePoolsToCache(client *clients.AviClient, cloud string, overrideUri ...NextPage) {
 	var poolsData []AviPoolCache
 	c.AviPopulateAllPools(client, cloud, &poolsData)
@@ -1130,6 +1478,11 @@ func (c *AviObjCache) AviPopulateOnePoolCache(client *clients.AviClient,
 			utils.AviLog.Warnf("Failed to unmarshal pool data, err: %v", err)
 			continue
 		}
+		var poolFields map[string]interface{}
+		if err = json.Unmarshal(elems[i], &poolFields); err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal pool data, err: %v", err)
+			continue
+		}
 
 		if pool.Name == nil || pool.UUID == nil || pool.CloudConfigCksum == nil {
 			utils.AviLog.Warnf("Incomplete pool data unmarshalled, %s", utils.Stringify(pool))
@@ -1155,13 +1508,24 @@ func (c *AviObjCache) AviPopulateOnePoolCache(client *clients.AviClient,
 			}
 		}
 
+		var persistenceKey NamespaceName
+		if pool.ApplicationPersistenceProfileRef != nil {
+			persistenceUuid := ExtractUuid(*pool.ApplicationPersistenceProfileRef, "applicationpersistenceprofile-.*.#")
+			persistenceName, foundPersistence := c.PersistenceProfileCache.AviCacheGetNameByUuid(persistenceUuid)
+			if foundPersistence {
+				persistenceKey = NamespaceName{Namespace: lib.GetTenant(), Name: persistenceName.(string)}
+			}
+		}
+
 		poolCacheObj := AviPoolCache{
-			Name:                 *pool.Name,
-			Uuid:                 *pool.UUID,
-			CloudConfigCksum:     *pool.CloudConfigCksum,
-			PkiProfileCollection: pkiKey,
-			ServiceMetadataObj:   svc_mdata_obj,
-			LastModified:         *pool.LastModified,
+			Name:                         *pool.Name,
+			Uuid:                         *pool.UUID,
+			CloudConfigCksum:             *pool.CloudConfigCksum,
+			FieldsCksum:                  PoolDriftChecksum(poolFields),
+			PkiProfileCollection:         pkiKey,
+			PersistenceProfileCollection: persistenceKey,
+			ServiceMetadataObj:           svc_mdata_obj,
+			LastModified:                 *pool.LastModified,
 		}
 		k := NamespaceName{Namespace: lib.GetTenant(), Name: *pool.Name}
 		c.PoolCache.AviCacheAdd(k, &poolCacheObj)
@@ -1432,12 +1796,13 @@ func (c *AviObjCache) AviPopulateOneVsHttpPolCache(client *clients.AviClient,
 		}
 
 		httpPolCacheObj := AviHTTPPolicyCache{
-			Name:             *httppol.Name,
-			Uuid:             *httppol.UUID,
-			CloudConfigCksum: *httppol.CloudConfigCksum,
-			PoolGroups:       poolGroups,
-			Pools:            pools,
-			LastModified:     *httppol.LastModified,
+			Name:                  *httppol.Name,
+			Uuid:                  *httppol.UUID,
+			CloudConfigCksum:      *httppol.CloudConfigCksum,
+			PoolGroups:            poolGroups,
+			Pools:                 pools,
+			StringGroupCollection: c.httpPolicyStringGroups(&httppol),
+			LastModified:          *httppol.LastModified,
 		}
 		k := NamespaceName{Namespace: lib.GetTenant(), Name: *httppol.Name}
 		c.HTTPPolicyCache.AviCacheAdd(k, &httpPolCacheObj)
@@ -1487,10 +1852,12 @@ func (c *AviObjCache) AviPopulateOneVsL4PolCache(client *clients.AviClient,
 			for _, rule := range l4pol.L4ConnectionPolicy.Rules {
 				protocols = append(protocols, *rule.Match.Protocol.Protocol)
 				if rule.Action != nil {
-					poolUuid := ExtractUuid(*rule.Action.SelectPool.PoolRef, "pool-.*.#")
-					poolName, found := c.PoolCache.AviCacheGetNameByUuid(poolUuid)
-					if found {
-						pools = append(pools, poolName.(string))
+					if rule.Action.SelectPool != nil && rule.Action.SelectPool.PoolRef != nil {
+						poolUuid := ExtractUuid(*rule.Action.SelectPool.PoolRef, "pool-.*.#")
+						poolName, found := c.PoolCache.AviCacheGetNameByUuid(poolUuid)
+						if found {
+							pools = append(pools, poolName.(string))
+						}
 					}
 				}
 				if rule.Match != nil {
@@ -1601,12 +1968,13 @@ func (c *AviObjCache) AviPopulateAllHttpPolicySets(client *clients.AviClient, cl
 			}
 		}
 		httpPolCacheObj := AviHTTPPolicyCache{
-			Name:             *httppol.Name,
-			Uuid:             *httppol.UUID,
-			CloudConfigCksum: *httppol.CloudConfigCksum,
-			PoolGroups:       poolGroups,
-			Pools:            pools,
-			LastModified:     *httppol.LastModified,
+			Name:                  *httppol.Name,
+			Uuid:                  *httppol.UUID,
+			CloudConfigCksum:      *httppol.CloudConfigCksum,
+			PoolGroups:            poolGroups,
+			Pools:                 pools,
+			StringGroupCollection: c.httpPolicyStringGroups(&httppol),
+			LastModified:          *httppol.LastModified,
 		}
 		*httpPolicyData = append(*httpPolicyData, httpPolCacheObj)
 	}
@@ -1705,10 +2073,12 @@ func (c *AviObjCache) AviPopulateAllL4PolicySets(client *clients.AviClient, clou
 						protocol = utils.UDP
 					}
 					protocols = append(protocols, protocol)
-					poolUuid := ExtractUuid(*rule.Action.SelectPool.PoolRef, "pool-.*.#")
-					poolName, found := c.PoolCache.AviCacheGetNameByUuid(poolUuid)
-					if found {
-						pools = append(pools, poolName.(string))
+					if rule.Action.SelectPool != nil && rule.Action.SelectPool.PoolRef != nil {
+						poolUuid := ExtractUuid(*rule.Action.SelectPool.PoolRef, "pool-.*.#")
+						poolName, found := c.PoolCache.AviCacheGetNameByUuid(poolUuid)
+						if found {
+							pools = append(pools, poolName.(string))
+						}
 					}
 				}
 				if rule.Match != nil {
@@ -1765,6 +2135,251 @@ func (c *AviObjCache) PopulateL4PolicySetToCache(client *clients.AviClient, clou
 	}
 }
 
+func (c *AviObjCache) AviPopulateAllTrafficCloneProfiles(client *clients.AviClient, cloud string, cloneProfileData *[]AviTrafficCloneProfileCache, nextPage ...NextPage) (*[]AviTrafficCloneProfileCache, int, error) {
+	var uri string
+
+	if len(nextPage) == 1 {
+		uri = nextPage[0].NextURI
+	} else {
+		uri = "/api/trafficcloneprofile/?" + "name.contains=" + lib.GetNamePrefix() + "&include_name=true" + "&cloud_ref.name=" + cloud + "&page_size=100"
+	}
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for trafficcloneprofile %v", uri, err)
+		return nil, 0, err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal trafficcloneprofile data, err: %v", err)
+		return nil, 0, err
+	}
+	for i := 0; i < len(elems); i++ {
+		cloneProfile := models.TrafficCloneProfile{}
+		err = json.Unmarshal(elems[i], &cloneProfile)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal trafficcloneprofile data, err: %v", err)
+			continue
+		}
+		if cloneProfile.Name == nil || cloneProfile.UUID == nil {
+			utils.AviLog.Warnf("Incomplete trafficcloneprofile data unmarshalled, %s", utils.Stringify(cloneProfile))
+			continue
+		}
+		*cloneProfileData = append(*cloneProfileData, trafficCloneProfileCacheObj(&cloneProfile))
+	}
+
+	if result.Next != "" {
+		// It has a next page, let's recursively call the same method.
+		next_uri := strings.Split(result.Next, "/api/trafficcloneprofile")
+		if len(next_uri) > 1 {
+			overrideUri := "/api/trafficcloneprofile" + next_uri[1]
+			nextPage := NextPage{NextURI: overrideUri}
+			_, _, err := c.AviPopulateAllTrafficCloneProfiles(client, cloud, cloneProfileData, nextPage)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	return cloneProfileData, result.Count, nil
+}
+
+func (c *AviObjCache) PopulateTrafficCloneProfileToCache(client *clients.AviClient, cloud string) {
+	var cloneProfileData []AviTrafficCloneProfileCache
+	_, count, err := c.AviPopulateAllTrafficCloneProfiles(client, cloud, &cloneProfileData)
+	if err != nil || len(cloneProfileData) != count {
+		return
+	}
+	cloneCacheData := c.TrafficCloneProfileCache.ShallowCopy()
+	for i, cloneCacheObj := range cloneProfileData {
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: cloneCacheObj.Name}
+		utils.AviLog.Debugf("Adding key to traffic clone profile cache :%s", utils.Stringify(cloneCacheObj))
+		c.TrafficCloneProfileCache.AviCacheAdd(k, &cloneProfileData[i])
+		delete(cloneCacheData, k)
+	}
+	// The data that is left in cloneCacheData should be explicitly removed
+	for key := range cloneCacheData {
+		utils.AviLog.Debugf("Deleting key from traffic clone profile cache :%s", key)
+		c.TrafficCloneProfileCache.AviCacheDelete(key)
+	}
+}
+
+func (c *AviObjCache) AviPopulateOneTrafficCloneProfileCache(client *clients.AviClient,
+	cloud string, objName string) error {
+	uri := "/api/trafficcloneprofile?name=" + objName + "&cloud_ref.name=" + cloud
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for trafficcloneprofile %v", uri, err)
+		return err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal trafficcloneprofile data, err: %v", err)
+		return err
+	}
+	for i := 0; i < len(elems); i++ {
+		cloneProfile := models.TrafficCloneProfile{}
+		err = json.Unmarshal(elems[i], &cloneProfile)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal trafficcloneprofile data, err: %v", err)
+			continue
+		}
+		if cloneProfile.Name == nil || cloneProfile.UUID == nil {
+			utils.AviLog.Warnf("Incomplete trafficcloneprofile data unmarshalled, %s", utils.Stringify(cloneProfile))
+			continue
+		}
+		//Only cache a traffic clone profiles that belongs to this AKO.
+		if !strings.HasPrefix(*cloneProfile.Name, lib.GetNamePrefix()) {
+			continue
+		}
+		cloneCacheObj := trafficCloneProfileCacheObj(&cloneProfile)
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: *cloneProfile.Name}
+		c.TrafficCloneProfileCache.AviCacheAdd(k, &cloneCacheObj)
+		utils.AviLog.Debugf("Adding traffic clone profile to Cache during refresh %s", k)
+	}
+	return nil
+}
+
+func trafficCloneProfileCacheObj(cloneProfile *models.TrafficCloneProfile) AviTrafficCloneProfileCache {
+	var cloneServers []string
+	for _, cloneServer := range cloneProfile.CloneServers {
+		if cloneServer.IPAddress != nil && cloneServer.IPAddress.Addr != nil {
+			cloneServers = append(cloneServers, *cloneServer.IPAddress.Addr)
+		}
+	}
+	cloneCacheObj := AviTrafficCloneProfileCache{
+		Name:             *cloneProfile.Name,
+		Uuid:             *cloneProfile.UUID,
+		CloudConfigCksum: lib.TrafficCloneProfileChecksum(cloneServers, utils.AviObjectMarkers{}, cloneProfile.Markers, true),
+	}
+	if cloneProfile.LastModified != nil {
+		cloneCacheObj.LastModified = *cloneProfile.LastModified
+	}
+	return cloneCacheObj
+}
+
+func (c *AviObjCache) AviPopulateAllNetworkSecurityPolicies(client *clients.AviClient, cloud string, nspData *[]AviNetworkSecurityPolicyCache, nextPage ...NextPage) (*[]AviNetworkSecurityPolicyCache, int, error) {
+	var uri string
+	akoUser := lib.AKOUser
+
+	if len(nextPage) == 1 {
+		uri = nextPage[0].NextURI
+	} else {
+		uri = "/api/networksecuritypolicy/?" + "&include_name=true&created_by=" + akoUser + "&page_size=100"
+	}
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for networksecuritypolicy %v", uri, err)
+		return nil, 0, err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal networksecuritypolicy data, err: %v", err)
+		return nil, 0, err
+	}
+	for i := 0; i < len(elems); i++ {
+		nsp := models.NetworkSecurityPolicy{}
+		err = json.Unmarshal(elems[i], &nsp)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal networksecuritypolicy data, err: %v", err)
+			continue
+		}
+		if nsp.Name == nil || nsp.UUID == nil {
+			utils.AviLog.Warnf("Incomplete networksecuritypolicy data unmarshalled, %s", utils.Stringify(nsp))
+			continue
+		}
+		*nspData = append(*nspData, networkSecurityPolicyCacheObj(&nsp))
+	}
+
+	if result.Next != "" {
+		// It has a next page, let's recursively call the same method.
+		next_uri := strings.Split(result.Next, "/api/networksecuritypolicy")
+		if len(next_uri) > 1 {
+			overrideUri := "/api/networksecuritypolicy" + next_uri[1]
+			nextPage := NextPage{NextURI: overrideUri}
+			_, _, err := c.AviPopulateAllNetworkSecurityPolicies(client, cloud, nspData, nextPage)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	return nspData, result.Count, nil
+}
+
+func (c *AviObjCache) PopulateNetworkSecurityPolicyToCache(client *clients.AviClient, cloud string) {
+	var nspData []AviNetworkSecurityPolicyCache
+	_, count, err := c.AviPopulateAllNetworkSecurityPolicies(client, cloud, &nspData)
+	if err != nil || len(nspData) != count {
+		return
+	}
+	nspCacheData := c.NetworkSecurityPolicyCache.ShallowCopy()
+	for i, nspCacheObj := range nspData {
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: nspCacheObj.Name}
+		utils.AviLog.Debugf("Adding key to network security policy cache :%s", utils.Stringify(nspCacheObj))
+		c.NetworkSecurityPolicyCache.AviCacheAdd(k, &nspData[i])
+		delete(nspCacheData, k)
+	}
+	// The data that is left in nspCacheData should be explicitly removed
+	for key := range nspCacheData {
+		utils.AviLog.Debugf("Deleting key from network security policy cache :%s", key)
+		c.NetworkSecurityPolicyCache.AviCacheDelete(key)
+	}
+}
+
+func (c *AviObjCache) AviPopulateOneNetworkSecurityPolicyCache(client *clients.AviClient,
+	cloud string, objName string) error {
+	var uri string
+	akoUser := lib.AKOUser
+
+	uri = "/api/networksecuritypolicy?name=" + objName + "&created_by=" + akoUser
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for networksecuritypolicy %v", uri, err)
+		return err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal networksecuritypolicy data, err: %v", err)
+		return err
+	}
+	for i := 0; i < len(elems); i++ {
+		nsp := models.NetworkSecurityPolicy{}
+		err = json.Unmarshal(elems[i], &nsp)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal networksecuritypolicy data, err: %v", err)
+			continue
+		}
+		if nsp.Name == nil || nsp.UUID == nil {
+			utils.AviLog.Warnf("Incomplete networksecuritypolicy data unmarshalled, %s", utils.Stringify(nsp))
+			continue
+		}
+		nspCacheObj := networkSecurityPolicyCacheObj(&nsp)
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: *nsp.Name}
+		c.NetworkSecurityPolicyCache.AviCacheAdd(k, &nspCacheObj)
+		utils.AviLog.Debugf("Adding network security policy to Cache during refresh %s", k)
+	}
+	return nil
+}
+
+func networkSecurityPolicyCacheObj(nsp *models.NetworkSecurityPolicy) AviNetworkSecurityPolicyCache {
+	nspCacheObj := AviNetworkSecurityPolicyCache{
+		Name:             *nsp.Name,
+		Tenant:           lib.GetTenant(),
+		Uuid:             *nsp.UUID,
+		CloudConfigCksum: lib.NetworkSecurityPolicyChecksum(lib.NetworkSecurityPolicySourceRanges(nsp.Rules), utils.AviObjectMarkers{}, nsp.Markers, true),
+	}
+	if nsp.LastModified != nil {
+		nspCacheObj.LastModified = *nsp.LastModified
+	}
+	return nspCacheObj
+}
+
 func (c *AviObjCache) AviObjVrfCachePopulate(client *clients.AviClient, cloud string) error {
 	if lib.GetDisableStaticRoute() {
 		utils.AviLog.Debugf("Static route sync disabled, skipping vrf cache population")
@@ -1887,6 +2502,8 @@ func (c *AviObjCache) AviObjVSCachePopulate(client *clients.AviClient, cloud str
 				var dsKeys []NamespaceName
 				var httpKeys []NamespaceName
 				var l4Keys []NamespaceName
+				var cloneKeys []NamespaceName
+				var nspKeys []NamespaceName
 				var poolgroupKeys []NamespaceName
 				var poolKeys []NamespaceName
 				var sharedVsOrL4 bool
@@ -2025,6 +2642,20 @@ func (c *AviObjCache) AviObjVSCachePopulate(client *clients.AviClient, cloud str
 						}
 					}
 				}
+				if vs["traffic_clone_profile_ref"] != nil {
+					cloneUuid := ExtractUuid(vs["traffic_clone_profile_ref"].(string), "trafficcloneprofile-.*.#")
+					cloneName, foundClone := c.TrafficCloneProfileCache.AviCacheGetNameByUuid(cloneUuid)
+					if foundClone {
+						cloneKeys = append(cloneKeys, NamespaceName{Namespace: lib.GetTenant(), Name: cloneName.(string)})
+					}
+				}
+				if vs["network_security_policy_ref"] != nil {
+					nspUuid := ExtractUuid(vs["network_security_policy_ref"].(string), "networksecuritypolicy-.*.#")
+					nspName, foundNsp := c.NetworkSecurityPolicyCache.AviCacheGetNameByUuid(nspUuid)
+					if foundNsp {
+						nspKeys = append(nspKeys, NamespaceName{Namespace: lib.GetTenant(), Name: nspName.(string)})
+					}
+				}
 				if vs["pool_ref"] != nil {
 					poolRef, ok := vs["pool_ref"].(string)
 					if ok {
@@ -2040,20 +2671,23 @@ func (c *AviObjCache) AviObjVSCachePopulate(client *clients.AviClient, cloud str
 
 				// Populate the vscache meta object here.
 				vsMetaObj := AviVsCache{
-					Name:                 vs["name"].(string),
-					Uuid:                 vs["uuid"].(string),
-					VSVipKeyCollection:   vsVipKey,
-					HTTPKeyCollection:    httpKeys,
-					DSKeyCollection:      dsKeys,
-					SSLKeyCertCollection: sslKeys,
-					PGKeyCollection:      poolgroupKeys,
-					PoolKeyCollection:    poolKeys,
-					CloudConfigCksum:     vs["cloud_config_cksum"].(string),
-					SNIChildCollection:   sni_child_collection,
-					ParentVSRef:          parentVSKey,
-					ServiceMetadataObj:   svc_mdata_obj,
-					L4PolicyCollection:   l4Keys,
-					LastModified:         vs["_last_modified"].(string),
+					Name:                            vs["name"].(string),
+					Uuid:                            vs["uuid"].(string),
+					VSVipKeyCollection:              vsVipKey,
+					HTTPKeyCollection:               httpKeys,
+					DSKeyCollection:                 dsKeys,
+					SSLKeyCertCollection:            sslKeys,
+					PGKeyCollection:                 poolgroupKeys,
+					PoolKeyCollection:               poolKeys,
+					CloudConfigCksum:                vs["cloud_config_cksum"].(string),
+					FieldsCksum:                     VSDriftChecksum(vs),
+					SNIChildCollection:              sni_child_collection,
+					ParentVSRef:                     parentVSKey,
+					ServiceMetadataObj:              svc_mdata_obj,
+					L4PolicyCollection:              l4Keys,
+					TrafficCloneProfileCollection:   cloneKeys,
+					NetworkSecurityPolicyCollection: nspKeys,
+					LastModified:                    vs["_last_modified"].(string),
 				}
 				if val, ok := vs["enable_rhi"]; ok {
 					vsMetaObj.EnableRhi = val.(bool)
@@ -2151,6 +2785,8 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 				var poolgroupKeys []NamespaceName
 				var poolKeys []NamespaceName
 				var l4Keys []NamespaceName
+				var cloneKeys []NamespaceName
+				var nspKeys []NamespaceName
 
 				// Populate the VSVIP cache
 				if vs["vsvip_ref"] != nil {
@@ -2269,6 +2905,20 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 						}
 					}
 				}
+				if vs["traffic_clone_profile_ref"] != nil {
+					cloneUuid := ExtractUuidWithoutHash(vs["traffic_clone_profile_ref"].(string), "trafficcloneprofile-.*.")
+					cloneName, foundClone := c.TrafficCloneProfileCache.AviCacheGetNameByUuid(cloneUuid)
+					if foundClone {
+						cloneKeys = append(cloneKeys, NamespaceName{Namespace: lib.GetTenant(), Name: cloneName.(string)})
+					}
+				}
+				if vs["network_security_policy_ref"] != nil {
+					nspUuid := ExtractUuidWithoutHash(vs["network_security_policy_ref"].(string), "networksecuritypolicy-.*.")
+					nspName, foundNsp := c.NetworkSecurityPolicyCache.AviCacheGetNameByUuid(nspUuid)
+					if foundNsp {
+						nspKeys = append(nspKeys, NamespaceName{Namespace: lib.GetTenant(), Name: nspName.(string)})
+					}
+				}
 				if vs["pool_group_ref"] != nil {
 					pgRef, ok := vs["pool_group_ref"].(string)
 					if ok {
@@ -2284,19 +2934,22 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 				}
 				// Populate the vscache meta object here.
 				vsMetaObj := AviVsCache{
-					Name:                 vs["name"].(string),
-					Uuid:                 vs["uuid"].(string),
-					VSVipKeyCollection:   vsVipKey,
-					HTTPKeyCollection:    httpKeys,
-					DSKeyCollection:      dsKeys,
-					SSLKeyCertCollection: sslKeys,
-					PGKeyCollection:      poolgroupKeys,
-					PoolKeyCollection:    poolKeys,
-					CloudConfigCksum:     vs["cloud_config_cksum"].(string),
-					SNIChildCollection:   sni_child_collection,
-					ParentVSRef:          parentVSKey,
-					L4PolicyCollection:   l4Keys,
-					ServiceMetadataObj:   svc_mdata_obj,
+					Name:                            vs["name"].(string),
+					Uuid:                            vs["uuid"].(string),
+					VSVipKeyCollection:              vsVipKey,
+					HTTPKeyCollection:               httpKeys,
+					DSKeyCollection:                 dsKeys,
+					SSLKeyCertCollection:            sslKeys,
+					PGKeyCollection:                 poolgroupKeys,
+					PoolKeyCollection:               poolKeys,
+					CloudConfigCksum:                vs["cloud_config_cksum"].(string),
+					FieldsCksum:                     VSDriftChecksum(vs),
+					SNIChildCollection:              sni_child_collection,
+					ParentVSRef:                     parentVSKey,
+					L4PolicyCollection:              l4Keys,
+					TrafficCloneProfileCollection:   cloneKeys,
+					NetworkSecurityPolicyCollection: nspKeys,
+					ServiceMetadataObj:              svc_mdata_obj,
 				}
 				if val, ok := vs["enable_rhi"]; ok {
 					vsMetaObj.EnableRhi = val.(bool)
@@ -3019,6 +3672,10 @@ func ConfigureSeGroupLabels(client *clients.AviClient, seGroup *models.ServiceEn
 	SetAdminTenant := session.SetTenant(lib.GetAdminTenant())
 	SetTenant := session.SetTenant(lib.GetTenant())
 	if len(labels) == 0 {
+		if lib.IsDryRunEnabled() {
+			utils.AviLog.Infof("dry-run, labels: %v would be set on Service Engine Group :%v", utils.Stringify(lib.GetLabels()), segName)
+			return nil
+		}
 		uri := "/api/serviceenginegroup/" + *seGroup.UUID
 		seGroup.Labels = lib.GetLabels()
 		response := models.ServiceEngineGroupAPIResponse{}
@@ -3053,7 +3710,7 @@ func ConfigureSeGroupLabels(client *clients.AviClient, seGroup *models.ServiceEn
 // DeConfigureSeGroupLabels deconfigures labels on the SeGroup.
 func DeConfigureSeGroupLabels() {
 
-	if !lib.AKOControlConfig().IsLeader() {
+	if !lib.AKOControlConfig().IsLeader() || lib.IsDryRunEnabled() {
 		return
 	}
 
@@ -0,0 +1,291 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package cache
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vmware/alb-sdk/go/clients"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+// The cloud_config_cksum of an object is set by AKO, and is not changed when the object is edited
+// outside AKO, e.g. from the Avi UI. To detect such edits, the cache also keeps a checksum of the
+// fields configured by AKO, computed from the object as returned by the controller.
+var poolDriftFields = []string{
+	"enabled",
+	"servers",
+	"default_server_port",
+	"lb_algorithm",
+	"lb_algorithm_hash",
+	"lb_algorithm_consistent_hash_hdr",
+	"health_monitor_refs",
+	"ssl_profile_ref",
+	"ssl_key_and_certificate_ref",
+	"pki_profile_ref",
+	"application_persistence_profile_ref",
+	"placement_networks",
+	"connection_ramp_duration",
+	"max_concurrent_connections_per_server",
+	"server_timeout",
+	"graceful_disable_timeout",
+	"request_queue_enabled",
+	"request_queue_depth",
+}
+
+// The fields of the pool servers set by AKO. The other ones, e.g. the vm_ref and the
+// discovered_networks, may be set by the controller.
+var poolServerDriftFields = []string{"ip", "port", "enabled", "ratio"}
+
+var vsDriftFields = []string{
+	"enabled",
+	"traffic_enabled",
+	"services",
+	"vsvip_ref",
+	"pool_ref",
+	"pool_group_ref",
+	"application_profile_ref",
+	"network_profile_ref",
+	"analytics_profile_ref",
+	"ssl_profile_ref",
+	"ssl_key_and_certificate_refs",
+	"waf_policy_ref",
+	"error_page_profile_ref",
+	"network_security_policy_ref",
+	"http_policies",
+	"vs_datascripts",
+	"l4_policies",
+	"se_group_ref",
+	"vrf_context_ref",
+}
+
+// DriftedObject is a pool or a virtualservice of AKO which was changed in the controller outside AKO.
+type DriftedObject struct {
+	// Pool or VirtualService
+	ObjectType string
+	Key        NamespaceName
+	// VSKey is the virtualservice whose model configures the object.
+	VSKey NamespaceName
+}
+
+func PoolDriftChecksum(pool map[string]interface{}) string {
+	return driftChecksum(pool, poolDriftFields)
+}
+
+func VSDriftChecksum(vs map[string]interface{}) string {
+	return driftChecksum(vs, vsDriftFields)
+}
+
+func driftChecksum(obj map[string]interface{}, fields []string) string {
+	var checksum uint32
+	for _, field := range fields {
+		if val, ok := obj[field]; ok && val != nil {
+			checksum += utils.Hash(field + utils.Stringify(normalizeDriftField(field, val)))
+		}
+	}
+	return strconv.Itoa(int(checksum))
+}
+
+// normalizeDriftField returns the value of a field in a form which doesn't depend on how the object
+// was fetched. The refs are reduced to the uuids, as the responses to a GET with include_name carry
+// the names of the objects after the uuids, and the lists are sorted.
+func normalizeDriftField(field string, val interface{}) interface{} {
+	switch v := val.(type) {
+	case string:
+		if strings.HasSuffix(field, "_ref") {
+			ref := strings.Split(v, "#")[0]
+			return ref[strings.LastIndex(ref, "/")+1:]
+		}
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			if server, ok := item.(map[string]interface{}); ok && field == "servers" {
+				item = selectDriftFields(server, poolServerDriftFields)
+			}
+			items = append(items, utils.Stringify(normalizeDriftField(strings.TrimSuffix(field, "s"), item)))
+		}
+		sort.Strings(items)
+		return items
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, fieldVal := range v {
+			normalized[key] = normalizeDriftField(key, fieldVal)
+		}
+		return normalized
+	}
+	return val
+}
+
+func selectDriftFields(obj map[string]interface{}, fields []string) map[string]interface{} {
+	selected := make(map[string]interface{})
+	for _, field := range fields {
+		if val, ok := obj[field]; ok {
+			selected[field] = val
+		}
+	}
+	return selected
+}
+
+// AviDetectDrift fetches the pools and the virtualservices of AKO from the controller, and compares
+// the checksums of their fields with the ones of the objects last synced by AKO. With repair, the
+// checksums of the drifted objects are reset in the cache, so that the objects are updated to the
+// desired state on the next sync of their models. Otherwise the fetched objects are taken as synced,
+// so that a change is reported once.
+func (c *AviObjCache) AviDetectDrift(client *clients.AviClient, cloud string, repair bool) ([]DriftedObject, error) {
+	var driftedObjs []DriftedObject
+	poolCksums := make(map[NamespaceName]string)
+	if err := c.aviGetAllDriftChecksums(client, "pool", cloud, PoolDriftChecksum, poolCksums); err != nil {
+		return driftedObjs, err
+	}
+	vsCksums := make(map[NamespaceName]string)
+	if err := c.aviGetAllDriftChecksums(client, "virtualservice", cloud, VSDriftChecksum, vsCksums); err != nil {
+		return driftedObjs, err
+	}
+
+	for k, cksum := range poolCksums {
+		poolCache, found := c.PoolCache.AviCacheGet(k)
+		if !found {
+			continue
+		}
+		poolCacheObj, ok := poolCache.(*AviPoolCache)
+		if !ok || poolCacheObj.FieldsCksum == "" || poolCacheObj.FieldsCksum == cksum {
+			continue
+		}
+		utils.AviLog.Warnf("Pool %s/%s was changed outside AKO, stored checksum: %s, checksum in the controller: %s", k.Namespace, k.Name, poolCacheObj.FieldsCksum, cksum)
+		// The pool cache entries are not changed once added, so the entry is replaced by a copy.
+		// If the rest layer updated the pool meanwhile, the entry is left as is, and the pool
+		// is checked again by the next drift detection.
+		driftedPool := *poolCacheObj
+		if repair {
+			driftedPool.CloudConfigCksum = ""
+		} else {
+			driftedPool.FieldsCksum = cksum
+		}
+		if !c.PoolCache.AviCacheReplace(k, poolCache, &driftedPool) {
+			continue
+		}
+		driftedObjs = append(driftedObjs, DriftedObject{ObjectType: "Pool", Key: k, VSKey: c.getDriftedPoolVSKey(k)})
+	}
+
+	for k, cksum := range vsCksums {
+		vsCache, found := c.VsCacheMeta.AviCacheGet(k)
+		if !found {
+			continue
+		}
+		vsCacheObj, ok := vsCache.(*AviVsCache)
+		if !ok {
+			continue
+		}
+		vsCacheObj.VSCacheLock.Lock()
+		if vsCacheObj.FieldsCksum == "" || vsCacheObj.FieldsCksum == cksum {
+			vsCacheObj.VSCacheLock.Unlock()
+			continue
+		}
+		utils.AviLog.Warnf("Virtualservice %s/%s was changed outside AKO, stored checksum: %s, checksum in the controller: %s", k.Namespace, k.Name, vsCacheObj.FieldsCksum, cksum)
+		if repair {
+			vsCacheObj.CloudConfigCksum = ""
+		} else {
+			vsCacheObj.FieldsCksum = cksum
+		}
+		vsCacheObj.VSCacheLock.Unlock()
+		driftedObjs = append(driftedObjs, DriftedObject{ObjectType: "VirtualService", Key: k, VSKey: getDriftedVSParentKey(k, vsCacheObj)})
+	}
+	return driftedObjs, nil
+}
+
+func (c *AviObjCache) aviGetAllDriftChecksums(client *clients.AviClient, objType, cloud string, checksumFunc func(map[string]interface{}) string, cksums map[NamespaceName]string, overrideUri ...NextPage) error {
+	var uri string
+	if len(overrideUri) == 1 {
+		uri = overrideUri[0].NextURI
+	} else {
+		uri = "/api/" + objType + "/?" + "include_name=true&cloud_ref.name=" + cloud + "&created_by=" + lib.AKOUser + "&page_size=100"
+	}
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for %s %v", uri, objType, err)
+		return err
+	}
+	var elems []map[string]interface{}
+	if err = json.Unmarshal(result.Results, &elems); err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal %s data, err: %v", objType, err)
+		return err
+	}
+	for _, elem := range elems {
+		name, ok := elem["name"].(string)
+		if !ok {
+			continue
+		}
+		cksums[NamespaceName{Namespace: driftObjectTenant(elem), Name: name}] = checksumFunc(elem)
+	}
+	if result.Next != "" {
+		// It has a next page, let's recursively call the same method.
+		next_uri := strings.Split(result.Next, "/api/"+objType)
+		if len(next_uri) > 1 {
+			nextPage := NextPage{NextURI: "/api/" + objType + next_uri[1]}
+			return c.aviGetAllDriftChecksums(client, objType, cloud, checksumFunc, cksums, nextPage)
+		}
+	}
+	return nil
+}
+
+// driftObjectTenant returns the tenant of an object fetched with include_name, from the name at the
+// end of its tenant_ref.
+func driftObjectTenant(obj map[string]interface{}) string {
+	if tenantRef, ok := obj["tenant_ref"].(string); ok {
+		if idx := strings.LastIndex(tenantRef, "#"); idx >= 0 && idx < len(tenantRef)-1 {
+			return tenantRef[idx+1:]
+		}
+	}
+	return lib.GetTenant()
+}
+
+// getDriftedPoolVSKey returns the virtualservice whose model configures the pool.
+func (c *AviObjCache) getDriftedPoolVSKey(poolKey NamespaceName) NamespaceName {
+	for _, vsKey := range c.VsCacheMeta.AviGetAllKeys() {
+		vsCache, found := c.VsCacheMeta.AviCacheGet(vsKey)
+		if !found {
+			continue
+		}
+		vsCacheObj, ok := vsCache.(*AviVsCache)
+		if !ok {
+			continue
+		}
+		vsCacheObj.VSCacheLock.RLock()
+		hasPool := utils.HasElem(vsCacheObj.PoolKeyCollection, poolKey)
+		vsCacheObj.VSCacheLock.RUnlock()
+		if hasPool {
+			return getDriftedVSParentKey(vsKey, vsCacheObj)
+		}
+	}
+	return NamespaceName{}
+}
+
+// getDriftedVSParentKey returns the parent of the SNI, EVH and passthrough child virtualservices,
+// as the models are built for the parents.
+func getDriftedVSParentKey(vsKey NamespaceName, vsCacheObj *AviVsCache) NamespaceName {
+	if vsCacheObj.ParentVSRef != (NamespaceName{}) {
+		return vsCacheObj.ParentVSRef
+	}
+	if vsCacheObj.ServiceMetadataObj.PassthroughParentRef != "" {
+		return NamespaceName{Namespace: vsKey.Namespace, Name: vsCacheObj.ServiceMetadataObj.PassthroughParentRef}
+	}
+	return vsKey
+}
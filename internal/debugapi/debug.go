@@ -0,0 +1,501 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+// Package debugapi serves read-only views of the AKO internal state, the graph models,
+// the Avi object cache and the work queues, over the AKO API server.
+package debugapi
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/rest"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	ModelsRoute = "/api/debug/models"
+	ModelRoute  = "/api/debug/model"
+	CacheRoute  = "/api/debug/cache"
+	K8sRoute    = "/api/debug/k8s"
+	QueuesRoute = "/api/debug/queues"
+	// SnapshotRoute serves the whole Avi object cache, in the format read by
+	// cache.ReadCacheSnapshot.
+	SnapshotRoute = "/api/debug/snapshot"
+	// PlanRoute serves the changes computed in the dry-run mode.
+	PlanRoute = "/api/debug/plan"
+)
+
+// DebugModel implements ApiModel. The endpoints are served only to the requests that carry
+// the token set in the debugApiToken key of the avi-secret as a bearer token, and are
+// disabled when the key is not set.
+type DebugModel struct{}
+
+type ModelNode struct {
+	Type string          `json:"type"`
+	Node json.RawMessage `json:"node"`
+}
+
+type ModelResponse struct {
+	Name          string      `json:"name"`
+	GraphChecksum uint32      `json:"graph_checksum"`
+	RetryCount    int         `json:"retry_count"`
+	Nodes         []ModelNode `json:"nodes"`
+}
+
+// CacheResponse holds the cache entries of a virtualservice, of its child virtualservices and
+// of all the objects referred by them. The checksums are part of the entries.
+type CacheResponse struct {
+	VirtualServices         []*cache.AviVsCache `json:"virtualservices"`
+	VSVips                  []interface{}       `json:"vsvips"`
+	PoolGroups              []interface{}       `json:"poolgroups"`
+	Pools                   []interface{}       `json:"pools"`
+	HTTPPolicySets          []interface{}       `json:"httppolicysets"`
+	DataScripts             []interface{}       `json:"datascripts"`
+	SSLKeyCerts             []interface{}       `json:"sslkeycerts"`
+	L4PolicySets            []interface{}       `json:"l4policysets"`
+	TrafficCloneProfiles    []interface{}       `json:"trafficcloneprofiles"`
+	NetworkSecurityPolicies []interface{}       `json:"networksecuritypolicies"`
+}
+
+type K8sObject struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+type WorkerStatus struct {
+	Id          uint32 `json:"id"`
+	Depth       int    `json:"depth"`
+	InFlightKey string `json:"in_flight_key,omitempty"`
+}
+
+type QueueStatus struct {
+	Name    string         `json:"name"`
+	Depth   int            `json:"depth"`
+	Workers []WorkerStatus `json:"workers"`
+	// DeadLetterKeys are the keys which failed to sync in all the attempts.
+	DeadLetterKeys []utils.DeadLetterKey `json:"dead_letter_keys,omitempty"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (a *DebugModel) InitModel() {}
+
+func (a *DebugModel) ApiOperationMap(prometheusEnabled bool, reg *prometheus.Registry) []models.OperationMap {
+	return []models.OperationMap{
+		{Route: ModelsRoute, Method: http.MethodGet, Handler: authorize(getModels)},
+		{Route: ModelRoute, Method: http.MethodGet, Handler: authorize(getModel)},
+		{Route: CacheRoute, Method: http.MethodGet, Handler: authorize(getCache)},
+		{Route: K8sRoute, Method: http.MethodGet, Handler: authorize(getK8sObjects)},
+		{Route: QueuesRoute, Method: http.MethodGet, Handler: authorize(getQueues)},
+		{Route: SnapshotRoute, Method: http.MethodGet, Handler: authorize(getSnapshot)},
+		{Route: PlanRoute, Method: http.MethodGet, Handler: authorize(getPlan)},
+	}
+}
+
+func respondError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(errorResponse{Error: msg})
+}
+
+func authorize(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, _ := utils.SharedCtrlProp().AviCacheGet(utils.ENV_DEBUG_API_TOKEN)
+		expected, _ := token.(string)
+		if expected == "" {
+			respondError(w, http.StatusForbidden, "debug API is disabled, debugApiToken is not set in "+lib.AviSecret)
+			return
+		}
+		provided := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(expected)) != 1 {
+			respondError(w, http.StatusUnauthorized, "invalid debug API token")
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// getGraph returns the model for the name query parameter, the response is written
+// when the model is not found.
+func getGraph(w http.ResponseWriter, r *http.Request) (string, *nodes.AviObjectGraph) {
+	modelName := r.URL.Query().Get("name")
+	if modelName == "" {
+		respondError(w, http.StatusBadRequest, "name is required")
+		return "", nil
+	}
+	found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	graph, ok := aviModel.(*nodes.AviObjectGraph)
+	if !found || !ok || graph == nil {
+		respondError(w, http.StatusNotFound, "model "+modelName+" not found")
+		return modelName, nil
+	}
+	return modelName, graph
+}
+
+func getModels(w http.ResponseWriter, r *http.Request) {
+	modelNames := []string{}
+	for modelName := range objects.SharedAviGraphLister().GetAll().(map[string]interface{}) {
+		modelNames = append(modelNames, modelName)
+	}
+	sort.Strings(modelNames)
+	utils.Respond(w, modelNames)
+}
+
+func getModel(w http.ResponseWriter, r *http.Request) {
+	modelName, graph := getGraph(w, r)
+	if graph == nil {
+		return
+	}
+	response, err := modelResponse(modelName, graph)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.Respond(w, response)
+}
+
+// modelResponse serializes the nodes under the model lock, the graph layer keeps updating
+// the saved models in place.
+func modelResponse(modelName string, graph *nodes.AviObjectGraph) (*ModelResponse, error) {
+	graph.Lock.RLock()
+	defer graph.Lock.RUnlock()
+	response := &ModelResponse{
+		Name:          modelName,
+		GraphChecksum: graph.GraphChecksum,
+		RetryCount:    graph.RetryCount,
+		Nodes:         []ModelNode{},
+	}
+	for _, node := range graph.GetOrderedNodes() {
+		nodeVal, err := serializeNode(node)
+		if err != nil {
+			return nil, err
+		}
+		nodeBytes, err := json.Marshal(nodeVal)
+		if err != nil {
+			return nil, err
+		}
+		response.Nodes = append(response.Nodes, ModelNode{Type: node.GetNodeType(), Node: nodeBytes})
+	}
+	return response, nil
+}
+
+func getCache(w http.ResponseWriter, r *http.Request) {
+	modelName := r.URL.Query().Get("name")
+	tenantVS := strings.SplitN(modelName, "/", 2)
+	if len(tenantVS) != 2 {
+		respondError(w, http.StatusBadRequest, "name must be of the form <tenant>/<model>")
+		return
+	}
+	aviObjCache := cache.SharedAviObjCache()
+	vsKey := cache.NamespaceName{Namespace: tenantVS[0], Name: tenantVS[1]}
+	vsCache, found := aviObjCache.VsCacheMeta.AviCacheGet(vsKey)
+	if !found {
+		respondError(w, http.StatusNotFound, "cache for virtualservice "+modelName+" not found")
+		return
+	}
+	vsKeys := []cache.NamespaceName{vsKey}
+	if vsCacheObj, ok := vsCache.(*cache.AviVsCache); ok {
+		vsCacheObj.VSCacheLock.RLock()
+		childUuids := append([]string{}, vsCacheObj.SNIChildCollection...)
+		vsCacheObj.VSCacheLock.RUnlock()
+		for _, childUuid := range childUuids {
+			if childKey, ok := aviObjCache.VsCacheMeta.AviCacheGetKeyByUuid(childUuid); ok {
+				vsKeys = append(vsKeys, childKey.(cache.NamespaceName))
+			}
+		}
+	}
+
+	response := CacheResponse{}
+	for _, key := range vsKeys {
+		vsCache, found := aviObjCache.VsCacheMeta.AviCacheGet(key)
+		if !found {
+			continue
+		}
+		vsCacheObj, ok := vsCache.(*cache.AviVsCache)
+		if !ok {
+			continue
+		}
+		vsCopy, ok := vsCacheObj.GetVSCopy()
+		if !ok {
+			continue
+		}
+		response.VirtualServices = append(response.VirtualServices, vsCopy)
+		response.VSVips = append(response.VSVips, cacheEntries(aviObjCache.VSVIPCache, vsCopy.VSVipKeyCollection)...)
+		response.PoolGroups = append(response.PoolGroups, cacheEntries(aviObjCache.PgCache, vsCopy.PGKeyCollection)...)
+		response.Pools = append(response.Pools, cacheEntries(aviObjCache.PoolCache, vsCopy.PoolKeyCollection)...)
+		response.HTTPPolicySets = append(response.HTTPPolicySets, cacheEntries(aviObjCache.HTTPPolicyCache, vsCopy.HTTPKeyCollection)...)
+		response.DataScripts = append(response.DataScripts, cacheEntries(aviObjCache.DSCache, vsCopy.DSKeyCollection)...)
+		response.SSLKeyCerts = append(response.SSLKeyCerts, cacheEntries(aviObjCache.SSLKeyCache, vsCopy.SSLKeyCertCollection)...)
+		response.L4PolicySets = append(response.L4PolicySets, cacheEntries(aviObjCache.L4PolicyCache, vsCopy.L4PolicyCollection)...)
+		response.TrafficCloneProfiles = append(response.TrafficCloneProfiles, cacheEntries(aviObjCache.TrafficCloneProfileCache, vsCopy.TrafficCloneProfileCollection)...)
+		response.NetworkSecurityPolicies = append(response.NetworkSecurityPolicies, cacheEntries(aviObjCache.NetworkSecurityPolicyCache, vsCopy.NetworkSecurityPolicyCollection)...)
+	}
+	utils.Respond(w, response)
+}
+
+func cacheEntries(objCache *cache.AviCache, keys []cache.NamespaceName) []interface{} {
+	var entries []interface{}
+	for _, key := range keys {
+		if entry, found := objCache.AviCacheGet(key); found {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func getK8sObjects(w http.ResponseWriter, r *http.Request) {
+	_, graph := getGraph(w, r)
+	if graph == nil {
+		return
+	}
+	graph.Lock.RLock()
+	var nodeMaps []interface{}
+	for _, node := range graph.GetOrderedNodes() {
+		nodeMap, err := serializeNode(node)
+		if err != nil {
+			graph.Lock.RUnlock()
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		nodeMaps = append(nodeMaps, nodeMap)
+	}
+	graph.Lock.RUnlock()
+
+	k8sObjects := make(map[K8sObject]bool)
+	for _, nodeMap := range nodeMaps {
+		collectK8sObjects(nodeMap, k8sObjects)
+	}
+	addBackendServices(k8sObjects)
+	response := []K8sObject{}
+	for k8sObject := range k8sObjects {
+		response = append(response, k8sObject)
+	}
+	sort.Slice(response, func(i, j int) bool {
+		if response[i].Kind != response[j].Kind {
+			return response[i].Kind < response[j].Kind
+		}
+		if response[i].Namespace != response[j].Namespace {
+			return response[i].Namespace < response[j].Namespace
+		}
+		return response[i].Name < response[j].Name
+	})
+	utils.Respond(w, response)
+}
+
+// redactedFields are the fields of the serialized model nodes which carry secrets, the private
+// keys of the TLS certificates and the client and server secrets of the OAuth settings.
+var redactedFields = map[string]bool{
+	"Key":          true,
+	"clientSecret": true,
+	"serverSecret": true,
+}
+
+const redactedValue = "<redacted>"
+
+// serializeNode returns the model node as a generic json value, with the values of the
+// secret fields, of the node and of its child nodes, replaced by a placeholder.
+func serializeNode(node nodes.AviModelNode) (interface{}, error) {
+	var nodeVal interface{}
+	nodeBytes, err := json.Marshal(node)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(nodeBytes, &nodeVal); err != nil {
+		return nil, err
+	}
+	redactSecrets(nodeVal)
+	return nodeVal, nil
+}
+
+func redactSecrets(val interface{}) {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if redactedFields[key] {
+				if child != nil && child != "" {
+					v[key] = redactedValue
+				}
+				continue
+			}
+			redactSecrets(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			redactSecrets(child)
+		}
+	}
+}
+
+// collectK8sObjects walks a serialized model node, along with its child nodes, and collects
+// the K8s objects recorded in the service metadata and the markers of the nodes.
+func collectK8sObjects(val interface{}, k8sObjects map[K8sObject]bool) {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			switch key {
+			case "ServiceMetadata":
+				var metadata lib.ServiceMetadataObj
+				if remarshal(child, &metadata) {
+					addServiceMetadataObjects(metadata, k8sObjects)
+				}
+			case "AviMarkers":
+				var markers utils.AviObjectMarkers
+				if remarshal(child, &markers) {
+					addMarkerObjects(markers, k8sObjects)
+				}
+			default:
+				collectK8sObjects(child, k8sObjects)
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			collectK8sObjects(child, k8sObjects)
+		}
+	}
+}
+
+func remarshal(val interface{}, out interface{}) bool {
+	valBytes, err := json.Marshal(val)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(valBytes, out) == nil
+}
+
+func ingressKind(isMCIIngress bool) string {
+	if isMCIIngress {
+		return lib.MultiClusterIngress
+	}
+	if utils.GetInformers().RouteInformer != nil {
+		return utils.OshiftRoute
+	}
+	return utils.Ingress
+}
+
+func addNamespacedObject(kind, namespacedName string, k8sObjects map[K8sObject]bool) {
+	nsName := strings.SplitN(namespacedName, "/", 2)
+	if len(nsName) != 2 || nsName[1] == "" {
+		return
+	}
+	k8sObjects[K8sObject{Kind: kind, Namespace: nsName[0], Name: nsName[1]}] = true
+}
+
+func addServiceMetadataObjects(metadata lib.ServiceMetadataObj, k8sObjects map[K8sObject]bool) {
+	kind := ingressKind(metadata.IsMCIIngress)
+	for _, ingress := range metadata.NamespaceIngressName {
+		addNamespacedObject(kind, ingress, k8sObjects)
+	}
+	if metadata.IngressName != "" {
+		addNamespacedObject(kind, metadata.Namespace+"/"+metadata.IngressName, k8sObjects)
+	}
+	for _, svc := range metadata.NamespaceServiceName {
+		addNamespacedObject(utils.Service, svc, k8sObjects)
+	}
+	if metadata.Gateway != "" {
+		addNamespacedObject(lib.Gateway, metadata.Gateway, k8sObjects)
+	}
+	if metadata.L7Rule != "" {
+		addNamespacedObject(lib.L7Rule, metadata.L7Rule, k8sObjects)
+	}
+	if metadata.AviInfraSetting != "" {
+		k8sObjects[K8sObject{Kind: lib.AviInfraSetting, Name: metadata.AviInfraSetting}] = true
+	}
+}
+
+func addMarkerObjects(markers utils.AviObjectMarkers, k8sObjects map[K8sObject]bool) {
+	if markers.Namespace != "" {
+		for _, ingress := range markers.IngressName {
+			addNamespacedObject(ingressKind(false), markers.Namespace+"/"+ingress, k8sObjects)
+		}
+		if markers.ServiceName != "" {
+			addNamespacedObject(utils.Service, markers.Namespace+"/"+markers.ServiceName, k8sObjects)
+		}
+		if markers.GatewayName != "" {
+			addNamespacedObject(lib.Gateway, markers.Namespace+"/"+markers.GatewayName, k8sObjects)
+		}
+	}
+	if markers.InfrasettingName != "" {
+		k8sObjects[K8sObject{Kind: lib.AviInfraSetting, Name: markers.InfrasettingName}] = true
+	}
+}
+
+// addBackendServices adds the backend services of the ingresses and the routes, the pools
+// built for them don't record the services.
+func addBackendServices(k8sObjects map[K8sObject]bool) {
+	for k8sObject := range k8sObjects {
+		var svcLister *objects.SvcLister
+		switch k8sObject.Kind {
+		case utils.Ingress:
+			svcLister = objects.SharedSvcLister()
+		case utils.OshiftRoute:
+			svcLister = objects.OshiftRouteSvcLister()
+		default:
+			continue
+		}
+		_, svcNames := svcLister.IngressMappings(k8sObject.Namespace).GetIngToSvc(k8sObject.Name)
+		for _, svcName := range svcNames {
+			k8sObjects[K8sObject{Kind: utils.Service, Namespace: k8sObject.Namespace, Name: svcName}] = true
+		}
+	}
+}
+
+func getSnapshot(w http.ResponseWriter, r *http.Request) {
+	utils.Respond(w, cache.SharedAviObjCache().Snapshot())
+}
+
+func getPlan(w http.ResponseWriter, r *http.Request) {
+	if !lib.IsDryRunEnabled() {
+		respondError(w, http.StatusNotFound, "AKO is not running in the dry-run mode")
+		return
+	}
+	utils.Respond(w, rest.SharedPlan().Entries())
+}
+
+func getQueues(w http.ResponseWriter, r *http.Request) {
+	response := []QueueStatus{}
+	sharedQueue := utils.GetSharedWorkQueue()
+	if sharedQueue == nil {
+		utils.Respond(w, response)
+		return
+	}
+	for _, queueName := range sharedQueue.GetQueueNames() {
+		queue := sharedQueue.GetQueueByName(queueName)
+		inFlightKeys := queue.InFlightKeys()
+		queueStatus := QueueStatus{Name: queueName, Workers: []WorkerStatus{}, DeadLetterKeys: queue.DeadLetterKeys()}
+		for workerId, depth := range queue.Depth() {
+			queueStatus.Depth += depth
+			queueStatus.Workers = append(queueStatus.Workers, WorkerStatus{
+				Id:          uint32(workerId),
+				Depth:       depth,
+				InFlightKey: inFlightKeys[uint32(workerId)],
+			})
+		}
+		response = append(response, queueStatus)
+	}
+	utils.Respond(w, response)
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package k8s
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	akov1alpha2 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha2"
+	akov1beta1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1beta1"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+// crdAdmissionReady is set once the bootup sync is done, since until then the CRD caches
+// used by the checks, such as the FQDN to HostRule mapping, are incomplete.
+var crdAdmissionReady atomic.Bool
+
+func IsCRDAdmissionReady() bool {
+	return crdAdmissionReady.Load()
+}
+
+// ValidateCRDForAdmission runs the checks done on the AKO CRDs before ingestion, on an object
+// which is yet to be stored. It does not update the status of the object, and looks up the
+// refs to Avi objects in the ref index before looking them up on the controller.
+func ValidateCRDForAdmission(obj interface{}) error {
+	refs := refChecker{useIndex: true}
+	switch crd := obj.(type) {
+	case *akov1beta1.HostRule:
+		return checkHostRuleObj(lib.HostRule+"/"+utils.ObjKey(crd), crd, refs)
+	case *akov1beta1.HTTPRule:
+		return checkHTTPRuleObj(lib.HTTPRule+"/"+utils.ObjKey(crd), crd, refs)
+	case *akov1beta1.AviInfraSetting:
+		return checkAviInfraSettingObj(lib.AviInfraSetting+"/"+utils.ObjKey(crd), crd, refs)
+	case *akov1alpha2.SSORule:
+		return checkSSORuleObj(lib.SSORule+"/"+utils.ObjKey(crd), crd, refs)
+	case *akov1alpha2.L4Rule:
+		return checkL4RuleObj(lib.L4Rule+"/"+utils.ObjKey(crd), crd, refs)
+	case *akov1alpha2.L7Rule:
+		return checkL7RuleObj(lib.L7Rule+"/"+utils.ObjKey(crd), crd, refs)
+	}
+	return fmt.Errorf("unsupported object type %T", obj)
+}
@@ -56,7 +56,9 @@ func PopulateCache() error {
 	aviObjCache := avicache.SharedAviObjCache()
 	// Randomly pickup a client.
 	if aviRestClientPool != nil && len(aviRestClientPool.AviClient) > 0 {
+		start := time.Now()
 		_, _, err = aviObjCache.AviObjCachePopulate(aviRestClientPool.AviClient, lib.AKOControlConfig().ControllerVersion(), utils.CloudName)
+		lib.ObserveCacheRefreshTime(lib.MetricCachePopulate, start)
 		if err != nil {
 			utils.AviLog.Warnf("failed to populate avi cache with error: %v", err.Error())
 			return err
@@ -503,8 +505,15 @@ func (c *AviController) InitController(informers K8sinformers, registeredInforme
 		numGraphWorkers = 8
 	}
 	graphQueueParams := utils.WorkerQueue{NumWorkers: numGraphWorkers, WorkqueueName: utils.GraphLayer}
+	graphQueueParams.RequeueFunc = func(key interface{}) {
+		lib.IncrementQueueCounter(utils.GraphLayer)
+	}
 	statusQueueParams := utils.WorkerQueue{NumWorkers: numGraphWorkers, WorkqueueName: utils.StatusQueue}
 	graphQueue = utils.SharedWorkQueue(&ingestionQueueParams, &graphQueueParams, &slowRetryQParams, &fastRetryQParams, &statusQueueParams).GetQueueByName(utils.GraphLayer)
+	graphQueue.SetMaxAttempts(lib.GetSyncMaxAttempts())
+	if drainPeriod := os.Getenv(lib.GRACEFUL_DRAIN_PERIOD); drainPeriod != "" && drainPeriod != "0" && !lib.IsGracefulDrainSupported() {
+		utils.AviLog.Warnf("%s requires %s in ClusterIP mode, graceful draining of pool servers is disabled", lib.GRACEFUL_DRAIN_PERIOD, lib.ENABLE_ENDPOINTSLICE)
+	}
 
 	err := PopulateCache()
 	if err != nil {
@@ -581,6 +590,7 @@ func (c *AviController) InitController(informers K8sinformers, registeredInforme
 	} else {
 		lib.AKOControlConfig().PodEventf(corev1.EventTypeNormal, lib.AKOReady, "AKO is now listening for Object updates in the cluster")
 	}
+	crdAdmissionReady.Store(true)
 
 	ingestionQueue := utils.SharedWorkQueue().GetQueueByName(utils.ObjectIngestionLayer)
 	ingestionQueue.SyncFunc = SyncFromIngestionLayer
@@ -771,7 +781,12 @@ func (c *AviController) FullSync() {
 	if len(aviRestClientPool.AviClient) > 0 {
 		aviObjCache.AviClusterStatusPopulate(aviRestClientPool.AviClient[0])
 		if !lib.IsWCP() {
+			start := time.Now()
 			aviObjCache.AviCacheRefresh(aviRestClientPool.AviClient[0], utils.CloudName)
+			lib.ObserveCacheRefreshTime(lib.MetricCacheRefresh, start)
+			if lib.GetDriftDetectionPolicy() != "" {
+				c.syncDriftedObjects(aviRestClientPool.AviClient[0])
+			}
 		} else {
 			// In this case we just sync the Gateway status to the LB status
 			restlayer := rest.NewRestOperations(aviObjCache, aviRestClientPool)
@@ -798,6 +813,41 @@ func (c *AviController) FullSync() {
 	}
 }
 
+// syncDriftedObjects detects the pools and virtualservices of AKO changed in the controller outside
+// AKO. An event is raised for each of them, and with the repair policy, their models are published
+// to the rest layer to apply the desired state again.
+func (c *AviController) syncDriftedObjects(client *clients.AviClient) {
+	repair := lib.GetDriftDetectionPolicy() == lib.DriftDetectionRepair
+	driftedObjs, err := avicache.SharedAviObjCache().AviDetectDrift(client, utils.CloudName, repair)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to detect the drift of the Avi objects: %v", err)
+		return
+	}
+	modelsToRepair := make(map[string]struct{})
+	for _, obj := range driftedObjs {
+		lib.IncrementDriftedObjectCounter(obj.ObjectType)
+		if !repair {
+			lib.AKOControlConfig().PodEventf(corev1.EventTypeWarning, lib.AviObjectDrifted, "%s %s was changed outside AKO", obj.ObjectType, obj.Key.Name)
+			continue
+		}
+		lib.AKOControlConfig().PodEventf(corev1.EventTypeWarning, lib.AviObjectDrifted, "%s %s was changed outside AKO, applying the desired configuration again", obj.ObjectType, obj.Key.Name)
+		if obj.VSKey != (avicache.NamespaceName{}) {
+			modelsToRepair[lib.GetModelName(obj.VSKey.Namespace, obj.VSKey.Name)] = struct{}{}
+		}
+	}
+	sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
+	for modelName := range modelsToRepair {
+		// The objects of a deleted model are removed by the rest layer, hence only the existing models
+		// are published.
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); !found || aviModel == nil {
+			utils.AviLog.Warnf("Model %s of the drifted objects not found, not repairing the objects", modelName)
+			continue
+		}
+		utils.AviLog.Infof("Publishing model %s to apply the desired configuration of the drifted objects", modelName)
+		nodes.PublishKeyToRestLayer(modelName, "fullsync", sharedQueue)
+	}
+}
+
 func (c *AviController) FullSyncK8s(sync bool) error {
 	if c.DisableSync {
 		utils.AviLog.Infof("Sync disabled, skipping full sync")
@@ -1252,6 +1302,7 @@ func (c *AviController) FullSyncK8s(sync bool) error {
 	if sync {
 		c.publishAllParentVSKeysToRestLayer()
 	}
+	lib.SetLastFullSyncTime()
 	return nil
 }
 
@@ -1403,6 +1454,10 @@ func SyncFromIngestionLayer(key interface{}, wg *sync.WaitGroup) error {
 		lib.DecrementQueueCounter(utils.ObjectIngestionLayer)
 		return nil
 	}
+	if addTime, ok := utils.SharedWorkQueue().GetQueueByName(utils.ObjectIngestionLayer).InFlightKeyAddTime(keyStr); ok {
+		lib.ObserveLayerProcessingTime(lib.MetricLayerIngestion, addTime)
+	}
+	defer lib.ObserveLayerProcessingTime(lib.MetricLayerGraph, time.Now())
 	nodes.DequeueIngestion(keyStr, false)
 	return nil
 }
@@ -1440,13 +1495,13 @@ func SyncFromNodesLayer(key interface{}, wg *sync.WaitGroup) error {
 	aviclient := avicache.SharedAVIClients()
 	restlayer := rest.NewRestOperations(cache, aviclient)
 	restlayer.DequeueNodes(keyStr)
-	return nil
+	return restlayer.SyncError()
 }
 
 func SyncFromStatusQueue(key interface{}, wg *sync.WaitGroup) error {
+	defer lib.ObserveLayerProcessingTime(lib.MetricLayerStatus, time.Now())
 	publisher := status.NewStatusPublisher()
-	publisher.DequeueStatus(key)
-	return nil
+	return publisher.DequeueStatus(key)
 }
 
 // Controller Specific method
@@ -27,6 +27,7 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 	oshiftclient "github.com/openshift/client-go/route/clientset/versioned"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
@@ -124,6 +125,12 @@ func isNodeUpdated(oldNode, newNode *corev1.Node) bool {
 		return true
 	}
 
+	// Terminating nodes are drained from the pools in NodePort mode.
+	if lib.IsNodePortMode() && lib.GetGracefulDrainPeriod() > 0 &&
+		lib.IsNodeTerminating(oldNode) != lib.IsNodeTerminating(newNode) {
+		return true
+	}
+
 	cniPlugin := lib.GetCNIPlugin()
 	if (cniPlugin == lib.CALICO_CNI) && (!reflect.DeepEqual(oldNode.Annotations[lib.CalicoIPv4AddressAnnotation], newNode.Annotations[lib.CalicoIPv4AddressAnnotation]) ||
 		!reflect.DeepEqual(oldNode.Annotations[lib.CalicoIPv6AddressAnnotation], newNode.Annotations[lib.CalicoIPv6AddressAnnotation])) {
@@ -601,6 +608,95 @@ func AddPodEventHandler(numWorkers uint32, c *AviController) cache.ResourceEvent
 	return podEventHandler
 }
 
+func AddEndpointSliceEventHandler(numWorkers uint32, c *AviController) cache.ResourceEventHandler {
+	epSliceEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			epSlice := obj.(*discoveryv1.EndpointSlice)
+			svcName, ok := lib.GetServiceNameForEndpointSlice(epSlice)
+			if !ok {
+				return
+			}
+			namespace := epSlice.Namespace
+			// EndpointSlices are merged per service, hence the key is that of the service's Endpoints.
+			key := utils.Endpoints + "/" + namespace + "/" + svcName
+			if lib.IsNamespaceBlocked(namespace) {
+				utils.AviLog.Debugf("key: %s, msg: EndpointSlice Add event: Namespace: %s didn't qualify filter", key, namespace)
+				return
+			}
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
+			utils.AviLog.Debugf("key: %s, msg: ADD EndpointSlice %s", key, epSlice.Name)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			epSlice, ok := obj.(*discoveryv1.EndpointSlice)
+			if !ok {
+				// endpointslice was deleted but its final state is unrecorded.
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				epSlice, ok = tombstone.Obj.(*discoveryv1.EndpointSlice)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not an EndpointSlice: %#v", obj)
+					return
+				}
+			}
+			svcName, ok := lib.GetServiceNameForEndpointSlice(epSlice)
+			if !ok {
+				return
+			}
+			namespace := epSlice.Namespace
+			key := utils.Endpoints + "/" + namespace + "/" + svcName
+			if lib.IsNamespaceBlocked(namespace) {
+				utils.AviLog.Debugf("key: %s, msg: EndpointSlice Delete event: Namespace: %s didn't qualify filter", key, namespace)
+				return
+			}
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
+			utils.AviLog.Debugf("key: %s, msg: DELETE EndpointSlice %s", key, epSlice.Name)
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oepSlice := old.(*discoveryv1.EndpointSlice)
+			cepSlice := cur.(*discoveryv1.EndpointSlice)
+			if reflect.DeepEqual(cepSlice.Endpoints, oepSlice.Endpoints) &&
+				reflect.DeepEqual(cepSlice.Ports, oepSlice.Ports) &&
+				reflect.DeepEqual(cepSlice.Labels, oepSlice.Labels) {
+				return
+			}
+			namespace := cepSlice.Namespace
+			if lib.IsNamespaceBlocked(namespace) {
+				utils.AviLog.Debugf("key: %s, msg: EndpointSlice Update event: Namespace: %s didn't qualify filter", utils.ObjKey(cepSlice), namespace)
+				return
+			}
+			// The service-name label can change, in which case both services need a resync.
+			for _, epSlice := range []*discoveryv1.EndpointSlice{oepSlice, cepSlice} {
+				svcName, ok := lib.GetServiceNameForEndpointSlice(epSlice)
+				if !ok {
+					continue
+				}
+				key := utils.Endpoints + "/" + namespace + "/" + svcName
+				bkt := utils.Bkt(namespace, numWorkers)
+				c.workqueue[bkt].AddRateLimited(key)
+				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
+				utils.AviLog.Debugf("key: %s, msg: UPDATE EndpointSlice %s", key, cepSlice.Name)
+			}
+		},
+	}
+	return epSliceEventHandler
+}
+
 func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 	mcpQueue := utils.SharedWorkQueue().GetQueueByName(utils.ObjectIngestionLayer)
 	c.workqueue = mcpQueue.Workqueue
@@ -832,7 +928,12 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 	}
 
 	if lib.GetServiceType() != lib.NodePortLocal {
-		c.informers.EpInformer.Informer().AddEventHandler(epEventHandler)
+		if lib.IsEndpointSliceEnabled() {
+			epSliceEventHandler := AddEndpointSliceEventHandler(numWorkers, c)
+			c.informers.EpSlicesInformer.Informer().AddEventHandler(epSliceEventHandler)
+		} else {
+			c.informers.EpInformer.Informer().AddEventHandler(epEventHandler)
+		}
 	}
 	c.informers.ServiceInformer.Informer().AddEventHandler(svcEventHandler)
 
@@ -1353,15 +1454,21 @@ func checkAviSecretUpdateAndShutdown(secret *corev1.Secret) bool {
 
 func (c *AviController) Start(stopCh <-chan struct{}) {
 	go c.informers.ServiceInformer.Informer().Run(stopCh)
-	go c.informers.EpInformer.Informer().Run(stopCh)
 	go c.informers.NSInformer.Informer().Run(stopCh)
 
 	informersList := []cache.InformerSynced{
-		c.informers.EpInformer.Informer().HasSynced,
 		c.informers.ServiceInformer.Informer().HasSynced,
 		c.informers.NSInformer.Informer().HasSynced,
 	}
 
+	if lib.IsEndpointSliceEnabled() {
+		go c.informers.EpSlicesInformer.Informer().Run(stopCh)
+		informersList = append(informersList, c.informers.EpSlicesInformer.Informer().HasSynced)
+	} else {
+		go c.informers.EpInformer.Informer().Run(stopCh)
+		informersList = append(informersList, c.informers.EpInformer.Informer().HasSynced)
+	}
+
 	if !lib.AviSecretInitialized {
 		go c.informers.SecretInformer.Informer().Run(stopCh)
 		informersList = append(informersList, c.informers.SecretInformer.Informer().HasSynced)
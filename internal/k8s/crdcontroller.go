@@ -29,6 +29,7 @@ import (
 	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
 	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
 	akov1alpha2 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha2"
 	akov1beta1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1beta1"
@@ -224,6 +225,7 @@ func (c *AviController) SetupAKOCRDEventHandlers(numWorkers uint32) {
 				key := lib.HostRule + "/" + utils.ObjKey(hostrule)
 				utils.AviLog.Debugf("key: %s, msg: DELETE", key)
 				objects.SharedResourceVerInstanceLister().Delete(key)
+				status.ForgetCRDStatus(lib.HostRule, hostrule)
 				bkt := utils.Bkt(namespace, numWorkers)
 				c.workqueue[bkt].AddRateLimited(key)
 				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
@@ -293,6 +295,7 @@ func (c *AviController) SetupAKOCRDEventHandlers(numWorkers uint32) {
 				// no need to validate for delete handler
 				bkt := utils.Bkt(namespace, numWorkers)
 				objects.SharedResourceVerInstanceLister().Delete(key)
+				status.ForgetCRDStatus(lib.HTTPRule, httprule)
 				c.workqueue[bkt].AddRateLimited(key)
 				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 			},
@@ -357,6 +360,7 @@ func (c *AviController) SetupAKOCRDEventHandlers(numWorkers uint32) {
 				namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(aviinfra))
 				utils.AviLog.Debugf("key: %s, msg: DELETE", key)
 				objects.SharedResourceVerInstanceLister().Delete(key)
+				status.ForgetCRDStatus(lib.AviInfraSetting, aviinfra)
 				// no need to validate for delete handler
 				bkt := utils.Bkt(namespace, numWorkers)
 				c.workqueue[bkt].AddRateLimited(key)
@@ -422,6 +426,7 @@ func (c *AviController) SetupAKOCRDEventHandlers(numWorkers uint32) {
 				key := lib.SSORule + "/" + utils.ObjKey(ssoRule)
 				utils.AviLog.Debugf("key: %s, msg: DELETE", key)
 				objects.SharedResourceVerInstanceLister().Delete(key)
+				status.ForgetCRDStatus(lib.SSORule, ssoRule)
 				bkt := utils.Bkt(namespace, numWorkers)
 				c.workqueue[bkt].AddRateLimited(key)
 				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
@@ -487,6 +492,7 @@ func (c *AviController) SetupAKOCRDEventHandlers(numWorkers uint32) {
 				utils.AviLog.Debugf("key: %s, msg: DELETE", key)
 				bkt := utils.Bkt(namespace, numWorkers)
 				objects.SharedResourceVerInstanceLister().Delete(key)
+				status.ForgetCRDStatus(lib.L4Rule, l4Rule)
 				c.workqueue[bkt].AddRateLimited(key)
 				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 			},
@@ -576,6 +582,7 @@ func (c *AviController) SetupAKOCRDEventHandlers(numWorkers uint32) {
 				namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(l7Rule))
 				utils.AviLog.Debugf("key: %s, msg: DELETE", key)
 				objects.SharedResourceVerInstanceLister().Delete(key)
+				status.ForgetCRDStatus(lib.L7Rule, l7Rule)
 				found, hostRules := objects.SharedCRDLister().GetL7RuleToHostRuleMapping(namespace + "/" + l7Rule.Name)
 				if found {
 					for hr := range hostRules {
@@ -953,19 +960,6 @@ func (c *AviController) SetupServiceImportEventHandlers(numWorkers uint32) {
 	c.informers.ServiceImportInformer.Informer().AddEventHandler(serviceImportEventHandler)
 }
 
-func checkRefsOnController(key string, refMap map[string]string) error {
-	for k, value := range refMap {
-		if k == "" {
-			continue
-		}
-
-		if err := checkRefOnController(key, value, k); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 var refModelMap = map[string]string{
 	"SslKeyCert":             "sslkeyandcertificate",
 	"WafPolicy":              "wafpolicy",
@@ -0,0 +1,123 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package k8s
+
+import (
+	"sync"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+)
+
+// refIndex caches the outcome of the successful ref checks done against the controller.
+// It is filled by every CRD validation, and is consulted by the admission webhook so that
+// the refs which are already known to be valid are not looked up on the controller again.
+type refIndex struct {
+	lock sync.RWMutex
+	refs map[string]refIndexEntry
+}
+
+type refIndexEntry struct {
+	value   bool
+	expires time.Time
+}
+
+var sharedRefIndex = &refIndex{refs: make(map[string]refIndexEntry)}
+
+// refIndexKey returns the index key of a ref. The CRD type is a part of the key,
+// since the same ref can be valid for one CRD type and invalid for another.
+func refIndexKey(key, refKey, refValue string) string {
+	objType, _, _ := lib.ExtractTypeNameNamespace(key)
+	return objType + "/" + refKey + "/" + refValue
+}
+
+func (r *refIndex) get(indexKey string) (bool, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	entry, ok := r.refs[indexKey]
+	if !ok || time.Now().After(entry.expires) {
+		return false, false
+	}
+	return entry.value, true
+}
+
+func (r *refIndex) add(indexKey string, value bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.refs[indexKey] = refIndexEntry{value: value, expires: time.Now().Add(time.Duration(lib.RefIndexTTL) * time.Second)}
+}
+
+// unresolvedRefError is returned by the ref checks when a ref to an Avi object can't be resolved.
+type unresolvedRefError struct {
+	err error
+}
+
+func (e *unresolvedRefError) Error() string {
+	return e.err.Error()
+}
+
+func (e *unresolvedRefError) Unwrap() error {
+	return e.err
+}
+
+// refChecker checks the refs to Avi objects specified in the CRDs. Every successful check is
+// recorded in the ref index. When useIndex is set, the refs found in the index are not looked
+// up on the controller, while the refs missing from it still are.
+type refChecker struct {
+	useIndex bool
+}
+
+func (r refChecker) checkRefs(key string, refMap map[string]string) error {
+	for k, value := range refMap {
+		if k == "" {
+			continue
+		}
+		indexKey := refIndexKey(key, value, k)
+		if _, ok := sharedRefIndex.get(indexKey); ok && r.useIndex {
+			continue
+		}
+		if err := checkRefOnController(key, value, k); err != nil {
+			return &unresolvedRefError{err: err}
+		}
+		sharedRefIndex.add(indexKey, true)
+	}
+	return nil
+}
+
+func (r refChecker) checkL4SSLAppProfile(key, refValue string) (bool, error) {
+	indexKey := refIndexKey(key, "L4SSLAppProfile", refValue)
+	if isL4SSL, ok := sharedRefIndex.get(indexKey); ok && r.useIndex {
+		return isL4SSL, nil
+	}
+	isL4SSL, err := checkForL4SSLAppProfile(key, refValue)
+	if err != nil {
+		return false, &unresolvedRefError{err: err}
+	}
+	sharedRefIndex.add(indexKey, isL4SSL)
+	return isL4SSL, nil
+}
+
+func (r refChecker) checkNetworkProfileTypeTCP(key, refValue string) (bool, error) {
+	indexKey := refIndexKey(key, "NetworkProfileTypeTCP", refValue)
+	if isTCP, ok := sharedRefIndex.get(indexKey); ok && r.useIndex {
+		return isTCP, nil
+	}
+	isTCP, err := checkForNetworkProfileTypeTCP(key, refValue)
+	if err != nil {
+		return false, &unresolvedRefError{err: err}
+	}
+	sharedRefIndex.add(indexKey, isTCP)
+	return isTCP, nil
+}
@@ -16,6 +16,7 @@ package k8s
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"regexp"
@@ -58,13 +59,47 @@ func NewValidator() Validator {
 // validateHostRuleObj would do validation checks
 // update internal CRD caches, and push relevant ingresses to ingestion
 func (l *leader) ValidateHostRuleObj(key string, hostrule *akov1beta1.HostRule) error {
+	if err := checkHostRuleObj(key, hostrule, refChecker{}); err != nil {
+		status.UpdateHostRuleStatus(key, hostrule, rejectedStatus(err))
+		return err
+	}
+
+	if hostrule.Spec.VirtualHost.L7Rule != "" {
+		objects.SharedCRDLister().UpdateL7RuleToHostRuleMapping(hostrule.Namespace+"/"+hostrule.Spec.VirtualHost.L7Rule, hostrule.Name)
+		_, err := lib.AKOControlConfig().CRDInformers().L7RuleInformer.Lister().L7Rules(hostrule.Namespace).Get(hostrule.Spec.VirtualHost.L7Rule)
+		if err != nil {
+			status.UpdateHostRuleStatus(key, hostrule, rejectedStatus(&unresolvedRefError{err: err}))
+			return err
+		}
+	}
+
+	// No need to update status of hostrule object as accepted since this generation was accepted before.
+	if hostrule.Status.Status == lib.StatusAccepted && hostrule.Status.ObservedGeneration == hostrule.Generation {
+		return nil
+	}
+
+	status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusAccepted, Error: ""})
+	return nil
+}
+
+// rejectedStatus returns the status of an object which is rejected with err.
+func rejectedStatus(err error) status.UpdateCRDStatusOptions {
+	var refErr *unresolvedRefError
+	return status.UpdateCRDStatusOptions{
+		Status:         lib.StatusRejected,
+		Error:          err.Error(),
+		UnresolvedRefs: errors.As(err, &refErr),
+	}
+}
 
+// checkHostRuleObj runs the checks on the HostRule spec that are shared by the
+// validation done before ingestion and the admission webhook.
+func checkHostRuleObj(key string, hostrule *akov1beta1.HostRule, refs refChecker) error {
 	var err error
 	fqdn := hostrule.Spec.VirtualHost.Fqdn
 	foundHost, foundHR := objects.SharedCRDLister().GetFQDNToHostruleMapping(fqdn)
 	if foundHost && foundHR != hostrule.Namespace+"/"+hostrule.Name {
 		err = fmt.Errorf("duplicate fqdn %s found in %s", fqdn, foundHR)
-		status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 		return err
 	}
 
@@ -81,7 +116,6 @@ func (l *leader) ValidateHostRuleObj(key string, hostrule *akov1beta1.HostRule)
 		re := regexp.MustCompile(lib.IPRegex)
 		if !re.MatchString(hostrule.Spec.VirtualHost.TCPSettings.LoadBalancerIP) {
 			err = fmt.Errorf("loadBalancerIP %s is not a valid IP", hostrule.Spec.VirtualHost.TCPSettings.LoadBalancerIP)
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 			return err
 		}
 	}
@@ -89,7 +123,6 @@ func (l *leader) ValidateHostRuleObj(key string, hostrule *akov1beta1.HostRule)
 	if hostrule.Spec.VirtualHost.Gslb.Fqdn != "" {
 		if fqdn == hostrule.Spec.VirtualHost.Gslb.Fqdn {
 			err = fmt.Errorf("GSLB FQDN and local FQDN are same")
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 			return err
 		}
 	}
@@ -104,7 +137,6 @@ func (l *leader) ValidateHostRuleObj(key string, hostrule *akov1beta1.HostRule)
 		}
 		if !sslEnabled {
 			err = fmt.Errorf("Hosting parent virtualservice must have SSL enabled")
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 			return err
 		}
 	}
@@ -112,26 +144,22 @@ func (l *leader) ValidateHostRuleObj(key string, hostrule *akov1beta1.HostRule)
 	if hostrule.Spec.VirtualHost.Aliases != nil {
 		if hostrule.Spec.VirtualHost.FqdnType != akov1beta1.Exact {
 			err = fmt.Errorf("Aliases is supported only when FQDN type is set as Exact")
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 			return err
 		}
 
 		if utils.HasElem(hostrule.Spec.VirtualHost.Aliases, fqdn) {
 			err = fmt.Errorf("Duplicate entry found. Aliases field has same entry as the FQDN field")
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 			return err
 		}
 
 		if utils.ContainsDuplicate(hostrule.Spec.VirtualHost.Aliases) {
 			err = fmt.Errorf("Aliases must be unique")
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 			return err
 		}
 
 		if hostrule.Spec.VirtualHost.Gslb.Fqdn != "" &&
 			utils.HasElem(hostrule.Spec.VirtualHost.Aliases, hostrule.Spec.VirtualHost.Gslb.Fqdn) {
 			err = fmt.Errorf("Aliases must not contain GSLB FQDN")
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 			return err
 		}
 
@@ -143,7 +171,6 @@ func (l *leader) ValidateHostRuleObj(key string, hostrule *akov1beta1.HostRule)
 			for _, alias := range hostrule.Spec.VirtualHost.Aliases {
 				if utils.HasElem(aliases, alias) {
 					err = fmt.Errorf("%s is already in use by hostrule %s", alias, cachedFQDN)
-					status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 					return err
 				}
 			}
@@ -165,7 +192,6 @@ func (l *leader) ValidateHostRuleObj(key string, hostrule *akov1beta1.HostRule)
 		secretName := hostrule.Spec.VirtualHost.TLS.SSLKeyCertificate.Name
 		err := validateSecretReferenceInHostrule(hostrule.Namespace, secretName)
 		if err != nil {
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 			return err
 		}
 	}
@@ -177,12 +203,10 @@ func (l *leader) ValidateHostRuleObj(key string, hostrule *akov1beta1.HostRule)
 		secretName := hostrule.Spec.VirtualHost.TLS.SSLKeyCertificate.AlternateCertificate.Name
 		err := validateSecretReferenceInHostrule(hostrule.Namespace, secretName)
 		if err != nil {
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 			return err
 		}
 	}
 	if len(hostrule.Spec.VirtualHost.ICAPProfile) > 1 {
-		status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: "Can only have 1 ICAP profile associated with VS"})
 		return fmt.Errorf("Can only have 1 ICAP profile associated with VS")
 	} else {
 		for _, icapprofile := range hostrule.Spec.VirtualHost.ICAPProfile {
@@ -204,27 +228,7 @@ func (l *leader) ValidateHostRuleObj(key string, hostrule *akov1beta1.HostRule)
 		refData[hostrule.Spec.VirtualHost.NetworkSecurityPolicy] = "NetworkSecurityPolicy"
 	}
 
-	if err := checkRefsOnController(key, refData); err != nil {
-		status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
-		return err
-	}
-
-	if hostrule.Spec.VirtualHost.L7Rule != "" {
-		objects.SharedCRDLister().UpdateL7RuleToHostRuleMapping(hostrule.Namespace+"/"+hostrule.Spec.VirtualHost.L7Rule, hostrule.Name)
-		_, err := lib.AKOControlConfig().CRDInformers().L7RuleInformer.Lister().L7Rules(hostrule.Namespace).Get(hostrule.Spec.VirtualHost.L7Rule)
-		if err != nil {
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
-			return err
-		}
-	}
-
-	// No need to update status of hostrule object as accepted since it was accepted before.
-	if hostrule.Status.Status == lib.StatusAccepted {
-		return nil
-	}
-
-	status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusAccepted, Error: ""})
-	return nil
+	return refs.checkRefs(key, refData)
 }
 
 func validateSecretReferenceInHostrule(namespace, secretName string) error {
@@ -260,15 +264,29 @@ func validateSecretReferenceInSSORule(namespace, secretName string) (*v1.Secret,
 // validateHTTPRuleObj would do validation checks
 // update internal CRD caches, and push relevant ingresses to ingestion
 func (l *leader) ValidateHTTPRuleObj(key string, httprule *akov1beta1.HTTPRule) error {
+	if err := checkHTTPRuleObj(key, httprule, refChecker{}); err != nil {
+		status.UpdateHTTPRuleStatus(key, httprule, rejectedStatus(err))
+		return err
+	}
+
+	// No need to update status of httprule object as accepted since this generation was accepted before.
+	if httprule.Status.Status == lib.StatusAccepted && httprule.Status.ObservedGeneration == httprule.Generation {
+		return nil
+	}
+
+	status.UpdateHTTPRuleStatus(key, httprule, status.UpdateCRDStatusOptions{
+		Status: lib.StatusAccepted,
+		Error:  "",
+	})
+	return nil
+}
 
+// checkHTTPRuleObj runs the checks on the HTTPRule spec.
+func checkHTTPRuleObj(key string, httprule *akov1beta1.HTTPRule, refs refChecker) error {
 	refData := make(map[string]string)
 	for _, path := range httprule.Spec.Paths {
 		if path.TLS.PKIProfile != "" && path.TLS.DestinationCA != "" {
 			//if both pkiProfile and destCA set, reject httprule
-			status.UpdateHTTPRuleStatus(key, httprule, status.UpdateCRDStatusOptions{
-				Status: lib.StatusRejected,
-				Error:  lib.HttpRulePkiAndDestCASetErr,
-			})
 			return fmt.Errorf("key: %s, msg: %s", key, lib.HttpRulePkiAndDestCASetErr)
 		}
 		refData[path.TLS.SSLProfile] = "SslProfile"
@@ -280,40 +298,117 @@ func (l *leader) ValidateHTTPRuleObj(key string, httprule *akov1beta1.HTTPRule)
 		for _, hm := range path.HealthMonitors {
 			refData[hm] = "HealthMonitor"
 		}
+
+		if err := validateHTTPRulePoolSettings(path.PoolSettings); err != nil {
+			return fmt.Errorf("invalid poolSettings for target %s: %v", path.Target, err)
+		}
+		if err := validateHTTPRuleAlternateBackends(path.AlternateBackends); err != nil {
+			return fmt.Errorf("invalid alternateBackends for target %s: %v", path.Target, err)
+		}
+	}
+
+	return refs.checkRefs(key, refData)
+}
+
+// validateHTTPRuleAlternateBackends checks that the alternate backends of an HTTPRule path are
+// distinct services, which together receive at most all the traffic of the path.
+func validateHTTPRuleAlternateBackends(backends []akov1beta1.HTTPRuleAlternateBackend) error {
+	var totalWeight uint32
+	services := make(map[string]struct{}, len(backends))
+	for _, backend := range backends {
+		if backend.ServiceName == "" {
+			return errors.New("serviceName must be specified")
+		}
+		if _, ok := services[backend.ServiceName]; ok {
+			return fmt.Errorf("service %s is specified more than once", backend.ServiceName)
+		}
+		services[backend.ServiceName] = struct{}{}
+		if backend.ServicePort < 0 || backend.ServicePort > 65535 {
+			return fmt.Errorf("servicePort of service %s must be between 1 and 65535", backend.ServiceName)
+		}
+		if backend.Weight > 100 {
+			return fmt.Errorf("weight of service %s must be between 0 and 100", backend.ServiceName)
+		}
+		totalWeight += backend.Weight
 	}
+	if totalWeight > 100 {
+		return fmt.Errorf("the weights add up to %d, more than 100", totalWeight)
+	}
+	return nil
+}
 
-	if err := checkRefsOnController(key, refData); err != nil {
-		status.UpdateHTTPRuleStatus(key, httprule, status.UpdateCRDStatusOptions{
-			Status: lib.StatusRejected,
-			Error:  err.Error(),
-		})
+// validateHTTPRulePoolSettings checks the pool settings of an HTTPRule path against the
+// ranges allowed by the controller.
+func validateHTTPRulePoolSettings(poolSettings akov1beta1.HTTPRulePoolSettings) error {
+	if poolSettings.ServerTimeout != nil && *poolSettings.ServerTimeout > lib.MaxPoolServerTimeout {
+		return fmt.Errorf("serverTimeout must be between 0 and %d milliseconds", lib.MaxPoolServerTimeout)
+	}
+	if poolSettings.MaxConcurrentConnectionsPerServer != nil && *poolSettings.MaxConcurrentConnectionsPerServer < 0 {
+		return errors.New("maxConcurrentConnectionsPerServer must not be negative")
+	}
+	if poolSettings.ConnectionRampDuration != nil &&
+		(*poolSettings.ConnectionRampDuration < 0 || *poolSettings.ConnectionRampDuration > lib.MaxPoolConnectionRampDuration) {
+		return fmt.Errorf("connectionRampDuration must be between 0 and %d minutes", lib.MaxPoolConnectionRampDuration)
+	}
+	if poolSettings.GracefulDisableTimeout != nil &&
+		(*poolSettings.GracefulDisableTimeout < -1 || *poolSettings.GracefulDisableTimeout > lib.MaxGracefulDisableTimeout) {
+		return fmt.Errorf("gracefulDisableTimeout must be between -1 and %d minutes", lib.MaxGracefulDisableTimeout)
+	}
+	if poolSettings.RequestQueueDepth != nil {
+		if poolSettings.RequestQueueEnabled == nil || !*poolSettings.RequestQueueEnabled {
+			return errors.New("requestQueueDepth must be specified only when requestQueueEnabled is true")
+		}
+		if *poolSettings.RequestQueueDepth == 0 {
+			return errors.New("requestQueueDepth must be greater than 0")
+		}
+	}
+	return nil
+}
+
+// validateAviInfraSetting would do validaion checks on the
+// ingested AviInfraSetting objects
+func (l *leader) ValidateAviInfraSetting(key string, infraSetting *akov1beta1.AviInfraSetting) error {
+	if err := checkAviInfraSettingObj(key, infraSetting, refChecker{}); err != nil {
+		status.UpdateAviInfraSettingStatus(key, infraSetting, rejectedStatus(err))
 		return err
 	}
 
-	// No need to update status of httprule object as accepted since it was accepted before.
-	if httprule.Status.Status == lib.StatusAccepted {
+	// This would add SEG labels only if they are not configured yet. In case there is a label mismatch
+	// to any pre-existing SEG labels, the AviInfraSettig CR will get Rejected from the checkRefs
+	// step before this.
+	segMgmtNetworK := ""
+	if infraSetting.Spec.SeGroup.Name != "" {
+		addSeGroupLabel(key, infraSetting.Spec.SeGroup.Name)
+		// Not required for NO access cloud
+		if lib.GetCloudType() == lib.CLOUD_VCENTER {
+			segMgmtNetworK = GetSEGManagementNetwork(infraSetting.Spec.SeGroup.Name)
+		}
+	}
+
+	if len(infraSetting.Spec.Network.VipNetworks) > 0 {
+		SetAviInfrasettingVIPNetworks(infraSetting.Name, segMgmtNetworK, infraSetting.Spec.SeGroup.Name, infraSetting.Spec.Network.VipNetworks)
+	}
+
+	if len(infraSetting.Spec.Network.NodeNetworks) > 0 {
+		SetAviInfrasettingNodeNetworks(infraSetting.Name, segMgmtNetworK, infraSetting.Spec.SeGroup.Name, infraSetting.Spec.Network.NodeNetworks)
+	}
+	// No need to update status of infra setting object as accepted since this generation was accepted before.
+	if infraSetting.Status.Status == lib.StatusAccepted && infraSetting.Status.ObservedGeneration == infraSetting.Generation {
 		return nil
 	}
 
-	status.UpdateHTTPRuleStatus(key, httprule, status.UpdateCRDStatusOptions{
+	status.UpdateAviInfraSettingStatus(key, infraSetting, status.UpdateCRDStatusOptions{
 		Status: lib.StatusAccepted,
 		Error:  "",
 	})
 	return nil
 }
 
-// validateAviInfraSetting would do validaion checks on the
-// ingested AviInfraSetting objects
-func (l *leader) ValidateAviInfraSetting(key string, infraSetting *akov1beta1.AviInfraSetting) error {
-
+// checkAviInfraSettingObj runs the checks on the AviInfraSetting spec.
+func checkAviInfraSettingObj(key string, infraSetting *akov1beta1.AviInfraSetting, refs refChecker) error {
 	if ((infraSetting.Spec.Network.EnableRhi != nil && !*infraSetting.Spec.Network.EnableRhi) || infraSetting.Spec.Network.EnableRhi == nil) &&
 		len(infraSetting.Spec.Network.BgpPeerLabels) > 0 {
-		err := fmt.Errorf("BGPPeerLabels cannot be set if EnableRhi is false.")
-		status.UpdateAviInfraSettingStatus(key, infraSetting, status.UpdateCRDStatusOptions{
-			Status: lib.StatusRejected,
-			Error:  err.Error(),
-		})
-		return err
+		return fmt.Errorf("BGPPeerLabels cannot be set if EnableRhi is false.")
 	}
 
 	refData := make(map[string]string)
@@ -321,23 +416,13 @@ func (l *leader) ValidateAviInfraSetting(key string, infraSetting *akov1beta1.Av
 		if vipNetwork.Cidr != "" {
 			re := regexp.MustCompile(lib.IPCIDRRegex)
 			if !re.MatchString(vipNetwork.Cidr) {
-				err := fmt.Errorf("invalid CIDR configuration %s detected for networkName %s in vipNetworkList", vipNetwork.Cidr, vipNetwork.NetworkName)
-				status.UpdateAviInfraSettingStatus(key, infraSetting, status.UpdateCRDStatusOptions{
-					Status: lib.StatusRejected,
-					Error:  err.Error(),
-				})
-				return err
+				return fmt.Errorf("invalid CIDR configuration %s detected for networkName %s in vipNetworkList", vipNetwork.Cidr, vipNetwork.NetworkName)
 			}
 		}
 		if vipNetwork.V6Cidr != "" {
 			re := regexp.MustCompile(lib.IPV6CIDRRegex)
 			if !re.MatchString(vipNetwork.V6Cidr) {
-				err := fmt.Errorf("invalid IPv6 CIDR configuration %s detected for networkName %s in vipNetworkList", vipNetwork.V6Cidr, vipNetwork.NetworkName)
-				status.UpdateAviInfraSettingStatus(key, infraSetting, status.UpdateCRDStatusOptions{
-					Status: lib.StatusRejected,
-					Error:  err.Error(),
-				})
-				return err
+				return fmt.Errorf("invalid IPv6 CIDR configuration %s detected for networkName %s in vipNetworkList", vipNetwork.V6Cidr, vipNetwork.NetworkName)
 			}
 		}
 		// Give preference to network uuid
@@ -368,51 +453,10 @@ func (l *leader) ValidateAviInfraSetting(key string, infraSetting *akov1beta1.Av
 			}
 		}
 		if !sslEnabled {
-			err := fmt.Errorf("One of the port in aviInfraSetting must have SSL enabled")
-			status.UpdateAviInfraSettingStatus(key, infraSetting, status.UpdateCRDStatusOptions{
-				Status: lib.StatusRejected,
-				Error:  err.Error(),
-			})
-			return err
-		}
-	}
-	if err := checkRefsOnController(key, refData); err != nil {
-		status.UpdateAviInfraSettingStatus(key, infraSetting, status.UpdateCRDStatusOptions{
-			Status: lib.StatusRejected,
-			Error:  err.Error(),
-		})
-		return err
-	}
-
-	// This would add SEG labels only if they are not configured yet. In case there is a label mismatch
-	// to any pre-existing SEG labels, the AviInfraSettig CR will get Rejected from the checkRefsOnController
-	// step before this.
-	segMgmtNetworK := ""
-	if infraSetting.Spec.SeGroup.Name != "" {
-		addSeGroupLabel(key, infraSetting.Spec.SeGroup.Name)
-		// Not required for NO access cloud
-		if lib.GetCloudType() == lib.CLOUD_VCENTER {
-			segMgmtNetworK = GetSEGManagementNetwork(infraSetting.Spec.SeGroup.Name)
+			return fmt.Errorf("One of the port in aviInfraSetting must have SSL enabled")
 		}
 	}
-
-	if len(infraSetting.Spec.Network.VipNetworks) > 0 {
-		SetAviInfrasettingVIPNetworks(infraSetting.Name, segMgmtNetworK, infraSetting.Spec.SeGroup.Name, infraSetting.Spec.Network.VipNetworks)
-	}
-
-	if len(infraSetting.Spec.Network.NodeNetworks) > 0 {
-		SetAviInfrasettingNodeNetworks(infraSetting.Name, segMgmtNetworK, infraSetting.Spec.SeGroup.Name, infraSetting.Spec.Network.NodeNetworks)
-	}
-	// No need to update status of infra setting object as accepted since it was accepted before.
-	if infraSetting.Status.Status == lib.StatusAccepted {
-		return nil
-	}
-
-	status.UpdateAviInfraSettingStatus(key, infraSetting, status.UpdateCRDStatusOptions{
-		Status: lib.StatusAccepted,
-		Error:  "",
-	})
-	return nil
+	return refs.checkRefs(key, refData)
 }
 
 // validateMultiClusterIngressObj validates the MCI CRD changes before pushing it to ingestion
@@ -463,12 +507,27 @@ func (l *leader) ValidateServiceImportObj(key string, serviceImport *akov1alpha1
 // ValidateSSORuleObj would do validation checks
 // update internal CRD caches, and push relevant ingresses to ingestion
 func (l *leader) ValidateSSORuleObj(key string, ssoRule *akov1alpha2.SSORule) error {
+	if err := checkSSORuleObj(key, ssoRule, refChecker{}); err != nil {
+		status.UpdateSSORuleStatus(key, ssoRule, rejectedStatus(err))
+		return err
+	}
+
+	// No need to update status of ssoRule object as accepted since this generation was accepted before.
+	if ssoRule.Status.Status == lib.StatusAccepted && ssoRule.Status.ObservedGeneration == ssoRule.Generation {
+		return nil
+	}
+
+	status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusAccepted, Error: ""})
+	return nil
+}
+
+// checkSSORuleObj runs the checks on the SSORule spec.
+func checkSSORuleObj(key string, ssoRule *akov1alpha2.SSORule, refs refChecker) error {
 	var err error
 	fqdn := *ssoRule.Spec.Fqdn
 	foundHost, foundSR := objects.SharedCRDLister().GetFQDNToSSORuleMapping(fqdn)
 	if foundHost && foundSR != ssoRule.Namespace+"/"+ssoRule.Name {
 		err = fmt.Errorf("duplicate fqdn %s found in %s", fqdn, foundSR)
-		status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 		return err
 	}
 
@@ -476,7 +535,6 @@ func (l *leader) ValidateSSORuleObj(key string, ssoRule *akov1alpha2.SSORule) er
 
 	if ssoRule.Spec.SsoPolicyRef == nil {
 		err = fmt.Errorf("SsoPolicyRef is not specified")
-		status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 		return err
 	}
 	refData[*ssoRule.Spec.SsoPolicyRef] = "SSOPolicy"
@@ -493,18 +551,15 @@ func (l *leader) ValidateSSORuleObj(key string, ssoRule *akov1alpha2.SSORule) er
 					clientSecretObj, err := validateSecretReferenceInSSORule(ssoRule.Namespace, clientSecret)
 					if err != nil {
 						err = fmt.Errorf("Got error while fetching %s secret : %s", clientSecret, err.Error())
-						status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 						return err
 					}
 					if clientSecretObj == nil {
 						err = fmt.Errorf("specified client secret is empty : %s", clientSecret)
-						status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 						return err
 					}
 					clientSecretString := string(clientSecretObj.Data["clientSecret"])
 					if clientSecretString == "" {
 						err = fmt.Errorf("clientSecret field not found in %s secret", clientSecret)
-						status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 						return err
 					}
 				}
@@ -512,12 +567,10 @@ func (l *leader) ValidateSSORuleObj(key string, ssoRule *akov1alpha2.SSORule) er
 				if profile.ResourceServer != nil {
 					if *profile.ResourceServer.AccessType == lib.ACCESS_TOKEN_TYPE_JWT && profile.ResourceServer.JwtParams == nil {
 						err = fmt.Errorf("Access Type is %s, but Jwt Params have not been specified", *profile.ResourceServer.AccessType)
-						status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 						return err
 					}
 					if *profile.ResourceServer.AccessType == lib.ACCESS_TOKEN_TYPE_OPAQUE && profile.ResourceServer.OpaqueTokenParams == nil {
 						err = fmt.Errorf("Access Type is %s, but Opaque Token Params have not been specified", *profile.ResourceServer.AccessType)
-						status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 						return err
 					}
 
@@ -526,18 +579,15 @@ func (l *leader) ValidateSSORuleObj(key string, ssoRule *akov1alpha2.SSORule) er
 						serverSecretObj, err := utils.GetInformers().ClientSet.CoreV1().Secrets(ssoRule.Namespace).Get(context.TODO(), serverSecret, metav1.GetOptions{})
 						if err != nil {
 							err = fmt.Errorf("Got error while fetching %s secret : %s", serverSecret, err.Error())
-							status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 							return err
 						}
 						if serverSecretObj == nil {
 							err = fmt.Errorf("specified server secret is empty : %s", serverSecret)
-							status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 							return err
 						}
 						serverSecretString := string(serverSecretObj.Data["serverSecret"])
 						if serverSecretString == "" {
 							err = fmt.Errorf("serverSecret field not found in %s secret", serverSecret)
-							status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 							return err
 						}
 					}
@@ -553,33 +603,61 @@ func (l *leader) ValidateSSORuleObj(key string, ssoRule *akov1alpha2.SSORule) er
 		}
 	}
 
-	if err := checkRefsOnController(key, refData); err != nil {
-		status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
+	return refs.checkRefs(key, refData)
+}
+
+// ValidateL4RuleObj would do validation checks and updates the status before
+// pushing to ingestion
+func (l *leader) ValidateL4RuleObj(key string, l4Rule *akov1alpha2.L4Rule) error {
+	if err := checkL4RuleObj(key, l4Rule, refChecker{}); err != nil {
+		status.UpdateL4RuleStatus(key, l4Rule, rejectedStatus(err))
+		return err
+	}
+
+	if err := checkL4RuleSourceRanges(l4Rule); err != nil {
+		status.UpdateL4RuleStatus(key, l4Rule, rejectedStatus(err))
 		return err
 	}
 
-	// No need to update status of ssoRule object as accepted since it was accepted before.
-	if ssoRule.Status.Status == lib.StatusAccepted {
+	// No need to update status of l4rule object as accepted since this generation was accepted before.
+	if l4Rule.Status.Status == lib.StatusAccepted && l4Rule.Status.ObservedGeneration == l4Rule.Generation {
 		return nil
 	}
 
-	status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusAccepted, Error: ""})
+	status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
+		Status: lib.StatusAccepted,
+		Error:  "",
+	})
+
 	return nil
 }
 
-// ValidateL4RuleObj would do validation checks and updates the status before
-// pushing to ingestion
-func (l *leader) ValidateL4RuleObj(key string, l4Rule *akov1alpha2.L4Rule) error {
+// checkL4RuleSourceRanges rejects an L4Rule which sets networkSecurityPolicyRef, when a Service it is
+// attached to has loadBalancerSourceRanges. A virtualservice refers to a single network security policy,
+// and the source ranges can't be merged into a policy not owned by AKO.
+func checkL4RuleSourceRanges(l4Rule *akov1alpha2.L4Rule) error {
+	if l4Rule.Spec.NetworkSecurityPolicyRef == nil {
+		return nil
+	}
+	services, err := utils.GetInformers().ServiceInformer.Informer().GetIndexer().ByIndex(lib.L4RuleToServicesIndex, l4Rule.Namespace+"/"+l4Rule.Name)
+	if err != nil {
+		return nil
+	}
+	for _, svc := range services {
+		if svcObj, ok := svc.(*v1.Service); ok && len(svcObj.Spec.LoadBalancerSourceRanges) != 0 {
+			return fmt.Errorf("networkSecurityPolicyRef can't be set for Service %s/%s with loadBalancerSourceRanges", svcObj.Namespace, svcObj.Name)
+		}
+	}
+	return nil
+}
 
+// checkL4RuleObj runs the checks on the L4Rule spec.
+func checkL4RuleObj(key string, l4Rule *akov1alpha2.L4Rule, refs refChecker) error {
 	l4RuleSpec := l4Rule.Spec
 
 	if l4RuleSpec.LoadBalancerIP != nil &&
 		net.ParseIP(*l4RuleSpec.LoadBalancerIP) == nil {
 		err := fmt.Errorf("loadBalancerIP %s is not valid", *l4RuleSpec.LoadBalancerIP)
-		status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
-			Status: lib.StatusRejected,
-			Error:  err.Error(),
-		})
 		return err
 	}
 
@@ -597,21 +675,13 @@ func (l *leader) ValidateL4RuleObj(key string, l4Rule *akov1alpha2.L4Rule) error
 				isSSLEnabled = true
 			}
 		}
-		isL4SSL, err := checkForL4SSLAppProfile(key, *l4RuleSpec.ApplicationProfileRef)
+		isL4SSL, err := refs.checkL4SSLAppProfile(key, *l4RuleSpec.ApplicationProfileRef)
 		if err != nil {
-			status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
-				Status: lib.StatusRejected,
-				Error:  err.Error(),
-			})
 			return err
 		}
 		if isL4SSL {
 			if !isSSLEnabled {
 				sslErr := fmt.Errorf("SSL is not enabled in l4rule listener Spec but App Profile %s is of type SSL", *l4RuleSpec.ApplicationProfileRef)
-				status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
-					Status: lib.StatusRejected,
-					Error:  sslErr.Error(),
-				})
 				return sslErr
 			}
 			if l4RuleSpec.SslProfileRef != nil {
@@ -621,12 +691,8 @@ func (l *leader) ValidateL4RuleObj(key string, l4Rule *akov1alpha2.L4Rule) error
 				refData[ref] = "SslKeyCert"
 			}
 			if l4RuleSpec.NetworkProfileRef != nil {
-				isNetworkProfileTypeTCP, err = checkForNetworkProfileTypeTCP(key, *l4RuleSpec.NetworkProfileRef)
+				isNetworkProfileTypeTCP, err = refs.checkNetworkProfileTypeTCP(key, *l4RuleSpec.NetworkProfileRef)
 				if err != nil {
-					status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
-						Status: lib.StatusRejected,
-						Error:  err.Error(),
-					})
 					return err
 				}
 			}
@@ -634,27 +700,15 @@ func (l *leader) ValidateL4RuleObj(key string, l4Rule *akov1alpha2.L4Rule) error
 			if *l4RuleSpec.ApplicationProfileRef != utils.DEFAULT_L4_APP_PROFILE {
 				if isSSLEnabled {
 					sslErr := fmt.Errorf("SSL is enabled in l4rule listener Spec but App Profile %s is not of type SSL", *l4RuleSpec.ApplicationProfileRef)
-					status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
-						Status: lib.StatusRejected,
-						Error:  sslErr.Error(),
-					})
 					return sslErr
 				}
 			}
 			if l4RuleSpec.SslProfileRef != nil {
 				sslProfileErr := fmt.Errorf("App Profile %s is not of type SSL but SslProfileRef is set", *l4RuleSpec.ApplicationProfileRef)
-				status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
-					Status: lib.StatusRejected,
-					Error:  sslProfileErr.Error(),
-				})
 				return sslProfileErr
 			}
 			if len(l4RuleSpec.SslKeyAndCertificateRefs) != 0 {
 				sslKeyCertErr := fmt.Errorf("App Profile %s is not of type SSL but SslKeyAndCertificateRefs are set", *l4RuleSpec.ApplicationProfileRef)
-				status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
-					Status: lib.StatusRejected,
-					Error:  sslKeyCertErr.Error(),
-				})
 				return sslKeyCertErr
 			}
 		}
@@ -699,38 +753,31 @@ func (l *leader) ValidateL4RuleObj(key string, l4Rule *akov1alpha2.L4Rule) error
 		}
 
 		if err := validateLBAlgorithm(backendProperties); err != nil {
-			status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
-				Status: lib.StatusRejected,
-				Error:  err.Error(),
-			})
 			return err
 		}
 	}
 
-	if err := checkRefsOnController(key, refData); err != nil {
-		status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
-			Status: lib.StatusRejected,
-			Error:  err.Error(),
-		})
+	return refs.checkRefs(key, refData)
+}
+
+// ValidateL7RuleObj would do validation checks and updates the status before
+// pushing to ingestion
+func (l *leader) ValidateL7RuleObj(key string, l7Rule *akov1alpha2.L7Rule) error {
+	if err := checkL7RuleObj(key, l7Rule, refChecker{}); err != nil {
+		status.UpdateL7RuleStatus(key, l7Rule, rejectedStatus(err))
 		return err
 	}
 
-	// No need to update status of l4rule object as accepted since it was accepted before.
-	if l4Rule.Status.Status == lib.StatusAccepted {
+	// No need to update status of l7rule object as accepted since this generation was accepted before.
+	if l7Rule.Status.Status == lib.StatusAccepted && l7Rule.Status.ObservedGeneration == l7Rule.Generation {
 		return nil
 	}
-
-	status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
-		Status: lib.StatusAccepted,
-		Error:  "",
-	})
-
+	status.UpdateL7RuleStatus(key, l7Rule, status.UpdateCRDStatusOptions{Status: lib.StatusAccepted, Error: ""})
 	return nil
 }
 
-// ValidateL7RuleObj would do validation checks and updates the status before
-// pushing to ingestion
-func (l *leader) ValidateL7RuleObj(key string, l7Rule *akov1alpha2.L7Rule) error {
+// checkL7RuleObj runs the checks on the L7Rule spec.
+func checkL7RuleObj(key string, l7Rule *akov1alpha2.L7Rule, refs refChecker) error {
 	l7RuleSpec := l7Rule.Spec
 	refData := make(map[string]string)
 	if l7RuleSpec.BotPolicyRef != nil {
@@ -744,19 +791,7 @@ func (l *leader) ValidateL7RuleObj(key string, l7Rule *akov1alpha2.L7Rule) error
 	if l7RuleSpec.TrafficCloneProfileRef != nil {
 		refData[*l7RuleSpec.TrafficCloneProfileRef] = "TrafficCloneProfile"
 	}
-	if err := checkRefsOnController(key, refData); err != nil {
-		status.UpdateL7RuleStatus(key, l7Rule, status.UpdateCRDStatusOptions{
-			Status: lib.StatusRejected,
-			Error:  err.Error(),
-		})
-		return err
-	}
-	// No need to update status of l7rule object as accepted since it was accepted before.
-	if l7Rule.Status.Status == lib.StatusAccepted {
-		return nil
-	}
-	status.UpdateL7RuleStatus(key, l7Rule, status.UpdateCRDStatusOptions{Status: lib.StatusAccepted, Error: ""})
-	return nil
+	return refs.checkRefs(key, refData)
 }
 
 func validateLBAlgorithm(backendProperties *akov1alpha2.BackendProperties) error {
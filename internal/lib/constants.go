@@ -32,6 +32,30 @@ const (
 	VLAN_TRANSPORT_ZONE       = "VLAN"
 	OVERLAY_TRANSPORT_ZONE    = "OVERLAY"
 	IP_FAMILY                 = "IP_FAMILY"
+	ENABLE_ENDPOINTSLICE      = "ENABLE_ENDPOINTSLICE"
+	TOPOLOGY_ZONE             = "TOPOLOGY_ZONE"
+	GRACEFUL_DRAIN_PERIOD     = "GRACEFUL_DRAIN_PERIOD"
+	MaxGracefulDisableTimeout = 7200
+	TRANSACTIONAL_REST_APPLY  = "TRANSACTIONAL_REST_APPLY"
+	RollbackSucceeded         = "succeeded"
+	RollbackFailed            = "failed"
+	ENABLE_CRD_WEBHOOK        = "ENABLE_CRD_WEBHOOK"
+	CRD_WEBHOOK_PORT          = "CRD_WEBHOOK_PORT"
+	CRD_WEBHOOK_CERT_DIR      = "CRD_WEBHOOK_CERT_DIR"
+	RefIndexTTL               = 600
+	MetricLayerIngestion      = "ingestion"
+	MetricLayerGraph          = "graph"
+	MetricLayerRest           = "rest"
+	MetricLayerStatus         = "status"
+	MetricCachePopulate       = "populate"
+	MetricCacheRefresh        = "refresh"
+	DRY_RUN                   = "DRY_RUN"
+	DRY_RUN_PLAN_FILE         = "DRY_RUN_PLAN_FILE"
+	DRIFT_DETECTION           = "DRIFT_DETECTION"
+	DriftDetectionDisabled    = "disabled"
+	DriftDetectionReport      = "report"
+	DriftDetectionRepair      = "repair"
+	SYNC_MAX_ATTEMPTS         = "SYNC_MAX_ATTEMPTS"
 
 	AVI_INGRESS_CLASS                          = "avi"
 	NETWORK_NAME                               = "NETWORK_NAME"
@@ -42,6 +66,8 @@ const (
 	DEFAULT_SE_GROUP                           = "Default-Group"
 	NODE_NETWORK_LIST                          = "NODE_NETWORK_LIST"
 	NODE_NETWORK_MAX_ENTRIES                   = 5
+	MaxPoolServerTimeout                       = 21600000
+	MaxPoolConnectionRampDuration              = 300
 	DEFAULT_DOMAIN                             = "DEFAULT_DOMAIN"
 	ADVANCED_L4                                = "ADVANCED_L4"
 	SERVICES_API                               = "SERVICES_API"
@@ -63,6 +89,12 @@ const (
 	STATUS_REDIRECT                            = "HTTP_REDIRECT_STATUS_CODE_302"
 	CLOSE_CONNECTION                           = "HTTP_SECURITY_ACTION_CLOSE_CONN"
 	IS_IN                                      = "IS_IN"
+	NSP_ACTION_ALLOW                           = "NETWORK_SECURITY_POLICY_ACTION_TYPE_ALLOW"
+	NSP_ACTION_DENY                            = "NETWORK_SECURITY_POLICY_ACTION_TYPE_DENY"
+	PERSISTENCE_TYPE_CLIENT_IP                 = "PERSISTENCE_TYPE_CLIENT_IP_ADDRESS"
+	MinClientIPPersistenceTimeout              = 1
+	MaxClientIPPersistenceTimeout              = 720
+	SG_TYPE_STRING                             = "SG_TYPE_STRING"
 	SLOW_SYNC_TIME                             = 90 // seconds
 	LOG_LEVEL                                  = "logLevel"
 	EnableEvents                               = "enableEvents"
@@ -116,9 +148,11 @@ const (
 	Gateway                                    = "Gateway"
 	GatewayClass                               = "GatewayClass"
 	HTTPRoute                                  = "HTTPRoute"
+	GRPCRoute                                  = "GRPCRoute"
 	TCPRoute                                   = "TCPRoute"
 	TLSRoute                                   = "TLSRoute"
 	UDPRoute                                   = "UDPRoute"
+	ReferenceGrant                             = "ReferenceGrant"
 	DuplicateBackends                          = "MultipleBackendsWithSameServiceError"
 	DummyVSForStaleData                        = "DummyVSForStaleData"
 	ControllerReqWaitTime                      = 300
@@ -157,6 +191,9 @@ const (
 	L4AdvPool                                  = "L4 Advance Pool"
 	L4PS                                       = "L4 Policyset"
 	L4PSRule                                   = "L4 Policyset Rule"
+	L4NSP                                      = "L4 Network Security Policy"
+	PersistenceProfile                         = "Persistence Profile"
+	StringGroup                                = "String Group"
 	SNIVS                                      = "SNI VirtualService"
 	VIP                                        = "VS VIP"
 	PG                                         = "Poolgroup"
@@ -169,6 +206,7 @@ const (
 	Pool                                       = "Pool"
 	TLSKeyCert                                 = "TLS KeyCert"
 	CACert                                     = "CA Cert"
+	TrafficCloneProfile                        = "Traffic Clone Profile"
 	IPCIDRRegex                                = `^(\b([01]?[0-9][0-9]?|2[0-4][0-9]|25[0-5])\.){3}([01]?[0-9][0-9]?|2[0-4][0-9]|25[0-5])\/(([0-9]|[1-2][0-9]|3[0-2]))?$`
 	IPRegex                                    = `\b(([01]?[0-9][0-9]?|2[0-4][0-9]|25[0-5])(\.|$)){4}\b`
 	IPV6CIDRRegex                              = `^(((?:[0-9A-Fa-f]{1,4}))*((?::[0-9A-Fa-f]{1,4}))*::((?:[0-9A-Fa-f]{1,4}))*((?::[0-9A-Fa-f]{1,4}))*|((?:[0-9A-Fa-f]{1,4}))((?::[0-9A-Fa-f]{1,4})){7})(\/([1-9]|[1-9][0-9]|1[0-1][0-9]|12[0-8])){0,1}$`
@@ -202,6 +240,18 @@ const (
 	HTTPMethodGet                              = "GET"
 	HTTPMethodPut                              = "PUT"
 
+	// AKO CRD status condition types and reasons
+	CRDConditionAccepted     = "Accepted"
+	CRDConditionResolvedRefs = "ResolvedRefs"
+	CRDConditionProgrammed   = "Programmed"
+	CRDReasonAccepted        = "Accepted"
+	CRDReasonInvalid         = "Invalid"
+	CRDReasonResolvedRefs    = "ResolvedRefs"
+	CRDReasonInvalidRef      = "InvalidRef"
+	CRDReasonPending         = "Pending"
+	CRDReasonProgrammed      = "Programmed"
+	CRDReasonNotApplied      = "NotApplied"
+
 	// AKO Event constants
 	AKOEventComponent        = "avi-kubernetes-operator"
 	AKOShutdown              = "AKOShutdown"
@@ -212,6 +262,8 @@ const (
 	AKOPause                 = "AKOPause"
 	DuplicateHostPath        = "DuplicateHostPath"
 	DuplicateHost            = "DuplicateHost"
+	DefaultBackendConflict   = "DefaultBackendConflict"
+	InvalidRegexPath         = "InvalidRegexPath"
 	Removed                  = "Removed"
 	Synced                   = "Synced"
 	Attached                 = "Attached"
@@ -221,6 +273,7 @@ const (
 	AKODeleteConfigUnset     = "AKODeleteConfigUnset"
 	AKODeleteConfigDone      = "AKODeleteConfigDone"
 	AKODeleteConfigTimeout   = "AKODeleteConfigTimeout"
+	AviObjectDrifted         = "AviObjectDrifted"
 	AKOGatewayEventComponent = "avi-kubernetes-operator-gateway-api"
 
 	DefaultIngressClassAnnotation    = "ingressclass.kubernetes.io/is-default-class"
@@ -240,9 +293,11 @@ const (
 	SvcApiAviGatewayController       = "ako.vmware.com/avi-lb"
 	NPLPodAnnotation                 = "nodeportlocal.antrea.io"
 	NPLSvcAnnotation                 = "nodeportlocal.antrea.io/enabled"
+	ToBeDeletedTaint                 = "ToBeDeletedByClusterAutoscaler"
 	InfraSettingNameAnnotation       = "aviinfrasetting.ako.vmware.com/name"
 	SkipNodePortAnnotation           = "skipnodeport.ako.vmware.com/enabled"
 	PassthroughAnnotation            = "passthrough.ako.vmware.com/enabled"
+	UseRegexAnnotation               = "ako.vmware.com/use-regex"
 	StaticRouteAnnotation            = "ako.vmware.com/pod-cidrs"
 	OVNNodeSubnetAnnotation          = "k8s.ovn.org/node-subnets"
 	WCPSEGroup                       = "ako.vmware.com/wcp-se-group"
@@ -21,12 +21,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"reflect"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
@@ -43,6 +45,7 @@ import (
 	"google.golang.org/protobuf/proto"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -91,6 +94,8 @@ type ServiceMetadataObj struct {
 	Gateway               string      `json:"gateway"` // ns/name
 	InsecureEdgeTermAllow bool        `json:"insecureedgetermallow"`
 	IsMCIIngress          bool        `json:"is_mci_ingress"`
+	L7Rule                string      `json:"l7_rule,omitempty"` // ns/name
+	AviInfraSetting       string      `json:"avi_infra_setting,omitempty"`
 }
 
 type ServiceMetadataMappingObjType string
@@ -137,8 +142,24 @@ func (c ServiceMetadataObj) ServiceMetadataMapping(objType string) ServiceMetada
 var RestOpPerKeyType *prometheus.CounterVec
 var TotalRestOp prometheus.Counter
 var ObjectsInQueue *prometheus.GaugeVec
+var RestOpRollbacks *prometheus.CounterVec
+var SyncLatency prometheus.Histogram
+var LayerProcessingTime *prometheus.HistogramVec
+var AviRestLatency *prometheus.HistogramVec
+var AviRestErrors *prometheus.CounterVec
+var CacheRefreshTime *prometheus.HistogramVec
+var DriftedObjects *prometheus.CounterVec
 var reg *prometheus.Registry
 
+// lastFullSyncTime is the time of the last successful full sync, the AKO start time until then.
+var lastFullSyncTime = time.Now()
+var lastFullSyncLock sync.RWMutex
+
+// modelSyncStartTime holds, for each model, the time of the oldest K8s event that is not yet
+// applied in the controller.
+var modelSyncStartTime = make(map[string]time.Time)
+var modelSyncStartLock sync.Mutex
+
 func SetPrometheusRegistry() {
 	// creating new registry so no default metrics (which contains basic go related metrics)
 	reg = prometheus.NewRegistry()
@@ -188,6 +209,159 @@ func RegisterPromMetrics() *prometheus.Registry {
 		},
 	)
 	reg.MustRegister(ObjectsInQueue)
+
+	RestOpRollbacks = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ako",
+			Subsystem: subSystem,
+			Name:      "rest_op_rollbacks",
+			Help:      "Number of rest operation batches rolled back after a failure in the transactional rest apply mode.",
+		},
+		[]string{
+			// Were all the executed operations rolled back?
+			"status",
+		},
+	)
+	reg.MustRegister(RestOpRollbacks)
+
+	SyncLatency = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "ako",
+			Subsystem: subSystem,
+			Name:      "sync_latency_seconds",
+			Help:      "Time taken from a K8s event to the Avi objects of the affected model being applied in the controller.",
+			Buckets:   prometheus.ExponentialBuckets(0.05, 2, 14),
+		},
+	)
+	reg.MustRegister(SyncLatency)
+
+	LayerProcessingTime = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "ako",
+			Subsystem: subSystem,
+			Name:      "layer_processing_seconds",
+			Help:      "Time taken to process a key in a layer. For the ingestion layer, it is the time for which the key of a K8s event waits to be picked up by the graph layer.",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 2, 16),
+		},
+		[]string{
+			// ingestion, graph, rest or status.
+			"layer",
+		},
+	)
+	reg.MustRegister(LayerProcessingTime)
+
+	AviRestLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "ako",
+			Subsystem: subSystem,
+			Name:      "avi_rest_latency_seconds",
+			Help:      "Latency of the rest operations sent to controller from AKO.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+		},
+		[]string{
+			"method",
+			"object_type",
+		},
+	)
+	reg.MustRegister(AviRestLatency)
+
+	AviRestErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ako",
+			Subsystem: subSystem,
+			Name:      "avi_rest_errors",
+			Help:      "Number of rest operations sent to controller from AKO that failed, per status code of the response.",
+		},
+		[]string{
+			// Status code of the response, unknown if the controller couldn't be reached.
+			"status_code",
+			"object_type",
+		},
+	)
+	reg.MustRegister(AviRestErrors)
+
+	for _, layer := range []string{FAST_RETRY_LAYER, SLOW_RETRY_LAYER} {
+		retryLayer := layer
+		reg.MustRegister(prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Namespace:   "ako",
+				Subsystem:   subSystem,
+				Name:        "keys_in_retry_layer",
+				Help:        "Number of keys waiting to be retried in the retry layer.",
+				ConstLabels: prometheus.Labels{"layer": retryLayer},
+			},
+			func() float64 {
+				sharedQueue := utils.GetSharedWorkQueue()
+				if sharedQueue == nil || sharedQueue.GetQueueByName(retryLayer) == nil {
+					return 0
+				}
+				return float64(sharedQueue.GetQueueByName(retryLayer).PendingKeys())
+			},
+		))
+	}
+
+	for _, queue := range []string{utils.ObjectIngestionLayer, utils.GraphLayer, FAST_RETRY_LAYER, SLOW_RETRY_LAYER, utils.StatusQueue} {
+		queueName := queue
+		reg.MustRegister(prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Namespace:   "ako",
+				Subsystem:   subSystem,
+				Name:        "dead_letter_keys",
+				Help:        "Number of keys which failed to sync in all the attempts, and are not retried until a related object changes.",
+				ConstLabels: prometheus.Labels{"queuename": queueName},
+			},
+			func() float64 {
+				sharedQueue := utils.GetSharedWorkQueue()
+				if sharedQueue == nil || sharedQueue.GetQueueByName(queueName) == nil {
+					return 0
+				}
+				return float64(len(sharedQueue.GetQueueByName(queueName).DeadLetterKeys()))
+			},
+		))
+	}
+
+	CacheRefreshTime = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "ako",
+			Subsystem: subSystem,
+			Name:      "cache_refresh_seconds",
+			Help:      "Time taken to populate or refresh the Avi object cache from the controller.",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+		},
+		[]string{
+			// populate for the full cache populate, refresh for the periodic refresh.
+			"operation",
+		},
+	)
+	reg.MustRegister(CacheRefreshTime)
+
+	DriftedObjects = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ako",
+			Subsystem: subSystem,
+			Name:      "drifted_objects",
+			Help:      "Number of times an Avi object of AKO was found changed outside AKO during a full sync.",
+		},
+		[]string{
+			// Pool or VirtualService, the objects are not labelled by name to keep the cardinality bounded.
+			"object_type",
+		},
+	)
+	reg.MustRegister(DriftedObjects)
+
+	reg.MustRegister(prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Namespace: "ako",
+			Subsystem: subSystem,
+			Name:      "seconds_since_last_full_sync",
+			Help:      "Time elapsed since the last successful full sync of the K8s objects, or since AKO started if there wasn't one.",
+		},
+		func() float64 {
+			lastFullSyncLock.RLock()
+			defer lastFullSyncLock.RUnlock()
+			return time.Since(lastFullSyncTime).Seconds()
+		},
+	))
 	return reg
 }
 
@@ -207,6 +381,81 @@ func IncrementRestOpCouter(restOpMethod, objName string) {
 		RestOpPerKeyType.With(prometheus.Labels{"type": restOpMethod, "key": objName}).Inc()
 	}
 }
+func IncrementRestOpRollbackCounter(status string) {
+	if AKOControlConfig().GetAKOAKOPrometheusFlag() {
+		RestOpRollbacks.With(prometheus.Labels{"status": status}).Inc()
+	}
+}
+func ObserveLayerProcessingTime(layer string, start time.Time) {
+	if AKOControlConfig().GetAKOAKOPrometheusFlag() {
+		LayerProcessingTime.With(prometheus.Labels{"layer": layer}).Observe(time.Since(start).Seconds())
+	}
+}
+func ObserveAviRestOp(method, objectType string, start time.Time, err error) {
+	if !AKOControlConfig().GetAKOAKOPrometheusFlag() {
+		return
+	}
+	AviRestLatency.With(prometheus.Labels{"method": method, "object_type": objectType}).Observe(time.Since(start).Seconds())
+	if err != nil {
+		statusCode := "unknown"
+		if aviErr, ok := err.(session.AviError); ok {
+			statusCode = strconv.Itoa(aviErr.HttpStatusCode)
+		}
+		AviRestErrors.With(prometheus.Labels{"status_code": statusCode, "object_type": objectType}).Inc()
+	}
+}
+func ObserveCacheRefreshTime(operation string, start time.Time) {
+	if AKOControlConfig().GetAKOAKOPrometheusFlag() {
+		CacheRefreshTime.With(prometheus.Labels{"operation": operation}).Observe(time.Since(start).Seconds())
+	}
+}
+func IncrementDriftedObjectCounter(objectType string) {
+	if AKOControlConfig().GetAKOAKOPrometheusFlag() {
+		DriftedObjects.With(prometheus.Labels{"object_type": objectType}).Inc()
+	}
+}
+func SetLastFullSyncTime() {
+	lastFullSyncLock.Lock()
+	defer lastFullSyncLock.Unlock()
+	lastFullSyncTime = time.Now()
+}
+
+// RecordModelSyncStart is called when the graph layer publishes a model for the key it is
+// processing. It records the time of the K8s event behind the key for the model, unless an older
+// event for the model is still to be applied in the controller.
+func RecordModelSyncStart(modelName, key string) {
+	if !AKOControlConfig().GetAKOAKOPrometheusFlag() {
+		return
+	}
+	sharedQueue := utils.GetSharedWorkQueue()
+	if sharedQueue == nil || sharedQueue.GetQueueByName(utils.ObjectIngestionLayer) == nil {
+		return
+	}
+	eventTime, ok := sharedQueue.GetQueueByName(utils.ObjectIngestionLayer).InFlightKeyAddTime(key)
+	if !ok {
+		return
+	}
+	modelSyncStartLock.Lock()
+	defer modelSyncStartLock.Unlock()
+	if startTime, ok := modelSyncStartTime[modelName]; !ok || eventTime.Before(startTime) {
+		modelSyncStartTime[modelName] = eventTime
+	}
+}
+
+// ObserveModelSyncLatency is called once the model is applied in the controller, and reports the
+// time elapsed since the oldest K8s event recorded for the model.
+func ObserveModelSyncLatency(modelName string) {
+	if !AKOControlConfig().GetAKOAKOPrometheusFlag() {
+		return
+	}
+	modelSyncStartLock.Lock()
+	startTime, ok := modelSyncStartTime[modelName]
+	delete(modelSyncStartTime, modelName)
+	modelSyncStartLock.Unlock()
+	if ok {
+		SyncLatency.Observe(time.Since(startTime).Seconds())
+	}
+}
 
 type VSNameMetadata struct {
 	Name      string
@@ -437,6 +686,22 @@ func GetL4PoolName(svcName, namespace, protocol string, port int32) string {
 	return Encode(poolName, L4Pool)
 }
 
+// GetClientIPPersistenceProfileName returns the name of the client IP persistence profile with the
+// given timeout in minutes. The profile is shared by all the pools with that timeout.
+func GetClientIPPersistenceProfileName(timeout int32) string {
+	persistenceProfileName := NamePrefix + "clientip-persistence-" + strconv.Itoa(int(timeout))
+	CheckObjectNameLength(persistenceProfileName, PersistenceProfile)
+	return persistenceProfileName
+}
+
+// GetRegexStringGroupName returns the name of the string group holding a regular expression path
+// matched by the rules of an HTTP policyset.
+func GetRegexStringGroupName(httpPolicySetName, regex string) string {
+	stringGroupName := httpPolicySetName + "-regex-" + strconv.FormatUint(uint64(utils.Hash(regex)), 10)
+	CheckObjectNameLength(stringGroupName, StringGroup)
+	return stringGroupName
+}
+
 func GetAdvL4PoolName(svcName, namespace, gwName string, port int32) string {
 	poolName := NamePrefix + namespace + "-" + svcName + "-" + gwName + "--" + strconv.Itoa(int(port))
 	return Encode(poolName, L4AdvPool)
@@ -466,6 +731,17 @@ func GetL7SharedPGName(vsName string) string {
 	return l7PGName
 }
 
+// GetL7DefaultBackendPoolName returns the name of the pool of the Ingress default backend on
+// a shared VS. The name is derived from the VS alone, since a shared VS has a single default
+// backend, whichever Ingress it is picked from.
+func GetL7DefaultBackendPoolName(vsName string) string {
+	return Encode(vsName+"-default-backend", Pool)
+}
+
+func GetL7DefaultBackendPGName(vsName string) string {
+	return Encode(vsName+"-default-backend", PG)
+}
+
 func GetPassthroughPGName(hostname, infrasettingName string) string {
 	var pgName string
 	if infrasettingName != "" {
@@ -1036,6 +1312,157 @@ func UseServicesAPI() bool {
 	return false
 }
 
+// If this flag is set to true, then AKO builds pool servers from discovery.k8s.io/v1
+// EndpointSlices instead of the legacy core/v1 Endpoints objects.
+func IsEndpointSliceEnabled() bool {
+	if ok, _ := strconv.ParseBool(os.Getenv(ENABLE_ENDPOINTSLICE)); ok {
+		return true
+	}
+	return false
+}
+
+// GetServiceNameForEndpointSlice returns the name of the Service that owns the
+// EndpointSlice, derived from the kubernetes.io/service-name label.
+func GetServiceNameForEndpointSlice(epSlice *discoveryv1.EndpointSlice) (string, bool) {
+	svcName, ok := epSlice.Labels[discoveryv1.LabelServiceName]
+	if !ok || svcName == "" {
+		return "", false
+	}
+	return svcName, true
+}
+
+// GetTopologyZone returns the zone used to honour EndpointSlice topology hints.
+// Hints are ignored when no zone is configured.
+func GetTopologyZone() string {
+	return os.Getenv(TOPOLOGY_ZONE)
+}
+
+// If this flag is set to true, then AKO rolls back the rest operations already executed
+// for a model when a later operation fails, so that no partially applied configuration
+// is left on the controller.
+func IsTransactionalRestApplyEnabled() bool {
+	if ok, _ := strconv.ParseBool(os.Getenv(TRANSACTIONAL_REST_APPLY)); ok {
+		return true
+	}
+	return false
+}
+
+// IsDryRunEnabled returns true if AKO has to compute the changes for the models without applying
+// them in the controller. The changes are recorded in a plan instead.
+func IsDryRunEnabled() bool {
+	if ok, _ := strconv.ParseBool(os.Getenv(DRY_RUN)); ok {
+		return true
+	}
+	return false
+}
+
+// GetDryRunPlanFile returns the file to which the plan is written in the dry-run mode.
+func GetDryRunPlanFile() string {
+	return os.Getenv(DRY_RUN_PLAN_FILE)
+}
+
+// GetDriftDetectionPolicy returns the policy for the Avi objects of AKO which are found changed
+// outside AKO during a full sync, report or repair. It is empty if the drift detection is disabled.
+func GetDriftDetectionPolicy() string {
+	policy := strings.ToLower(os.Getenv(DRIFT_DETECTION))
+	switch policy {
+	case DriftDetectionReport, DriftDetectionRepair:
+		return policy
+	case "", DriftDetectionDisabled:
+	default:
+		utils.AviLog.Warnf("Invalid value %s for drift detection, the drift detection is disabled", policy)
+	}
+	return ""
+}
+
+// GetSyncMaxAttempts returns the number of times the rest layer syncs a model which fails with
+// an error that is not retried by the retry layers, before the model is dead-lettered.
+func GetSyncMaxAttempts() int {
+	maxAttempts := os.Getenv(SYNC_MAX_ATTEMPTS)
+	if maxAttempts == "" {
+		return utils.DefaultMaxAttempts
+	}
+	attempts, err := strconv.Atoi(maxAttempts)
+	if err != nil || attempts < 1 {
+		utils.AviLog.Warnf("Invalid value %s for %s, using %d", maxAttempts, SYNC_MAX_ATTEMPTS, utils.DefaultMaxAttempts)
+		return utils.DefaultMaxAttempts
+	}
+	return attempts
+}
+
+// IsCRDWebhookEnabled returns true if the validating admission webhook of the AKO CRDs
+// has to be started along with the AKO API server.
+func IsCRDWebhookEnabled() bool {
+	if ok, _ := strconv.ParseBool(os.Getenv(ENABLE_CRD_WEBHOOK)); ok {
+		return true
+	}
+	return false
+}
+
+func GetCRDWebhookPort() string {
+	port := os.Getenv(CRD_WEBHOOK_PORT)
+	if port != "" {
+		return port
+	}
+	return "9443"
+}
+
+// GetCRDWebhookCertDir returns the directory which has the tls.crt and tls.key files
+// served by the CRD admission webhook.
+func GetCRDWebhookCertDir() string {
+	certDir := os.Getenv(CRD_WEBHOOK_CERT_DIR)
+	if certDir != "" {
+		return certDir
+	}
+	return "/etc/ako/webhook-certs"
+}
+
+// GetGracefulDrainPeriod returns the period for which terminating endpoints are kept
+// in the pools as disabled servers, so that in-flight connections are drained.
+// A period of 0 disables draining.
+func GetGracefulDrainPeriod() time.Duration {
+	drainPeriod := os.Getenv(GRACEFUL_DRAIN_PERIOD)
+	if drainPeriod == "" || !IsGracefulDrainSupported() {
+		return 0
+	}
+	seconds, err := strconv.Atoi(drainPeriod)
+	if err != nil || seconds < 0 {
+		utils.AviLog.Warnf("Invalid value %s for %s, graceful draining of pool servers is disabled", drainPeriod, GRACEFUL_DRAIN_PERIOD)
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// IsGracefulDrainSupported returns true if the terminating endpoints can be found. In ClusterIP
+// mode they are known only from the EndpointSlices, as the legacy Endpoints drop them.
+func IsGracefulDrainSupported() bool {
+	return IsNodePortMode() || GetServiceType() == NodePortLocal || IsEndpointSliceEnabled()
+}
+
+// GetPoolGracefulDisableTimeout returns the graceful_disable_timeout of the pools, in
+// minutes, that covers the graceful drain period.
+func GetPoolGracefulDisableTimeout() int32 {
+	minutes := int32(math.Ceil(GetGracefulDrainPeriod().Minutes()))
+	if minutes > MaxGracefulDisableTimeout {
+		minutes = MaxGracefulDisableTimeout
+	}
+	return minutes
+}
+
+// IsNodeTerminating returns true if the node is being deleted, or has been marked
+// for deletion by the cluster autoscaler.
+func IsNodeTerminating(node *corev1.Node) bool {
+	if node.DeletionTimestamp != nil {
+		return true
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == ToBeDeletedTaint {
+			return true
+		}
+	}
+	return false
+}
+
 // CompareVersions compares version v1 against version v2.
 func CompareVersions(v1, cmpSign, v2 string) bool {
 	if c, err := semver.NewConstraint(cmpSign + v2); err == nil {
@@ -1358,15 +1785,21 @@ func PopulatePassthroughPoolMarkers(host, svcName, infrasettingName string) util
 func InformersToRegister(kclient *kubernetes.Clientset, oclient *oshiftclient.Clientset) ([]string, error) {
 	var isOshift bool
 	// Initialize the following informers in all AKO deployments. Provide AKO the ability to watch over
-	// Services, Endpoints, Secrets, ConfigMaps and Namespaces.
+	// Services, Endpoints/EndpointSlices, Secrets, ConfigMaps and Namespaces.
 	allInformers := []string{
 		utils.ServiceInformer,
-		utils.EndpointInformer,
 		utils.SecretInformer,
 		utils.ConfigMapInformer,
 		utils.NSInformer,
 	}
 
+	// Watch over EndpointSlices instead of the legacy Endpoints when enabled.
+	if IsEndpointSliceEnabled() {
+		allInformers = append(allInformers, utils.EndpointSlicesInformer)
+	} else {
+		allInformers = append(allInformers, utils.EndpointInformer)
+	}
+
 	// AKO must watch over Pods in case of NodePortLocal, to get Antrea annotation values.
 	if GetServiceType() == NodePortLocal {
 		allInformers = append(allInformers, utils.PodInformer)
@@ -1468,6 +1901,98 @@ func L4PolicyChecksum(ports []int64, protocols []string, ingestionMarkers utils.
 	return checksum
 }
 
+func TrafficCloneProfileChecksum(cloneServers []string, ingestionMarkers utils.AviObjectMarkers, markers []*models.RoleFilterMatchLabel, populateCache bool) uint32 {
+	servers := make([]string, len(cloneServers))
+	copy(servers, cloneServers)
+	sort.Strings(servers)
+	checksum := utils.Hash(utils.Stringify(servers))
+	if populateCache {
+		if markers != nil {
+			checksum += ObjectLabelChecksum(markers)
+		}
+		return checksum
+	}
+	checksum += GetMarkersChecksum(ingestionMarkers)
+	return checksum
+}
+
+// NetworkSecurityPolicyChecksum returns the checksum of the client CIDRs allowed on each VS port.
+func NetworkSecurityPolicyChecksum(sourceRanges map[int64][]string, ingestionMarkers utils.AviObjectMarkers, markers []*models.RoleFilterMatchLabel, populateCache bool) uint32 {
+	ports := make([]int, 0, len(sourceRanges))
+	for port := range sourceRanges {
+		ports = append(ports, int(port))
+	}
+	sort.Ints(ports)
+	var rangeStrings []string
+	for _, port := range ports {
+		cidrs := make([]string, len(sourceRanges[int64(port)]))
+		copy(cidrs, sourceRanges[int64(port)])
+		sort.Strings(cidrs)
+		rangeStrings = append(rangeStrings, strconv.Itoa(port)+"="+strings.Join(cidrs, ","))
+	}
+	checksum := utils.Hash(utils.Stringify(rangeStrings))
+	if populateCache {
+		if markers != nil {
+			checksum += ObjectLabelChecksum(markers)
+		}
+		return checksum
+	}
+	checksum += GetMarkersChecksum(ingestionMarkers)
+	return checksum
+}
+
+// PersistenceProfileChecksum returns the checksum of a client IP persistence profile created by AKO.
+func PersistenceProfileChecksum(timeout int32, ingestionMarkers utils.AviObjectMarkers, markers []*models.RoleFilterMatchLabel, populateCache bool) uint32 {
+	checksum := utils.Hash(PERSISTENCE_TYPE_CLIENT_IP + ":" + strconv.Itoa(int(timeout)))
+	if populateCache {
+		if markers != nil {
+			checksum += ObjectLabelChecksum(markers)
+		}
+		return checksum
+	}
+	checksum += GetMarkersChecksum(ingestionMarkers)
+	return checksum
+}
+
+// StringGroupChecksum returns the checksum of a string group of regular expressions created by AKO.
+func StringGroupChecksum(regex []string, ingestionMarkers utils.AviObjectMarkers, markers []*models.RoleFilterMatchLabel, populateCache bool) uint32 {
+	sortedRegex := make([]string, len(regex))
+	copy(sortedRegex, regex)
+	sort.Strings(sortedRegex)
+	checksum := utils.Hash(SG_TYPE_STRING + ":" + strings.Join(sortedRegex, ","))
+	if populateCache {
+		if markers != nil {
+			checksum += ObjectLabelChecksum(markers)
+		}
+		return checksum
+	}
+	checksum += GetMarkersChecksum(ingestionMarkers)
+	return checksum
+}
+
+// NetworkSecurityPolicySourceRanges returns the client CIDRs allowed on each VS port by the
+// allow rules of a network security policy created by AKO.
+func NetworkSecurityPolicySourceRanges(rules []*models.NetworkSecurityRule) map[int64][]string {
+	sourceRanges := make(map[int64][]string)
+	for _, rule := range rules {
+		if rule.Action == nil || *rule.Action != NSP_ACTION_ALLOW ||
+			rule.Match == nil || rule.Match.VsPort == nil || rule.Match.ClientIP == nil {
+			continue
+		}
+		var cidrs []string
+		for _, prefix := range rule.Match.ClientIP.Prefixes {
+			if prefix.IPAddr == nil || prefix.IPAddr.Addr == nil || prefix.Mask == nil {
+				continue
+			}
+			cidrs = append(cidrs, *prefix.IPAddr.Addr+"/"+strconv.Itoa(int(*prefix.Mask)))
+		}
+		for _, port := range rule.Match.VsPort.Ports {
+			sourceRanges[port] = append(sourceRanges[port], cidrs...)
+		}
+	}
+	return sourceRanges
+}
+
 func IsNodePortMode() bool {
 	nodePortType := os.Getenv(SERVICE_TYPE)
 	if nodePortType == NODE_PORT {
@@ -1954,6 +2479,11 @@ func GetControllerPropertiesFromSecret(cs kubernetes.Interface) (map[string]stri
 	} else {
 		ctrlProps[utils.ENV_CTRL_CADATA] = ""
 	}
+	if aviSecret.Data["debugApiToken"] != nil {
+		ctrlProps[utils.ENV_DEBUG_API_TOKEN] = string(aviSecret.Data["debugApiToken"])
+	} else {
+		ctrlProps[utils.ENV_DEBUG_API_TOKEN] = ""
+	}
 	return ctrlProps, nil
 }
 
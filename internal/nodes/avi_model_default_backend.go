@@ -0,0 +1,300 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package nodes
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	akov1beta1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1beta1"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	avimodels "github.com/vmware/alb-sdk/go/models"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ingressDefaultBackend is the default backend of an Ingress, along with the details needed to
+// build its pool on the shared VSes.
+type ingressDefaultBackend struct {
+	namespace    string
+	ingName      string
+	created      time.Time
+	infraSetting *akov1beta1.AviInfraSetting
+	backend      IngressHostPathSvc
+}
+
+func (b *ingressDefaultBackend) key() string {
+	return b.namespace + "/" + b.ingName
+}
+
+// defaultBackendStore tracks the Ingress default backends of the shared VSes. The default backend
+// of an Ingress applies to all the shared VSes of its IngressClass, and when more than one Ingress
+// has a default backend for a shared VS, the default backend of the oldest Ingress is picked, so
+// that the Ingresses of different namespaces don't override each other's default backend.
+type defaultBackendStore struct {
+	lock sync.RWMutex
+	// shared VS name -> namespace/ingress -> default backend
+	vsToBackends map[string]map[string]*ingressDefaultBackend
+	// namespace/ingress -> shared VS names
+	ingToVSNames map[string][]string
+}
+
+var sharedDefaultBackendStore = &defaultBackendStore{
+	vsToBackends: make(map[string]map[string]*ingressDefaultBackend),
+	ingToVSNames: make(map[string][]string),
+}
+
+// update sets the default backend of an Ingress for the given shared VSes, and returns the VSes
+// the default backend was applicable to before the update along with the ones it applies to now.
+// A nil backend removes the default backend of the Ingress.
+func (s *defaultBackendStore) update(ingKey string, vsNames []string, backend *ingressDefaultBackend) []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	oldVSNames := s.ingToVSNames[ingKey]
+	for _, vsName := range oldVSNames {
+		delete(s.vsToBackends[vsName], ingKey)
+		if len(s.vsToBackends[vsName]) == 0 {
+			delete(s.vsToBackends, vsName)
+		}
+	}
+	delete(s.ingToVSNames, ingKey)
+	if backend == nil || len(vsNames) == 0 {
+		return oldVSNames
+	}
+	for _, vsName := range vsNames {
+		if _, ok := s.vsToBackends[vsName]; !ok {
+			s.vsToBackends[vsName] = make(map[string]*ingressDefaultBackend)
+		}
+		s.vsToBackends[vsName][ingKey] = backend
+	}
+	s.ingToVSNames[ingKey] = vsNames
+	affected := append([]string{}, vsNames...)
+	for _, vsName := range oldVSNames {
+		if !utils.HasElem(affected, vsName) {
+			affected = append(affected, vsName)
+		}
+	}
+	return affected
+}
+
+// get returns the default backend picked for a shared VS, the one of the oldest Ingress, with the
+// namespace/name of the Ingresses breaking the ties.
+func (s *defaultBackendStore) get(vsName string) *ingressDefaultBackend {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	var backends []*ingressDefaultBackend
+	for _, backend := range s.vsToBackends[vsName] {
+		backends = append(backends, backend)
+	}
+	if len(backends) == 0 {
+		return nil
+	}
+	sort.Slice(backends, func(i, j int) bool {
+		if !backends[i].created.Equal(backends[j].created) {
+			return backends[i].created.Before(backends[j].created)
+		}
+		return backends[i].key() < backends[j].key()
+	})
+	return backends[0]
+}
+
+// getSharedVSNamesForDefaultBackend returns the names of the shared VSes of the IngressClass
+// of an Ingress. The default backend is not applied to dedicated VSes.
+func getSharedVSNamesForDefaultBackend(routeIgrObj RouteIngressModel, key string) []string {
+	infraSetting := routeIgrObj.GetAviInfraSetting()
+	var vsPrefix string
+	if lib.IsEvhEnabled() {
+		vsPrefix = lib.GetNamePrefix() + lib.GetAKOIDPrefix() + lib.ShardEVHVSPrefix
+	} else {
+		vsPrefix = GetShardVSPrefix(key)
+	}
+	if infraSetting != nil {
+		vsPrefix += infraSetting.Name + "-"
+	}
+	if lib.IsEvhEnabled() && lib.VIPPerNamespace() {
+		return []string{vsPrefix + "NS-" + routeIgrObj.GetNamespace()}
+	}
+	shardSize := lib.GetShardSizeFromAviInfraSetting(infraSetting)
+	if shardSize == 0 {
+		return nil
+	}
+	var vsNames []string
+	for i := uint32(0); i < shardSize; i++ {
+		vsNames = append(vsNames, vsPrefix+strconv.Itoa(int(i)))
+	}
+	return vsNames
+}
+
+// ProcessDefaultBackend applies the default backend of an Ingress to the shared VSes of its
+// IngressClass, and removes it from the shared VSes it no longer applies to. A nil defaultBackend
+// removes the default backend of the Ingress, e.g. when the Ingress is deleted. The default backend
+// is applied to the shared VSes which are present in the models, the shared VSes which are created
+// later pick it up when they get created.
+func ProcessDefaultBackend(routeIgrObj RouteIngressModel, key string, defaultBackend *IngressHostPathSvc, modelList *[]string) {
+	if routeIgrObj.GetType() != utils.Ingress {
+		return
+	}
+	namespace, ingName := routeIgrObj.GetNamespace(), routeIgrObj.GetName()
+	ingKey := namespace + "/" + ingName
+
+	var backend *ingressDefaultBackend
+	var vsNames []string
+	if defaultBackend != nil {
+		ingObj, err := utils.GetInformers().IngressInformer.Lister().Ingresses(namespace).Get(ingName)
+		if err != nil {
+			utils.AviLog.Warnf("key: %s, msg: unable to get the Ingress for the default backend: %v", key, err)
+		} else {
+			vsNames = getSharedVSNamesForDefaultBackend(routeIgrObj, key)
+			if len(vsNames) == 0 {
+				utils.AviLog.Warnf("key: %s, msg: default backend of Ingress %s is not applied to dedicated virtualservices", key, ingKey)
+			}
+			backend = &ingressDefaultBackend{
+				namespace:    namespace,
+				ingName:      ingName,
+				created:      ingObj.CreationTimestamp.Time,
+				infraSetting: routeIgrObj.GetAviInfraSetting(),
+				backend:      *defaultBackend,
+			}
+		}
+	}
+
+	affectedVSNames := sharedDefaultBackendStore.update(ingKey, vsNames, backend)
+	var conflicts []string
+	for _, vsName := range affectedVSNames {
+		if backend != nil && utils.HasElem(vsNames, vsName) {
+			if picked := sharedDefaultBackendStore.get(vsName); picked != nil && picked.key() != ingKey && !utils.HasElem(conflicts, picked.key()) {
+				conflicts = append(conflicts, picked.key())
+			}
+		}
+		modelName := lib.GetModelName(lib.GetTenant(), vsName)
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found || aviModel == nil {
+			continue
+		}
+		aviModel.(*AviObjectGraph).BuildDefaultBackendForVs(vsName, key)
+		changedModel := saveAviModel(modelName, aviModel.(*AviObjectGraph), key)
+		if !utils.HasElem(*modelList, modelName) && changedModel {
+			*modelList = append(*modelList, modelName)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		utils.AviLog.Warnf("key: %s, msg: default backend of Ingress %s is not applied to all the shared virtualservices, since the older Ingresses %v have a default backend", key, ingKey, conflicts)
+		if ingObj, err := utils.GetInformers().IngressInformer.Lister().Ingresses(namespace).Get(ingName); err == nil {
+			lib.AKOControlConfig().EventRecorder().Eventf(ingObj, corev1.EventTypeWarning, lib.DefaultBackendConflict,
+				"Default backend is not applied to all the shared virtualservices, since the older Ingresses %v have a default backend", conflicts)
+		}
+	}
+}
+
+// BuildDefaultBackendForVs sets the default backend picked for a shared VS in the model, as the
+// default poolgroup of the VS.
+func (o *AviObjectGraph) BuildDefaultBackendForVs(vsName, key string) {
+	o.Lock.Lock()
+	defer o.Lock.Unlock()
+	o.buildDefaultBackendForVs(vsName, key)
+}
+
+func (o *AviObjectGraph) buildDefaultBackendForVs(vsName, key string) {
+	poolName, pgName := lib.GetL7DefaultBackendPoolName(vsName), lib.GetL7DefaultBackendPGName(vsName)
+	var poolNode *AviPoolNode
+	var pgNode *AviPoolGroupNode
+	if backend := sharedDefaultBackendStore.get(vsName); backend != nil {
+		poolNode, pgNode = buildDefaultBackendPoolPG(key, poolName, pgName, backend)
+		utils.AviLog.Infof("key: %s, msg: default backend of Ingress %s is the default backend of virtualservice %s", key, backend.key(), vsName)
+	}
+
+	if vsNodes := o.GetAviEvhVS(); len(vsNodes) > 0 {
+		vsNode := vsNodes[0]
+		vsNode.PoolRefs = replaceDefaultBackendPool(vsNode.PoolRefs, poolName, poolNode)
+		vsNode.PoolGroupRefs = replaceDefaultBackendPG(vsNode.PoolGroupRefs, pgName, pgNode)
+		vsNode.DefaultPoolGroup = ""
+		if pgNode != nil {
+			vsNode.DefaultPoolGroup = pgName
+		}
+	} else if vsNodes := o.GetAviVS(); len(vsNodes) > 0 {
+		vsNode := vsNodes[0]
+		vsNode.PoolRefs = replaceDefaultBackendPool(vsNode.PoolRefs, poolName, poolNode)
+		vsNode.PoolGroupRefs = replaceDefaultBackendPG(vsNode.PoolGroupRefs, pgName, pgNode)
+		vsNode.DefaultPoolGroup = ""
+		if pgNode != nil {
+			vsNode.DefaultPoolGroup = pgName
+		}
+	}
+}
+
+func buildDefaultBackendPoolPG(key, poolName, pgName string, backend *ingressDefaultBackend) (*AviPoolNode, *AviPoolGroupNode) {
+	var infraSettingName string
+	if backend.infraSetting != nil {
+		infraSettingName = backend.infraSetting.Name
+	}
+	poolNode := buildPoolNode(key, poolName, backend.ingName, backend.namespace, "", "", backend.infraSetting, "", nil, false, backend.backend)
+	// The default backend pool serves no host, and is not a part of the shared poolgroup of the VS,
+	// which picks the pools by priority label.
+	poolNode.IngressName = ""
+	poolNode.PriorityLabel = ""
+	poolNode.ServiceMetadata = lib.ServiceMetadataObj{}
+	poolNode.AviMarkers = utils.AviObjectMarkers{
+		Namespace:        backend.namespace,
+		InfrasettingName: infraSettingName,
+		ServiceName:      backend.backend.ServiceName,
+		IngressName:      []string{backend.ingName},
+	}
+
+	poolRef := fmt.Sprintf("/api/pool?name=%s", poolNode.Name)
+	ratio := backend.backend.weight
+	pgNode := &AviPoolGroupNode{
+		Name:    pgName,
+		Tenant:  lib.GetTenant(),
+		Members: []*avimodels.PoolGroupMember{{PoolRef: &poolRef, Ratio: &ratio}},
+		AviMarkers: utils.AviObjectMarkers{
+			Namespace:        backend.namespace,
+			InfrasettingName: infraSettingName,
+			IngressName:      []string{backend.ingName},
+		},
+	}
+	return poolNode, pgNode
+}
+
+func replaceDefaultBackendPool(poolRefs []*AviPoolNode, poolName string, poolNode *AviPoolNode) []*AviPoolNode {
+	for i := range poolRefs {
+		if poolRefs[i].Name == poolName {
+			poolRefs = append(poolRefs[:i], poolRefs[i+1:]...)
+			break
+		}
+	}
+	if poolNode != nil {
+		poolRefs = append(poolRefs, poolNode)
+	}
+	return poolRefs
+}
+
+func replaceDefaultBackendPG(pgRefs []*AviPoolGroupNode, pgName string, pgNode *AviPoolGroupNode) []*AviPoolGroupNode {
+	for i := range pgRefs {
+		if pgRefs[i].Name == pgName {
+			pgRefs = append(pgRefs[:i], pgRefs[i+1:]...)
+			break
+		}
+	}
+	if pgNode != nil {
+		pgRefs = append(pgRefs, pgNode)
+	}
+	return pgRefs
+}
@@ -28,7 +28,6 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 
 	avimodels "github.com/vmware/alb-sdk/go/models"
-	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
@@ -135,6 +134,7 @@ type AviEvhVsNode struct {
 	SSLKeyCertRefs      []*AviTLSKeyCertNode
 	HttpPolicyRefs      []*AviHttpPolicySetNode
 	VSVIPRefs           []*AviVSVIPNode
+	TrafficCloneRefs    []*AviTrafficCloneProfileNode
 	TLSType             string
 	ServiceMetadata     lib.ServiceMetadataObj
 	VrfContext          string
@@ -852,6 +852,9 @@ func (o *AviObjectGraph) ConstructAviL7SharedVsNodeForEvh(vsName, key string, ro
 	if avi_vs_meta.SharedVS && configuredSharedVSFqdn != "" {
 		BuildL7HostRule(configuredSharedVSFqdn, key, avi_vs_meta)
 	}
+	if avi_vs_meta.SharedVS {
+		o.buildDefaultBackendForVs(vsName, key)
+	}
 }
 
 func (o *AviObjectGraph) BuildPolicyPGPoolsForEVH(vsNode []*AviEvhVsNode, childNode *AviEvhVsNode, namespace, ingName, key string, infraSetting *akov1beta1.AviInfraSetting, hosts []string, paths []IngressHostPathSvc, tlsSettings *TlsSettings, modelType string) {
@@ -889,13 +892,7 @@ func (o *AviObjectGraph) BuildPolicyPGPoolsForEVH(vsNode []*AviEvhVsNode, childN
 	for _, path := range paths {
 		httpPGPath := AviHostPathPortPoolPG{Host: allFqdns}
 
-		if path.PathType == networkingv1.PathTypeExact {
-			httpPGPath.MatchCriteria = "EQUALS"
-		} else {
-			// PathTypePrefix and PathTypeImplementationSpecific
-			// default behaviour for AKO set be Prefix match on the path
-			httpPGPath.MatchCriteria = "BEGINS_WITH"
-		}
+		httpPGPath.MatchCriteria = getPathMatchCriteria(path)
 
 		if path.Path != "" {
 			httpPGPath.Path = append(httpPGPath.Path, path.Path)
@@ -2165,6 +2162,7 @@ func buildWithInfraSettingForEvh(key, namespace string, vs *AviEvhVsNode, vsvip
 		if infraSetting.Spec.NSXSettings.T1LR != nil {
 			vsvip.T1Lr = *infraSetting.Spec.NSXSettings.T1LR
 		}
+		vs.ServiceMetadata.AviInfraSetting = infraSetting.Name
 		utils.AviLog.Debugf("key: %s, msg: Applied AviInfraSetting configuration over VSNode %s", key, vs.Name)
 	}
 }
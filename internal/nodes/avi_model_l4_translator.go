@@ -16,9 +16,11 @@ package nodes
 
 import (
 	"fmt"
+	"net"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"google.golang.org/protobuf/proto"
 
@@ -34,7 +36,9 @@ import (
 	"github.com/vmware/alb-sdk/go/models"
 	avimodels "github.com/vmware/alb-sdk/go/models"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	k8net "k8s.io/utils/net"
@@ -117,7 +121,7 @@ func (o *AviObjectGraph) ConstructAviL4VsNode(svcObj *corev1.Service, key string
 		avi_vs_meta.ApplicationProfile = utils.DEFAULT_L4_APP_PROFILE
 	}
 
-	avi_vs_meta.NetworkProfile = getNetworkProfile(isSCTP, isTCP, isUDP)
+	avi_vs_meta.NetworkProfile = GetNetworkProfile(isSCTP, isTCP, isUDP)
 
 	vsVipName := lib.GetL4VSVipName(svcObj.ObjectMeta.Name, svcObj.ObjectMeta.Namespace)
 	vsVipNode := &AviVSVIPNode{
@@ -143,6 +147,10 @@ func (o *AviObjectGraph) ConstructAviL4VsNode(svcObj *corev1.Service, key string
 		buildWithL4Rule(key, avi_vs_meta, l4Rule)
 	}
 
+	sourceRanges := make(map[int64][]string)
+	addLoadBalancerSourceRanges(key, svcObj, sourceRanges)
+	buildWithLoadBalancerSourceRanges(key, avi_vs_meta, sourceRanges)
+
 	if lib.HasSpecLoadBalancerIP(svcObj) {
 		vsVipNode.IPAddress = svcObj.Spec.LoadBalancerIP
 	} else if lib.HasLoadBalancerIPAnnotation(svcObj) {
@@ -234,6 +242,7 @@ func (o *AviObjectGraph) ConstructAviL4PolPoolNodes(svcObj *corev1.Service, vsNo
 		}
 
 		poolNode.AviMarkers = lib.PopulateL4PoolNodeMarkers(svcObj.ObjectMeta.Namespace, svcObj.ObjectMeta.Name, strconv.Itoa(int(filterPort)))
+		buildPoolWithSessionAffinity(key, poolNode, svcObj)
 		pool_ref := fmt.Sprintf("/api/pool?name=%s", poolNode.Name)
 		portPool := AviHostPathPortPoolPG{Port: uint32(filterPort), Pool: pool_ref, Protocol: portProto.Protocol}
 		portPoolSet = append(portPoolSet, portPool)
@@ -293,8 +302,24 @@ func PopulateServersForNPL(poolNode *AviPoolNode, ns string, serviceName string,
 	v6ServerCount := 0
 	var poolMeta []AviPoolMetaServer
 
+	// Terminating pods are kept as draining servers within the graceful drain period.
+	terminatingPods := sets.NewString()
+	drainingPods := sets.NewString()
+	if lib.GetGracefulDrainPeriod() > 0 {
+		for _, pod := range pods {
+			podObj, err := utils.GetInformers().PodInformer.Lister().Pods(pod.Namespace).Get(pod.Name)
+			if err == nil && podObj.DeletionTimestamp != nil {
+				terminatingPods.Insert(pod.Name)
+			}
+		}
+		drainingPods = getDrainingServers(ns, serviceName, poolNode.Name, terminatingPods.List(), key)
+	}
+
 	for _, pod := range pods {
 		var annotations []lib.NPLAnnotation
+		if terminatingPods.Has(pod.Name) && !drainingPods.Has(pod.Name) {
+			continue
+		}
 		found, obj := objects.SharedNPLLister().Get(ns + "/" + pod.Name)
 		if !found {
 			continue
@@ -318,7 +343,9 @@ func PopulateServersForNPL(poolNode *AviPoolNode, ns string, serviceName string,
 					Ip: models.IPAddr{
 						Addr: &a.NodeIP,
 						Type: &atype,
-					}}
+					},
+					Draining: drainingPods.Has(pod.Name),
+				}
 				poolMeta = append(poolMeta, server)
 			}
 		}
@@ -376,6 +403,24 @@ func PopulateServersForNodePort(poolNode *AviPoolNode, ns string, serviceName st
 		utils.AviLog.Debugf("key: %s, msg: ClusterIP is not processed in NodePort: %s", key, serviceName)
 		return poolMeta
 	}
+	// Nodes that are being removed are kept as draining servers within the graceful drain period.
+	terminatingNodes := sets.NewString()
+	drainingNodes := sets.NewString()
+	if lib.GetGracefulDrainPeriod() > 0 {
+		for _, nodeIntf := range allNodes {
+			if node, ok := nodeIntf.(*corev1.Node); ok && lib.IsNodeTerminating(node) {
+				terminatingNodes.Insert(node.Name)
+			}
+		}
+		drainingNodes = getDrainingServers(ns, serviceName, poolNode.Name, terminatingNodes.List(), key)
+	}
+	// With the Local external traffic policy the nodes drop the traffic for the service
+	// unless they host one of its endpoints, hence only such nodes are added as servers.
+	var endpointNodes sets.String
+	if svcObj.Spec.ExternalTrafficPolicy == corev1.ServiceExternalTrafficPolicyLocal {
+		endpointNodes = getEndpointNodes(poolNode, ns, serviceName, key)
+		utils.AviLog.Debugf("key: %s, msg: externalTrafficPolicy is Local for service %s, nodes with endpoints: %v", key, serviceName, endpointNodes.List())
+	}
 	for _, port := range svcObj.Spec.Ports {
 		if port.Name != poolNode.PortName && len(svcObj.Spec.Ports) != 1 {
 			// continue only if port name does not match and its multiport svcobj
@@ -400,6 +445,12 @@ func PopulateServersForNodePort(poolNode *AviPoolNode, ns string, serviceName st
 				}
 
 			}
+			if terminatingNodes.Has(node.Name) && !drainingNodes.Has(node.Name) {
+				continue
+			}
+			if endpointNodes != nil && !endpointNodes.Has(node.Name) {
+				continue
+			}
 			nodeIP, nodeIP6 := lib.GetIPFromNode(node)
 			var atype string
 			var serverIP avimodels.IPAddr
@@ -415,7 +466,7 @@ func PopulateServersForNodePort(poolNode *AviPoolNode, ns string, serviceName st
 				continue
 			}
 
-			server := AviPoolMetaServer{Ip: serverIP}
+			server := AviPoolMetaServer{Ip: serverIP, Draining: drainingNodes.Has(node.Name)}
 			poolMeta = append(poolMeta, server)
 		}
 	}
@@ -435,6 +486,27 @@ func PopulateServersForNodePort(poolNode *AviPoolNode, ns string, serviceName st
 	return poolMeta
 }
 
+// getEndpointNodes returns the names of the nodes hosting the endpoints of the service that
+// serve the pool's port.
+func getEndpointNodes(poolNode *AviPoolNode, ns, serviceName, key string) sets.String {
+	// The endpoint lookups overwrite the pool port with the target port, which must stay the
+	// node port in NodePort mode, hence they are done on a node carrying just the port details.
+	epPoolNode := &AviPoolNode{PortName: poolNode.PortName, TargetPort: poolNode.TargetPort}
+	var addresses []corev1.EndpointAddress
+	if lib.IsEndpointSliceEnabled() {
+		addresses, _ = getEndpointSliceAddresses(epPoolNode, ns, serviceName, key)
+	} else {
+		addresses = getEndpointsAddresses(epPoolNode, ns, serviceName, key)
+	}
+	nodeNames := sets.NewString()
+	for _, addr := range addresses {
+		if addr.NodeName != nil && *addr.NodeName != "" {
+			nodeNames.Insert(*addr.NodeName)
+		}
+	}
+	return nodeNames
+}
+
 func PopulateServers(poolNode *AviPoolNode, ns string, serviceName string, ingress bool, key string) []AviPoolMetaServer {
 
 	// Find the servers that match the port.
@@ -466,12 +538,68 @@ func PopulateServers(poolNode *AviPoolNode, ns string, serviceName string, ingre
 	} else {
 		v4Family = true
 	}
+	var addresses, terminatingAddresses []corev1.EndpointAddress
+	if lib.IsEndpointSliceEnabled() {
+		addresses, terminatingAddresses = getEndpointSliceAddresses(poolNode, ns, serviceName, key)
+	} else {
+		addresses = getEndpointsAddresses(poolNode, ns, serviceName, key)
+	}
+	drainingIPs := sets.NewString()
+	if lib.GetGracefulDrainPeriod() > 0 {
+		var terminatingIPs []string
+		for _, addr := range terminatingAddresses {
+			terminatingIPs = append(terminatingIPs, addr.IP)
+		}
+		drainingIPs = getDrainingServers(ns, serviceName, poolNode.Name, terminatingIPs, key)
+		for _, addr := range terminatingAddresses {
+			if drainingIPs.Has(addr.IP) {
+				addresses = append(addresses, addr)
+			}
+		}
+	}
+	var pool_meta []AviPoolMetaServer
+	for _, addr := range addresses {
+		var atype string
+		ip := addr.IP
+		if v4enabled && v4Family && utils.IsV4(addr.IP) {
+			v4ServerCount++
+			atype = "V4"
+		} else if v6enabled && v6Family && k8net.IsIPv6String(addr.IP) {
+			v6ServerCount++
+			atype = "V6"
+		} else {
+			continue
+		}
+		a := avimodels.IPAddr{Type: &atype, Addr: &ip}
+		server := AviPoolMetaServer{Ip: a, Draining: drainingIPs.Has(ip)}
+		if addr.NodeName != nil {
+			server.ServerNode = *addr.NodeName
+		}
+		pool_meta = append(pool_meta, server)
+	}
+	if len(pool_meta) == 0 {
+		utils.AviLog.Warnf("key: %s, msg: no servers for port: %v", key, poolNode.Port)
+	} else {
+		if v4Family && v4ServerCount == 0 {
+			utils.AviLog.Warnf("key: %s, msg: expected IPv4 servers but found none for port %v", key, poolNode.Port)
+		}
+		if v6Family && v6ServerCount == 0 {
+			utils.AviLog.Warnf("key: %s, msg: expected IPv6 servers but found none for port %v", key, poolNode.Port)
+		}
+		utils.AviLog.Infof("key: %s, msg: servers for port: %v , are: %v", key, poolNode.Port, utils.Stringify(pool_meta))
+	}
+	return pool_meta
+}
+
+// getEndpointsAddresses returns the addresses of the legacy Endpoints object of the
+// service, for the subsets that expose the pool's port.
+func getEndpointsAddresses(poolNode *AviPoolNode, ns, serviceName, key string) []corev1.EndpointAddress {
 	epObj, err := utils.GetInformers().EpInformer.Lister().Endpoints(ns).Get(serviceName)
 	if err != nil {
 		utils.AviLog.Warnf("key: %s, msg: error while retrieving endpoints: %s", key, err)
 		return nil
 	}
-	var pool_meta []AviPoolMetaServer
+	var addresses []corev1.EndpointAddress
 	for _, ss := range epObj.Subsets {
 		port_match := false
 		for _, epp := range ss.Ports {
@@ -487,40 +615,171 @@ func PopulateServers(poolNode *AviPoolNode, ns string, serviceName string, ingre
 			poolNode.Port = ss.Ports[0].Port
 		}
 		if port_match {
-			var atype string
 			utils.AviLog.Infof("key: %s, msg: found port match for port %v", key, poolNode.Port)
-			for _, addr := range ss.Addresses {
-				ip := addr.IP
-				if v4enabled && v4Family && utils.IsV4(addr.IP) {
-					v4ServerCount++
-					atype = "V4"
-				} else if v6enabled && v6Family && k8net.IsIPv6String(addr.IP) {
-					v6ServerCount++
-					atype = "V6"
-				} else {
-					continue
-				}
-				a := avimodels.IPAddr{Type: &atype, Addr: &ip}
-				server := AviPoolMetaServer{Ip: a}
-				if addr.NodeName != nil {
-					server.ServerNode = *addr.NodeName
-				}
-				pool_meta = append(pool_meta, server)
+			addresses = append(addresses, ss.Addresses...)
+		}
+	}
+	return addresses
+}
+
+// getEndpointSliceAddresses merges the EndpointSlices of the service and returns the
+// addresses of the endpoints that expose the pool's port. Ready endpoints are preferred,
+// serving but terminating endpoints are used only when no endpoint is ready, and are
+// returned separately otherwise. Topology hints are honoured when AKO is configured
+// with a zone and every endpoint carries a hint.
+func getEndpointSliceAddresses(poolNode *AviPoolNode, ns, serviceName, key string) ([]corev1.EndpointAddress, []corev1.EndpointAddress) {
+	selector := labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: serviceName})
+	epSlices, err := utils.GetInformers().EpSlicesInformer.Lister().EndpointSlices(ns).List(selector)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: error while retrieving endpointslices: %s", key, err)
+		return nil, nil
+	}
+	if len(epSlices) == 0 {
+		utils.AviLog.Warnf("key: %s, msg: no endpointslices found for service %s/%s", key, ns, serviceName)
+		return nil, nil
+	}
+
+	// If the slices expose just a single port then we make that as the server port.
+	slicePorts := sets.NewInt32()
+	for _, epSlice := range epSlices {
+		for _, epp := range epSlice.Ports {
+			if epp.Port != nil {
+				slicePorts.Insert(*epp.Port)
 			}
 		}
 	}
-	if len(pool_meta) == 0 {
-		utils.AviLog.Warnf("key: %s, msg: no servers for port: %v", key, poolNode.Port)
-	} else {
-		if v4Family && v4ServerCount == 0 {
-			utils.AviLog.Warnf("key: %s, msg: expected IPv4 servers but found none for port %v", key, poolNode.Port)
+	singlePort := slicePorts.Len() == 1
+
+	var readyEps, terminatingEps []discoveryv1.Endpoint
+	seen := sets.NewString()
+	for _, epSlice := range epSlices {
+		if epSlice.AddressType == discoveryv1.AddressTypeFQDN {
+			continue
 		}
-		if v6Family && v6ServerCount == 0 {
-			utils.AviLog.Warnf("key: %s, msg: expected IPv6 servers but found none for port %v", key, poolNode.Port)
+		port_match := false
+		for _, epp := range epSlice.Ports {
+			if epp.Port == nil {
+				continue
+			}
+			if (epp.Name != nil && poolNode.PortName == *epp.Name) ||
+				(epp.Name == nil && poolNode.PortName == "") ||
+				int32(poolNode.TargetPort.IntValue()) == *epp.Port || singlePort {
+				port_match = true
+				poolNode.Port = *epp.Port
+				break
+			}
+		}
+		if !port_match {
+			continue
+		}
+		utils.AviLog.Infof("key: %s, msg: found port match for port %v in endpointslice %s", key, poolNode.Port, epSlice.Name)
+		for _, ep := range epSlice.Endpoints {
+			// Consumers may use only the first address, as all addresses of an endpoint are fungible.
+			if len(ep.Addresses) == 0 || seen.Has(ep.Addresses[0]) {
+				continue
+			}
+			ready := ep.Conditions.Ready == nil || *ep.Conditions.Ready
+			serving := ready
+			if ep.Conditions.Serving != nil {
+				serving = *ep.Conditions.Serving
+			}
+			terminating := ep.Conditions.Terminating != nil && *ep.Conditions.Terminating
+			if ready && !terminating {
+				readyEps = append(readyEps, ep)
+			} else if serving && terminating {
+				terminatingEps = append(terminatingEps, ep)
+			} else {
+				continue
+			}
+			seen.Insert(ep.Addresses[0])
 		}
-		utils.AviLog.Infof("key: %s, msg: servers for port: %v , are: %v", key, poolNode.Port, utils.Stringify(pool_meta))
 	}
-	return pool_meta
+
+	endpoints := readyEps
+	if len(endpoints) == 0 && len(terminatingEps) != 0 {
+		utils.AviLog.Infof("key: %s, msg: no ready endpoints, using %d serving terminating endpoints", key, len(terminatingEps))
+		endpoints = terminatingEps
+		terminatingEps = nil
+	}
+	endpoints = filterEndpointsByTopologyHints(endpoints, key)
+
+	return endpointSliceToAddresses(endpoints), endpointSliceToAddresses(terminatingEps)
+}
+
+func endpointSliceToAddresses(endpoints []discoveryv1.Endpoint) []corev1.EndpointAddress {
+	var addresses []corev1.EndpointAddress
+	for _, ep := range endpoints {
+		addresses = append(addresses, corev1.EndpointAddress{IP: ep.Addresses[0], NodeName: ep.NodeName})
+	}
+	// Slices are listed in no particular order, sort to keep the pool servers stable.
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].IP < addresses[j].IP
+	})
+	return addresses
+}
+
+// getDrainingServers returns the terminating servers of the pool that are still within
+// the graceful drain period, and schedules a resync of the service for when the earliest
+// of them expires, so that it is removed from the pool.
+func getDrainingServers(ns, serviceName, poolName string, terminating []string, key string) sets.String {
+	drainPeriod := lib.GetGracefulDrainPeriod()
+	svcKey := ns + "/" + serviceName
+	poolKey := svcKey + "/" + poolName
+	now := time.Now()
+	drainStart := objects.SharedDrainingServerLister().Refresh(poolKey, terminating, now)
+	draining := sets.NewString()
+	var nextExpiry time.Duration
+	for server, start := range drainStart {
+		remaining := drainPeriod - now.Sub(start)
+		if remaining <= 0 {
+			utils.AviLog.Infof("key: %s, msg: drain period expired for server %s of service %s", key, server, svcKey)
+			continue
+		}
+		draining.Insert(server)
+		if nextExpiry == 0 || remaining < nextExpiry {
+			nextExpiry = remaining
+		}
+	}
+	if nextExpiry > 0 {
+		objects.SharedDrainingServerLister().ScheduleResync(poolKey, nextExpiry, func() {
+			resyncKey := utils.Endpoints + "/" + svcKey
+			sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.ObjectIngestionLayer)
+			bkt := utils.Bkt(ns, sharedQueue.NumWorkers)
+			sharedQueue.Workqueue[bkt].AddRateLimited(resyncKey)
+			lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
+			utils.AviLog.Debugf("key: %s, msg: drain period expired, resyncing service", resyncKey)
+		})
+	}
+	if len(draining) != 0 {
+		utils.AviLog.Infof("key: %s, msg: draining servers of pool %s of service %s: %v", key, poolName, svcKey, draining.List())
+	}
+	return draining
+}
+
+// filterEndpointsByTopologyHints keeps the endpoints hinted for the configured zone. Hints
+// are ignored if any endpoint is missing them, or if no endpoint is hinted for the zone.
+func filterEndpointsByTopologyHints(endpoints []discoveryv1.Endpoint, key string) []discoveryv1.Endpoint {
+	zone := lib.GetTopologyZone()
+	if zone == "" || len(endpoints) == 0 {
+		return endpoints
+	}
+	var zonalEps []discoveryv1.Endpoint
+	for _, ep := range endpoints {
+		if ep.Hints == nil || len(ep.Hints.ForZones) == 0 {
+			return endpoints
+		}
+		for _, forZone := range ep.Hints.ForZones {
+			if forZone.Name == zone {
+				zonalEps = append(zonalEps, ep)
+				break
+			}
+		}
+	}
+	if len(zonalEps) == 0 {
+		utils.AviLog.Warnf("key: %s, msg: no endpoints hinted for zone %s, ignoring topology hints", key, zone)
+		return endpoints
+	}
+	return zonalEps
 }
 
 func PopulateServersForMultiClusterIngress(poolNode *AviPoolNode, ns, cluster, serviceNamespace, serviceName string, key string) []AviPoolMetaServer {
@@ -760,10 +1019,63 @@ func buildWithL4Rule(key string, vs *AviVsNode, l4Rule *akov1alpha2.L4Rule) {
 	}
 	vs.AviVsNodeCommonFields.ConvertToRef()
 	vs.AviVsNodeGeneratedFields.ConvertToRef()
+	vs.ServiceMetadata.CRDStatus = lib.CRDMetadata{
+		Type:   lib.L4Rule,
+		Value:  l4Rule.Namespace + "/" + l4Rule.Name,
+		Status: lib.CRDActive,
+	}
 
 	utils.AviLog.Debugf("key: %s, msg: Applied L4Rule %s configuration over VS %s", key, l4Rule.Name, vs.Name)
 }
 
+// addLoadBalancerSourceRanges adds the client CIDRs in the loadBalancerSourceRanges of the Service
+// to the CIDRs allowed on each of its ports.
+func addLoadBalancerSourceRanges(key string, svcObj *corev1.Service, sourceRanges map[int64][]string) {
+	var cidrs []string
+	for _, sourceRange := range svcObj.Spec.LoadBalancerSourceRanges {
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(sourceRange))
+		if err != nil {
+			utils.AviLog.Warnf("key: %s, msg: invalid loadBalancerSourceRange %s in Service %s/%s, err: %v", key, sourceRange, svcObj.Namespace, svcObj.Name, err)
+			continue
+		}
+		cidrs = append(cidrs, ipNet.String())
+	}
+	if len(cidrs) == 0 {
+		return
+	}
+	for _, svcPort := range svcObj.Spec.Ports {
+		port := int64(svcPort.Port)
+		for _, cidr := range cidrs {
+			if !utils.HasElem(sourceRanges[port], cidr) {
+				sourceRanges[port] = append(sourceRanges[port], cidr)
+			}
+		}
+	}
+}
+
+// buildWithLoadBalancerSourceRanges attaches a network security policy owned by AKO to the VS,
+// which allows only the given client CIDRs on the restricted ports. A VS refers to a single network
+// security policy, and the rules of a policy referred by an L4Rule are not known to AKO, so the
+// source ranges take precedence over a networkSecurityPolicyRef set on the VS by an L4Rule. Such an
+// L4Rule is also rejected when it is validated.
+func buildWithLoadBalancerSourceRanges(key string, vs *AviVsNode, sourceRanges map[int64][]string) {
+	if len(sourceRanges) == 0 {
+		return
+	}
+	if vs.NetworkSecurityPolicyRef != nil {
+		utils.AviLog.Warnf("key: %s, msg: VS %s refers to network security policy %s from L4Rule, which is replaced by the policy of the loadBalancerSourceRanges", key, vs.Name, *vs.NetworkSecurityPolicyRef)
+	}
+	nspNode := &AviNetworkSecurityPolicyNode{
+		Name:         vs.Name,
+		Tenant:       vs.Tenant,
+		SourceRanges: sourceRanges,
+		AviMarkers:   vs.AviMarkers,
+	}
+	vs.NetworkSecurityPolicyRefs = []*AviNetworkSecurityPolicyNode{nspNode}
+	vs.NetworkSecurityPolicyRef = proto.String("/api/networksecuritypolicy?name=" + nspNode.Name)
+	utils.AviLog.Infof("key: %s, msg: evaluated network security policy :%v", key, utils.Stringify(nspNode))
+}
+
 func buildPoolWithL4Rule(key string, pool *AviPoolNode, l4Rule *akov1alpha2.L4Rule) {
 
 	if l4Rule == nil {
@@ -790,12 +1102,45 @@ func buildPoolWithL4Rule(key string, pool *AviPoolNode, l4Rule *akov1alpha2.L4Ru
 	utils.AviLog.Debugf("key: %s, msg: Applied L4Rule %s configuration over Pool %s", key, l4Rule.Name, pool.Name)
 }
 
+// buildPoolWithSessionAffinity attaches the client IP persistence profile owned by AKO for the
+// affinity timeout to the pool of a Service with ClientIP session affinity. An
+// applicationPersistenceProfileRef set on the pool by an L4Rule takes precedence.
+func buildPoolWithSessionAffinity(key string, pool *AviPoolNode, svcObj *corev1.Service) {
+	if svcObj.Spec.SessionAffinity != corev1.ServiceAffinityClientIP {
+		return
+	}
+	if pool.ApplicationPersistenceProfileRef != nil {
+		utils.AviLog.Warnf("key: %s, msg: pool %s refers to persistence profile %s from L4Rule, sessionAffinity is not applied", key, pool.Name, *pool.ApplicationPersistenceProfileRef)
+		return
+	}
+	timeoutSeconds := corev1.DefaultClientIPServiceAffinitySeconds
+	if svcObj.Spec.SessionAffinityConfig != nil && svcObj.Spec.SessionAffinityConfig.ClientIP != nil &&
+		svcObj.Spec.SessionAffinityConfig.ClientIP.TimeoutSeconds != nil {
+		timeoutSeconds = *svcObj.Spec.SessionAffinityConfig.ClientIP.TimeoutSeconds
+	}
+	// The persistence timeout is in minutes on the controller and is limited to 12 hours.
+	timeout := (timeoutSeconds + 59) / 60
+	if timeout < lib.MinClientIPPersistenceTimeout {
+		timeout = lib.MinClientIPPersistenceTimeout
+	} else if timeout > lib.MaxClientIPPersistenceTimeout {
+		utils.AviLog.Warnf("key: %s, msg: sessionAffinity timeout %d seconds of Service %s/%s exceeds the maximum, using %d minutes", key, timeoutSeconds, svcObj.Namespace, svcObj.Name, lib.MaxClientIPPersistenceTimeout)
+		timeout = lib.MaxClientIPPersistenceTimeout
+	}
+	// The profile is shared by the pools with the same timeout, hence it carries only the cluster marker.
+	pool.PersistenceProfile = &AviPersistenceProfileNode{
+		Name:    lib.GetClientIPPersistenceProfileName(timeout),
+		Tenant:  pool.Tenant,
+		Timeout: timeout,
+	}
+	utils.AviLog.Debugf("key: %s, msg: evaluated persistence profile for pool %s: %v", key, pool.Name, utils.Stringify(pool.PersistenceProfile))
+}
+
 // In case the VS has services that are a mix of TCP and UDP/SCTP sockets,
 // we create the VS with global network profile TCP Proxy or Fast Path based on license,
 // and override required services with UDP Fast Path or SCTP proxy. Having a separate
 // internally used network profile (MIXED_NET_PROFILE) helps ensure PUT calls
 // on existing VSes.
-func getNetworkProfile(isSCTP, isTCP, isUDP bool) string {
+func GetNetworkProfile(isSCTP, isTCP, isUDP bool) string {
 	if isSCTP && !isTCP && !isUDP {
 		return utils.SYSTEM_SCTP_PROXY
 	}
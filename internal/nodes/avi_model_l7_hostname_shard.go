@@ -25,7 +25,6 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 
 	avimodels "github.com/vmware/alb-sdk/go/models"
-	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
@@ -147,6 +146,7 @@ func (o *AviObjectGraph) BuildPoolPGPolicyForDedicatedVS(vsNode []*AviVsNode, na
 		vsNode[0].HttpPolicyRefs = append(vsNode[0].HttpPolicyRefs, policyNode)
 	}
 
+	builtPools, priorityLabels := sets.NewString(), sets.NewString()
 	utils.AviLog.Infof("key: %s, msg: The pathsvc mapping: %v", key, paths)
 	for _, obj := range paths {
 		isPoolNameLenExceedAviLimit := false
@@ -155,13 +155,7 @@ func (o *AviObjectGraph) BuildPoolPGPolicyForDedicatedVS(vsNode []*AviVsNode, na
 		var pgNode *AviPoolGroupNode
 		httpPGPath := AviHostPathPortPoolPG{Host: pathFQDNs}
 
-		if obj.PathType == networkingv1.PathTypeExact {
-			httpPGPath.MatchCriteria = "EQUALS"
-		} else {
-			// PathTypePrefix and PathTypeImplementationSpecific
-			// default behaviour for AKO set be Prefix match on the path
-			httpPGPath.MatchCriteria = "BEGINS_WITH"
-		}
+		httpPGPath.MatchCriteria = getPathMatchCriteria(obj)
 
 		if obj.Path != "" {
 			httpPGPath.Path = append(httpPGPath.Path, obj.Path)
@@ -169,7 +163,7 @@ func (o *AviObjectGraph) BuildPoolPGPolicyForDedicatedVS(vsNode []*AviVsNode, na
 		} else {
 			priorityLabel = hostname
 		}
-		if isIngr {
+		if isIngr && !obj.alternateBackend {
 			poolName = lib.GetSniPoolName(ingName, namespace, hostname, obj.Path, infraSettingName, vsNode[0].Dedicated)
 		} else {
 			poolName = lib.GetSniPoolName(ingName, namespace, hostname, obj.Path, infraSettingName, vsNode[0].Dedicated, obj.ServiceName)
@@ -206,6 +200,8 @@ func (o *AviObjectGraph) BuildPoolPGPolicyForDedicatedVS(vsNode []*AviVsNode, na
 		var storedHosts []string
 		storedHosts = append(storedHosts, hostname)
 		poolNode := buildPoolNode(key, poolName, ingName, namespace, priorityLabel, hostname, infraSetting, obj.ServiceName, storedHosts, insecureEdgeTermAllow, obj)
+		builtPools.Insert(poolNode.Name)
+		priorityLabels.Insert(poolNode.PriorityLabel)
 		isPoolNameLenExceedAviLimit = false
 		if lib.CheckObjectNameLength(poolNode.Name, lib.Pool) {
 			isPoolNameLenExceedAviLimit = true
@@ -245,6 +241,9 @@ func (o *AviObjectGraph) BuildPoolPGPolicyForDedicatedVS(vsNode []*AviVsNode, na
 		}
 		BuildPoolHTTPRule(hostname, obj.Path, ingName, namespace, infraSettingName, key, vsNode[0], true, vsNode[0].Dedicated)
 	}
+	if isIngr {
+		vsNode[0].PoolRefs = removeStaleIngressPathPools(vsNode[0].PoolRefs, namespace, ingName, priorityLabels, builtPools)
+	}
 	vsNode[0].Paths = pathSet.List()
 	vsNode[0].IngressNames = ingressNameSet.List()
 	utils.AviLog.Infof("key: %s, msg: added pools and poolgroups. NodeChecksum for Insecure Dedicated Vs :%s is :%v", key, vsNode[0].Name, vsNode[0].GetCheckSum())
@@ -278,6 +277,7 @@ func (o *AviObjectGraph) BuildL7VSGraphHostNameShard(vsName, hostname string, ro
 		infraSettingName = infraSetting.Name
 	}
 
+	builtPools, priorityLabels := sets.NewString(), sets.NewString()
 	utils.AviLog.Infof("key: %s, msg: The pathsvc mapping: %v", key, pathsvc)
 	for _, obj := range pathsvc {
 		if obj.Path != "" {
@@ -288,13 +288,15 @@ func (o *AviObjectGraph) BuildL7VSGraphHostNameShard(vsName, hostname string, ro
 
 		// Using servicename in poolname for routes, but not in ingress for consistency with existing naming convention.
 		// If possible, we would make this uniform
-		if routeIgrObj.GetType() == utils.Ingress {
+		if routeIgrObj.GetType() == utils.Ingress && !obj.alternateBackend {
 			poolName = lib.GetL7PoolName(priorityLabel, namespace, ingName, infraSettingName)
 			serviceName = ""
 		} else {
 			poolName = lib.GetL7PoolName(priorityLabel, namespace, ingName, infraSettingName, obj.ServiceName)
 			serviceName = obj.ServiceName
 		}
+		builtPools.Insert(poolName)
+		priorityLabels.Insert(strings.ToLower(priorityLabel))
 
 		// First check if there are pools related to this ingress present in the model already
 		poolNodes := o.GetAviPoolNodesByIngress(namespace, ingName)
@@ -325,6 +327,9 @@ func (o *AviObjectGraph) BuildL7VSGraphHostNameShard(vsName, hostname string, ro
 		}
 
 	}
+	if routeIgrObj.GetType() == utils.Ingress {
+		vsNode[0].PoolRefs = removeStaleIngressPathPools(vsNode[0].PoolRefs, namespace, ingName, priorityLabels, builtPools)
+	}
 	for _, obj := range pathsvc {
 		BuildPoolHTTPRule(hostname, obj.Path, ingName, namespace, infraSettingName, key, vsNode[0], false, vsNode[0].Dedicated)
 	}
@@ -332,6 +337,10 @@ func (o *AviObjectGraph) BuildL7VSGraphHostNameShard(vsName, hostname string, ro
 	// Reset the PG Node members and rebuild them
 	pgNode.Members = nil
 	for _, poolNode := range vsNode[0].PoolRefs {
+		if poolNode.PriorityLabel == "" {
+			// the default backend pool is the default poolgroup of the VS
+			continue
+		}
 		ratio := poolNode.ServiceMetadata.PoolRatio
 		pool_ref := fmt.Sprintf("/api/pool?name=%s", poolNode.Name)
 		pgNode.Members = append(pgNode.Members, &avimodels.PoolGroupMember{PoolRef: &pool_ref, PriorityLabel: &poolNode.PriorityLabel, Ratio: &ratio})
@@ -405,7 +414,6 @@ func (o *AviObjectGraph) DeletePoolForHostname(vsName, hostname string, routeIgr
 	namespace := routeIgrObj.GetNamespace()
 	ingName := routeIgrObj.GetName()
 	vsNode := o.GetAviVS()
-	var poolName string
 
 	keepSni := false
 	if !secure && !vsNode[0].Dedicated {
@@ -422,13 +430,13 @@ func (o *AviObjectGraph) DeletePoolForHostname(vsName, hostname string, routeIgr
 					priorityLabel = hostname
 				}
 				for _, svcName := range services {
+					// The pools of the alternate backends of an ingress path carry the service name.
+					poolNames := []string{lib.GetL7PoolName(priorityLabel, namespace, ingName, infraSettingName, svcName)}
 					if routeIgrObj.GetType() == utils.Ingress {
-						poolName = lib.GetL7PoolName(priorityLabel, namespace, ingName, infraSettingName)
-					} else {
-						poolName = lib.GetL7PoolName(priorityLabel, namespace, ingName, infraSettingName, svcName)
+						poolNames = append(poolNames, lib.GetL7PoolName(priorityLabel, namespace, ingName, infraSettingName))
 					}
-					if poolName == pool.Name {
-						o.RemovePoolNodeRefs(poolName)
+					if utils.HasElem(poolNames, pool.Name) {
+						o.RemovePoolNodeRefs(pool.Name)
 					}
 				}
 			}
@@ -439,6 +447,10 @@ func (o *AviObjectGraph) DeletePoolForHostname(vsName, hostname string, routeIgr
 		if pgNode != nil {
 			pgNode.Members = nil
 			for _, poolNode := range vsNode[0].PoolRefs {
+				if poolNode.PriorityLabel == "" {
+					// the default backend pool is the default poolgroup of the VS
+					continue
+				}
 				ratio := poolNode.ServiceMetadata.PoolRatio
 				pool_ref := fmt.Sprintf("/api/pool?name=%s", poolNode.Name)
 				pgNode.Members = append(pgNode.Members, &avimodels.PoolGroupMember{PoolRef: &pool_ref, PriorityLabel: &poolNode.PriorityLabel, Ratio: &ratio})
@@ -495,6 +507,12 @@ func (o *AviObjectGraph) manipulateVsNode(vsNode *AviVsNode, ingName, namespace,
 			var sniPool string
 			if isIngr {
 				sniPool = lib.GetSniPoolName(ingName, namespace, hostname, path, infraSettingName, vsNode.Dedicated)
+				// The pools of the alternate backends of an ingress path carry the service name.
+				alternatePool := lib.GetSniPoolName(ingName, namespace, hostname, path, infraSettingName, vsNode.Dedicated, svc)
+				o.RemovePoolNodeRefsFromSni(alternatePool, vsNode)
+				if pgNode != nil {
+					o.RemovePoolRefsFromPG(alternatePool, pgNode)
+				}
 			} else {
 				sniPool = lib.GetSniPoolName(ingName, namespace, hostname, path, infraSettingName, vsNode.Dedicated, svc)
 			}
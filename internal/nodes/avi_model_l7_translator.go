@@ -137,6 +137,9 @@ func (o *AviObjectGraph) ConstructAviL7VsNode(vsName string, key string, routeIg
 	if avi_vs_meta.SharedVS && configuredSharedVSFqdn != "" {
 		BuildL7HostRule(configuredSharedVSFqdn, key, avi_vs_meta)
 	}
+	if avi_vs_meta.SharedVS {
+		o.buildDefaultBackendForVs(vsName, key)
+	}
 }
 
 func (o *AviObjectGraph) ConstructShardVsPGNode(vsName string, key string, vsNode *AviVsNode) *AviPoolGroupNode {
@@ -326,6 +329,7 @@ func (o *AviObjectGraph) BuildPolicyPGPoolsForSNI(vsNode []*AviVsNode, tlsNode *
 	var priorityLabel string
 	var policyNode *AviHttpPolicySetNode
 	pathSet := sets.NewString(tlsNode.Paths...)
+	builtPools, priorityLabels := sets.NewString(), sets.NewString()
 
 	var infraSettingName string
 	if infraSetting != nil {
@@ -379,13 +383,7 @@ func (o *AviObjectGraph) BuildPolicyPGPoolsForSNI(vsNode []*AviVsNode, tlsNode *
 			isPGNameLenExceedAviLimit := false
 			httpPGPath := AviHostPathPortPoolPG{Host: pathFQDNs}
 
-			if path.PathType == networkingv1.PathTypeExact {
-				httpPGPath.MatchCriteria = "EQUALS"
-			} else {
-				// PathTypePrefix and PathTypeImplementationSpecific
-				// default behaviour for AKO set be Prefix match on the path
-				httpPGPath.MatchCriteria = "BEGINS_WITH"
-			}
+			httpPGPath.MatchCriteria = getPathMatchCriteria(path)
 
 			if path.Path != "" {
 				httpPGPath.Path = append(httpPGPath.Path, path.Path)
@@ -397,7 +395,7 @@ func (o *AviObjectGraph) BuildPolicyPGPoolsForSNI(vsNode []*AviVsNode, tlsNode *
 			var pgfound bool
 			var pgNode *AviPoolGroupNode
 			// Do not use serviceName in SNI Pool Name for ingress for backward compatibility
-			if isIngr {
+			if isIngr && !path.alternateBackend {
 				poolName = lib.GetSniPoolName(ingName, namespace, host, path.Path, infraSettingName, vsNode[0].Dedicated)
 			} else {
 				poolName = lib.GetSniPoolName(ingName, namespace, host, path.Path, infraSettingName, vsNode[0].Dedicated, path.ServiceName)
@@ -453,6 +451,8 @@ func (o *AviObjectGraph) BuildPolicyPGPoolsForSNI(vsNode []*AviVsNode, tlsNode *
 				VrfContext: lib.GetVrf(),
 			}
 
+			builtPools.Insert(poolNode.Name)
+			priorityLabels.Insert(poolNode.PriorityLabel)
 			poolNode.NetworkPlacementSettings = lib.GetNodeNetworkMap()
 
 			t1lr := lib.GetT1LRPath()
@@ -536,6 +536,9 @@ func (o *AviObjectGraph) BuildPolicyPGPoolsForSNI(vsNode []*AviVsNode, tlsNode *
 		}
 		sniFQDNs = append(sniFQDNs, pathFQDNs...)
 	}
+	if isIngr {
+		tlsNode.PoolRefs = removeStaleIngressPathPools(tlsNode.PoolRefs, namespace, ingName, priorityLabels, builtPools)
+	}
 	tlsNode.Paths = pathSet.List()
 	tlsNode.IngressNames = ingressNameSet.List()
 
@@ -737,6 +740,7 @@ func buildWithInfraSetting(key, namespace string, vs *AviVsNode, vsvip *AviVSVIP
 		if infraSetting.Spec.NSXSettings.T1LR != nil {
 			vsvip.T1Lr = *infraSetting.Spec.NSXSettings.T1LR
 		}
+		vs.ServiceMetadata.AviInfraSetting = infraSetting.Name
 		utils.AviLog.Debugf("key: %s, msg: Applied AviInfraSetting configuration over VSNode %s", key, vs.Name)
 	}
 }
@@ -784,3 +788,16 @@ func buildListenerPortsWithInfraSetting(infraSetting *akov1beta1.AviInfraSetting
 	}
 	return aviPortProto
 }
+
+// getPathMatchCriteria returns the match criteria of the path in the httppolicyset rules.
+func getPathMatchCriteria(path IngressHostPathSvc) string {
+	if path.PathType == networkingv1.PathTypeExact {
+		return "EQUALS"
+	}
+	if path.RegexPath {
+		return "REGEX_MATCH"
+	}
+	// PathTypePrefix and PathTypeImplementationSpecific
+	// default behaviour for AKO set be Prefix match on the path
+	return "BEGINS_WITH"
+}
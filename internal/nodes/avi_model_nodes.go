@@ -181,6 +181,10 @@ func (v *AviVsNode) CalculateForGraphChecksum() uint32 {
 		checksumStringSlice = append(checksumStringSlice, fmt.Sprint(l4pol.GetCheckSum()))
 	}
 
+	for _, nsp := range v.NetworkSecurityPolicyRefs {
+		checksumStringSlice = append(checksumStringSlice, fmt.Sprint(nsp.GetCheckSum()))
+	}
+
 	return utils.Hash(strings.Join(checksumStringSlice, ":"))
 }
 
@@ -374,45 +378,46 @@ func (o *AviObjectGraph) GetIstioNodes() (*AviPkiProfileNode, *AviTLSKeyCertNode
 }
 
 type AviVsNode struct {
-	Name                  string
-	Tenant                string
-	ServiceEngineGroup    string
-	ApplicationProfile    string
-	NetworkProfile        string
-	Enabled               *bool
-	EnableRhi             *bool
-	PortProto             []AviPortHostProtocol // for listeners
-	DefaultPool           string
-	CloudConfigCksum      uint32
-	DefaultPoolGroup      string
-	HTTPChecksum          uint32
-	SNIParent             bool
-	PoolGroupRefs         []*AviPoolGroupNode
-	PoolRefs              []*AviPoolNode
-	HTTPDSrefs            []*AviHTTPDataScriptNode
-	SniNodes              []*AviVsNode
-	PassthroughChildNodes []*AviVsNode
-	SharedVS              bool
-	CACertRefs            []*AviTLSKeyCertNode
-	SSLKeyCertRefs        []*AviTLSKeyCertNode
-	HttpPolicyRefs        []*AviHttpPolicySetNode
-	VSVIPRefs             []*AviVSVIPNode
-	L4PolicyRefs          []*AviL4PolicyNode
-	VHParentName          string
-	VHDomainNames         []string
-	TLSType               string
-	IsSNIChild            bool
-	ServiceMetadata       lib.ServiceMetadataObj
-	VrfContext            string
-	ICAPProfileRefs       []string
-	ErrorPageProfileRef   string
-	HttpPolicySetRefs     []string
-	AviMarkers            utils.AviObjectMarkers
-	Paths                 []string
-	IngressNames          []string
-	Dedicated             bool
-	IsL4VS                bool
-	Secure                bool
+	Name                      string
+	Tenant                    string
+	ServiceEngineGroup        string
+	ApplicationProfile        string
+	NetworkProfile            string
+	Enabled                   *bool
+	EnableRhi                 *bool
+	PortProto                 []AviPortHostProtocol // for listeners
+	DefaultPool               string
+	CloudConfigCksum          uint32
+	DefaultPoolGroup          string
+	HTTPChecksum              uint32
+	SNIParent                 bool
+	PoolGroupRefs             []*AviPoolGroupNode
+	PoolRefs                  []*AviPoolNode
+	HTTPDSrefs                []*AviHTTPDataScriptNode
+	SniNodes                  []*AviVsNode
+	PassthroughChildNodes     []*AviVsNode
+	SharedVS                  bool
+	CACertRefs                []*AviTLSKeyCertNode
+	SSLKeyCertRefs            []*AviTLSKeyCertNode
+	HttpPolicyRefs            []*AviHttpPolicySetNode
+	VSVIPRefs                 []*AviVSVIPNode
+	L4PolicyRefs              []*AviL4PolicyNode
+	NetworkSecurityPolicyRefs []*AviNetworkSecurityPolicyNode
+	VHParentName              string
+	VHDomainNames             []string
+	TLSType                   string
+	IsSNIChild                bool
+	ServiceMetadata           lib.ServiceMetadataObj
+	VrfContext                string
+	ICAPProfileRefs           []string
+	ErrorPageProfileRef       string
+	HttpPolicySetRefs         []string
+	AviMarkers                utils.AviObjectMarkers
+	Paths                     []string
+	IngressNames              []string
+	Dedicated                 bool
+	IsL4VS                    bool
+	Secure                    bool
 
 	AviVsNodeCommonFields
 
@@ -1045,6 +1050,10 @@ func (v *AviVsNode) CalculateCheckSum() {
 		checksum += lib.GetAnalyticsPolicyChecksum(v.AnalyticsPolicy)
 	}
 
+	if v.DefaultPoolGroup != "" {
+		checksum += utils.Hash(v.DefaultPoolGroup)
+	}
+
 	checksum += v.AviVsNodeGeneratedFields.CalculateCheckSumOfGeneratedCode()
 
 	v.CloudConfigCksum = checksum
@@ -1184,6 +1193,133 @@ func (v *AviHttpPolicySetNode) CopyNode() AviModelNode {
 	return &newNode
 }
 
+// GetRegexStringGroups returns the string groups holding the regular expression paths of the
+// policyset. The rules matching those paths refer to the string groups.
+func (v *AviHttpPolicySetNode) GetRegexStringGroups() []*AviStringGroupNode {
+	var stringGroups []*AviStringGroupNode
+	for _, hpp := range v.HppMap {
+		if hpp.MatchCriteria != "REGEX_MATCH" || len(hpp.Path) == 0 {
+			continue
+		}
+		regex := make([]string, len(hpp.Path))
+		copy(regex, hpp.Path)
+		sort.Strings(regex)
+		stringGroups = append(stringGroups, &AviStringGroupNode{
+			Name:   lib.GetRegexStringGroupName(v.Name, strings.Join(regex, ",")),
+			Tenant: v.Tenant,
+			Regex:  regex,
+		})
+	}
+	return stringGroups
+}
+
+type AviStringGroupNode struct {
+	Name             string
+	Tenant           string
+	CloudConfigCksum uint32
+	// Regex is the list of regular expressions held by the string group.
+	Regex      []string
+	AviMarkers utils.AviObjectMarkers
+}
+
+func (v *AviStringGroupNode) GetNodeType() string {
+	return "StringGroupNode"
+}
+
+func (v *AviStringGroupNode) CopyNode() AviModelNode {
+	newNode := AviStringGroupNode{}
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to marshal AviStringGroupNode: %s", err)
+	}
+	err = json.Unmarshal(bytes, &newNode)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to unmarshal AviStringGroupNode: %s", err)
+	}
+	return &newNode
+}
+
+func (v *AviStringGroupNode) GetCheckSum() uint32 {
+	// Calculate checksum and return
+	v.CalculateCheckSum()
+	return v.CloudConfigCksum
+}
+
+func (v *AviStringGroupNode) CalculateCheckSum() {
+	v.CloudConfigCksum = lib.StringGroupChecksum(v.Regex, v.AviMarkers, nil, false)
+}
+
+type AviTrafficCloneProfileNode struct {
+	Name             string
+	Tenant           string
+	CloudConfigCksum uint32
+	CloneServers     []string
+	AviMarkers       utils.AviObjectMarkers
+}
+
+func (v *AviTrafficCloneProfileNode) GetCheckSum() uint32 {
+	// Calculate checksum and return
+	v.CalculateCheckSum()
+	return v.CloudConfigCksum
+}
+
+func (v *AviTrafficCloneProfileNode) CalculateCheckSum() {
+	v.CloudConfigCksum = lib.TrafficCloneProfileChecksum(v.CloneServers, v.AviMarkers, nil, false)
+}
+
+func (v *AviTrafficCloneProfileNode) GetNodeType() string {
+	return "TrafficCloneProfileNode"
+}
+
+func (v *AviTrafficCloneProfileNode) CopyNode() AviModelNode {
+	newNode := AviTrafficCloneProfileNode{}
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to marshal AviTrafficCloneProfileNode: %s", err)
+	}
+	err = json.Unmarshal(bytes, &newNode)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to unmarshal AviTrafficCloneProfileNode: %s", err)
+	}
+	return &newNode
+}
+
+type AviNetworkSecurityPolicyNode struct {
+	Name             string
+	Tenant           string
+	CloudConfigCksum uint32
+	// SourceRanges are the client CIDRs allowed on each restricted VS port.
+	SourceRanges map[int64][]string
+	AviMarkers   utils.AviObjectMarkers
+}
+
+func (v *AviNetworkSecurityPolicyNode) GetCheckSum() uint32 {
+	// Calculate checksum and return
+	v.CalculateCheckSum()
+	return v.CloudConfigCksum
+}
+
+func (v *AviNetworkSecurityPolicyNode) CalculateCheckSum() {
+	v.CloudConfigCksum = lib.NetworkSecurityPolicyChecksum(v.SourceRanges, v.AviMarkers, nil, false)
+}
+
+func (v *AviNetworkSecurityPolicyNode) GetNodeType() string {
+	return "NetworkSecurityPolicyNode"
+}
+
+func (v *AviNetworkSecurityPolicyNode) CopyNode() AviModelNode {
+	newNode := AviNetworkSecurityPolicyNode{}
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to marshal AviNetworkSecurityPolicyNode: %s", err)
+	}
+	err = json.Unmarshal(bytes, &newNode)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to unmarshal AviNetworkSecurityPolicyNode: %s", err)
+	}
+	return &newNode
+}
+
 type AviHostPathPortPoolPG struct {
 	Name          string
 	Checksum      uint32
@@ -1521,6 +1657,42 @@ func (v *AviPkiProfileNode) CalculateCheckSum() {
 	v.CloudConfigCksum = checksum
 }
 
+type AviPersistenceProfileNode struct {
+	Name             string
+	Tenant           string
+	CloudConfigCksum uint32
+	// Timeout is the client IP persistence timeout in minutes.
+	Timeout    int32
+	AviMarkers utils.AviObjectMarkers
+}
+
+func (v *AviPersistenceProfileNode) GetNodeType() string {
+	return "PersistenceProfileNode"
+}
+
+func (v *AviPersistenceProfileNode) CopyNode() AviModelNode {
+	newNode := AviPersistenceProfileNode{}
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to marshal AviPersistenceProfileNode: %s", err)
+	}
+	err = json.Unmarshal(bytes, &newNode)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to unmarshal AviPersistenceProfileNode: %s", err)
+	}
+	return &newNode
+}
+
+func (v *AviPersistenceProfileNode) GetCheckSum() uint32 {
+	// Calculate checksum and return
+	v.CalculateCheckSum()
+	return v.CloudConfigCksum
+}
+
+func (v *AviPersistenceProfileNode) CalculateCheckSum() {
+	v.CloudConfigCksum = lib.PersistenceProfileChecksum(v.Timeout, v.AviMarkers, nil, false)
+}
+
 type AviPoolNode struct {
 	Name                     string
 	Tenant                   string
@@ -1535,11 +1707,13 @@ type AviPoolNode struct {
 	ServiceMetadata          lib.ServiceMetadataObj
 	SniEnabled               bool
 	PkiProfile               *AviPkiProfileNode
+	PersistenceProfile       *AviPersistenceProfileNode
 	NetworkPlacementSettings map[string]lib.NodeNetworkMap
 	VrfContext               string
 	T1Lr                     string // Only applicable to NSX-T cloud, if this value is set, we automatically should unset the VRF context value.
 	AviMarkers               utils.AviObjectMarkers
 	AttachedWithSharedVS     bool
+	EnableHttp2              bool
 
 	AviPoolCommonFields
 
@@ -1553,14 +1727,33 @@ type AviPoolNode struct {
 // This struct is added to avoid any collision between the generated and
 // existing struct fields.
 type AviPoolCommonFields struct {
-	ApplicationPersistenceProfileRef *string
-	HealthMonitorRefs                []string
-	LbAlgorithm                      *string
-	LbAlgorithmHash                  *string
-	LbAlgorithmConsistentHashHdr     *string
-	PkiProfileRef                    *string
-	SslProfileRef                    *string
-	SslKeyAndCertificateRef          *string
+	ApplicationPersistenceProfileRef  *string
+	HealthMonitorRefs                 []string
+	LbAlgorithm                       *string
+	LbAlgorithmHash                   *string
+	LbAlgorithmConsistentHashHdr      *string
+	PkiProfileRef                     *string
+	SslProfileRef                     *string
+	SslKeyAndCertificateRef           *string
+	ServerTimeout                     *uint32
+	MaxConcurrentConnectionsPerServer *int32
+	ConnectionRampDuration            *int32
+	GracefulDisableTimeout            *int32
+	RequestQueueEnabled               *bool
+	RequestQueueDepth                 *uint32
+}
+
+// GetGracefulDisableTimeout returns the graceful_disable_timeout of the pool, the one set by an
+// HTTPRule, or else the one covering the graceful drain period, when draining is enabled.
+func (v *AviPoolNode) GetGracefulDisableTimeout() *int32 {
+	if v.GracefulDisableTimeout != nil {
+		return v.GracefulDisableTimeout
+	}
+	if lib.GetGracefulDrainPeriod() > 0 {
+		gracefulDisableTimeout := lib.GetPoolGracefulDisableTimeout()
+		return &gracefulDisableTimeout
+	}
+	return nil
 }
 
 func (v *AviPoolNode) GetCheckSum() uint32 {
@@ -1639,12 +1832,39 @@ func (v *AviPoolNode) CalculateCheckSum() {
 		checksum += utils.Hash(*v.ApplicationPersistenceProfileRef)
 	}
 
+	if v.PersistenceProfile != nil {
+		checksum += utils.Hash(v.PersistenceProfile.Name) + v.PersistenceProfile.GetCheckSum()
+	}
+
+	if v.ServerTimeout != nil {
+		checksum += utils.Hash("serverTimeout" + utils.Stringify(*v.ServerTimeout))
+	}
+	if v.MaxConcurrentConnectionsPerServer != nil {
+		checksum += utils.Hash("maxConcurrentConnectionsPerServer" + utils.Stringify(*v.MaxConcurrentConnectionsPerServer))
+	}
+	if v.ConnectionRampDuration != nil {
+		checksum += utils.Hash("connectionRampDuration" + utils.Stringify(*v.ConnectionRampDuration))
+	}
+	if gracefulDisableTimeout := v.GetGracefulDisableTimeout(); gracefulDisableTimeout != nil {
+		checksum += utils.Hash("gracefulDisableTimeout" + utils.Stringify(*gracefulDisableTimeout))
+	}
+	if v.RequestQueueEnabled != nil {
+		checksum += utils.Hash("requestQueueEnabled" + utils.Stringify(*v.RequestQueueEnabled))
+	}
+	if v.RequestQueueDepth != nil {
+		checksum += utils.Hash("requestQueueDepth" + utils.Stringify(*v.RequestQueueDepth))
+	}
+
 	checksum += lib.GetMarkersChecksum(v.AviMarkers)
 
 	if v.T1Lr != "" {
 		checksum += utils.Hash(v.T1Lr)
 	}
 
+	if v.EnableHttp2 {
+		checksum += utils.Hash(utils.Stringify(v.EnableHttp2))
+	}
+
 	checksum += v.AviPoolGeneratedFields.CalculateCheckSumOfGeneratedCode()
 
 	v.CloudConfigCksum = checksum
@@ -1704,18 +1924,25 @@ type AviPoolMetaServer struct {
 	Ip         avimodels.IPAddr
 	ServerNode string
 	Port       int32
+	// Draining servers are terminating endpoints, kept disabled in the pool
+	// until the graceful drain period expires.
+	Draining bool `json:",omitempty"`
 }
 
 type IngressHostPathSvc struct {
 	ServiceName    string
 	Path           string
 	PathType       networkingv1.PathType
+	RegexPath      bool // ImplementationSpecific path to be matched as a regular expression
 	Port           int32
 	weight         uint32 //required for alternate backends in openshift route
 	PortName       string
 	TargetPort     intstr.IntOrString
 	clusterContext string // required for Multi-cluster ingress
 	svcNamespace   string // required for Multi-cluster ingress
+	// alternateBackend is set for the alternate backends of an Ingress path set by an HTTPRule,
+	// the pool names of which carry the service name.
+	alternateBackend bool
 }
 
 type IngressHostMap map[string]HostMetadata
@@ -1751,6 +1978,8 @@ type IngressConfig struct {
 	TlsCollection         []TlsSettings
 	IngressHostMap
 	InsecureEdgeTermAllow bool
+	// DefaultBackend is the backend of the requests which match none of the rules.
+	DefaultBackend *IngressHostPathSvc
 }
 
 type SecureHostNameMapProp struct {
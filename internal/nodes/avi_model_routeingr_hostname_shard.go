@@ -81,6 +81,13 @@ func HostNameShardAndPublish(objType, objname, namespace, key string, fullsync b
 			} else {
 				RouteIngrDeletePoolsByHostname(routeIgrObj, namespace, objname, key, fullsync, sharedQueue)
 			}
+			var modelList []string
+			ProcessDefaultBackend(routeIgrObj, key, nil, &modelList)
+			if !fullsync {
+				for _, modelName := range modelList {
+					PublishKeyToRestLayer(modelName, key, sharedQueue)
+				}
+			}
 		}
 		return
 	}
@@ -110,6 +117,7 @@ func HostNameShardAndPublish(objType, objname, namespace, key string, fullsync b
 		updateHostPathCache(namespace, objname, oldHostMap, hostsMap)
 
 		routeIgrObj.GetSvcLister().IngressMappings(namespace).UpdateRouteIngToHostMapping(objname, hostsMap)
+		ProcessDefaultBackend(routeIgrObj, key, parsedIng.DefaultBackend, &modelList)
 		// publish to rest layer
 		if !fullsync {
 			utils.AviLog.Infof("key: %s, msg: List of models to publish: %s", key, modelList)
@@ -137,6 +145,8 @@ func HostNameShardAndPublish(objType, objname, namespace, key string, fullsync b
 
 	routeIgrObj.GetSvcLister().IngressMappings(namespace).UpdateRouteIngToHostMapping(objname, hostsMap)
 
+	ProcessDefaultBackend(routeIgrObj, key, parsedIng.DefaultBackend, &modelList)
+
 	if !fullsync {
 		utils.AviLog.Infof("key: %s, msg: List of models to publish: %s", key, modelList)
 		for _, modelName := range modelList {
@@ -65,6 +65,7 @@ func BuildL7HostRule(host, key string, vsNode AviVsEvhSniModel) {
 	var vsEnabled *bool
 	var crdStatus lib.CRDMetadata
 	var vsICAPProfile []string
+	var l7RuleNSName string
 
 	// Initializing the values of vsHTTPPolicySets and vsDatascripts, using a nil value would impact the value of VS checksum
 	vsHTTPPolicySets := []string{}
@@ -192,7 +193,7 @@ func BuildL7HostRule(host, key string, vsNode AviVsEvhSniModel) {
 		}
 		if lib.IsEvhEnabled() {
 			if hostrule.Spec.VirtualHost.L7Rule != "" {
-				BuildL7Rule(host, key, hostrule.Spec.VirtualHost.L7Rule, hrNSName[0], vsNode)
+				l7RuleNSName = BuildL7Rule(host, key, hostrule.Spec.VirtualHost.L7Rule, hrNSName[0], vsNode)
 			} else {
 				vsNode.GetGeneratedFields().ConvertL7RuleFieldsToNil()
 			}
@@ -230,6 +231,7 @@ func BuildL7HostRule(host, key string, vsNode AviVsEvhSniModel) {
 
 	serviceMetadataObj := vsNode.GetServiceMetadata()
 	serviceMetadataObj.CRDStatus = crdStatus
+	serviceMetadataObj.L7Rule = l7RuleNSName
 	vsNode.SetServiceMetadata(serviceMetadataObj)
 
 }
@@ -356,6 +358,12 @@ func BuildPoolHTTPRule(host, poolPath, ingName, namespace, infraSettingName, key
 				pool.PkiProfile = destinationCertNode
 				pool.HealthMonitorRefs = pathHMs
 				pool.ApplicationPersistenceProfileRef = persistenceProfile
+				pool.ServerTimeout = httpRulePath.PoolSettings.ServerTimeout
+				pool.MaxConcurrentConnectionsPerServer = httpRulePath.PoolSettings.MaxConcurrentConnectionsPerServer
+				pool.ConnectionRampDuration = httpRulePath.PoolSettings.ConnectionRampDuration
+				pool.GracefulDisableTimeout = httpRulePath.PoolSettings.GracefulDisableTimeout
+				pool.RequestQueueEnabled = httpRulePath.PoolSettings.RequestQueueEnabled
+				pool.RequestQueueDepth = httpRulePath.PoolSettings.RequestQueueDepth
 
 				// from this path, generate refs to this pool node
 				if httpRulePath.LoadBalancerPolicy.Algorithm != "" {
@@ -489,7 +497,9 @@ func BuildL7SSORule(host, key string, vsNode AviVsEvhSniModel) {
 	vsNode.SetServiceMetadata(serviceMetadataObj)
 }
 
-func BuildL7Rule(host, key, l7RuleName, namespace string, vsNode AviVsEvhSniModel) {
+// BuildL7Rule applies the L7Rule on the vsNode, and returns the namespace/name of the
+// L7Rule which is attached to the vsNode.
+func BuildL7Rule(host, key, l7RuleName, namespace string, vsNode AviVsEvhSniModel) string {
 	deleteL7RuleCase := false
 	l7Rule, err := lib.AKOControlConfig().CRDInformers().L7RuleInformer.Lister().L7Rules(namespace).Get(l7RuleName)
 	if err != nil {
@@ -497,7 +507,7 @@ func BuildL7Rule(host, key, l7RuleName, namespace string, vsNode AviVsEvhSniMode
 		deleteL7RuleCase = true
 	} else if l7Rule.Status.Status == lib.StatusRejected {
 		// do not apply a rejected L7Rule, this way the VS would retain
-		return
+		return vsNode.GetServiceMetadata().L7Rule
 	}
 	generatedFields := vsNode.GetGeneratedFields()
 	if !deleteL7RuleCase {
@@ -510,7 +520,8 @@ func BuildL7Rule(host, key, l7RuleName, namespace string, vsNode AviVsEvhSniMode
 		}
 		utils.AviLog.Infof("key: %s, Successfully attached L7Rule %s on vsNode %s", key, l7RuleName, vsNode.GetName())
 		generatedFields.ConvertToRef()
-	} else {
-		generatedFields.ConvertL7RuleFieldsToNil()
+		return namespace + "/" + l7RuleName
 	}
+	generatedFields.ConvertL7RuleFieldsToNil()
+	return ""
 }
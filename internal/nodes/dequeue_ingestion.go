@@ -728,6 +728,7 @@ func handleL4Service(key string, fullsync bool) {
 	objects.SharedAviGraphLister().Save(model_name, nil)
 	if !fullsync {
 		bkt := utils.Bkt(model_name, sharedQueue.NumWorkers)
+		sharedQueue.ResetRetries(model_name)
 		sharedQueue.Workqueue[bkt].AddRateLimited(model_name)
 	}
 }
@@ -841,6 +842,10 @@ func processNodeObj(key, nodename string, sharedQueue *utils.WorkerQueue, fullsy
 
 func PublishKeyToRestLayer(modelName string, key string, sharedQueue *utils.WorkerQueue) {
 	bkt := utils.Bkt(modelName, sharedQueue.NumWorkers)
+	lib.RecordModelSyncStart(modelName, key)
+	// The model is published for a change to a related object, so that the model is synced
+	// again even if it was dead-lettered after failing in all the attempts.
+	sharedQueue.ResetRetries(modelName)
 	sharedQueue.Workqueue[bkt].AddRateLimited(modelName)
 	lib.IncrementQueueCounter(utils.GraphLayer)
 	utils.AviLog.Infof("key: %s, msg: Published key with modelName: %s", key, modelName)
@@ -0,0 +1,163 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package nodes
+
+import (
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
+	akov1beta1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1beta1"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+// findHTTPRuleForIngressPath returns the accepted HTTPRule in the namespace of an Ingress whose
+// target is the path of the Ingress host, along with the settings of that target.
+func findHTTPRuleForIngressPath(key, namespace, host, path string) (*akov1beta1.HTTPRule, *akov1beta1.HTTPRulePaths) {
+	found, pathRules := objects.SharedCRDLister().GetFqdnHTTPRulesMapping(host)
+	if !found {
+		return nil, nil
+	}
+	rule, ok := pathRules[path]
+	if !ok {
+		return nil, nil
+	}
+	ruleNSName := strings.Split(rule, "/")
+	if len(ruleNSName) != 2 || ruleNSName[0] != namespace {
+		return nil, nil
+	}
+	httpRule, err := lib.AKOControlConfig().CRDInformers().HTTPRuleInformer.Lister().HTTPRules(ruleNSName[0]).Get(ruleNSName[1])
+	if err != nil {
+		utils.AviLog.Debugf("key: %s, msg: httprule not found err: %+v", key, err)
+		return nil, nil
+	} else if httpRule.Status.Status == lib.StatusRejected {
+		return nil, nil
+	}
+	for i := range httpRule.Spec.Paths {
+		if httpRule.Spec.Paths[i].Target == path {
+			return httpRule, &httpRule.Spec.Paths[i]
+		}
+	}
+	return nil, nil
+}
+
+// alternateBackendsForIngressPath returns the alternate backends set by an HTTPRule for the path
+// of an Ingress host, and sets the weight of the backend of the path to the share of the traffic
+// left to it. The alternate backends whose service is not found are skipped, and their share of
+// the traffic stays with the backend of the path. The effective split is reported in the status
+// of the HTTPRule.
+func (v *Validator) alternateBackendsForIngressPath(key, namespace, ingName, host string, pathSvc *IngressHostPathSvc) []IngressHostPathSvc {
+	if lib.AKOControlConfig().CRDInformers() == nil || lib.AKOControlConfig().CRDInformers().HTTPRuleInformer == nil {
+		return nil
+	}
+	httpRule, httpRulePath := findHTTPRuleForIngressPath(key, namespace, host, pathSvc.Path)
+	if httpRulePath == nil || len(httpRulePath.AlternateBackends) == 0 {
+		return nil
+	}
+	if lib.GetNoPGForSNI() {
+		utils.AviLog.Warnf("key: %s, msg: alternate backends of httprule %s/%s are not supported without poolgroups for SNI VSes", key, httpRule.Namespace, httpRule.Name)
+		return nil
+	}
+
+	var alternates []IngressHostPathSvc
+	split := akov1beta1.HTTPRuleTrafficSplit{
+		Target:  httpRulePath.Target,
+		Ingress: namespace + "/" + ingName,
+	}
+	weight := pathSvc.weight
+	for _, backend := range httpRulePath.AlternateBackends {
+		if backend.ServiceName == pathSvc.ServiceName {
+			utils.AviLog.Warnf("key: %s, msg: skipping alternate backend %s of path %s, it is the backend of the path", key, backend.ServiceName, pathSvc.Path)
+			continue
+		}
+		if _, err := utils.GetInformers().ServiceInformer.Lister().Services(namespace).Get(backend.ServiceName); err != nil {
+			utils.AviLog.Warnf("key: %s, msg: skipping alternate backend %s of path %s, service not found: %v", key, backend.ServiceName, pathSvc.Path, err)
+			continue
+		}
+		if backend.Weight > weight {
+			// The weights are validated to add up to at most 100.
+			continue
+		}
+		port := pathSvc.Port
+		if backend.ServicePort != 0 {
+			port = backend.ServicePort
+		}
+		alternate := IngressHostPathSvc{
+			ServiceName:      backend.ServiceName,
+			Path:             pathSvc.Path,
+			PathType:         pathSvc.PathType,
+			RegexPath:        pathSvc.RegexPath,
+			Port:             port,
+			PortName:         v.findPortName(backend.ServiceName, namespace, port, key),
+			TargetPort:       v.findTargetPort(backend.ServiceName, namespace, &networkingv1.ServiceBackendPort{Number: port}, key),
+			weight:           backend.Weight,
+			alternateBackend: true,
+		}
+		weight -= backend.Weight
+		alternates = append(alternates, alternate)
+		split.Backends = append(split.Backends, akov1beta1.HTTPRuleBackendWeight{ServiceName: backend.ServiceName, Weight: backend.Weight})
+	}
+	pathSvc.weight = weight
+	split.Backends = append([]akov1beta1.HTTPRuleBackendWeight{{ServiceName: pathSvc.ServiceName, Weight: weight}}, split.Backends...)
+	utils.AviLog.Infof("key: %s, msg: traffic split for path %s of host %s: %s", key, pathSvc.Path, host, utils.Stringify(split.Backends))
+	status.UpdateHTTPRuleTrafficSplit(key, httpRule, split)
+	return alternates
+}
+
+// alternateServicesForIngress returns the services of the alternate backends set by HTTPRules for
+// the paths of an Ingress.
+func alternateServicesForIngress(key, namespace string, ingSpec networkingv1.IngressSpec) []string {
+	if lib.AKOControlConfig().CRDInformers() == nil || lib.AKOControlConfig().CRDInformers().HTTPRuleInformer == nil {
+		return nil
+	}
+	var services []string
+	for _, rule := range ingSpec.Rules {
+		if rule.IngressRuleValue.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.IngressRuleValue.HTTP.Paths {
+			_, httpRulePath := findHTTPRuleForIngressPath(key, namespace, rule.Host, path.Path)
+			if httpRulePath == nil {
+				continue
+			}
+			for _, backend := range httpRulePath.AlternateBackends {
+				if !utils.HasElem(services, backend.ServiceName) {
+					services = append(services, backend.ServiceName)
+				}
+			}
+		}
+	}
+	return services
+}
+
+// removeStaleIngressPathPools removes the pools of the paths of an Ingress which are not among
+// the pools just built for them, i.e. the pools of the alternate backends which are removed from
+// an HTTPRule while the path stays in the Ingress.
+func removeStaleIngressPathPools(pools []*AviPoolNode, namespace, ingName string, priorityLabels, builtPools sets.String) []*AviPoolNode {
+	var result []*AviPoolNode
+	for _, pool := range pools {
+		if pool.IngressName == ingName && pool.ServiceMetadata.Namespace == namespace &&
+			priorityLabels.Has(pool.PriorityLabel) && !builtPools.Has(pool.Name) {
+			utils.AviLog.Infof("msg: removing stale pool %s of ingress %s/%s", pool.Name, namespace, ingName)
+			continue
+		}
+		result = append(result, pool)
+	}
+	return result
+}
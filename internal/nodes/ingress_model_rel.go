@@ -381,6 +381,11 @@ func IngressChanges(ingName string, namespace string, key string) ([]string, boo
 
 		_, oldSvcs := objects.SharedSvcLister().IngressMappings(namespace).GetIngToSvc(ingName)
 		currSvcs := parseServicesForIngress(ingObj.Spec, key)
+		for _, svc := range alternateServicesForIngress(key, namespace, ingObj.Spec) {
+			if !utils.HasElem(currSvcs, svc) {
+				currSvcs = append(currSvcs, svc)
+			}
+		}
 
 		svcToDel := lib.Difference(oldSvcs, currSvcs)
 		for _, svc := range svcToDel {
@@ -814,6 +819,18 @@ func HTTPRuleToIng(rrname string, namespace string, key string) ([]string, bool)
 		}
 	}
 
+	// The ingresses are synced on the changes to the services of the alternate backends.
+	if httprule != nil && err == nil {
+		for _, path := range httprule.Spec.Paths {
+			for _, backend := range path.AlternateBackends {
+				for _, ing := range allIngresses {
+					_, _, ingName := lib.ExtractTypeNameNamespace(ing)
+					objects.SharedSvcLister().IngressMappings(namespace).UpdateIngressMappings(ingName, backend.ServiceName)
+				}
+			}
+		}
+	}
+
 	utils.AviLog.Debugf("key: %s, msg: Ingresses retrieved %s", key, allIngresses)
 	return allIngresses, true
 }
@@ -974,6 +991,10 @@ func parseServicesForIngress(ingSpec networkingv1.IngressSpec, key string) []str
 			}
 		}
 	}
+	if ingSpec.DefaultBackend != nil && ingSpec.DefaultBackend.Service != nil &&
+		!utils.HasElem(services, ingSpec.DefaultBackend.Service.Name) {
+		services = append(services, ingSpec.DefaultBackend.Service.Name)
+	}
 	utils.AviLog.Debugf("key: %s, msg: total services retrieved from corev1: %s", key, services)
 	return services
 }
@@ -208,7 +208,7 @@ func (m *K8sIngressModel) GetSpec() interface{} {
 
 func (m *K8sIngressModel) ParseHostPath() IngressConfig {
 	o := NewNodesValidator()
-	return o.ParseHostPathForIngress(m.namespace, m.name, m.spec, m.annotations, m.key)
+	return o.ParseHostPathForIngress(m.namespace, m.name, m.spec, m.annotations, m.infrasetting, m.key)
 }
 
 func (m *K8sIngressModel) Exists() bool {
@@ -16,6 +16,7 @@ package nodes
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
@@ -190,7 +191,7 @@ func destinationCAHTTPRulePresent(key, host, path string) (bool, string) {
 
 // ParseHostPathForIngress handling for hostrule: if the host has a hostrule, and that hostrule has a tls.sslkeycertref then
 // move that host in the tls.hosts, this should be only in case of hostname sharding
-func (v *Validator) ParseHostPathForIngress(ns string, ingName string, ingSpec networkingv1.IngressSpec, annotations map[string]string, key string) IngressConfig {
+func (v *Validator) ParseHostPathForIngress(ns string, ingName string, ingSpec networkingv1.IngressSpec, annotations map[string]string, infraSetting *akov1beta1.AviInfraSetting, key string) IngressConfig {
 	// Figure out the service names that are part of this ingress
 
 	ingressConfig := IngressConfig{}
@@ -211,6 +212,14 @@ func (v *Validator) ParseHostPathForIngress(ns string, ingName string, ingSpec n
 		passthroughEnabled = strings.EqualFold(val, "true")
 	}
 
+	// The ImplementationSpecific paths are matched as regular expressions when the AviInfraSetting of
+	// the IngressClass has useRegex set, the annotation on the Ingress takes precedence over it.
+	useRegex := infraSetting != nil && infraSetting.Spec.L7Settings.UseRegex
+	if val, found := annotations[lib.UseRegexAnnotation]; found {
+		useRegex = strings.EqualFold(val, "true")
+	}
+	var invalidRegexPaths []string
+
 	var tlsConfigs []TlsSettings
 	for _, rule := range ingSpec.Rules {
 		var hostPathMapSvcList HostMetadata
@@ -267,9 +276,18 @@ func (v *Validator) ParseHostPathForIngress(ns string, ingName string, ingSpec n
 				if path.PathType != nil {
 					pathType = *path.PathType
 				}
+				regexPath := useRegex && pathType == networkingv1.PathTypeImplementationSpecific
+				if regexPath {
+					if _, err := regexp.Compile(path.Path); err != nil {
+						utils.AviLog.Warnf("key: %s, msg: skipping path %s of host %s, not a valid regular expression: %v", key, path.Path, hostName, err)
+						invalidRegexPaths = append(invalidRegexPaths, path.Path)
+						continue
+					}
+				}
 				hostPathMapSvc := IngressHostPathSvc{
 					Path:        path.Path,
 					PathType:    pathType,
+					RegexPath:   regexPath,
 					ServiceName: path.Backend.Service.Name,
 					Port:        path.Backend.Service.Port.Number,
 					PortName:    path.Backend.Service.Port.Name,
@@ -285,7 +303,12 @@ func (v *Validator) ParseHostPathForIngress(ns string, ingName string, ingSpec n
 				}
 				// for ingress use 100 as default weight
 				hostPathMapSvc.weight = 100
+				var alternates []IngressHostPathSvc
+				if !passthroughEnabled {
+					alternates = v.alternateBackendsForIngressPath(key, ns, ingName, hostName, &hostPathMapSvc)
+				}
 				hostPathMapSvcList.ingressHPSvc = append(hostPathMapSvcList.ingressHPSvc, hostPathMapSvc)
+				hostPathMapSvcList.ingressHPSvc = append(hostPathMapSvcList.ingressHPSvc, alternates...)
 			}
 		}
 
@@ -318,6 +341,13 @@ func (v *Validator) ParseHostPathForIngress(ns string, ingName string, ingSpec n
 		}
 	}
 
+	if len(invalidRegexPaths) > 0 {
+		if ingObj, err := utils.GetInformers().IngressInformer.Lister().Ingresses(ns).Get(ingName); err == nil {
+			lib.AKOControlConfig().EventRecorder().Eventf(ingObj, corev1.EventTypeWarning, lib.InvalidRegexPath,
+				"Paths %v are not valid regular expressions, skipping them", invalidRegexPaths)
+		}
+	}
+
 	if passthroughEnabled {
 		ingressConfig.PassthroughCollection = passConfig
 		utils.AviLog.Infof("key: %s, msg: host path config from passthrough enabled ingress: %+v", key, utils.Stringify(ingressConfig))
@@ -398,10 +428,37 @@ func (v *Validator) ParseHostPathForIngress(ns string, ingName string, ingSpec n
 
 	ingressConfig.TlsCollection = tlsConfigs
 	ingressConfig.IngressHostMap = hostMap
+	ingressConfig.DefaultBackend = v.parseDefaultBackend(ns, ingSpec.DefaultBackend, key)
 	utils.AviLog.Infof("key: %s, msg: host path config from ingress: %+v", key, utils.Stringify(ingressConfig))
 	return ingressConfig
 }
 
+// parseDefaultBackend returns the default backend of an Ingress. Only the Service backends are
+// supported, the Resource backends are ignored.
+func (v *Validator) parseDefaultBackend(ns string, backend *networkingv1.IngressBackend, key string) *IngressHostPathSvc {
+	if backend == nil {
+		return nil
+	}
+	if backend.Service == nil {
+		utils.AviLog.Warnf("key: %s, msg: only Service backends are supported as the default backend of an Ingress", key)
+		return nil
+	}
+	defaultBackend := &IngressHostPathSvc{
+		ServiceName: backend.Service.Name,
+		Port:        backend.Service.Port.Number,
+		PortName:    backend.Service.Port.Name,
+		TargetPort:  v.findTargetPort(backend.Service.Name, ns, &backend.Service.Port, key),
+		weight:      100,
+	}
+	if defaultBackend.PortName == "" {
+		defaultBackend.PortName = v.findPortName(backend.Service.Name, ns, backend.Service.Port.Number, key)
+	}
+	if defaultBackend.Port == 0 {
+		defaultBackend.Port = 80
+	}
+	return defaultBackend
+}
+
 func (v *Validator) findTargetPort(serviceName, ns string, serviceBackendPort *networkingv1.ServiceBackendPort, key string) intstr.IntOrString {
 	// Query the service and obtain the targetPort
 	svcObj, err := utils.GetInformers().ServiceInformer.Lister().Services(ns).Get(serviceName)
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package objects
+
+import (
+	"sync"
+	"time"
+)
+
+var drainingServerInstance *DrainingServerLister
+var drainingServerOnce sync.Once
+
+func SharedDrainingServerLister() *DrainingServerLister {
+	drainingServerOnce.Do(func() {
+		drainingServerInstance = &DrainingServerLister{
+			pools: make(map[string]*drainingServers),
+		}
+	})
+	return drainingServerInstance
+}
+
+// DrainingServerLister stores, per pool of a service, the time at which each terminating
+// pool server started draining, along with the timer that resyncs the service once the
+// earliest drain period expires. The pools of a service are tracked apart, as the pools
+// built for different ports of the service may have different terminating servers.
+type DrainingServerLister struct {
+	lock  sync.Mutex
+	pools map[string]*drainingServers
+}
+
+type drainingServers struct {
+	since map[string]time.Time
+	timer *time.Timer
+}
+
+// Refresh records the terminating servers of a pool and forgets the ones that are no
+// longer terminating. It returns the drain start time of every terminating server.
+func (d *DrainingServerLister) Refresh(poolKey string, terminating []string, now time.Time) map[string]time.Time {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	entry, ok := d.pools[poolKey]
+	if !ok {
+		if len(terminating) == 0 {
+			return nil
+		}
+		entry = &drainingServers{since: make(map[string]time.Time)}
+		d.pools[poolKey] = entry
+	}
+	since := make(map[string]time.Time, len(terminating))
+	for _, server := range terminating {
+		if start, ok := entry.since[server]; ok {
+			since[server] = start
+		} else {
+			since[server] = now
+		}
+	}
+	entry.since = since
+	if len(since) == 0 {
+		if entry.timer != nil {
+			entry.timer.Stop()
+		}
+		delete(d.pools, poolKey)
+		return nil
+	}
+	result := make(map[string]time.Time, len(since))
+	for server, start := range since {
+		result[server] = start
+	}
+	return result
+}
+
+// ScheduleResync replaces the pending resync of a pool with one that runs resync after the given duration.
+func (d *DrainingServerLister) ScheduleResync(poolKey string, after time.Duration, resync func()) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	entry, ok := d.pools[poolKey]
+	if !ok {
+		return
+	}
+	if entry.timer != nil {
+		entry.timer.Stop()
+	}
+	entry.timer = time.AfterFunc(after, resync)
+}
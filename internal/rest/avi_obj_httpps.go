@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 
 	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
@@ -127,7 +128,14 @@ func (rest *RestOperations) AviHttpPSBuild(hps_meta *nodes.AviHttpPolicySetNode,
 			path_match := avimodels.PathMatch{
 				MatchCriteria: &match_crit,
 				MatchCase:     &match_case,
-				MatchStr:      hppmap.Path,
+			}
+			if match_crit == "REGEX_MATCH" {
+				// The regular expressions are matched only through string groups.
+				sort.Strings(hppmap.Path)
+				sg_ref := fmt.Sprintf("/api/stringgroup/?name=%s", lib.GetRegexStringGroupName(hps_meta.Name, strings.Join(hppmap.Path, ",")))
+				path_match.StringGroupRefs = []string{sg_ref}
+			} else {
+				path_match.MatchStr = hppmap.Path
 			}
 			match_target.Path = &path_match
 		}
@@ -370,11 +378,12 @@ func (rest *RestOperations) AviHTTPPolicyCacheAdd(rest_op *utils.RestOp, vsKey a
 			}
 		}
 		http_cache_obj := avicache.AviHTTPPolicyCache{Name: name, Tenant: rest_op.Tenant,
-			Uuid:             uuid,
-			CloudConfigCksum: cksum,
-			LastModified:     lastModifiedStr,
-			PoolGroups:       pgMembers,
-			Pools:            poolMembers,
+			Uuid:                  uuid,
+			CloudConfigCksum:      cksum,
+			LastModified:          lastModifiedStr,
+			PoolGroups:            pgMembers,
+			Pools:                 poolMembers,
+			StringGroupCollection: httpPolicyStringGroups(rest_op),
 		}
 		if lastModifiedStr == "" {
 			http_cache_obj.InvalidData = true
@@ -416,3 +425,30 @@ func (rest *RestOperations) AviHTTPPolicyCacheDel(rest_op *utils.RestOp, vsKey a
 
 	return nil
 }
+
+// httpPolicyStringGroups returns the string groups referred to by the path matches of the HTTP
+// policyset sent in the rest operation. AKO refers to the string groups by name.
+func httpPolicyStringGroups(rest_op *utils.RestOp) []avicache.NamespaceName {
+	var hps avimodels.HTTPPolicySet
+	switch rest_op.Obj.(type) {
+	case utils.AviRestObjMacro:
+		hps, _ = rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.HTTPPolicySet)
+	case avimodels.HTTPPolicySet:
+		hps = rest_op.Obj.(avimodels.HTTPPolicySet)
+	}
+	var stringGroups []avicache.NamespaceName
+	if hps.HTTPRequestPolicy == nil {
+		return stringGroups
+	}
+	for _, rule := range hps.HTTPRequestPolicy.Rules {
+		if rule.Match == nil || rule.Match.Path == nil {
+			continue
+		}
+		for _, sgRef := range rule.Match.Path.StringGroupRefs {
+			if sgName := strings.TrimPrefix(sgRef, "/api/stringgroup/?name="); sgName != sgRef {
+				stringGroups = append(stringGroups, avicache.NamespaceName{Namespace: rest_op.Tenant, Name: sgName})
+			}
+		}
+	}
+	return stringGroups
+}
@@ -0,0 +1,238 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	avimodels "github.com/vmware/alb-sdk/go/models"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+func (rest *RestOperations) AviNetworkSecurityPolicyBuild(nsp_meta *nodes.AviNetworkSecurityPolicyNode, cache_obj *avicache.AviNetworkSecurityPolicyCache, key string) *utils.RestOp {
+	if lib.CheckObjectNameLength(nsp_meta.Name, lib.L4NSP) {
+		utils.AviLog.Warnf("key: %s not processing network security policy object", key)
+		return nil
+	}
+	name := nsp_meta.Name
+	tenant := fmt.Sprintf("/api/tenant/?name=%s", nsp_meta.Tenant)
+	cr := lib.AKOUser
+
+	nsp := avimodels.NetworkSecurityPolicy{
+		Name:      &name,
+		CreatedBy: &cr,
+		TenantRef: &tenant,
+	}
+	nsp.Markers = lib.GetAllMarkers(nsp_meta.AviMarkers)
+
+	var ports []int64
+	for port := range nsp_meta.SourceRanges {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+
+	// Each restricted port gets a rule allowing its client CIDRs, the traffic of the other
+	// clients on these ports is denied by the last rule.
+	var idx uint32
+	for _, port := range ports {
+		clientIP := &avimodels.IPAddrMatch{MatchCriteria: proto.String(lib.IS_IN)}
+		for _, cidr := range nsp_meta.SourceRanges[port] {
+			ip, ipNet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				utils.AviLog.Warnf("key: %s, msg: skipping invalid CIDR %s in network security policy %s", key, cidr, name)
+				continue
+			}
+			addr := ip.String()
+			addrType := "V4"
+			if !utils.IsV4(addr) {
+				addrType = "V6"
+			}
+			ones, _ := ipNet.Mask.Size()
+			mask := int32(ones)
+			clientIP.Prefixes = append(clientIP.Prefixes, &avimodels.IPAddrPrefix{
+				IPAddr: &avimodels.IPAddr{Addr: &addr, Type: &addrType},
+				Mask:   &mask,
+			})
+		}
+		nsp.Rules = append(nsp.Rules, networkSecurityRule("allow-"+strconv.Itoa(int(port)), lib.NSP_ACTION_ALLOW, idx,
+			&avimodels.NetworkSecurityMatchTarget{
+				ClientIP: clientIP,
+				VsPort:   &avimodels.PortMatch{MatchCriteria: proto.String(lib.IS_IN), Ports: []int64{port}},
+			}))
+		idx++
+	}
+	if len(ports) > 0 {
+		nsp.Rules = append(nsp.Rules, networkSecurityRule("deny", lib.NSP_ACTION_DENY, idx,
+			&avimodels.NetworkSecurityMatchTarget{
+				VsPort: &avimodels.PortMatch{MatchCriteria: proto.String(lib.IS_IN), Ports: ports},
+			}))
+	}
+
+	var rest_op utils.RestOp
+	if cache_obj == nil {
+		// Update an existing network security policy if it exists in the cache but is not associated with this VS.
+		nsp_key := avicache.NamespaceName{Namespace: nsp_meta.Tenant, Name: nsp_meta.Name}
+		nsp_cache, ok := rest.cache.NetworkSecurityPolicyCache.AviCacheGet(nsp_key)
+		if ok {
+			cache_obj, _ = nsp_cache.(*avicache.AviNetworkSecurityPolicyCache)
+		}
+	}
+	if cache_obj != nil {
+		rest_op = utils.RestOp{
+			ObjName: nsp_meta.Name,
+			Path:    "/api/networksecuritypolicy/" + cache_obj.Uuid,
+			Method:  utils.RestPut,
+			Obj:     nsp,
+			Tenant:  nsp_meta.Tenant,
+			Model:   "NetworkSecurityPolicy",
+		}
+	} else {
+		rest_op = utils.RestOp{
+			ObjName: nsp_meta.Name,
+			Path:    "/api/networksecuritypolicy/",
+			Method:  utils.RestPost,
+			Obj:     nsp,
+			Tenant:  nsp_meta.Tenant,
+			Model:   "NetworkSecurityPolicy",
+		}
+	}
+
+	utils.AviLog.Debug(spew.Sprintf("key: %s, msg: NetworkSecurityPolicy Restop %v AviNetworkSecurityPolicyMeta %v",
+		key, rest_op, utils.Stringify(nsp_meta)))
+	return &rest_op
+}
+
+func networkSecurityRule(name, action string, index uint32, match *avimodels.NetworkSecurityMatchTarget) *avimodels.NetworkSecurityRule {
+	enable := true
+	return &avimodels.NetworkSecurityRule{
+		Name:   &name,
+		Action: &action,
+		Enable: &enable,
+		Index:  &index,
+		Match:  match,
+	}
+}
+
+func (rest *RestOperations) AviNetworkSecurityPolicyDel(uuid string, tenant string, key string) *utils.RestOp {
+	path := "/api/networksecuritypolicy/" + uuid
+	rest_op := utils.RestOp{
+		Path:   path,
+		Method: "DELETE",
+		Tenant: tenant,
+		Model:  "NetworkSecurityPolicy",
+	}
+	utils.AviLog.Debug(spew.Sprintf("key: %s, msg: Network Security Policy DELETE Restop %v ", key,
+		utils.Stringify(rest_op)))
+	return &rest_op
+}
+
+func (rest *RestOperations) AviNetworkSecurityPolicyCacheAdd(rest_op *utils.RestOp, vsKey avicache.NamespaceName, key string) error {
+	if (rest_op.Err != nil) || (rest_op.Response == nil) {
+		utils.AviLog.Warnf("key: %s, rest_op has err or no response for networksecuritypolicy, err: %s, response: %s", key, rest_op.Err, rest_op.Response)
+		return errors.New("errored rest_op")
+	}
+
+	resp_elems := rest.restOperator.RestRespArrToObjByType(rest_op, "networksecuritypolicy", key)
+	if resp_elems == nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to find Network Security Policy obj in resp %v", key, rest_op.Response)
+		return errors.New("Network Security Policy object not found")
+	}
+
+	for _, resp := range resp_elems {
+		name, ok := resp["name"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, Name not present in response %v", key, resp)
+			continue
+		}
+
+		uuid, ok := resp["uuid"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, Uuid not present in response %v", key, resp)
+			continue
+		}
+
+		var lastModifiedStr string
+		lastModifiedIntf, ok := resp["_last_modified"]
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: last_modified not present in response %v", key, resp)
+		} else {
+			lastModifiedStr, ok = lastModifiedIntf.(string)
+			if !ok {
+				utils.AviLog.Warnf("key: %s, msg: last_modified is not of type string", key)
+			}
+		}
+
+		var nsp avimodels.NetworkSecurityPolicy
+		switch rest_op.Obj.(type) {
+		case utils.AviRestObjMacro:
+			nsp = rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.NetworkSecurityPolicy)
+		case avimodels.NetworkSecurityPolicy:
+			nsp = rest_op.Obj.(avimodels.NetworkSecurityPolicy)
+		}
+		//This is fetching data from response send at avi controller.
+		cksum := lib.NetworkSecurityPolicyChecksum(lib.NetworkSecurityPolicySourceRanges(nsp.Rules), utils.AviObjectMarkers{}, nsp.Markers, true)
+		nsp_cache_obj := avicache.AviNetworkSecurityPolicyCache{Name: name, Tenant: rest_op.Tenant,
+			Uuid:             uuid,
+			LastModified:     lastModifiedStr,
+			CloudConfigCksum: cksum,
+		}
+
+		k := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: name}
+		rest.cache.NetworkSecurityPolicyCache.AviCacheAdd(k, &nsp_cache_obj)
+		vs_cache, ok := rest.cache.VsCacheMeta.AviCacheGet(vsKey)
+		if ok {
+			vs_cache_obj, found := vs_cache.(*avicache.AviVsCache)
+			if found {
+				vs_cache_obj.AddToNetworkSecurityPolicyCollection(k)
+				utils.AviLog.Debugf("key: %s, msg: modified the VS cache for network security policy object. The cache now is :%v", key, utils.Stringify(vs_cache_obj))
+			}
+		} else {
+			vs_cache_obj := rest.cache.VsCacheMeta.AviCacheAddVS(vsKey)
+			vs_cache_obj.AddToNetworkSecurityPolicyCollection(k)
+			utils.AviLog.Debug(spew.Sprintf("key: %s, msg: added VS cache key during network security policy update %v val %v", key, vsKey,
+				vs_cache_obj))
+		}
+		utils.AviLog.Debug(spew.Sprintf("key: %s, msg: added Network Security Policy cache k %v val %v", key, k,
+			nsp_cache_obj))
+	}
+
+	return nil
+}
+
+func (rest *RestOperations) AviNetworkSecurityPolicyCacheDel(rest_op *utils.RestOp, vsKey avicache.NamespaceName, key string) error {
+	nspKey := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: rest_op.ObjName}
+	rest.cache.NetworkSecurityPolicyCache.AviCacheDelete(nspKey)
+	vs_cache, ok := rest.cache.VsCacheMeta.AviCacheGet(vsKey)
+	if ok {
+		vs_cache_obj, found := vs_cache.(*avicache.AviVsCache)
+		if found {
+			vs_cache_obj.RemoveFromNetworkSecurityPolicyCollection(nspKey)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	avimodels "github.com/vmware/alb-sdk/go/models"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+func (rest *RestOperations) AviPersistenceProfileBuild(persistence_meta *nodes.AviPersistenceProfileNode, cache_obj *avicache.AviPersistenceProfileCache, key string) *utils.RestOp {
+	if lib.CheckObjectNameLength(persistence_meta.Name, lib.PersistenceProfile) {
+		utils.AviLog.Warnf("key: %s not processing persistence profile object", key)
+		return nil
+	}
+	name := persistence_meta.Name
+	tenant := fmt.Sprintf("/api/tenant/?name=%s", persistence_meta.Tenant)
+	persistenceType := lib.PERSISTENCE_TYPE_CLIENT_IP
+	timeout := persistence_meta.Timeout
+
+	persistenceProfile := avimodels.ApplicationPersistenceProfile{
+		Name:            &name,
+		TenantRef:       &tenant,
+		PersistenceType: &persistenceType,
+		IPPersistenceProfile: &avimodels.IPPersistenceProfile{
+			IPPersistentTimeout: &timeout,
+		},
+	}
+	persistenceProfile.Markers = lib.GetAllMarkers(persistence_meta.AviMarkers)
+
+	var rest_op utils.RestOp
+	if cache_obj != nil {
+		rest_op = utils.RestOp{
+			ObjName: persistence_meta.Name,
+			Path:    "/api/applicationpersistenceprofile/" + cache_obj.Uuid,
+			Method:  utils.RestPut,
+			Obj:     persistenceProfile,
+			Tenant:  persistence_meta.Tenant,
+			Model:   "ApplicationPersistenceProfile",
+		}
+	} else {
+		rest_op = utils.RestOp{
+			ObjName: persistence_meta.Name,
+			Path:    "/api/applicationpersistenceprofile/",
+			Method:  utils.RestPost,
+			Obj:     persistenceProfile,
+			Tenant:  persistence_meta.Tenant,
+			Model:   "ApplicationPersistenceProfile",
+		}
+	}
+
+	utils.AviLog.Debug(spew.Sprintf("key: %s, msg: ApplicationPersistenceProfile Restop %v AviPersistenceProfileMeta %v",
+		key, rest_op, utils.Stringify(persistence_meta)))
+	return &rest_op
+}
+
+func (rest *RestOperations) AviPersistenceProfileDel(uuid string, tenant string, key string) *utils.RestOp {
+	path := "/api/applicationpersistenceprofile/" + uuid
+	rest_op := utils.RestOp{
+		Path:   path,
+		Method: "DELETE",
+		Tenant: tenant,
+		Model:  "ApplicationPersistenceProfile",
+	}
+	utils.AviLog.Debug(spew.Sprintf("key: %s, msg: ApplicationPersistenceProfile DELETE Restop %v ", key,
+		utils.Stringify(rest_op)))
+	return &rest_op
+}
+
+// AviPersistenceProfileCacheAdd adds the persistence profile to the cache. The pool refers to the
+// profile in its response, hence the pool cache is linked to the profile when the pool is cached.
+func (rest *RestOperations) AviPersistenceProfileCacheAdd(rest_op *utils.RestOp, key string) error {
+	if (rest_op.Err != nil) || (rest_op.Response == nil) {
+		utils.AviLog.Warnf("key: %s, rest_op has err or no response for applicationpersistenceprofile, err: %s, response: %s", key, rest_op.Err, rest_op.Response)
+		return errors.New("errored rest_op")
+	}
+
+	resp_elems := rest.restOperator.RestRespArrToObjByType(rest_op, "applicationpersistenceprofile", key)
+	if resp_elems == nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to find ApplicationPersistenceProfile obj in resp %v", key, rest_op.Response)
+		return errors.New("ApplicationPersistenceProfile object not found")
+	}
+
+	for _, resp := range resp_elems {
+		name, ok := resp["name"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, Name not present in response %v", key, resp)
+			continue
+		}
+
+		uuid, ok := resp["uuid"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, Uuid not present in response %v", key, resp)
+			continue
+		}
+
+		var lastModifiedStr string
+		lastModifiedIntf, ok := resp["_last_modified"]
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: last_modified not present in response %v", key, resp)
+		} else {
+			lastModifiedStr, ok = lastModifiedIntf.(string)
+			if !ok {
+				utils.AviLog.Warnf("key: %s, msg: last_modified is not of type string", key)
+			}
+		}
+
+		var persistenceProfile avimodels.ApplicationPersistenceProfile
+		switch rest_op.Obj.(type) {
+		case utils.AviRestObjMacro:
+			persistenceProfile = rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.ApplicationPersistenceProfile)
+		case avimodels.ApplicationPersistenceProfile:
+			persistenceProfile = rest_op.Obj.(avimodels.ApplicationPersistenceProfile)
+		}
+		var timeout int32
+		if persistenceProfile.IPPersistenceProfile != nil && persistenceProfile.IPPersistenceProfile.IPPersistentTimeout != nil {
+			timeout = *persistenceProfile.IPPersistenceProfile.IPPersistentTimeout
+		}
+		persistence_cache_obj := avicache.AviPersistenceProfileCache{Name: name, Tenant: rest_op.Tenant,
+			Uuid:             uuid,
+			LastModified:     lastModifiedStr,
+			CloudConfigCksum: lib.PersistenceProfileChecksum(timeout, utils.AviObjectMarkers{}, persistenceProfile.Markers, true),
+		}
+
+		k := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: name}
+		rest.cache.PersistenceProfileCache.AviCacheAdd(k, &persistence_cache_obj)
+		utils.AviLog.Debug(spew.Sprintf("key: %s, msg: added ApplicationPersistenceProfile cache k %v val %v", key, k,
+			persistence_cache_obj))
+	}
+
+	return nil
+}
+
+func (rest *RestOperations) AviPersistenceProfileCacheDel(rest_op *utils.RestOp, key string) error {
+	persistenceKey := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: rest_op.ObjName}
+	rest.cache.PersistenceProfileCache.AviCacheDelete(persistenceKey)
+	utils.AviLog.Debugf("key: %s, msg: deleted ApplicationPersistenceProfile cache k %v", key, persistenceKey)
+	return nil
+}
@@ -117,6 +117,10 @@ func (rest *RestOperations) AviPoolBuild(pool_meta *nodes.AviPoolNode, cache_obj
 		pool.Tier1Lr = &pool_meta.T1Lr
 	}
 
+	if pool_meta.EnableHttp2 {
+		pool.EnableHttp2 = &pool_meta.EnableHttp2
+	}
+
 	if !pool_meta.AttachedWithSharedVS {
 		pool.Markers = lib.GetAllMarkers(pool_meta.AviMarkers)
 	} else {
@@ -141,6 +145,9 @@ func (rest *RestOperations) AviPoolBuild(pool_meta *nodes.AviPoolNode, cache_obj
 
 	if pool_meta.ApplicationPersistenceProfileRef != nil {
 		pool.ApplicationPersistenceProfileRef = pool_meta.ApplicationPersistenceProfileRef
+	} else if pool_meta.PersistenceProfile != nil {
+		persistenceProfileName := "/api/applicationpersistenceprofile?name=" + pool_meta.PersistenceProfile.Name
+		pool.ApplicationPersistenceProfileRef = &persistenceProfileName
 	}
 
 	for i, server := range pool_meta.Servers {
@@ -155,8 +162,20 @@ func (rest *RestOperations) AviPoolBuild(pool_meta *nodes.AviPoolNode, cache_obj
 			sn := server.ServerNode
 			s.ServerNode = &sn
 		}
+		if server.Draining {
+			// Disabled servers stop receiving new connections, existing ones are
+			// drained for the pool's graceful_disable_timeout.
+			enabled := false
+			s.Enabled = &enabled
+		}
 		pool.Servers = append(pool.Servers, &s)
 	}
+	pool.GracefulDisableTimeout = pool_meta.GetGracefulDisableTimeout()
+	pool.ServerTimeout = pool_meta.ServerTimeout
+	pool.MaxConcurrentConnectionsPerServer = pool_meta.MaxConcurrentConnectionsPerServer
+	pool.ConnectionRampDuration = pool_meta.ConnectionRampDuration
+	pool.RequestQueueEnabled = pool_meta.RequestQueueEnabled
+	pool.RequestQueueDepth = pool_meta.RequestQueueDepth
 
 	// overwrite with healthmonitors provided by CRD
 	if len(pool_meta.HealthMonitorRefs) > 0 {
@@ -290,6 +309,15 @@ func (rest *RestOperations) AviPoolCacheAdd(rest_op *utils.RestOp, vsKey avicach
 			}
 		}
 
+		var persistenceKey avicache.NamespaceName
+		if persistenceProf, ok := resp["application_persistence_profile_ref"]; ok && persistenceProf != "" {
+			persistenceUuid := avicache.ExtractUuid(persistenceProf.(string), "applicationpersistenceprofile-.*.#")
+			persistenceName, foundPersistence := rest.cache.PersistenceProfileCache.AviCacheGetNameByUuid(persistenceUuid)
+			if foundPersistence {
+				persistenceKey = avicache.NamespaceName{Namespace: lib.GetTenant(), Name: persistenceName.(string)}
+			}
+		}
+
 		k := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: name}
 		oldCacheServiceMetadataCRD := lib.CRDMetadata{}
 		if poolCache, ok := rest.cache.PoolCache.AviCacheGet(k); ok {
@@ -299,13 +327,15 @@ func (rest *RestOperations) AviPoolCacheAdd(rest_op *utils.RestOp, vsKey avicach
 		}
 
 		pool_cache_obj := avicache.AviPoolCache{
-			Name:                 name,
-			Tenant:               rest_op.Tenant,
-			Uuid:                 uuid,
-			CloudConfigCksum:     cksum,
-			ServiceMetadataObj:   svc_mdata_obj,
-			PkiProfileCollection: pkiKey,
-			LastModified:         lastModifiedStr,
+			Name:                         name,
+			Tenant:                       rest_op.Tenant,
+			Uuid:                         uuid,
+			CloudConfigCksum:             cksum,
+			FieldsCksum:                  avicache.PoolDriftChecksum(resp),
+			ServiceMetadataObj:           svc_mdata_obj,
+			PkiProfileCollection:         pkiKey,
+			PersistenceProfileCollection: persistenceKey,
+			LastModified:                 lastModifiedStr,
 		}
 		if lastModifiedStr == "" {
 			pool_cache_obj.InvalidData = true
@@ -315,6 +345,7 @@ func (rest *RestOperations) AviPoolCacheAdd(rest_op *utils.RestOp, vsKey avicach
 		if (oldCacheServiceMetadataCRD != lib.CRDMetadata{}) {
 			status.HttpRuleEventBroadcast(k.Name, oldCacheServiceMetadataCRD, svc_mdata_obj.CRDStatus)
 		}
+		status.UpdateCRDAppliedTo(key, status.AviObjectPool, name, uuid, lib.ServiceMetadataObj{CRDStatus: oldCacheServiceMetadataCRD}, svc_mdata_obj)
 
 		// Update the VS object
 		vs_cache, ok := rest.cache.VsCacheMeta.AviCacheGet(vsKey)
@@ -395,15 +426,18 @@ func (rest *RestOperations) AviPoolCacheDel(rest_op *utils.RestOp, vsKey avicach
 	}
 	utils.AviLog.Debugf("key: %s, msg: deleting pool with key: %s", key, poolKey)
 	cacheServiceMetadataCRD := lib.CRDMetadata{}
+	poolUuid := ""
 	if poolCache, ok := rest.cache.PoolCache.AviCacheGet(poolKey); ok {
 		if poolCacheObj, found := poolCache.(*avicache.AviPoolCache); found {
 			cacheServiceMetadataCRD = poolCacheObj.ServiceMetadataObj.CRDStatus
+			poolUuid = poolCacheObj.Uuid
 		}
 	}
 	rest.cache.PoolCache.AviCacheDelete(poolKey)
 	if (cacheServiceMetadataCRD != lib.CRDMetadata{}) {
 		status.HttpRuleEventBroadcast(poolKey.Name, cacheServiceMetadataCRD, lib.CRDMetadata{})
 	}
+	status.UpdateCRDAppliedTo(key, status.AviObjectPool, poolKey.Name, poolUuid, lib.ServiceMetadataObj{CRDStatus: cacheServiceMetadataCRD}, lib.ServiceMetadataObj{})
 	return nil
 }
 
@@ -0,0 +1,163 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	avimodels "github.com/vmware/alb-sdk/go/models"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+func (rest *RestOperations) AviStringGroupBuild(sg_meta *nodes.AviStringGroupNode, cache_obj *avicache.AviStringGroupCache, key string) *utils.RestOp {
+	if lib.CheckObjectNameLength(sg_meta.Name, lib.StringGroup) {
+		utils.AviLog.Warnf("key: %s not processing string group object", key)
+		return nil
+	}
+	name := sg_meta.Name
+	tenant := fmt.Sprintf("/api/tenant/?name=%s", sg_meta.Tenant)
+	sgType := lib.SG_TYPE_STRING
+	longestMatch := false
+
+	stringGroup := avimodels.StringGroup{
+		Name:         &name,
+		TenantRef:    &tenant,
+		Type:         &sgType,
+		LongestMatch: &longestMatch,
+	}
+	for i := range sg_meta.Regex {
+		stringGroup.Kv = append(stringGroup.Kv, &avimodels.KeyValue{Key: &sg_meta.Regex[i]})
+	}
+	stringGroup.Markers = lib.GetAllMarkers(sg_meta.AviMarkers)
+
+	var rest_op utils.RestOp
+	if cache_obj != nil {
+		rest_op = utils.RestOp{
+			ObjName: sg_meta.Name,
+			Path:    "/api/stringgroup/" + cache_obj.Uuid,
+			Method:  utils.RestPut,
+			Obj:     stringGroup,
+			Tenant:  sg_meta.Tenant,
+			Model:   "StringGroup",
+		}
+	} else {
+		rest_op = utils.RestOp{
+			ObjName: sg_meta.Name,
+			Path:    "/api/stringgroup/",
+			Method:  utils.RestPost,
+			Obj:     stringGroup,
+			Tenant:  sg_meta.Tenant,
+			Model:   "StringGroup",
+		}
+	}
+
+	utils.AviLog.Debug(spew.Sprintf("key: %s, msg: StringGroup Restop %v AviStringGroupMeta %v",
+		key, rest_op, utils.Stringify(sg_meta)))
+	return &rest_op
+}
+
+func (rest *RestOperations) AviStringGroupDel(uuid string, tenant string, key string) *utils.RestOp {
+	path := "/api/stringgroup/" + uuid
+	rest_op := utils.RestOp{
+		Path:   path,
+		Method: "DELETE",
+		Tenant: tenant,
+		Model:  "StringGroup",
+	}
+	utils.AviLog.Debug(spew.Sprintf("key: %s, msg: StringGroup DELETE Restop %v ", key,
+		utils.Stringify(rest_op)))
+	return &rest_op
+}
+
+// AviStringGroupCacheAdd adds the string group to the cache. The HTTP policyset refers to the
+// string group, hence the HTTP policyset cache is linked to the group when the policyset is cached.
+func (rest *RestOperations) AviStringGroupCacheAdd(rest_op *utils.RestOp, key string) error {
+	if (rest_op.Err != nil) || (rest_op.Response == nil) {
+		utils.AviLog.Warnf("key: %s, rest_op has err or no response for stringgroup, err: %s, response: %s", key, rest_op.Err, rest_op.Response)
+		return errors.New("errored rest_op")
+	}
+
+	resp_elems := rest.restOperator.RestRespArrToObjByType(rest_op, "stringgroup", key)
+	if resp_elems == nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to find StringGroup obj in resp %v", key, rest_op.Response)
+		return errors.New("StringGroup object not found")
+	}
+
+	for _, resp := range resp_elems {
+		name, ok := resp["name"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, Name not present in response %v", key, resp)
+			continue
+		}
+
+		uuid, ok := resp["uuid"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, Uuid not present in response %v", key, resp)
+			continue
+		}
+
+		var lastModifiedStr string
+		lastModifiedIntf, ok := resp["_last_modified"]
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: last_modified not present in response %v", key, resp)
+		} else {
+			lastModifiedStr, ok = lastModifiedIntf.(string)
+			if !ok {
+				utils.AviLog.Warnf("key: %s, msg: last_modified is not of type string", key)
+			}
+		}
+
+		var stringGroup avimodels.StringGroup
+		switch rest_op.Obj.(type) {
+		case utils.AviRestObjMacro:
+			stringGroup = rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.StringGroup)
+		case avimodels.StringGroup:
+			stringGroup = rest_op.Obj.(avimodels.StringGroup)
+		}
+		var regex []string
+		for _, kv := range stringGroup.Kv {
+			if kv != nil && kv.Key != nil {
+				regex = append(regex, *kv.Key)
+			}
+		}
+		sg_cache_obj := avicache.AviStringGroupCache{Name: name, Tenant: rest_op.Tenant,
+			Uuid:             uuid,
+			LastModified:     lastModifiedStr,
+			CloudConfigCksum: lib.StringGroupChecksum(regex, utils.AviObjectMarkers{}, stringGroup.Markers, true),
+		}
+
+		k := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: name}
+		rest.cache.StringGroupCache.AviCacheAdd(k, &sg_cache_obj)
+		utils.AviLog.Debug(spew.Sprintf("key: %s, msg: added StringGroup cache k %v val %v", key, k,
+			sg_cache_obj))
+	}
+
+	return nil
+}
+
+func (rest *RestOperations) AviStringGroupCacheDel(rest_op *utils.RestOp, key string) error {
+	sgKey := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: rest_op.ObjName}
+	rest.cache.StringGroupCache.AviCacheDelete(sgKey)
+	utils.AviLog.Debugf("key: %s, msg: deleted StringGroup cache k %v", key, sgKey)
+	return nil
+}
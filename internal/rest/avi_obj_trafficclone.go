@@ -0,0 +1,198 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	avimodels "github.com/vmware/alb-sdk/go/models"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+func (rest *RestOperations) AviTrafficCloneProfileBuild(clone_meta *nodes.AviTrafficCloneProfileNode, cache_obj *avicache.AviTrafficCloneProfileCache, key string) *utils.RestOp {
+	if lib.CheckObjectNameLength(clone_meta.Name, lib.TrafficCloneProfile) {
+		utils.AviLog.Warnf("key: %s not processing traffic clone profile object", key)
+		return nil
+	}
+	name := clone_meta.Name
+	tenant := fmt.Sprintf("/api/tenant/?name=%s", clone_meta.Tenant)
+	cloudRef := "/api/cloud?name=" + utils.CloudName
+
+	cloneProfile := avimodels.TrafficCloneProfile{
+		Name:      &name,
+		TenantRef: &tenant,
+		CloudRef:  &cloudRef,
+	}
+	cloneProfile.Markers = lib.GetAllMarkers(clone_meta.AviMarkers)
+
+	for i := range clone_meta.CloneServers {
+		addr := clone_meta.CloneServers[i]
+		addrType := "V4"
+		if !utils.IsV4(addr) {
+			addrType = "V6"
+		}
+		cloneServer := &avimodels.CloneServer{
+			IPAddress: &avimodels.IPAddr{Addr: &addr, Type: &addrType},
+		}
+		cloneProfile.CloneServers = append(cloneProfile.CloneServers, cloneServer)
+	}
+
+	var rest_op utils.RestOp
+	if cache_obj == nil {
+		// Update an existing traffic clone profile if it exists in the cache but is not associated with this VS.
+		clone_key := avicache.NamespaceName{Namespace: clone_meta.Tenant, Name: clone_meta.Name}
+		clone_cache, ok := rest.cache.TrafficCloneProfileCache.AviCacheGet(clone_key)
+		if ok {
+			cache_obj, _ = clone_cache.(*avicache.AviTrafficCloneProfileCache)
+		}
+	}
+	if cache_obj != nil {
+		rest_op = utils.RestOp{
+			ObjName: clone_meta.Name,
+			Path:    "/api/trafficcloneprofile/" + cache_obj.Uuid,
+			Method:  utils.RestPut,
+			Obj:     cloneProfile,
+			Tenant:  clone_meta.Tenant,
+			Model:   "TrafficCloneProfile",
+		}
+	} else {
+		rest_op = utils.RestOp{
+			ObjName: clone_meta.Name,
+			Path:    "/api/trafficcloneprofile/",
+			Method:  utils.RestPost,
+			Obj:     cloneProfile,
+			Tenant:  clone_meta.Tenant,
+			Model:   "TrafficCloneProfile",
+		}
+	}
+
+	utils.AviLog.Debug(spew.Sprintf("key: %s, msg: TrafficCloneProfile Restop %v AviTrafficCloneProfileMeta %v",
+		key, rest_op, utils.Stringify(clone_meta)))
+	return &rest_op
+}
+
+func (rest *RestOperations) AviTrafficCloneProfileDel(uuid string, tenant string, key string) *utils.RestOp {
+	path := "/api/trafficcloneprofile/" + uuid
+	rest_op := utils.RestOp{
+		Path:   path,
+		Method: "DELETE",
+		Tenant: tenant,
+		Model:  "TrafficCloneProfile",
+	}
+	utils.AviLog.Debug(spew.Sprintf("key: %s, msg: Traffic Clone Profile DELETE Restop %v ", key,
+		utils.Stringify(rest_op)))
+	return &rest_op
+}
+
+func (rest *RestOperations) AviTrafficCloneProfileCacheAdd(rest_op *utils.RestOp, vsKey avicache.NamespaceName, key string) error {
+	if (rest_op.Err != nil) || (rest_op.Response == nil) {
+		utils.AviLog.Warnf("key: %s, rest_op has err or no response for trafficcloneprofile, err: %s, response: %s", key, rest_op.Err, rest_op.Response)
+		return errors.New("errored rest_op")
+	}
+
+	resp_elems := rest.restOperator.RestRespArrToObjByType(rest_op, "trafficcloneprofile", key)
+	if resp_elems == nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to find Traffic Clone Profile obj in resp %v", key, rest_op.Response)
+		return errors.New("Traffic Clone Profile object not found")
+	}
+
+	for _, resp := range resp_elems {
+		name, ok := resp["name"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, Name not present in response %v", key, resp)
+			continue
+		}
+
+		uuid, ok := resp["uuid"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, Uuid not present in response %v", key, resp)
+			continue
+		}
+
+		var lastModifiedStr string
+		lastModifiedIntf, ok := resp["_last_modified"]
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: last_modified not present in response %v", key, resp)
+		} else {
+			lastModifiedStr, ok = lastModifiedIntf.(string)
+			if !ok {
+				utils.AviLog.Warnf("key: %s, msg: last_modified is not of type string", key)
+			}
+		}
+
+		var cloneProfile avimodels.TrafficCloneProfile
+		switch rest_op.Obj.(type) {
+		case utils.AviRestObjMacro:
+			cloneProfile = rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.TrafficCloneProfile)
+		case avimodels.TrafficCloneProfile:
+			cloneProfile = rest_op.Obj.(avimodels.TrafficCloneProfile)
+		}
+		var cloneServers []string
+		for _, cloneServer := range cloneProfile.CloneServers {
+			if cloneServer.IPAddress != nil && cloneServer.IPAddress.Addr != nil {
+				cloneServers = append(cloneServers, *cloneServer.IPAddress.Addr)
+			}
+		}
+		//This is fetching data from response send at avi controller.
+		cksum := lib.TrafficCloneProfileChecksum(cloneServers, utils.AviObjectMarkers{}, cloneProfile.Markers, true)
+		clone_cache_obj := avicache.AviTrafficCloneProfileCache{Name: name, Tenant: rest_op.Tenant,
+			Uuid:             uuid,
+			LastModified:     lastModifiedStr,
+			CloudConfigCksum: cksum,
+		}
+
+		k := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: name}
+		rest.cache.TrafficCloneProfileCache.AviCacheAdd(k, &clone_cache_obj)
+		vs_cache, ok := rest.cache.VsCacheMeta.AviCacheGet(vsKey)
+		if ok {
+			vs_cache_obj, found := vs_cache.(*avicache.AviVsCache)
+			if found {
+				vs_cache_obj.AddToTrafficCloneProfileCollection(k)
+				utils.AviLog.Debugf("key: %s, msg: modified the VS cache for traffic clone profile object. The cache now is :%v", key, utils.Stringify(vs_cache_obj))
+			}
+		} else {
+			vs_cache_obj := rest.cache.VsCacheMeta.AviCacheAddVS(vsKey)
+			vs_cache_obj.AddToTrafficCloneProfileCollection(k)
+			utils.AviLog.Debug(spew.Sprintf("key: %s, msg: added VS cache key during traffic clone profile update %v val %v", key, vsKey,
+				vs_cache_obj))
+		}
+		utils.AviLog.Debug(spew.Sprintf("key: %s, msg: added Traffic Clone Profile cache k %v val %v", key, k,
+			clone_cache_obj))
+	}
+
+	return nil
+}
+
+func (rest *RestOperations) AviTrafficCloneProfileCacheDel(rest_op *utils.RestOp, vsKey avicache.NamespaceName, key string) error {
+	cloneKey := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: rest_op.ObjName}
+	rest.cache.TrafficCloneProfileCache.AviCacheDelete(cloneKey)
+	vs_cache, ok := rest.cache.VsCacheMeta.AviCacheGet(vsKey)
+	if ok {
+		vs_cache_obj, found := vs_cache.(*avicache.AviVsCache)
+		if found {
+			vs_cache_obj.RemoveFromTrafficCloneProfileCollection(cloneKey)
+		}
+	}
+
+	return nil
+}
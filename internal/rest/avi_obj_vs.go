@@ -685,9 +685,11 @@ func (rest *RestOperations) AviVsCacheAdd(rest_op *utils.RestOp, key string) err
 			if found {
 				vs_cache_obj.Uuid = uuid
 				vs_cache_obj.CloudConfigCksum = cksum
+				vs_cache_obj.FieldsCksum = avicache.VSDriftChecksum(resp)
 
 				status.HostRuleEventBroadcast(vs_cache_obj.Name, vs_cache_obj.ServiceMetadataObj.CRDStatus, svc_mdata_obj.CRDStatus)
 				status.SSORuleEventBroadcast(vs_cache_obj.Name, vs_cache_obj.ServiceMetadataObj.CRDStatus, svc_mdata_obj.CRDStatus)
+				status.UpdateCRDAppliedTo(key, status.AviObjectVirtualService, vs_cache_obj.Name, uuid, vs_cache_obj.ServiceMetadataObj, svc_mdata_obj)
 				vs_cache_obj.ServiceMetadataObj = svc_mdata_obj
 				if val, ok := resp["enable_rhi"].(bool); ok {
 					vs_cache_obj.EnableRhi = val
@@ -716,6 +718,7 @@ func (rest *RestOperations) AviVsCacheAdd(rest_op *utils.RestOp, key string) err
 				Tenant:             rest_op.Tenant,
 				Uuid:               uuid,
 				CloudConfigCksum:   cksum,
+				FieldsCksum:        avicache.VSDriftChecksum(resp),
 				ServiceMetadataObj: svc_mdata_obj,
 				LastModified:       lastModifiedStr,
 			}
@@ -732,6 +735,7 @@ func (rest *RestOperations) AviVsCacheAdd(rest_op *utils.RestOp, key string) err
 			rest.cache.VsCacheMeta.AviCacheAdd(k, vs_cache_obj)
 			status.HostRuleEventBroadcast(vs_cache_obj.Name, lib.CRDMetadata{}, svc_mdata_obj.CRDStatus)
 			status.SSORuleEventBroadcast(vs_cache_obj.Name, lib.CRDMetadata{}, svc_mdata_obj.CRDStatus)
+			status.UpdateCRDAppliedTo(key, status.AviObjectVirtualService, vs_cache_obj.Name, uuid, lib.ServiceMetadataObj{}, svc_mdata_obj)
 			utils.AviLog.Infof("key: %s, msg: added VS cache key %v val %v", key, k, utils.Stringify(vs_cache_obj))
 		}
 
@@ -843,6 +847,7 @@ func (rest *RestOperations) AviVsCacheDel(rest_op *utils.RestOp, vsKey avicache.
 					rest.DeletePoolIngressStatus(poolKey, true, vs_cache_obj.Name, key)
 				}
 			}
+			status.UpdateCRDAppliedTo(key, status.AviObjectVirtualService, vs_cache_obj.Name, vs_cache_obj.Uuid, vs_cache_obj.ServiceMetadataObj, lib.ServiceMetadataObj{})
 		}
 	}
 	utils.AviLog.Infof("key: %s, msg: deleting vs cache for key: %s", key, vsKey)
@@ -20,6 +20,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/vmware/alb-sdk/go/clients"
@@ -39,6 +40,38 @@ type RestOperations struct {
 	cache             *avicache.AviObjCache
 	aviRestPoolClient *utils.AviRestClientPool
 	restOperator      RestOperator
+	// syncFailed is set when a rest call fails while the key is being dequeued, in which case
+	// the model is not yet applied in the controller.
+	syncFailed bool
+	// syncErr is the error of a rest call which is neither retried by the retry layers nor
+	// resolved otherwise, in which case the key is retried by the graph layer queue.
+	syncErr error
+	// plan is set in the dry-run mode, in which the rest ops are recorded in it instead of
+	// being executed.
+	plan *Plan
+	// persistenceProfileLocked is set while persistenceProfileLock is held for the rest ops being
+	// built and executed.
+	persistenceProfileLocked bool
+}
+
+// persistenceProfileLock serializes the keys whose rest ops create, refer to or delete the persistence
+// profiles, which are shared across the pools of different keys. It is held from the check whether a
+// persistence profile exists or is still in use, until the rest ops built on it are executed and cached,
+// so that a profile is not deleted while a pool referring to it is being created by another key.
+var persistenceProfileLock sync.Mutex
+
+func (rest *RestOperations) lockPersistenceProfiles() {
+	if !rest.persistenceProfileLocked {
+		persistenceProfileLock.Lock()
+		rest.persistenceProfileLocked = true
+	}
+}
+
+func (rest *RestOperations) unlockPersistenceProfiles() {
+	if rest.persistenceProfileLocked {
+		rest.persistenceProfileLocked = false
+		persistenceProfileLock.Unlock()
+	}
 }
 
 func NewRestOperations(cache *avicache.AviObjCache, aviRestPoolClient *utils.AviRestClientPool, overrideLeaderFlag ...bool) RestOperations {
@@ -46,6 +79,9 @@ func NewRestOperations(cache *avicache.AviObjCache, aviRestPoolClient *utils.Avi
 	restOp.cache = cache
 	restOp.aviRestPoolClient = aviRestPoolClient
 	restOp.restOperator = NewRestOperator(&restOp, overrideLeaderFlag...)
+	if lib.IsDryRunEnabled() {
+		restOp.plan = SharedPlan()
+	}
 	return restOp
 }
 
@@ -58,9 +94,28 @@ func (rest *RestOperations) CleanupVS(key string, skipVS bool) {
 	utils.AviLog.Infof("key: %s, msg: cleanup mode, stale object removal done", key)
 }
 
+// SyncError returns the error for which the last key dequeued is to be retried by the graph
+// layer queue, nil if the key was synced or is retried by the retry layers.
+func (rest *RestOperations) SyncError() error {
+	return rest.syncErr
+}
+
 func (rest *RestOperations) DequeueNodes(key string) {
 	utils.AviLog.Infof("key: %s, msg: start rest layer sync.", key)
 	lib.DecrementQueueCounter(utils.GraphLayer)
+	defer lib.ObserveLayerProcessingTime(lib.MetricLayerRest, time.Now())
+	rest.syncFailed = false
+	rest.syncErr = nil
+	defer rest.unlockPersistenceProfiles()
+	defer func() {
+		if !rest.syncFailed {
+			lib.ObserveModelSyncLatency(key)
+		}
+	}()
+	if rest.plan != nil {
+		rest.plan.reset(key)
+		defer rest.writePlan(key)
+	}
 	// Got the key from the Graph Layer - let's fetch the model
 	ok, avimodelIntf := objects.SharedAviGraphLister().Get(key)
 	if !ok {
@@ -292,6 +347,7 @@ func (rest *RestOperations) RestOperation(vsName string, namespace string, avimo
 	var sni_to_delete []avicache.NamespaceName
 	var httppol_to_delete []avicache.NamespaceName
 	var l4pol_to_delete []avicache.NamespaceName
+	var nsp_to_delete []avicache.NamespaceName
 	var sslkey_cert_delete []avicache.NamespaceName
 	var vsvipErr error
 	var publishKey string
@@ -329,6 +385,7 @@ func (rest *RestOperations) RestOperation(vsName string, namespace string, avimo
 		httppol_to_delete, rest_ops = rest.HTTPPolicyCU(aviVsNode.HttpPolicyRefs, vs_cache_obj, namespace, rest_ops, key)
 		ds_to_delete, rest_ops = rest.DatascriptCU(aviVsNode.HTTPDSrefs, vs_cache_obj, namespace, rest_ops, key)
 		l4pol_to_delete, rest_ops = rest.L4PolicyCU(aviVsNode.L4PolicyRefs, vs_cache_obj, namespace, rest_ops, key)
+		nsp_to_delete, rest_ops = rest.NetworkSecurityPolicyCU(aviVsNode.NetworkSecurityPolicyRefs, vs_cache_obj, namespace, rest_ops, key)
 		utils.AviLog.Debugf("key: %s, msg: stored checksum for VS: %s, model checksum: %s", key, vs_cache_obj.CloudConfigCksum, strconv.Itoa(int(aviVsNode.GetCheckSum())))
 		if vs_cache_obj.CloudConfigCksum == strconv.Itoa(int(aviVsNode.GetCheckSum())) {
 			utils.AviLog.Debugf("key: %s, msg: the checksums are same for vs %s, not doing anything", key, vs_cache_obj.Name)
@@ -360,6 +417,7 @@ func (rest *RestOperations) RestOperation(vsName string, namespace string, avimo
 		_, rest_ops = rest.PoolGroupCU(aviVsNode.PoolGroupRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.HTTPPolicyCU(aviVsNode.HttpPolicyRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.L4PolicyCU(aviVsNode.L4PolicyRefs, nil, namespace, rest_ops, key)
+		_, rest_ops = rest.NetworkSecurityPolicyCU(aviVsNode.NetworkSecurityPolicyRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.DatascriptCU(aviVsNode.HTTPDSrefs, nil, namespace, rest_ops, key)
 
 		// The cache was not found - it's a POST call.
@@ -391,6 +449,7 @@ func (rest *RestOperations) RestOperation(vsName string, namespace string, avimo
 	}
 	rest_ops = rest.HTTPPolicyDelete(httppol_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.L4PolicyDelete(l4pol_to_delete, namespace, rest_ops, key)
+	rest_ops = rest.NetworkSecurityPolicyDelete(nsp_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.DSDelete(ds_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.PoolGroupDelete(pgs_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.PoolDelete(pools_to_delete, namespace, rest_ops, key)
@@ -528,6 +587,8 @@ func (rest *RestOperations) DeleteVSOper(vsKey avicache.NamespaceName, vs_cache_
 		rest_ops = rest.SSLKeyCertDelete(vs_cache_obj.SSLKeyCertCollection, namespace, rest_ops, key)
 		rest_ops = rest.HTTPPolicyDelete(vs_cache_obj.HTTPKeyCollection, namespace, rest_ops, key)
 		rest_ops = rest.L4PolicyDelete(vs_cache_obj.L4PolicyCollection, namespace, rest_ops, key)
+		rest_ops = rest.TrafficCloneProfileDelete(vs_cache_obj.TrafficCloneProfileCollection, namespace, rest_ops, key)
+		rest_ops = rest.NetworkSecurityPolicyDelete(vs_cache_obj.NetworkSecurityPolicyCollection, namespace, rest_ops, key)
 		rest_ops = rest.PoolGroupDelete(vs_cache_obj.PGKeyCollection, namespace, rest_ops, key)
 		rest_ops = rest.PoolDelete(vs_cache_obj.PoolKeyCollection, namespace, rest_ops, key)
 		success, _ := rest.ExecuteRestAndPopulateCache(rest_ops, vsKey, nil, key, false)
@@ -567,6 +628,7 @@ func (rest *RestOperations) deleteSniVs(vsKey avicache.NamespaceName, vs_cache_o
 		rest_ops = rest.DataScriptDelete(vs_cache_obj.DSKeyCollection, namespace, rest_ops, key)
 		rest_ops = rest.SSLKeyCertDelete(vs_cache_obj.SSLKeyCertCollection, namespace, rest_ops, key)
 		rest_ops = rest.HTTPPolicyDelete(vs_cache_obj.HTTPKeyCollection, namespace, rest_ops, key)
+		rest_ops = rest.TrafficCloneProfileDelete(vs_cache_obj.TrafficCloneProfileCollection, namespace, rest_ops, key)
 		rest_ops = rest.PoolGroupDelete(vs_cache_obj.PGKeyCollection, namespace, rest_ops, key)
 		rest_ops = rest.PoolDelete(vs_cache_obj.PoolKeyCollection, namespace, rest_ops, key)
 		success, _ := rest.ExecuteRestAndPopulateCache(rest_ops, vsKey, avimodel, key, false)
@@ -576,6 +638,7 @@ func (rest *RestOperations) deleteSniVs(vsKey avicache.NamespaceName, vs_cache_o
 }
 
 func (rest *RestOperations) ExecuteRestAndPopulateCache(rest_ops []*utils.RestOp, aviObjKey avicache.NamespaceName, avimodel *nodes.AviObjectGraph, key string, isEvh bool, sslKey ...utils.NamespaceName) (bool, bool) {
+	defer rest.unlockPersistenceProfiles()
 	// Choose a avi client based on the model name hash. This would ensure that the same worker queue processes updates for a given VS all the time.
 	shardSize := lib.GetshardSize()
 	if shardSize == 0 {
@@ -584,6 +647,17 @@ func (rest *RestOperations) ExecuteRestAndPopulateCache(rest_ops []*utils.RestOp
 	}
 	var retry, fastRetry, processNextObj bool
 	bkt := utils.Bkt(key, shardSize)
+	if rest.plan != nil {
+		if len(rest_ops) > 0 {
+			var aviclient *clients.AviClient
+			if rest.aviRestPoolClient != nil && len(rest.aviRestPoolClient.AviClient) > 0 {
+				// A planner can be created with fewer clients than the shards.
+				aviclient = rest.aviRestPoolClient.AviClient[bkt%uint32(len(rest.aviRestPoolClient.AviClient))]
+			}
+			rest.recordPlan(aviclient, rest_ops, key)
+		}
+		return true, true
+	}
 	if len(rest.aviRestPoolClient.AviClient) > 0 && len(rest_ops) > 0 {
 		utils.AviLog.Infof("key: %s, msg: processing in rest queue number: %v", key, bkt)
 		aviclient := rest.aviRestPoolClient.AviClient[bkt]
@@ -601,6 +675,7 @@ func (rest *RestOperations) ExecuteRestAndPopulateCache(rest_ops []*utils.RestOp
 			utils.AviLog.Warnf("key: %s, msg: error in rest request %v, for %s, won't retry", key, err.Error(), aviObjKey.Name)
 			return false, processNextObj
 		} else {
+			rest.syncFailed = true
 			var publishKey string
 			if avimodel != nil && isEvh && len(avimodel.GetAviEvhVS()) > 0 {
 				publishKey = avimodel.GetAviEvhVS()[0].Name
@@ -626,6 +701,7 @@ func (rest *RestOperations) ExecuteRestAndPopulateCache(rest_ops []*utils.RestOp
 			}
 			utils.AviLog.Warnf("key: %s, msg: there was an error sending the macro %v", key, err.Error())
 			models.RestStatus.UpdateAviApiRestStatus("", err)
+			var unresolvedErr error
 			for i := len(rest_ops) - 1; i >= 0; i-- {
 				// Go over each of the failed requests and enqueue them to the worker queue for retry.
 				if rest_ops[i].Err != nil {
@@ -649,9 +725,13 @@ func (rest *RestOperations) ExecuteRestAndPopulateCache(rest_ops []*utils.RestOp
 						aviError, ok := rest_ops[i].Err.(session.AviError)
 						if !ok {
 							utils.AviLog.Infof("key: %s, msg: Error is not of type AviError, err: %v, %T", key, rest_ops[i].Err, rest_ops[i].Err)
+							unresolvedErr = rest_ops[i].Err
 							continue
 						}
 						retryable, fastRetryable, nextObj := rest.RefreshCacheForRetryLayer(publishKey, aviObjKey, rest_ops[i], aviError, aviclient, avimodel, key, isEvh)
+						if !retryable {
+							unresolvedErr = rest_ops[i].Err
+						}
 						retry = retry || retryable
 						processNextObj = processNextObj || nextObj
 						if avimodel.GetRetryCounter() != 0 {
@@ -677,6 +757,8 @@ func (rest *RestOperations) ExecuteRestAndPopulateCache(rest_ops []*utils.RestOp
 							} else {
 								rest.AviVsCacheDel(rest_ops[i], aviObjKey, key)
 							}
+						} else {
+							unresolvedErr = rest_ops[i].Err
 						}
 					}
 				} else {
@@ -690,6 +772,8 @@ func (rest *RestOperations) ExecuteRestAndPopulateCache(rest_ops []*utils.RestOp
 				} else {
 					rest.PublishKeyToSlowRetryLayer(publishKey, key)
 				}
+			} else if unresolvedErr != nil {
+				rest.syncErr = unresolvedErr
 			}
 			return false, processNextObj
 		}
@@ -764,6 +848,10 @@ func (rest *RestOperations) PopulateOneCache(rest_op *utils.RestOp, aviObjKey av
 		utils.AviLog.Infof("key: %s, msg: creating/updating %s cache, method: %s", key, rest_op.Model, rest_op.Method)
 		if rest_op.Model == "PKIprofile" {
 			rest.AviPkiProfileAdd(rest_op, aviObjKey, key)
+		} else if rest_op.Model == "ApplicationPersistenceProfile" {
+			rest.AviPersistenceProfileCacheAdd(rest_op, key)
+		} else if rest_op.Model == "StringGroup" {
+			rest.AviStringGroupCacheAdd(rest_op, key)
 		} else if rest_op.Model == "Pool" {
 			rest.AviPoolCacheAdd(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "VirtualService" {
@@ -778,6 +866,10 @@ func (rest *RestOperations) PopulateOneCache(rest_op *utils.RestOp, aviObjKey av
 			rest.AviSSLKeyCertAdd(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "L4PolicySet" {
 			rest.AviL4PolicyCacheAdd(rest_op, aviObjKey, key)
+		} else if rest_op.Model == "TrafficCloneProfile" {
+			rest.AviTrafficCloneProfileCacheAdd(rest_op, aviObjKey, key)
+		} else if rest_op.Model == "NetworkSecurityPolicy" {
+			rest.AviNetworkSecurityPolicyCacheAdd(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "VrfContext" {
 			rest.AviVrfCacheAdd(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "VsVip" {
@@ -789,6 +881,10 @@ func (rest *RestOperations) PopulateOneCache(rest_op *utils.RestOp, aviObjKey av
 		utils.AviLog.Infof("key: %s, msg: deleting %s cache", key, rest_op.Model)
 		if rest_op.Model == "PKIprofile" {
 			rest.AviPkiProfileCacheDel(rest_op, aviObjKey, key)
+		} else if rest_op.Model == "ApplicationPersistenceProfile" {
+			rest.AviPersistenceProfileCacheDel(rest_op, key)
+		} else if rest_op.Model == "StringGroup" {
+			rest.AviStringGroupCacheDel(rest_op, key)
 		} else if rest_op.Model == "Pool" {
 			rest.AviPoolCacheDel(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "VirtualService" {
@@ -801,6 +897,10 @@ func (rest *RestOperations) PopulateOneCache(rest_op *utils.RestOp, aviObjKey av
 			rest.AviSSLCacheDel(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "L4PolicySet" {
 			rest.AviL4PolicyCacheDel(rest_op, aviObjKey, key)
+		} else if rest_op.Model == "TrafficCloneProfile" {
+			rest.AviTrafficCloneProfileCacheDel(rest_op, aviObjKey, key)
+		} else if rest_op.Model == "NetworkSecurityPolicy" {
+			rest.AviNetworkSecurityPolicyCacheDel(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "VsVip" {
 			rest.AviVsVipCacheDel(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "VSDataScriptSet" {
@@ -979,6 +1079,30 @@ func (rest *RestOperations) RefreshCacheForRetryLayer(parentVsKey string, aviObj
 					rest_op.ObjName = L4PolicySet
 				}
 				rest.AviL4PolicyCacheDel(rest_op, aviObjKey, key)
+			case "TrafficCloneProfile":
+				var TrafficCloneProfile string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					TrafficCloneProfile = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.TrafficCloneProfile).Name
+				case avimodels.TrafficCloneProfile:
+					TrafficCloneProfile = *rest_op.Obj.(avimodels.TrafficCloneProfile).Name
+				}
+				if TrafficCloneProfile != "" {
+					rest_op.ObjName = TrafficCloneProfile
+				}
+				rest.AviTrafficCloneProfileCacheDel(rest_op, aviObjKey, key)
+			case "NetworkSecurityPolicy":
+				var NetworkSecurityPolicy string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					NetworkSecurityPolicy = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.NetworkSecurityPolicy).Name
+				case avimodels.NetworkSecurityPolicy:
+					NetworkSecurityPolicy = *rest_op.Obj.(avimodels.NetworkSecurityPolicy).Name
+				}
+				if NetworkSecurityPolicy != "" {
+					rest_op.ObjName = NetworkSecurityPolicy
+				}
+				rest.AviNetworkSecurityPolicyCacheDel(rest_op, aviObjKey, key)
 			case "SSLKeyAndCertificate":
 				var SSLKeyAndCertificate string
 				switch rest_op.Obj.(type) {
@@ -1003,6 +1127,30 @@ func (rest *RestOperations) RefreshCacheForRetryLayer(parentVsKey string, aviObj
 					rest_op.ObjName = PKIprofile
 				}
 				rest.AviPkiProfileCacheDel(rest_op, aviObjKey, key)
+			case "ApplicationPersistenceProfile":
+				var ApplicationPersistenceProfile string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					ApplicationPersistenceProfile = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.ApplicationPersistenceProfile).Name
+				case avimodels.ApplicationPersistenceProfile:
+					ApplicationPersistenceProfile = *rest_op.Obj.(avimodels.ApplicationPersistenceProfile).Name
+				}
+				if ApplicationPersistenceProfile != "" {
+					rest_op.ObjName = ApplicationPersistenceProfile
+				}
+				rest.AviPersistenceProfileCacheDel(rest_op, key)
+			case "StringGroup":
+				var StringGroup string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					StringGroup = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.StringGroup).Name
+				case avimodels.StringGroup:
+					StringGroup = *rest_op.Obj.(avimodels.StringGroup).Name
+				}
+				if StringGroup != "" {
+					rest_op.ObjName = StringGroup
+				}
+				rest.AviStringGroupCacheDel(rest_op, key)
 			case "VirtualService":
 				rest.AviVsCacheDel(rest_op, aviObjKey, key)
 			case "VSDataScriptSet":
@@ -1070,6 +1218,24 @@ func (rest *RestOperations) RefreshCacheForRetryLayer(parentVsKey string, aviObj
 					L4PolicySet = *rest_op.Obj.(avimodels.L4PolicySet).Name
 				}
 				aviObjCache.AviPopulateOneVsL4PolCache(c, utils.CloudName, L4PolicySet)
+			case "TrafficCloneProfile":
+				var TrafficCloneProfile string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					TrafficCloneProfile = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.TrafficCloneProfile).Name
+				case avimodels.TrafficCloneProfile:
+					TrafficCloneProfile = *rest_op.Obj.(avimodels.TrafficCloneProfile).Name
+				}
+				aviObjCache.AviPopulateOneTrafficCloneProfileCache(c, utils.CloudName, TrafficCloneProfile)
+			case "NetworkSecurityPolicy":
+				var NetworkSecurityPolicy string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					NetworkSecurityPolicy = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.NetworkSecurityPolicy).Name
+				case avimodels.NetworkSecurityPolicy:
+					NetworkSecurityPolicy = *rest_op.Obj.(avimodels.NetworkSecurityPolicy).Name
+				}
+				aviObjCache.AviPopulateOneNetworkSecurityPolicyCache(c, utils.CloudName, NetworkSecurityPolicy)
 			case "SSLKeyAndCertificate":
 				var SSLKeyAndCertificate string
 				switch rest_op.Obj.(type) {
@@ -1088,6 +1254,24 @@ func (rest *RestOperations) RefreshCacheForRetryLayer(parentVsKey string, aviObj
 					PKIprofile = *rest_op.Obj.(avimodels.PKIprofile).Name
 				}
 				aviObjCache.AviPopulateOnePKICache(c, utils.CloudName, PKIprofile)
+			case "ApplicationPersistenceProfile":
+				var ApplicationPersistenceProfile string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					ApplicationPersistenceProfile = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.ApplicationPersistenceProfile).Name
+				case avimodels.ApplicationPersistenceProfile:
+					ApplicationPersistenceProfile = *rest_op.Obj.(avimodels.ApplicationPersistenceProfile).Name
+				}
+				aviObjCache.AviPopulateOnePersistenceProfileCache(c, utils.CloudName, ApplicationPersistenceProfile)
+			case "StringGroup":
+				var StringGroup string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					StringGroup = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.StringGroup).Name
+				case avimodels.StringGroup:
+					StringGroup = *rest_op.Obj.(avimodels.StringGroup).Name
+				}
+				aviObjCache.AviPopulateOneStringGroupCache(c, utils.CloudName, StringGroup)
 			case "VirtualService":
 				aviObjCache.AviObjOneVSCachePopulate(c, utils.CloudName, aviObjKey.Name)
 				vsObjMeta, ok := rest.cache.VsCacheMeta.AviCacheGet(aviObjKey)
@@ -1145,6 +1329,7 @@ func ExtractStatusCode(word string) string {
 
 func (rest *RestOperations) PoolDelete(pools_to_delete []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
 	utils.AviLog.Debugf("key: %s, msg: about to delete the pools %s", key, utils.Stringify(pools_to_delete))
+	var persistence_to_delete []avicache.NamespaceName
 	for _, del_pool := range pools_to_delete {
 		// fetch trhe pool uuid from cache
 		pool_key := avicache.NamespaceName{Namespace: namespace, Name: del_pool.Name}
@@ -1159,8 +1344,26 @@ func (rest *RestOperations) PoolDelete(pools_to_delete []avicache.NamespaceName,
 			if pkiProfile.Name != "" {
 				rest_ops = rest.PkiProfileDelete([]avicache.NamespaceName{pkiProfile}, namespace, rest_ops, key)
 			}
+
+			persistenceProfile := avicache.NamespaceName{Namespace: namespace, Name: pool_cache_obj.PersistenceProfileCollection.Name}
+			if persistenceProfile.Name != "" && !utils.HasElem(persistence_to_delete, persistenceProfile) {
+				persistence_to_delete = append(persistence_to_delete, persistenceProfile)
+			}
 		}
 	}
+	// The persistence profiles are shared across pools, and are deleted after the pools referring to them.
+	if len(persistence_to_delete) > 0 {
+		rest.lockPersistenceProfiles()
+	}
+	var unused_persistence []avicache.NamespaceName
+	for _, persistenceProfile := range persistence_to_delete {
+		if !rest.persistenceProfileInUse(persistenceProfile, pools_to_delete) {
+			unused_persistence = append(unused_persistence, persistenceProfile)
+		}
+	}
+	if len(unused_persistence) > 0 {
+		rest_ops = rest.PersistenceProfileDelete(unused_persistence, namespace, rest_ops, key)
+	}
 	return rest_ops
 }
 
@@ -1234,6 +1437,7 @@ func (rest *RestOperations) DSDelete(ds_to_delete []avicache.NamespaceName, name
 func (rest *RestOperations) PoolCU(pool_nodes []*nodes.AviPoolNode, vs_cache_obj *avicache.AviVsCache, namespace string, rest_ops []*utils.RestOp, key string) ([]avicache.NamespaceName, []*utils.RestOp) {
 	var cache_pool_nodes []avicache.NamespaceName
 	var pool_pkiprofile_delete []avicache.NamespaceName
+	var pool_persistence_delete []avicache.NamespaceName
 	if vs_cache_obj != nil {
 		cache_pool_nodes = make([]avicache.NamespaceName, len(vs_cache_obj.PoolKeyCollection))
 		copy(cache_pool_nodes, vs_cache_obj.PoolKeyCollection)
@@ -1251,6 +1455,13 @@ func (rest *RestOperations) PoolCU(pool_nodes []*nodes.AviPoolNode, vs_cache_obj
 				if ok {
 					pool_cache_obj, _ := pool_cache.(*avicache.AviPoolCache)
 					pool_pkiprofile_delete, rest_ops = rest.PkiProfileCU(pool.PkiProfile, pool_cache_obj, namespace, rest_ops, key)
+					var persistence_delete []avicache.NamespaceName
+					persistence_delete, rest_ops = rest.PersistenceProfileCU(pool.PersistenceProfile, pool_cache_obj, namespace, rest_ops, key)
+					for _, persistenceProfile := range persistence_delete {
+						if !utils.HasElem(pool_persistence_delete, persistenceProfile) {
+							pool_persistence_delete = append(pool_persistence_delete, persistenceProfile)
+						}
+					}
 
 					// Cache found. Let's compare the checksums
 					utils.AviLog.Debugf("key: %s, msg: poolcache: %v", key, pool_cache_obj)
@@ -1268,6 +1479,7 @@ func (rest *RestOperations) PoolCU(pool_nodes []*nodes.AviPoolNode, vs_cache_obj
 			} else {
 				utils.AviLog.Debugf("key: %s, msg: pool %s not found in cache, operation: POST", key, pool.Name)
 				_, rest_ops = rest.PkiProfileCU(pool.PkiProfile, nil, namespace, rest_ops, key)
+				_, rest_ops = rest.PersistenceProfileCU(pool.PersistenceProfile, nil, namespace, rest_ops, key)
 				// Not found - it should be a POST call.
 				restOp := rest.AviPoolBuild(pool, nil, key)
 				if restOp != nil {
@@ -1278,11 +1490,37 @@ func (rest *RestOperations) PoolCU(pool_nodes []*nodes.AviPoolNode, vs_cache_obj
 				rest_ops = rest.PkiProfileDelete(pool_pkiprofile_delete, namespace, rest_ops, key)
 			}
 		}
+		// The persistence profiles no longer referred by the pools are deleted after all the pools are updated,
+		// unless other pools still refer to them. The ones referred by the pools to be deleted are left to PoolDelete.
+		if len(pool_persistence_delete) > 0 {
+			rest.lockPersistenceProfiles()
+		}
+		var unused_persistence []avicache.NamespaceName
+		var updated_pools []avicache.NamespaceName
+		for _, pool := range pool_nodes {
+			updated_pools = append(updated_pools, avicache.NamespaceName{Namespace: namespace, Name: pool.Name})
+		}
+		for _, persistenceProfile := range pool_persistence_delete {
+			inUse := false
+			for _, pool := range pool_nodes {
+				if pool.PersistenceProfile != nil && pool.PersistenceProfile.Name == persistenceProfile.Name {
+					inUse = true
+					break
+				}
+			}
+			if !inUse && !rest.persistenceProfileInUse(persistenceProfile, updated_pools) {
+				unused_persistence = append(unused_persistence, persistenceProfile)
+			}
+		}
+		if len(unused_persistence) > 0 {
+			rest_ops = rest.PersistenceProfileDelete(unused_persistence, namespace, rest_ops, key)
+		}
 
 	} else {
 		// Everything is a POST call
 		for _, pool := range pool_nodes {
 			_, rest_ops = rest.PkiProfileCU(pool.PkiProfile, nil, namespace, rest_ops, key)
+			_, rest_ops = rest.PersistenceProfileCU(pool.PersistenceProfile, nil, namespace, rest_ops, key)
 
 			utils.AviLog.Debugf("key: %s, msg: pool cache does not exist %s, operation: POST", key, pool.Name)
 			restOp := rest.AviPoolBuild(pool, nil, key)
@@ -1585,6 +1823,7 @@ func (rest *RestOperations) HTTPPolicyCU(http_nodes []*nodes.AviHttpPolicySetNod
 		cache_http_nodes = make([]avicache.NamespaceName, len(vs_cache_obj.HTTPKeyCollection))
 		copy(cache_http_nodes, vs_cache_obj.HTTPKeyCollection)
 		for _, http := range http_nodes {
+			var http_sg_delete []avicache.NamespaceName
 			http_key := avicache.NamespaceName{Namespace: namespace, Name: http.Name}
 			found := utils.HasElem(cache_http_nodes, http_key)
 			if found {
@@ -1592,6 +1831,7 @@ func (rest *RestOperations) HTTPPolicyCU(http_nodes []*nodes.AviHttpPolicySetNod
 				if ok {
 					cache_http_nodes = avicache.RemoveNamespaceName(cache_http_nodes, http_key)
 					http_cache_obj, _ := http_cache.(*avicache.AviHTTPPolicyCache)
+					http_sg_delete, rest_ops = rest.StringGroupCU(http.GetRegexStringGroups(), http_cache_obj, namespace, rest_ops, key)
 					// Cache found. Let's compare the checksums
 					if http_cache_obj.CloudConfigCksum == strconv.Itoa(int(http.GetCheckSum())) {
 						utils.AviLog.Debugf("The checksums are same for HTTP cache obj %s, not doing anything", http_cache_obj.Name)
@@ -1605,16 +1845,21 @@ func (rest *RestOperations) HTTPPolicyCU(http_nodes []*nodes.AviHttpPolicySetNod
 				}
 			} else {
 				// Not found - it should be a POST call.
+				_, rest_ops = rest.StringGroupCU(http.GetRegexStringGroups(), nil, namespace, rest_ops, key)
 				restOp := rest.AviHttpPSBuild(http, nil, key)
 				if restOp != nil {
 					rest_ops = append(rest_ops, restOp)
 				}
 			}
-
+			// The string groups are deleted once the policyset no longer refers to them.
+			if len(http_sg_delete) > 0 {
+				rest_ops = rest.StringGroupDelete(http_sg_delete, namespace, rest_ops, key)
+			}
 		}
 	} else {
 		// Everything is a POST call
 		for _, http := range http_nodes {
+			_, rest_ops = rest.StringGroupCU(http.GetRegexStringGroups(), nil, namespace, rest_ops, key)
 			restOp := rest.AviHttpPSBuild(http, nil, key)
 			if restOp != nil {
 				rest_ops = append(rest_ops, restOp)
@@ -1686,6 +1931,58 @@ func (rest *RestOperations) HTTPPolicyDelete(https_to_delete []avicache.Namespac
 			restOp := rest.AviHttpPolicyDel(http_cache_obj.Uuid, namespace, key)
 			restOp.ObjName = del_http.Name
 			rest_ops = append(rest_ops, restOp)
+			rest_ops = rest.StringGroupDelete(http_cache_obj.StringGroupCollection, namespace, rest_ops, key)
+		}
+	}
+	return rest_ops
+}
+
+// StringGroupCU returns the rest operations to create or update the string groups of an HTTP
+// policyset, and the cached string groups of the policyset to be deleted once the policyset no
+// longer refers to them.
+func (rest *RestOperations) StringGroupCU(sg_nodes []*nodes.AviStringGroupNode, http_cache_obj *avicache.AviHTTPPolicyCache, namespace string, rest_ops []*utils.RestOp, key string) ([]avicache.NamespaceName, []*utils.RestOp) {
+	var cache_sg_nodes []avicache.NamespaceName
+	if http_cache_obj != nil {
+		cache_sg_nodes = make([]avicache.NamespaceName, len(http_cache_obj.StringGroupCollection))
+		copy(cache_sg_nodes, http_cache_obj.StringGroupCollection)
+	}
+	for _, sg_node := range sg_nodes {
+		sg_key := avicache.NamespaceName{Namespace: namespace, Name: sg_node.Name}
+		cache_sg_nodes = avicache.RemoveNamespaceName(cache_sg_nodes, sg_key)
+		sg_cache, ok := rest.cache.StringGroupCache.AviCacheGet(sg_key)
+		if ok {
+			sg_cache_obj, _ := sg_cache.(*avicache.AviStringGroupCache)
+			// Cache found. Let's compare the checksums
+			if sg_cache_obj.CloudConfigCksum == sg_node.GetCheckSum() {
+				utils.AviLog.Debugf("key: %s, msg: the checksums are same for string group %s, not doing anything", key, sg_cache_obj.Name)
+				continue
+			}
+			// The checksums are different, so it should be a PUT call.
+			restOp := rest.AviStringGroupBuild(sg_node, sg_cache_obj, key)
+			if restOp != nil {
+				rest_ops = append(rest_ops, restOp)
+			}
+		} else {
+			// Not found - it should be a POST call.
+			restOp := rest.AviStringGroupBuild(sg_node, nil, key)
+			if restOp != nil {
+				rest_ops = append(rest_ops, restOp)
+			}
+		}
+	}
+	return cache_sg_nodes, rest_ops
+}
+
+func (rest *RestOperations) StringGroupDelete(sg_to_delete []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
+	utils.AviLog.Debugf("key: %s, msg: about to delete string groups %s", key, utils.Stringify(sg_to_delete))
+	for _, del_sg := range sg_to_delete {
+		sg_key := avicache.NamespaceName{Namespace: namespace, Name: del_sg.Name}
+		sg_cache, ok := rest.cache.StringGroupCache.AviCacheGet(sg_key)
+		if ok {
+			sg_cache_obj, _ := sg_cache.(*avicache.AviStringGroupCache)
+			restOp := rest.AviStringGroupDel(sg_cache_obj.Uuid, namespace, key)
+			restOp.ObjName = del_sg.Name
+			rest_ops = append(rest_ops, restOp)
 		}
 	}
 	return rest_ops
@@ -1715,6 +2012,102 @@ func (rest *RestOperations) L4PolicyDelete(l4_to_delete []avicache.NamespaceName
 	return rest_ops
 }
 
+func (rest *RestOperations) TrafficCloneProfileCU(clone_nodes []*nodes.AviTrafficCloneProfileNode, vs_cache_obj *avicache.AviVsCache, namespace string, rest_ops []*utils.RestOp, key string) ([]avicache.NamespaceName, []*utils.RestOp) {
+	var cache_clone_nodes []avicache.NamespaceName
+	if vs_cache_obj != nil {
+		cache_clone_nodes = make([]avicache.NamespaceName, len(vs_cache_obj.TrafficCloneProfileCollection))
+		copy(cache_clone_nodes, vs_cache_obj.TrafficCloneProfileCollection)
+	}
+	for _, clone := range clone_nodes {
+		clone_key := avicache.NamespaceName{Namespace: namespace, Name: clone.Name}
+		cache_clone_nodes = avicache.RemoveNamespaceName(cache_clone_nodes, clone_key)
+		clone_cache, ok := rest.cache.TrafficCloneProfileCache.AviCacheGet(clone_key)
+		if ok {
+			clone_cache_obj, _ := clone_cache.(*avicache.AviTrafficCloneProfileCache)
+			// Cache found. Let's compare the checksums
+			if clone_cache_obj.CloudConfigCksum == clone.GetCheckSum() {
+				utils.AviLog.Debugf("key: %s, msg: the checksums are same for traffic clone profile %s, not doing anything", key, clone_cache_obj.Name)
+				continue
+			}
+			// The checksums are different, so it should be a PUT call.
+			restOp := rest.AviTrafficCloneProfileBuild(clone, clone_cache_obj, key)
+			if restOp != nil {
+				rest_ops = append(rest_ops, restOp)
+			}
+		} else {
+			// Not found - it should be a POST call.
+			restOp := rest.AviTrafficCloneProfileBuild(clone, nil, key)
+			if restOp != nil {
+				rest_ops = append(rest_ops, restOp)
+			}
+		}
+	}
+	utils.AviLog.Debugf("key: %s, msg: the traffic clone profiles to be deleted are: %s", key, cache_clone_nodes)
+	return cache_clone_nodes, rest_ops
+}
+
+func (rest *RestOperations) TrafficCloneProfileDelete(clone_to_delete []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
+	for _, del_clone := range clone_to_delete {
+		clone_key := avicache.NamespaceName{Namespace: namespace, Name: del_clone.Name}
+		clone_cache, ok := rest.cache.TrafficCloneProfileCache.AviCacheGet(clone_key)
+		if ok {
+			clone_cache_obj, _ := clone_cache.(*avicache.AviTrafficCloneProfileCache)
+			restOp := rest.AviTrafficCloneProfileDel(clone_cache_obj.Uuid, namespace, key)
+			restOp.ObjName = del_clone.Name
+			rest_ops = append(rest_ops, restOp)
+		}
+	}
+	return rest_ops
+}
+
+func (rest *RestOperations) NetworkSecurityPolicyCU(nsp_nodes []*nodes.AviNetworkSecurityPolicyNode, vs_cache_obj *avicache.AviVsCache, namespace string, rest_ops []*utils.RestOp, key string) ([]avicache.NamespaceName, []*utils.RestOp) {
+	var cache_nsp_nodes []avicache.NamespaceName
+	if vs_cache_obj != nil {
+		cache_nsp_nodes = make([]avicache.NamespaceName, len(vs_cache_obj.NetworkSecurityPolicyCollection))
+		copy(cache_nsp_nodes, vs_cache_obj.NetworkSecurityPolicyCollection)
+	}
+	for _, nsp := range nsp_nodes {
+		nsp_key := avicache.NamespaceName{Namespace: namespace, Name: nsp.Name}
+		cache_nsp_nodes = avicache.RemoveNamespaceName(cache_nsp_nodes, nsp_key)
+		nsp_cache, ok := rest.cache.NetworkSecurityPolicyCache.AviCacheGet(nsp_key)
+		if ok {
+			nsp_cache_obj, _ := nsp_cache.(*avicache.AviNetworkSecurityPolicyCache)
+			// Cache found. Let's compare the checksums
+			if nsp_cache_obj.CloudConfigCksum == nsp.GetCheckSum() {
+				utils.AviLog.Debugf("key: %s, msg: the checksums are same for network security policy %s, not doing anything", key, nsp_cache_obj.Name)
+				continue
+			}
+			// The checksums are different, so it should be a PUT call.
+			restOp := rest.AviNetworkSecurityPolicyBuild(nsp, nsp_cache_obj, key)
+			if restOp != nil {
+				rest_ops = append(rest_ops, restOp)
+			}
+		} else {
+			// Not found - it should be a POST call.
+			restOp := rest.AviNetworkSecurityPolicyBuild(nsp, nil, key)
+			if restOp != nil {
+				rest_ops = append(rest_ops, restOp)
+			}
+		}
+	}
+	utils.AviLog.Debugf("key: %s, msg: the network security policies to be deleted are: %s", key, cache_nsp_nodes)
+	return cache_nsp_nodes, rest_ops
+}
+
+func (rest *RestOperations) NetworkSecurityPolicyDelete(nsp_to_delete []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
+	for _, del_nsp := range nsp_to_delete {
+		nsp_key := avicache.NamespaceName{Namespace: namespace, Name: del_nsp.Name}
+		nsp_cache, ok := rest.cache.NetworkSecurityPolicyCache.AviCacheGet(nsp_key)
+		if ok {
+			nsp_cache_obj, _ := nsp_cache.(*avicache.AviNetworkSecurityPolicyCache)
+			restOp := rest.AviNetworkSecurityPolicyDel(nsp_cache_obj.Uuid, namespace, key)
+			restOp.ObjName = del_nsp.Name
+			rest_ops = append(rest_ops, restOp)
+		}
+	}
+	return rest_ops
+}
+
 func (rest *RestOperations) KeyCertCU(sslkey_nodes []*nodes.AviTLSKeyCertNode, certKeys []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) ([]avicache.NamespaceName, []*utils.RestOp) {
 	// Default is POST
 	var cache_ssl_nodes []avicache.NamespaceName
@@ -1834,6 +2227,82 @@ func (rest *RestOperations) PkiProfileCU(pki_node *nodes.AviPkiProfileNode, pool
 	return cache_pki_nodes, rest_ops
 }
 
+// PersistenceProfileCU returns the rest operation to create or update the persistence profile of the
+// pool, and the cached persistence profile of the pool if the pool no longer refers to it.
+func (rest *RestOperations) PersistenceProfileCU(persistence_node *nodes.AviPersistenceProfileNode, pool_cache_obj *avicache.AviPoolCache, namespace string, rest_ops []*utils.RestOp, key string) ([]avicache.NamespaceName, []*utils.RestOp) {
+	var cache_persistence_nodes []avicache.NamespaceName
+	if pool_cache_obj != nil && pool_cache_obj.PersistenceProfileCollection.Name != "" {
+		cache_persistence_nodes = []avicache.NamespaceName{pool_cache_obj.PersistenceProfileCollection}
+	}
+	if persistence_node == nil {
+		return cache_persistence_nodes, rest_ops
+	}
+	// The pool referring to the persistence profile is created or updated along with the profile.
+	rest.lockPersistenceProfiles()
+	persistence_key := avicache.NamespaceName{Namespace: namespace, Name: persistence_node.Name}
+	cache_persistence_nodes = avicache.RemoveNamespaceName(cache_persistence_nodes, persistence_key)
+	// The persistence profile is shared across pools, and is created or updated once for all of them.
+	for _, restOp := range rest_ops {
+		if restOp.Model == "ApplicationPersistenceProfile" && restOp.ObjName == persistence_node.Name && restOp.Method != utils.RestDelete {
+			return cache_persistence_nodes, rest_ops
+		}
+	}
+	persistence_cache, ok := rest.cache.PersistenceProfileCache.AviCacheGet(persistence_key)
+	if ok {
+		persistence_cache_obj, _ := persistence_cache.(*avicache.AviPersistenceProfileCache)
+		// Cache found. Let's compare the checksums
+		if persistence_cache_obj.CloudConfigCksum == persistence_node.GetCheckSum() {
+			utils.AviLog.Debugf("key: %s, msg: the checksums are same for persistence profile %s, not doing anything", key, persistence_cache_obj.Name)
+			return cache_persistence_nodes, rest_ops
+		}
+		// The checksums are different, so it should be a PUT call.
+		restOp := rest.AviPersistenceProfileBuild(persistence_node, persistence_cache_obj, key)
+		if restOp != nil {
+			rest_ops = append(rest_ops, restOp)
+		}
+	} else {
+		// Not found - it should be a POST call.
+		restOp := rest.AviPersistenceProfileBuild(persistence_node, nil, key)
+		if restOp != nil {
+			rest_ops = append(rest_ops, restOp)
+		}
+	}
+	return cache_persistence_nodes, rest_ops
+}
+
+func (rest *RestOperations) PersistenceProfileDelete(persistence_to_delete []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
+	utils.AviLog.Debugf("key: %s, msg: about to delete persistence profiles %s", key, utils.Stringify(persistence_to_delete))
+	for _, del_persistence := range persistence_to_delete {
+		persistence_key := avicache.NamespaceName{Namespace: namespace, Name: del_persistence.Name}
+		persistence_cache, ok := rest.cache.PersistenceProfileCache.AviCacheGet(persistence_key)
+		if ok {
+			persistence_cache_obj, _ := persistence_cache.(*avicache.AviPersistenceProfileCache)
+			restOp := rest.AviPersistenceProfileDel(persistence_cache_obj.Uuid, namespace, key)
+			restOp.ObjName = del_persistence.Name
+			rest_ops = append(rest_ops, restOp)
+		}
+	}
+	return rest_ops
+}
+
+// persistenceProfileInUse checks whether a cached pool, other than the given ones, refers to the persistence profile.
+func (rest *RestOperations) persistenceProfileInUse(persistence_key avicache.NamespaceName, skip_pools []avicache.NamespaceName) bool {
+	for _, pool_key := range rest.cache.PoolCache.AviGetAllKeys() {
+		if pool_key.Namespace != persistence_key.Namespace || utils.HasElem(skip_pools, pool_key) {
+			continue
+		}
+		pool_cache, ok := rest.cache.PoolCache.AviCacheGet(pool_key)
+		if !ok {
+			continue
+		}
+		pool_cache_obj, _ := pool_cache.(*avicache.AviPoolCache)
+		if pool_cache_obj.PersistenceProfileCollection.Name == persistence_key.Name {
+			return true
+		}
+	}
+	return false
+}
+
 func (rest *RestOperations) PkiProfileDelete(pkiProfileDelete []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
 	utils.AviLog.Debugf("key: %s, msg: about to delete pki profile %s", key, utils.Stringify(pkiProfileDelete))
 	for _, delPki := range pkiProfileDelete {
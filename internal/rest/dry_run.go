@@ -0,0 +1,299 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/vmware/alb-sdk/go/clients"
+	"github.com/vmware/alb-sdk/go/session"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+const (
+	PlanCreate = "create"
+	PlanUpdate = "update"
+	PlanDelete = "delete"
+)
+
+// Fields which are set by the controller, and never by AKO.
+var planIgnoredFields = map[string]struct{}{
+	"uuid":           {},
+	"url":            {},
+	"_last_modified": {},
+}
+
+// FieldDiff is a field of an object whose value in the controller differs from the one AKO
+// would set. The Field is the JSON path of the field, e.g. servers[0].ip.addr.
+type FieldDiff struct {
+	Field   string      `json:"field"`
+	Current interface{} `json:"current,omitempty"`
+	Desired interface{} `json:"desired,omitempty"`
+}
+
+// PlanEntry is a change AKO would make to an Avi object for a model.
+type PlanEntry struct {
+	Model      string      `json:"model"`
+	Action     string      `json:"action"`
+	ObjectType string      `json:"object_type"`
+	Tenant     string      `json:"tenant"`
+	Name       string      `json:"name,omitempty"`
+	Uuid       string      `json:"uuid,omitempty"`
+	PatchOp    string      `json:"patch_op,omitempty"`
+	Diff       []FieldDiff `json:"diff,omitempty"`
+}
+
+// Plan holds the changes computed in the dry-run mode, per model. The cache is not updated in
+// the dry-run mode, so every sync of a model computes all its pending changes again and
+// replaces the ones recorded earlier for it.
+type Plan struct {
+	lock    sync.RWMutex
+	entries map[string][]PlanEntry
+}
+
+var sharedPlan *Plan
+var planOnce sync.Once
+
+func NewPlan() *Plan {
+	return &Plan{entries: make(map[string][]PlanEntry)}
+}
+
+// SharedPlan returns the plan of the dry-run mode of AKO.
+func SharedPlan() *Plan {
+	planOnce.Do(func() {
+		sharedPlan = NewPlan()
+	})
+	return sharedPlan
+}
+
+func (p *Plan) reset(model string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	delete(p.entries, model)
+}
+
+func (p *Plan) add(model string, entries ...PlanEntry) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.entries[model] = append(p.entries[model], entries...)
+}
+
+// Entries returns the changes ordered by model, and in the order in which they would be made
+// for a model.
+func (p *Plan) Entries() []PlanEntry {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	models := make([]string, 0, len(p.entries))
+	for model := range p.entries {
+		models = append(models, model)
+	}
+	sort.Strings(models)
+	entries := []PlanEntry{}
+	for _, model := range models {
+		entries = append(entries, p.entries[model]...)
+	}
+	return entries
+}
+
+// WriteFile writes the plan to the file as JSON. The file is replaced in a single step, so that
+// a reader never sees a partially written plan.
+func (p *Plan) WriteFile(path string) error {
+	data, err := json.MarshalIndent(p.Entries(), "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), path)
+}
+
+// NewRestPlanner returns a rest layer which records the changes for the models in the plan,
+// without making them. The changes are computed against the cache, e.g. one loaded from a
+// snapshot by avicache.ReadCacheSnapshot. The clients are used only to get the objects to be
+// updated, the updates are recorded without a diff when no client is passed.
+func NewRestPlanner(cache *avicache.AviObjCache, aviRestPoolClient *utils.AviRestClientPool, plan *Plan) *RestOperations {
+	if aviRestPoolClient == nil {
+		aviRestPoolClient = &utils.AviRestClientPool{}
+	}
+	restOp := &RestOperations{
+		cache:             cache,
+		aviRestPoolClient: aviRestPoolClient,
+		plan:              plan,
+	}
+	restOp.restOperator = NewRestOperator(restOp, true)
+	return restOp
+}
+
+// recordPlan records the rest ops in the plan in place of executing them. The current state of
+// the objects to be updated is fetched from the controller, if a client is available, to report
+// the fields which would change.
+func (rest *RestOperations) recordPlan(c *clients.AviClient, restOps []*utils.RestOp, key string) {
+	var entries []PlanEntry
+	for _, op := range restOps {
+		entry := PlanEntry{
+			Model:      key,
+			ObjectType: op.Model,
+			Tenant:     op.Tenant,
+			Name:       op.ObjName,
+		}
+		var desired map[string]interface{}
+		if op.Obj != nil {
+			if data, err := json.Marshal(op.Obj); err == nil {
+				json.Unmarshal(data, &desired)
+			}
+			if name, ok := desired["name"].(string); ok {
+				entry.Name = name
+			}
+		}
+		switch op.Method {
+		case utils.RestPost:
+			entry.Action = PlanCreate
+		case utils.RestPut, utils.RestPatch:
+			entry.Action = PlanUpdate
+			entry.Uuid = uuidFromPath(op.Path)
+			entry.PatchOp = op.PatchOp
+			if op.Method == utils.RestPatch {
+				// The patch carries only the fields to be added, replaced or deleted.
+				entry.Diff = diffAviObject("", desired, nil)
+			} else if current := rest.currentObject(c, op, key); current != nil {
+				entry.Diff = diffAviObject("", desired, current)
+			}
+		case utils.RestDelete:
+			entry.Action = PlanDelete
+			entry.Uuid = uuidFromPath(op.Path)
+		default:
+			continue
+		}
+		utils.AviLog.Infof("key: %s, msg: dry-run, %s %s %s/%s", key, entry.Action, entry.ObjectType, entry.Tenant, entry.Name)
+		entries = append(entries, entry)
+	}
+	rest.plan.add(key, entries...)
+}
+
+func (rest *RestOperations) currentObject(c *clients.AviClient, op *utils.RestOp, key string) map[string]interface{} {
+	if c == nil {
+		return nil
+	}
+	session.SetTenant(op.Tenant)(c.AviSession)
+	var current map[string]interface{}
+	if err := c.AviSession.Get(op.Path, &current); err != nil {
+		utils.AviLog.Warnf("key: %s, msg: dry-run, failed to get %s %s, err: %v", key, op.Model, op.ObjName, err)
+		return nil
+	}
+	return current
+}
+
+// writePlan writes the plan to the file configured for the dry-run mode.
+func (rest *RestOperations) writePlan(key string) {
+	planFile := lib.GetDryRunPlanFile()
+	if rest.plan != SharedPlan() || planFile == "" {
+		return
+	}
+	if err := rest.plan.WriteFile(planFile); err != nil {
+		utils.AviLog.Warnf("key: %s, msg: dry-run, failed to write the plan to %s, err: %v", key, planFile, err)
+	}
+}
+
+func uuidFromPath(path string) string {
+	path = strings.SplitN(path, "?", 2)[0]
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// refName returns the name of the object referred by an Avi ref. AKO sets the refs by name,
+// e.g. /api/pool/?name=pool1, and the controller returns them by uuid with the name appended,
+// e.g. https://host/api/pool/pool-uuid#pool1.
+func refName(ref string) string {
+	if i := strings.LastIndex(ref, "#"); i != -1 {
+		return ref[i+1:]
+	}
+	if i := strings.LastIndex(ref, "name="); i != -1 {
+		return strings.SplitN(ref[i+len("name="):], "&", 2)[0]
+	}
+	return ref
+}
+
+func isRefField(field string) bool {
+	field = strings.TrimRight(field, "]0123456789")
+	field = strings.TrimSuffix(field, "[")
+	return strings.HasSuffix(field, "_ref") || strings.HasSuffix(field, "_refs")
+}
+
+// diffAviObject compares the object AKO would set with the one in the controller. Only the fields
+// set by AKO are compared, the fields which are not set by AKO carry the controller defaults.
+func diffAviObject(field string, desired, current interface{}) []FieldDiff {
+	var diffs []FieldDiff
+	switch desiredVal := desired.(type) {
+	case map[string]interface{}:
+		currentVal, ok := current.(map[string]interface{})
+		if !ok && current != nil {
+			return []FieldDiff{{Field: field, Current: current, Desired: desired}}
+		}
+		keys := make([]string, 0, len(desiredVal))
+		for k := range desiredVal {
+			if _, ignored := planIgnoredFields[k]; !ignored {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			childField := k
+			if field != "" {
+				childField = field + "." + k
+			}
+			diffs = append(diffs, diffAviObject(childField, desiredVal[k], currentVal[k])...)
+		}
+	case []interface{}:
+		currentVal, ok := current.([]interface{})
+		if !ok || len(currentVal) != len(desiredVal) {
+			return []FieldDiff{{Field: field, Current: current, Desired: desired}}
+		}
+		for i := range desiredVal {
+			diffs = append(diffs, diffAviObject(fmt.Sprintf("%s[%d]", field, i), desiredVal[i], currentVal[i])...)
+		}
+	case string:
+		currentVal, ok := current.(string)
+		if ok && isRefField(field) && refName(desiredVal) == refName(currentVal) {
+			return nil
+		}
+		if !ok || currentVal != desiredVal {
+			return []FieldDiff{{Field: field, Current: current, Desired: desired}}
+		}
+	default:
+		if !reflect.DeepEqual(desired, current) {
+			return []FieldDiff{{Field: field, Current: current, Desired: desired}}
+		}
+	}
+	return diffs
+}
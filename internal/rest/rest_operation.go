@@ -269,6 +269,14 @@ func (l *leader) isRetryRequired(key string, err error) bool {
 }
 
 func (l *leader) AviRestOperate(c *clients.AviClient, rest_ops []*utils.RestOp, key string) error {
+	// In the transactional mode, the inverse of every successful operation is recorded,
+	// so that a partially applied model can be rolled back when a later operation fails.
+	var journal *utils.RestOpJournal
+	if lib.IsTransactionalRestApplyEnabled() {
+		journal = utils.NewRestOpJournal()
+	} else {
+		utils.ResetRestOpJournal()
+	}
 	for i, op := range rest_ops {
 		// This condition check is introduced to prevent any keys which is already present in the Graph
 		// Queue from doing any POST/PUT/PATCH/GET operations at the controller when the `deleteConfig` is set.
@@ -285,6 +293,12 @@ func (l *leader) AviRestOperate(c *clients.AviClient, rest_ops []*utils.RestOp,
 			SetVersion := session.SetVersion(op.Version)
 			SetVersion(c.AviSession)
 		}
+		// The last operation is never rolled back, no later operation of the batch can fail.
+		var prior map[string]interface{}
+		if journal != nil && i < len(rest_ops)-1 {
+			prior = journal.Snapshot(c, op, key)
+		}
+		start := time.Now()
 		switch op.Method {
 		case utils.RestPost:
 			op.Err = c.AviSession.Post(op.Path, op.Obj, &op.Response)
@@ -301,6 +315,7 @@ func (l *leader) AviRestOperate(c *clients.AviClient, rest_ops []*utils.RestOp,
 			utils.AviLog.Errorf("Unknown RestOp %v", op.Method)
 			op.Err = fmt.Errorf("Unknown RestOp %v", op.Method)
 		}
+		lib.ObserveAviRestOp(string(op.Method), op.Model, start, op.Err)
 		if op.Err != nil {
 			utils.AviLog.Warnf("key: %s, msg: RestOp method %v path %v tenant %v Obj %s returned err %s with response %s",
 				key, op.Method, op.Path, op.Tenant, utils.Stringify(op.Obj), utils.Stringify(op.Err), utils.Stringify(op.Response))
@@ -316,7 +331,9 @@ func (l *leader) AviRestOperate(c *clients.AviClient, rest_ops []*utils.RestOp,
 				continue
 			} else if op.Model == "VrfContext" && aviErr.HttpStatusCode == 412 {
 				utils.AviLog.Debugf("key: %s, msg: Error in rest operation for VrfContext Put request.", key)
-			} else if !isErrorRetryable(aviErr.HttpStatusCode, *aviErr.Message) {
+			} else if !isErrorRetryable(aviErr.HttpStatusCode, *aviErr.Message) && journal == nil {
+				// In the transactional mode, the batch is not applied partially, the failure
+				// aborts the remaining operations and rolls back the executed ones below.
 				if op.Method != utils.RestPost {
 					continue
 				}
@@ -328,10 +345,22 @@ func (l *leader) AviRestOperate(c *clients.AviClient, rest_ops []*utils.RestOp,
 			for j := i + 1; j < len(rest_ops); j++ {
 				rest_ops[j].Err = errors.New("Aborted due to prev error")
 			}
+			if journal != nil && journal.Len() > 0 {
+				utils.AviLog.Warnf("key: %s, msg: rolling back %d rest operations", key, journal.Len())
+				if rollbackErr := journal.Rollback(c, key); rollbackErr != nil {
+					utils.AviLog.Warnf("key: %s, msg: rollback of the rest operations failed, err: %v", key, rollbackErr)
+					lib.IncrementRestOpRollbackCounter(lib.RollbackFailed)
+				} else {
+					lib.IncrementRestOpRollbackCounter(lib.RollbackSucceeded)
+				}
+			}
 			return err
 		} else {
 			utils.AviLog.Debugf("key: %s, msg: RestOp method %v path %v tenant %v response %v objName %v",
 				key, op.Method, op.Path, op.Tenant, utils.Stringify(op.Response), op.ObjName)
+			if journal != nil {
+				journal.Record(op, prior, key)
+			}
 		}
 	}
 	return nil
@@ -377,7 +406,9 @@ func (f *follower) AviRestOperate(c *clients.AviClient, rest_ops []*utils.RestOp
 		}
 
 		utils.AviLog.Debugf("key: %s, msg: Got a REST operation: %s, %s", key, op.ObjName, op.Path)
+		start := time.Now()
 		op.Err = c.AviSession.Get(op.Path, &op.Response)
+		lib.ObserveAviRestOp(string(utils.RestGet), op.Model, start, op.Err)
 		if op.Err != nil {
 			utils.AviLog.Warnf("key: %s, msg: RestOp method %v path %v tenant %v Obj %s returned err %s with response %s",
 				key, op.Method, op.Path, op.Tenant, utils.Stringify(op.Obj), utils.Stringify(op.Err), utils.Stringify(op.Response))
@@ -0,0 +1,487 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package status
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	akov1alpha2 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha2"
+	akov1beta1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1beta1"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	AviObjectVirtualService = "VirtualService"
+	AviObjectPool           = "Pool"
+
+	// maxAppliedTo is the number of Avi objects listed in the appliedTo of a CRD status, the
+	// Programmed condition carries the number of all the Avi objects the CRD is applied to.
+	maxAppliedTo = 100
+)
+
+type aviObjectRef struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	UUID string `json:"uuid,omitempty"`
+}
+
+// crdStatusState is the status last written to a CRD. The status patches replace the lists
+// in the status as a whole, and the CRD in the informer cache can lag behind the patches,
+// so the conditions and the Avi objects the CRD is applied to are tracked here.
+type crdStatusState struct {
+	uid                types.UID
+	status             string
+	err                string
+	observedGeneration int64
+	conditions         []metav1.Condition
+	appliedTo          []aviObjectRef
+	// trafficSplit is tracked only for the HTTPRules.
+	trafficSplit    []akov1beta1.HTTPRuleTrafficSplit
+	hasTrafficSplit bool
+	// version is incremented on every change of the status, so that a patch which raced with
+	// a change of the status is followed by the patch of the latest status.
+	version int
+}
+
+var crdStatusStore = struct {
+	sync.Mutex
+	states map[string]*crdStatusState
+}{states: make(map[string]*crdStatusState)}
+
+// crdStatusKey returns the key of a CRD in the status store, objType/namespace/name, or
+// objType/name for the cluster scoped CRDs.
+func crdStatusKey(objType string, obj metav1.Object) string {
+	if obj.GetNamespace() == "" {
+		return objType + "/" + obj.GetName()
+	}
+	return objType + "/" + obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// ForgetCRDStatus drops the status tracked for a CRD, once the CRD is deleted.
+func ForgetCRDStatus(objType string, obj metav1.Object) {
+	crdStatusStore.Lock()
+	defer crdStatusStore.Unlock()
+	delete(crdStatusStore.states, crdStatusKey(objType, obj))
+}
+
+func newCRDStatusState(obj interface{}) (metav1.Object, *crdStatusState) {
+	state := &crdStatusState{}
+	var crdObj metav1.Object
+	var appliedTo interface{}
+	switch crd := obj.(type) {
+	case *akov1beta1.HostRule:
+		crdObj, appliedTo = crd, crd.Status.AppliedTo
+		state.status, state.err, state.observedGeneration, state.conditions = crd.Status.Status, crd.Status.Error, crd.Status.ObservedGeneration, crd.Status.Conditions
+	case *akov1beta1.HTTPRule:
+		crdObj, appliedTo = crd, crd.Status.AppliedTo
+		state.status, state.err, state.observedGeneration, state.conditions = crd.Status.Status, crd.Status.Error, crd.Status.ObservedGeneration, crd.Status.Conditions
+		state.trafficSplit, state.hasTrafficSplit = crd.Status.TrafficSplit, true
+	case *akov1beta1.AviInfraSetting:
+		crdObj, appliedTo = crd, crd.Status.AppliedTo
+		state.status, state.err, state.observedGeneration, state.conditions = crd.Status.Status, crd.Status.Error, crd.Status.ObservedGeneration, crd.Status.Conditions
+	case *akov1alpha2.SSORule:
+		crdObj, appliedTo = crd, crd.Status.AppliedTo
+		state.status, state.err, state.observedGeneration, state.conditions = crd.Status.Status, crd.Status.Error, crd.Status.ObservedGeneration, crd.Status.Conditions
+	case *akov1alpha2.L4Rule:
+		crdObj, appliedTo = crd, crd.Status.AppliedTo
+		state.status, state.err, state.observedGeneration, state.conditions = crd.Status.Status, crd.Status.Error, crd.Status.ObservedGeneration, crd.Status.Conditions
+	case *akov1alpha2.L7Rule:
+		crdObj, appliedTo = crd, crd.Status.AppliedTo
+		state.status, state.err, state.observedGeneration, state.conditions = crd.Status.Status, crd.Status.Error, crd.Status.ObservedGeneration, crd.Status.Conditions
+	default:
+		return nil, nil
+	}
+	state.uid = crdObj.GetUID()
+	state = state.copy()
+	// The AviObjectRef types of the API versions share the json encoding.
+	if data, err := json.Marshal(appliedTo); err == nil {
+		json.Unmarshal(data, &state.appliedTo)
+	}
+	return crdObj, state
+}
+
+func (s *crdStatusState) copy() *crdStatusState {
+	c := *s
+	if s.conditions != nil {
+		c.conditions = make([]metav1.Condition, len(s.conditions))
+		for i := range s.conditions {
+			s.conditions[i].DeepCopyInto(&c.conditions[i])
+		}
+	}
+	if s.appliedTo != nil {
+		c.appliedTo = append([]aviObjectRef{}, s.appliedTo...)
+	}
+	if s.trafficSplit != nil {
+		c.trafficSplit = make([]akov1beta1.HTTPRuleTrafficSplit, len(s.trafficSplit))
+		for i := range s.trafficSplit {
+			s.trafficSplit[i].DeepCopyInto(&c.trafficSplit[i])
+		}
+	}
+	return &c
+}
+
+func (s *crdStatusState) setCondition(conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&s.conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: s.observedGeneration,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+func (s *crdStatusState) setProgrammedCondition() {
+	if len(s.appliedTo) == 0 {
+		s.setCondition(lib.CRDConditionProgrammed, metav1.ConditionFalse, lib.CRDReasonNotApplied, "The configuration is not applied to any Avi object")
+		return
+	}
+	s.setCondition(lib.CRDConditionProgrammed, metav1.ConditionTrue, lib.CRDReasonProgrammed,
+		fmt.Sprintf("The configuration is applied to %d Avi object(s)", len(s.appliedTo)))
+}
+
+// setValidated records the outcome of the validation of the given generation of the CRD.
+func (s *crdStatusState) setValidated(generation int64, updateStatus UpdateCRDStatusOptions) {
+	if generation != s.observedGeneration || updateStatus.Status != lib.StatusAccepted {
+		// The traffic split is reported again as the Ingresses are synced with the new spec.
+		s.trafficSplit = nil
+	}
+	s.status, s.err, s.observedGeneration = updateStatus.Status, updateStatus.Error, generation
+	if updateStatus.Status == lib.StatusAccepted {
+		s.setCondition(lib.CRDConditionAccepted, metav1.ConditionTrue, lib.CRDReasonAccepted, "The object is valid")
+		s.setCondition(lib.CRDConditionResolvedRefs, metav1.ConditionTrue, lib.CRDReasonResolvedRefs, "All the references are resolved")
+	} else {
+		s.setCondition(lib.CRDConditionAccepted, metav1.ConditionFalse, lib.CRDReasonInvalid, updateStatus.Error)
+		if updateStatus.UnresolvedRefs {
+			s.setCondition(lib.CRDConditionResolvedRefs, metav1.ConditionFalse, lib.CRDReasonInvalidRef, updateStatus.Error)
+		} else {
+			s.setCondition(lib.CRDConditionResolvedRefs, metav1.ConditionUnknown, lib.CRDReasonInvalid, "The references are not resolved since the object is invalid")
+		}
+	}
+	// Programmed is updated when the Avi objects are synced. Till then, an object which is not
+	// applied yet is pending if it is accepted, and will not get applied if it is rejected.
+	if len(s.appliedTo) != 0 {
+		for i := range s.conditions {
+			s.conditions[i].ObservedGeneration = generation
+		}
+	} else if updateStatus.Status == lib.StatusAccepted {
+		s.setCondition(lib.CRDConditionProgrammed, metav1.ConditionUnknown, lib.CRDReasonPending, "Waiting for the configuration to be applied to the Avi objects")
+	} else {
+		s.setProgrammedCondition()
+	}
+}
+
+// setApplied adds or removes an Avi object from the objects the CRD is applied to.
+func (s *crdStatusState) setApplied(ref aviObjectRef, applied bool) {
+	for i := range s.appliedTo {
+		if s.appliedTo[i].Type == ref.Type && s.appliedTo[i].Name == ref.Name {
+			if applied {
+				s.appliedTo[i].UUID = ref.UUID
+			} else {
+				s.appliedTo = append(s.appliedTo[:i], s.appliedTo[i+1:]...)
+			}
+			s.setProgrammedCondition()
+			return
+		}
+	}
+	if !applied {
+		return
+	}
+	s.appliedTo = append(s.appliedTo, ref)
+	sort.Slice(s.appliedTo, func(i, j int) bool {
+		if s.appliedTo[i].Type != s.appliedTo[j].Type {
+			return s.appliedTo[i].Type < s.appliedTo[j].Type
+		}
+		return s.appliedTo[i].Name < s.appliedTo[j].Name
+	})
+	s.setProgrammedCondition()
+}
+
+// setTrafficSplit replaces the traffic split of a path of an Ingress, the split is removed if it
+// has no backends.
+func (s *crdStatusState) setTrafficSplit(split akov1beta1.HTTPRuleTrafficSplit) {
+	for i := range s.trafficSplit {
+		if s.trafficSplit[i].Target == split.Target && s.trafficSplit[i].Ingress == split.Ingress {
+			s.trafficSplit = append(s.trafficSplit[:i], s.trafficSplit[i+1:]...)
+			break
+		}
+	}
+	if len(split.Backends) == 0 {
+		return
+	}
+	s.trafficSplit = append(s.trafficSplit, split)
+	sort.Slice(s.trafficSplit, func(i, j int) bool {
+		if s.trafficSplit[i].Target != s.trafficSplit[j].Target {
+			return s.trafficSplit[i].Target < s.trafficSplit[j].Target
+		}
+		return s.trafficSplit[i].Ingress < s.trafficSplit[j].Ingress
+	})
+}
+
+func (s *crdStatusState) patchPayload() []byte {
+	status := map[string]interface{}{
+		"error":              s.err,
+		"observedGeneration": s.observedGeneration,
+		"conditions":         s.conditions,
+		"appliedTo":          s.appliedTo,
+	}
+	if s.status != "" {
+		status["status"] = s.status
+	}
+	if len(s.appliedTo) == 0 {
+		status["appliedTo"] = nil
+	} else if len(s.appliedTo) > maxAppliedTo {
+		status["appliedTo"] = s.appliedTo[:maxAppliedTo]
+	}
+	if s.hasTrafficSplit {
+		status["trafficSplit"] = s.trafficSplit
+		if len(s.trafficSplit) == 0 {
+			status["trafficSplit"] = nil
+		}
+	}
+	patchPayload, _ := json.Marshal(map[string]interface{}{
+		"status": status,
+	})
+	return patchPayload
+}
+
+// patchCRDStatus applies update on the status of the CRD, and patches the CRD status if it
+// changed. The status is patched outside of the lock of the store. If the status is changed
+// while it is patched, the CRD is published to the status queue, to patch the latest status.
+func patchCRDStatus(objType string, obj interface{}, update func(*crdStatusState), patch func([]byte) error) error {
+	statusKey, payload, version := updateCRDStatusState(objType, obj, update)
+	if payload == nil {
+		return nil
+	}
+	if err := patch(payload); err != nil {
+		return err
+	}
+	crdStatusStore.Lock()
+	current, ok := crdStatusStore.states[statusKey]
+	changed := ok && current.version != version
+	crdStatusStore.Unlock()
+	if changed {
+		publishCRDStatus(statusKey, statusKey)
+	}
+	return nil
+}
+
+// updateCRDStatusState applies update on the status of the CRD in the store. It returns the
+// status key of the CRD, and the patch payload along with the version of the status, if the
+// status of the CRD is to be patched.
+func updateCRDStatusState(objType string, obj interface{}, update func(*crdStatusState)) (string, []byte, int) {
+	crdObj, seed := newCRDStatusState(obj)
+	if crdObj == nil {
+		utils.AviLog.Warnf("msg: unsupported object type %T for the CRD status", obj)
+		return "", nil, 0
+	}
+	statusKey := crdStatusKey(objType, crdObj)
+
+	crdStatusStore.Lock()
+	defer crdStatusStore.Unlock()
+	current, ok := crdStatusStore.states[statusKey]
+	if !ok || current.uid != seed.uid {
+		current = seed
+	}
+	next := current.copy()
+	update(next)
+	// The patch is skipped only if the status is unchanged, and the status of the object
+	// is the one last written. The patch payloads are compared, since the condition times
+	// of the object are truncated to seconds. A failed patch is hence retried on the next
+	// update, as the object keeps the old status.
+	payload := next.patchPayload()
+	if !bytes.Equal(payload, current.patchPayload()) {
+		next.version++
+	}
+	crdStatusStore.states[statusKey] = next
+	if bytes.Equal(payload, current.patchPayload()) && bytes.Equal(payload, seed.patchPayload()) {
+		return statusKey, nil, next.version
+	}
+	return statusKey, payload, next.version
+}
+
+// publishCRDStatus publishes a CRD to the status queue, to patch the status tracked for it.
+func publishCRDStatus(key, crd string) {
+	PublishToStatusQueue(crd, StatusOptions{
+		ObjType: strings.SplitN(crd, "/", 2)[0],
+		Op:      lib.UpdateStatus,
+		ObjName: crd,
+		Key:     key,
+	})
+}
+
+// UpdateCRDStatus patches the status tracked for a CRD. It is called by the status queue.
+func (l *leader) UpdateCRDStatus(key, crd string) error {
+	obj, patch := getCRDForStatus(crd)
+	if obj == nil {
+		return nil
+	}
+	objType := strings.SplitN(crd, "/", 2)[0]
+	err := patchCRDStatus(objType, obj, func(s *crdStatusState) {}, patch)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: failed to update the status of %s: %v", key, crd, err)
+	}
+	return err
+}
+
+// appliedCRDs returns the status keys of the CRDs applied to an Avi object, as recorded in its
+// service metadata.
+func appliedCRDs(metadata lib.ServiceMetadataObj) []string {
+	var crds []string
+	if metadata.CRDStatus.Status == lib.CRDActive && metadata.CRDStatus.Value != "" {
+		nsName := strings.SplitN(metadata.CRDStatus.Value, "/", 3)
+		if len(nsName) >= 2 {
+			crds = append(crds, metadata.CRDStatus.Type+"/"+nsName[0]+"/"+nsName[1])
+		}
+	}
+	if metadata.L7Rule != "" {
+		crds = append(crds, lib.L7Rule+"/"+metadata.L7Rule)
+	}
+	// The AviInfraSetting is recorded only in the metadata of the Virtual Services, hence its
+	// status lists the Virtual Services and not their pools.
+	if metadata.AviInfraSetting != "" {
+		crds = append(crds, lib.AviInfraSetting+"/"+metadata.AviInfraSetting)
+	}
+	return crds
+}
+
+// UpdateCRDAppliedTo updates the status of the CRDs applied to an Avi object, when the Avi object
+// is added, updated or deleted. oldMetadata is the service metadata of the Avi object in the cache,
+// and newMetadata is the one after the change, which is empty for a delete. The CRDs whose status
+// changed are published to the status queue, which patches them.
+func UpdateCRDAppliedTo(key, aviObjType, name, uuid string, oldMetadata, newMetadata lib.ServiceMetadataObj) {
+	oldCRDs, newCRDs := appliedCRDs(oldMetadata), appliedCRDs(newMetadata)
+	ref := aviObjectRef{Type: aviObjType, Name: name, UUID: uuid}
+	for _, crd := range newCRDs {
+		updateCRDAppliedTo(key, crd, ref, true)
+	}
+	for _, crd := range oldCRDs {
+		if !utils.HasElem(newCRDs, crd) {
+			updateCRDAppliedTo(key, crd, ref, false)
+		}
+	}
+}
+
+// UpdateHTTPRuleTrafficSplit updates the traffic split of a path of an Ingress in the status of
+// the HTTPRule which sets the alternate backends of the path.
+func UpdateHTTPRuleTrafficSplit(key string, rr *akov1beta1.HTTPRule, split akov1beta1.HTTPRuleTrafficSplit) {
+	crd := lib.HTTPRule + "/" + rr.Namespace + "/" + rr.Name
+	obj, _ := getCRDForStatus(crd)
+	if obj == nil {
+		return
+	}
+	if _, payload, _ := updateCRDStatusState(lib.HTTPRule, obj, func(s *crdStatusState) { s.setTrafficSplit(split) }); payload != nil {
+		publishCRDStatus(key, crd)
+	}
+}
+
+func updateCRDAppliedTo(key, crd string, ref aviObjectRef, applied bool) {
+	obj, _ := getCRDForStatus(crd)
+	if obj == nil {
+		return
+	}
+	objType := strings.SplitN(crd, "/", 2)[0]
+	if _, payload, _ := updateCRDStatusState(objType, obj, func(s *crdStatusState) { s.setApplied(ref, applied) }); payload != nil {
+		publishCRDStatus(key, crd)
+	}
+}
+
+// getCRDForStatus returns the CRD of a status key from the informer cache, along with the
+// function to patch the CRD status.
+func getCRDForStatus(crd string) (interface{}, func([]byte) error) {
+	informers := lib.AKOControlConfig().CRDInformers()
+	if informers == nil {
+		return nil, nil
+	}
+	parts := strings.Split(crd, "/")
+	ctx, opts := context.TODO(), metav1.PatchOptions{}
+	if len(parts) == 2 && parts[0] == lib.AviInfraSetting && informers.AviInfraSettingInformer != nil {
+		obj, err := informers.AviInfraSettingInformer.Lister().Get(parts[1])
+		if err != nil {
+			return nil, nil
+		}
+		return obj, func(payload []byte) error {
+			_, err := lib.AKOControlConfig().V1beta1CRDClientset().AkoV1beta1().AviInfraSettings().Patch(ctx, obj.Name, types.MergePatchType, payload, opts, "status")
+			return err
+		}
+	}
+	if len(parts) != 3 {
+		return nil, nil
+	}
+	namespace, name := parts[1], parts[2]
+	switch parts[0] {
+	case lib.HostRule:
+		if informers.HostRuleInformer == nil {
+			return nil, nil
+		}
+		if obj, err := informers.HostRuleInformer.Lister().HostRules(namespace).Get(name); err == nil {
+			return obj, func(payload []byte) error {
+				_, err := lib.AKOControlConfig().V1beta1CRDClientset().AkoV1beta1().HostRules(namespace).Patch(ctx, name, types.MergePatchType, payload, opts, "status")
+				return err
+			}
+		}
+	case lib.HTTPRule:
+		if informers.HTTPRuleInformer == nil {
+			return nil, nil
+		}
+		if obj, err := informers.HTTPRuleInformer.Lister().HTTPRules(namespace).Get(name); err == nil {
+			return obj, func(payload []byte) error {
+				_, err := lib.AKOControlConfig().V1beta1CRDClientset().AkoV1beta1().HTTPRules(namespace).Patch(ctx, name, types.MergePatchType, payload, opts, "status")
+				return err
+			}
+		}
+	case lib.SSORule:
+		if informers.SSORuleInformer == nil {
+			return nil, nil
+		}
+		if obj, err := informers.SSORuleInformer.Lister().SSORules(namespace).Get(name); err == nil {
+			return obj, func(payload []byte) error {
+				_, err := lib.AKOControlConfig().V1alpha2CRDClientset().AkoV1alpha2().SSORules(namespace).Patch(ctx, name, types.MergePatchType, payload, opts, "status")
+				return err
+			}
+		}
+	case lib.L4Rule:
+		if informers.L4RuleInformer == nil {
+			return nil, nil
+		}
+		if obj, err := informers.L4RuleInformer.Lister().L4Rules(namespace).Get(name); err == nil {
+			return obj, func(payload []byte) error {
+				_, err := lib.AKOControlConfig().V1alpha2CRDClientset().AkoV1alpha2().L4Rules(namespace).Patch(ctx, name, types.MergePatchType, payload, opts, "status")
+				return err
+			}
+		}
+	case lib.L7Rule:
+		if informers.L7RuleInformer == nil {
+			return nil, nil
+		}
+		if obj, err := informers.L7RuleInformer.Lister().L7Rules(namespace).Get(name); err == nil {
+			return obj, func(payload []byte) error {
+				_, err := lib.AKOControlConfig().V1alpha2CRDClientset().AkoV1alpha2().L7Rules(namespace).Patch(ctx, name, types.MergePatchType, payload, opts, "status")
+				return err
+			}
+		}
+	}
+	return nil, nil
+}
@@ -16,7 +16,6 @@ package status
 
 import (
 	"context"
-	"encoding/json"
 	"strings"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
@@ -34,6 +33,9 @@ import (
 type UpdateCRDStatusOptions struct {
 	Status string
 	Error  string
+	// UnresolvedRefs is set when the object is rejected since it refers to objects which
+	// could not be found.
+	UnresolvedRefs bool
 }
 
 // UpdateHostRuleStatus HostRule status updates
@@ -47,11 +49,10 @@ func UpdateHostRuleStatus(key string, hr *akov1beta1.HostRule, updateStatus Upda
 		}
 	}
 
-	patchPayload, _ := json.Marshal(map[string]interface{}{
-		"status": akov1beta1.HostRuleStatus(updateStatus),
+	err := patchCRDStatus(lib.HostRule, hr, func(s *crdStatusState) { s.setValidated(hr.Generation, updateStatus) }, func(patchPayload []byte) error {
+		_, err := lib.AKOControlConfig().V1beta1CRDClientset().AkoV1beta1().HostRules(hr.Namespace).Patch(context.TODO(), hr.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+		return err
 	})
-
-	_, err := lib.AKOControlConfig().V1beta1CRDClientset().AkoV1beta1().HostRules(hr.Namespace).Patch(context.TODO(), hr.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
 	if err != nil {
 		utils.AviLog.Errorf("key: %s, msg: there was an error in updating the hostrule status: %+v", key, err)
 		updatedHr, err := lib.AKOControlConfig().CRDInformers().HostRuleInformer.Lister().HostRules(hr.Namespace).Get(hr.Name)
@@ -117,11 +118,10 @@ func UpdateHTTPRuleStatus(key string, rr *akov1beta1.HTTPRule, updateStatus Upda
 		}
 	}
 
-	patchPayload, _ := json.Marshal(map[string]interface{}{
-		"status": akov1beta1.HTTPRuleStatus(updateStatus),
+	err := patchCRDStatus(lib.HTTPRule, rr, func(s *crdStatusState) { s.setValidated(rr.Generation, updateStatus) }, func(patchPayload []byte) error {
+		_, err := lib.AKOControlConfig().V1beta1CRDClientset().AkoV1beta1().HTTPRules(rr.Namespace).Patch(context.TODO(), rr.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+		return err
 	})
-
-	_, err := lib.AKOControlConfig().V1beta1CRDClientset().AkoV1beta1().HTTPRules(rr.Namespace).Patch(context.TODO(), rr.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
 	if err != nil {
 		utils.AviLog.Errorf("key: %s, msg: %d there was an error in updating the httprule status: %+v", key, retry, err)
 		updatedRr, err := lib.AKOControlConfig().CRDInformers().HTTPRuleInformer.Lister().HTTPRules(rr.Namespace).Get(rr.Name)
@@ -188,11 +188,10 @@ func UpdateAviInfraSettingStatus(key string, infraSetting *akov1beta1.AviInfraSe
 		}
 	}
 
-	patchPayload, _ := json.Marshal(map[string]interface{}{
-		"status": akov1beta1.AviInfraSettingStatus(updateStatus),
+	err := patchCRDStatus(lib.AviInfraSetting, infraSetting, func(s *crdStatusState) { s.setValidated(infraSetting.Generation, updateStatus) }, func(patchPayload []byte) error {
+		_, err := lib.AKOControlConfig().V1beta1CRDClientset().AkoV1beta1().AviInfraSettings().Patch(context.TODO(), infraSetting.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+		return err
 	})
-
-	_, err := lib.AKOControlConfig().V1beta1CRDClientset().AkoV1beta1().AviInfraSettings().Patch(context.TODO(), infraSetting.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
 	if err != nil {
 		utils.AviLog.Errorf("key: %s, msg: %d there was an error in updating the aviinfrasetting status: %+v", key, retry, err)
 		updatedInfraSetting, err := lib.AKOControlConfig().CRDInformers().AviInfraSettingInformer.Lister().Get(infraSetting.Name)
@@ -220,11 +219,10 @@ func UpdateL4RuleStatus(key string, l4Rule *akov1alpha2.L4Rule, updateStatus Upd
 		}
 	}
 
-	patchPayload, _ := json.Marshal(map[string]interface{}{
-		"status": akov1alpha2.L4RuleStatus(updateStatus),
+	err := patchCRDStatus(lib.L4Rule, l4Rule, func(s *crdStatusState) { s.setValidated(l4Rule.Generation, updateStatus) }, func(patchPayload []byte) error {
+		_, err := lib.AKOControlConfig().V1alpha2CRDClientset().AkoV1alpha2().L4Rules(l4Rule.Namespace).Patch(context.TODO(), l4Rule.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+		return err
 	})
-
-	_, err := lib.AKOControlConfig().V1alpha2CRDClientset().AkoV1alpha2().L4Rules(l4Rule.Namespace).Patch(context.TODO(), l4Rule.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
 	if err != nil {
 		utils.AviLog.Errorf("key: %s, msg: %d there was an error in updating the L4Rule status: %+v", key, retry, err)
 		updatedL4RuleObj, err := lib.AKOControlConfig().V1alpha2CRDClientset().AkoV1alpha2().L4Rules(l4Rule.Namespace).Get(context.TODO(), l4Rule.Name, metav1.GetOptions{})
@@ -291,11 +289,10 @@ func UpdateSSORuleStatus(key string, sr *akov1alpha2.SSORule, updateStatus Updat
 		}
 	}
 
-	patchPayload, _ := json.Marshal(map[string]interface{}{
-		"status": akov1alpha2.SSORuleStatus(updateStatus),
+	err := patchCRDStatus(lib.SSORule, sr, func(s *crdStatusState) { s.setValidated(sr.Generation, updateStatus) }, func(patchPayload []byte) error {
+		_, err := lib.AKOControlConfig().V1alpha2CRDClientset().AkoV1alpha2().SSORules(sr.Namespace).Patch(context.TODO(), sr.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+		return err
 	})
-
-	_, err := lib.AKOControlConfig().V1alpha2CRDClientset().AkoV1alpha2().SSORules(sr.Namespace).Patch(context.TODO(), sr.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
 	if err != nil {
 		utils.AviLog.Errorf("key: %s, msg: there was an error in updating the SSORule status: %+v", key, err)
 		updatedSr, err := lib.AKOControlConfig().CRDInformers().SSORuleInformer.Lister().SSORules(sr.Namespace).Get(sr.Name)
@@ -361,11 +358,10 @@ func UpdateL7RuleStatus(key string, l7Rule *akov1alpha2.L7Rule, updateStatus Upd
 		}
 	}
 
-	patchPayload, _ := json.Marshal(map[string]interface{}{
-		"status": akov1alpha2.L7RuleStatus(updateStatus),
+	err := patchCRDStatus(lib.L7Rule, l7Rule, func(s *crdStatusState) { s.setValidated(l7Rule.Generation, updateStatus) }, func(patchPayload []byte) error {
+		_, err := lib.AKOControlConfig().V1alpha2CRDClientset().AkoV1alpha2().L7Rules(l7Rule.Namespace).Patch(context.TODO(), l7Rule.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+		return err
 	})
-
-	_, err := lib.AKOControlConfig().V1alpha2CRDClientset().AkoV1alpha2().L7Rules(l7Rule.Namespace).Patch(context.TODO(), l7Rule.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
 	if err != nil {
 		utils.AviLog.Errorf("key: %s, msg: %d there was an error in updating the L7Rule status: %+v", key, retry, err)
 		updatedL7RuleObj, err := lib.AKOControlConfig().V1alpha2CRDClientset().AkoV1alpha2().L7Rules(l7Rule.Namespace).Get(context.TODO(), l7Rule.Name, metav1.GetOptions{})
@@ -87,6 +87,10 @@ func (l *leader) DequeueStatus(objIntf interface{}) error {
 		} else if obj.Op == lib.DeleteStatus {
 			l.DeleteMultiClusterIngressStatusAndAnnotation(obj.Key, obj.Options)
 		}
+	case lib.HostRule, lib.HTTPRule, lib.AviInfraSetting, lib.SSORule, lib.L4Rule, lib.L7Rule:
+		if obj.Op == lib.UpdateStatus {
+			return l.UpdateCRDStatus(obj.Key, obj.ObjName)
+		}
 	}
 	return nil
 }
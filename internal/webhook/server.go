@@ -0,0 +1,170 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	akov1alpha2 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha2"
+	akov1beta1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1beta1"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/gorilla/mux"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ValidateCRDRoute = "/validate-ako-crd"
+	tlsCertFile      = "tls.crt"
+	tlsKeyFile       = "tls.key"
+)
+
+// WebhookServer is the validating admission webhook of the AKO CRDs. It runs the checks
+// that AKO does on the CRDs before ingestion, so that an invalid object is denied by the
+// api server instead of getting stored and rejected later.
+type WebhookServer struct {
+	http.Server
+	Port    string
+	CertDir string
+}
+
+func NewServer(port, certDir string) *WebhookServer {
+	s := &WebhookServer{
+		Server: http.Server{
+			Addr:         ":" + port,
+			ReadTimeout:  10 * time.Second,
+			WriteTimeout: 10 * time.Second,
+		},
+		Port:    port,
+		CertDir: certDir,
+	}
+	router := mux.NewRouter()
+	router.HandleFunc(ValidateCRDRoute, ValidateCRD).Methods(http.MethodPost)
+	router.Use(utils.LogApi)
+	s.Handler = router
+	return s
+}
+
+func (s *WebhookServer) InitWebhook() {
+	go func() {
+		utils.AviLog.Infof("Starting CRD admission webhook server at %s", s.Server.Addr)
+		err := s.ListenAndServeTLS(filepath.Join(s.CertDir, tlsCertFile), filepath.Join(s.CertDir, tlsKeyFile))
+		if err != nil {
+			utils.AviLog.Infof("CRD admission webhook server shutdown: %v", err)
+		}
+	}()
+}
+
+func (s *WebhookServer) ShutDown() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	utils.AviLog.Infof("Shutting down the CRD admission webhook server")
+	if err := s.Shutdown(ctx); err != nil {
+		utils.AviLog.Warnf("Error Shutting down the CRD admission webhook server :%s", err)
+	}
+}
+
+// ValidateCRD handles the AdmissionReview requests for the AKO CRDs, and denies the create
+// and update requests of the objects which fail validation.
+func ValidateCRD(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	review := admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(body, &review); err != nil || review.Request == nil {
+		utils.AviLog.Warnf("Invalid AdmissionReview request: %s", string(body))
+		http.Error(w, "invalid AdmissionReview request", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = admit(review.Request)
+	review.Response.UID = review.Request.UID
+	review.Request = nil
+	resp, err := json.Marshal(review)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
+
+func admit(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	}
+	// Fail open till AKO is done with the bootup sync, the checks can't be trusted before that.
+	// The objects are validated again before ingestion in any case.
+	if !k8s.IsCRDAdmissionReady() {
+		utils.AviLog.Warnf("AKO is not ready, skipping admission checks of %s %s/%s", req.Kind.Kind, req.Namespace, req.Name)
+		return &admissionv1.AdmissionResponse{
+			Allowed:  true,
+			Warnings: []string{"AKO is not ready, the object will be validated by AKO after it is stored"},
+		}
+	}
+
+	obj, err := decodeCRD(req)
+	if err == nil {
+		err = k8s.ValidateCRDForAdmission(obj)
+	}
+	if err != nil {
+		utils.AviLog.Infof("Denying %s of %s %s/%s: %v", req.Operation, req.Kind.Kind, req.Namespace, req.Name, err)
+		return &admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Status:  metav1.StatusFailure,
+				Message: err.Error(),
+				Reason:  metav1.StatusReasonInvalid,
+				Code:    http.StatusUnprocessableEntity,
+			},
+		}
+	}
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
+
+func decodeCRD(req *admissionv1.AdmissionRequest) (interface{}, error) {
+	var obj interface{}
+	switch req.Kind.Kind {
+	case lib.HostRule:
+		obj = &akov1beta1.HostRule{}
+	case lib.HTTPRule:
+		obj = &akov1beta1.HTTPRule{}
+	case lib.AviInfraSetting:
+		obj = &akov1beta1.AviInfraSetting{}
+	case lib.SSORule:
+		obj = &akov1alpha2.SSORule{}
+	case lib.L4Rule:
+		obj = &akov1alpha2.L4Rule{}
+	case lib.L7Rule:
+		obj = &akov1alpha2.L7Rule{}
+	default:
+		return nil, fmt.Errorf("kind %s is not supported by the AKO admission webhook", req.Kind.Kind)
+	}
+	if err := json.Unmarshal(req.Object.Raw, obj); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %v", req.Kind.Kind, err)
+	}
+	return obj, nil
+}
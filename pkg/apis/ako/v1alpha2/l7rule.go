@@ -37,8 +37,11 @@ type L7RuleSpec struct {
 
 
 type L7RuleStatus struct {
-	Status string `json:"status,omitempty"`
-	Error  string `json:"error"`
+	Status             string             `json:"status,omitempty"`
+	Error              string             `json:"error"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	AppliedTo          []AviObjectRef     `json:"appliedTo,omitempty"`
 }
 
 // +genclient
@@ -30,8 +30,11 @@ type SSORuleSpec struct {
 
 
 type SSORuleStatus struct {
-	Status string `json:"status,omitempty"`
-	Error  string `json:"error"`
+	Status             string             `json:"status,omitempty"`
+	Error              string             `json:"error"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	AppliedTo          []AviObjectRef     `json:"appliedTo,omitempty"`
 }
 
 // +genclient
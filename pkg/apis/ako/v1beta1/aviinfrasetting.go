@@ -78,12 +78,18 @@ type AviInfraSettingSeGroup struct {
 
 type AviInfraL7Settings struct {
 	ShardSize string `json:"shardSize,omitempty"`
+	// UseRegex matches the Ingress paths of pathType ImplementationSpecific as regular expressions,
+	// instead of as path prefixes.
+	UseRegex bool `json:"useRegex,omitempty"`
 }
 
 // AviInfraSettingStatus holds the status of the AviInfraSetting
 type AviInfraSettingStatus struct {
-	Status string `json:"status,omitempty"`
-	Error  string `json:"error"`
+	Status             string             `json:"status,omitempty"`
+	Error              string             `json:"error"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	AppliedTo          []AviObjectRef     `json:"appliedTo,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -132,8 +132,11 @@ type HostRuleGSLB struct {
 
 // HostRuleStatus holds the status of the HostRule
 type HostRuleStatus struct {
-	Status string `json:"status,omitempty"`
-	Error  string `json:"error"`
+	Status             string             `json:"status,omitempty"`
+	Error              string             `json:"error"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	AppliedTo          []AviObjectRef     `json:"appliedTo,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -39,11 +39,25 @@ type HTTPRuleSpec struct {
 
 // HTTPRulePaths has settings for a specific target path
 type HTTPRulePaths struct {
-	Target                 string           `json:"target,omitempty"`
-	LoadBalancerPolicy     HTTPRuleLBPolicy `json:"loadBalancerPolicy,omitempty"`
-	TLS                    HTTPRuleTLS      `json:"tls,omitempty"`
-	HealthMonitors         []string         `json:"healthMonitors,omitempty"`
-	ApplicationPersistence string           `json:"applicationPersistence,omitempty"`
+	Target                 string               `json:"target,omitempty"`
+	LoadBalancerPolicy     HTTPRuleLBPolicy     `json:"loadBalancerPolicy,omitempty"`
+	TLS                    HTTPRuleTLS          `json:"tls,omitempty"`
+	HealthMonitors         []string             `json:"healthMonitors,omitempty"`
+	ApplicationPersistence string               `json:"applicationPersistence,omitempty"`
+	PoolSettings           HTTPRulePoolSettings `json:"poolSettings,omitempty"`
+	// AlternateBackends receive a share of the traffic of the Ingress path matching the target,
+	// the backend of the Ingress path receives the rest.
+	AlternateBackends []HTTPRuleAlternateBackend `json:"alternateBackends,omitempty"`
+}
+
+// HTTPRuleAlternateBackend is a service which receives a percentage of the traffic of a path
+type HTTPRuleAlternateBackend struct {
+	// ServiceName is the name of a service in the namespace of the HTTPRule.
+	ServiceName string `json:"serviceName"`
+	// ServicePort is the port of the service, the port of the Ingress backend is used if not set.
+	ServicePort int32 `json:"servicePort,omitempty"`
+	// Weight is the percentage of the requests sent to the service.
+	Weight uint32 `json:"weight"`
 }
 
 // HTTPRuleLBPolicy holds a path/pool's load balancer policies
@@ -61,10 +75,48 @@ type HTTPRuleTLS struct {
 	DestinationCA string `json:"destinationCA,omitempty"`
 }
 
+// HTTPRulePoolSettings holds a path/pool's timeouts and connection limits
+type HTTPRulePoolSettings struct {
+	// ServerTimeout is the time, in milliseconds, within which a server connection needs to be
+	// established and a request-response exchange needs to complete.
+	ServerTimeout *uint32 `json:"serverTimeout,omitempty"`
+	// MaxConcurrentConnectionsPerServer limits the concurrent connections to each server, 0 means no limit.
+	MaxConcurrentConnectionsPerServer *int32 `json:"maxConcurrentConnectionsPerServer,omitempty"`
+	// ConnectionRampDuration is the duration, in minutes, over which the new connections are gradually
+	// ramped up to a server brought online.
+	ConnectionRampDuration *int32 `json:"connectionRampDuration,omitempty"`
+	// GracefulDisableTimeout is the time, in minutes, the existing connections to a disabled server are
+	// kept, -1 means infinite.
+	GracefulDisableTimeout *int32 `json:"gracefulDisableTimeout,omitempty"`
+	// RequestQueueEnabled queues the requests when the pool is full.
+	RequestQueueEnabled *bool `json:"requestQueueEnabled,omitempty"`
+	// RequestQueueDepth is the minimum number of requests queued when the pool is full.
+	RequestQueueDepth *uint32 `json:"requestQueueDepth,omitempty"`
+}
+
 // HTTPRuleStatus holds the status of the HTTPRule
 type HTTPRuleStatus struct {
-	Status string `json:"status,omitempty"`
-	Error  string `json:"error"`
+	Status             string             `json:"status,omitempty"`
+	Error              string             `json:"error"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	AppliedTo          []AviObjectRef     `json:"appliedTo,omitempty"`
+	// TrafficSplit is the effective split of the traffic of the paths with alternate backends.
+	TrafficSplit []HTTPRuleTrafficSplit `json:"trafficSplit,omitempty"`
+}
+
+// HTTPRuleTrafficSplit is the split of the traffic of an Ingress path between its backend and
+// the alternate backends
+type HTTPRuleTrafficSplit struct {
+	Target   string                  `json:"target"`
+	Ingress  string                  `json:"ingress"`
+	Backends []HTTPRuleBackendWeight `json:"backends"`
+}
+
+// HTTPRuleBackendWeight is the percentage of the traffic of a path sent to a service
+type HTTPRuleBackendWeight struct {
+	ServiceName string `json:"serviceName"`
+	Weight      uint32 `json:"weight"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -22,6 +22,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -103,7 +104,7 @@ func (in *AviInfraSetting) DeepCopyInto(out *AviInfraSetting) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
@@ -269,6 +270,18 @@ func (in *AviInfraSettingSpec) DeepCopy() *AviInfraSettingSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AviInfraSettingStatus) DeepCopyInto(out *AviInfraSettingStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AppliedTo != nil {
+		in, out := &in.AppliedTo, &out.AppliedTo
+		*out = make([]AviObjectRef, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -298,6 +311,22 @@ func (in *AviInfraSettingVipNetwork) DeepCopy() *AviInfraSettingVipNetwork {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AviObjectRef) DeepCopyInto(out *AviObjectRef) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AviObjectRef.
+func (in *AviObjectRef) DeepCopy() *AviObjectRef {
+	if in == nil {
+		return nil
+	}
+	out := new(AviObjectRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FullClientLogs) DeepCopyInto(out *FullClientLogs) {
 	*out = *in
@@ -324,7 +353,7 @@ func (in *HTTPRule) DeepCopyInto(out *HTTPRule) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
@@ -347,6 +376,38 @@ func (in *HTTPRule) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRuleAlternateBackend) DeepCopyInto(out *HTTPRuleAlternateBackend) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRuleAlternateBackend.
+func (in *HTTPRuleAlternateBackend) DeepCopy() *HTTPRuleAlternateBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRuleAlternateBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRuleBackendWeight) DeepCopyInto(out *HTTPRuleBackendWeight) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRuleBackendWeight.
+func (in *HTTPRuleBackendWeight) DeepCopy() *HTTPRuleBackendWeight {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRuleBackendWeight)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPRuleLBPolicy) DeepCopyInto(out *HTTPRuleLBPolicy) {
 	*out = *in
@@ -406,6 +467,12 @@ func (in *HTTPRulePaths) DeepCopyInto(out *HTTPRulePaths) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	in.PoolSettings.DeepCopyInto(&out.PoolSettings)
+	if in.AlternateBackends != nil {
+		in, out := &in.AlternateBackends, &out.AlternateBackends
+		*out = make([]HTTPRuleAlternateBackend, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -419,6 +486,52 @@ func (in *HTTPRulePaths) DeepCopy() *HTTPRulePaths {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRulePoolSettings) DeepCopyInto(out *HTTPRulePoolSettings) {
+	*out = *in
+	if in.ServerTimeout != nil {
+		in, out := &in.ServerTimeout, &out.ServerTimeout
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.MaxConcurrentConnectionsPerServer != nil {
+		in, out := &in.MaxConcurrentConnectionsPerServer, &out.MaxConcurrentConnectionsPerServer
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ConnectionRampDuration != nil {
+		in, out := &in.ConnectionRampDuration, &out.ConnectionRampDuration
+		*out = new(int32)
+		**out = **in
+	}
+	if in.GracefulDisableTimeout != nil {
+		in, out := &in.GracefulDisableTimeout, &out.GracefulDisableTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RequestQueueEnabled != nil {
+		in, out := &in.RequestQueueEnabled, &out.RequestQueueEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RequestQueueDepth != nil {
+		in, out := &in.RequestQueueDepth, &out.RequestQueueDepth
+		*out = new(uint32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRulePoolSettings.
+func (in *HTTPRulePoolSettings) DeepCopy() *HTTPRulePoolSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRulePoolSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPRuleSpec) DeepCopyInto(out *HTTPRuleSpec) {
 	*out = *in
@@ -445,6 +558,25 @@ func (in *HTTPRuleSpec) DeepCopy() *HTTPRuleSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPRuleStatus) DeepCopyInto(out *HTTPRuleStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AppliedTo != nil {
+		in, out := &in.AppliedTo, &out.AppliedTo
+		*out = make([]AviObjectRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.TrafficSplit != nil {
+		in, out := &in.TrafficSplit, &out.TrafficSplit
+		*out = make([]HTTPRuleTrafficSplit, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -474,12 +606,33 @@ func (in *HTTPRuleTLS) DeepCopy() *HTTPRuleTLS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRuleTrafficSplit) DeepCopyInto(out *HTTPRuleTrafficSplit) {
+	*out = *in
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]HTTPRuleBackendWeight, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRuleTrafficSplit.
+func (in *HTTPRuleTrafficSplit) DeepCopy() *HTTPRuleTrafficSplit {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRuleTrafficSplit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostRule) DeepCopyInto(out *HostRule) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
@@ -651,6 +804,18 @@ func (in *HostRuleSpec) DeepCopy() *HostRuleSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostRuleStatus) DeepCopyInto(out *HostRuleStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AppliedTo != nil {
+		in, out := &in.AppliedTo, &out.AppliedTo
+		*out = make([]AviObjectRef, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -0,0 +1,224 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package utils
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/vmware/alb-sdk/go/clients"
+	"github.com/vmware/alb-sdk/go/session"
+)
+
+const RolledBackRestOpError = "Rolled back due to later error"
+
+// RestOpJournal records the inverse of every successful rest op of a batch, so that
+// the objects created, updated or deleted by a batch which fails midway can be
+// restored on the controller.
+type RestOpJournal struct {
+	entries []restOpJournalEntry
+}
+
+type restOpJournalEntry struct {
+	op      *RestOp
+	inverse *RestOp
+}
+
+func NewRestOpJournal() *RestOpJournal {
+	return &RestOpJournal{}
+}
+
+// restObjStore keeps the objects as last written by the rest ops of the transactional mode, by
+// tenant and path, so that the inverse of a PUT or PATCH is built without fetching the object.
+// An object modified outside of AKO since then is restored to the state AKO last wrote.
+type restObjStore struct {
+	lock sync.RWMutex
+	objs map[string]map[string]interface{}
+}
+
+var restObjs = &restObjStore{objs: make(map[string]map[string]interface{})}
+
+func restObjKey(tenant, path string) string {
+	return tenant + "/" + strings.Trim(path, "/")
+}
+
+// get returns a copy of the stored object, whose top level fields can be changed by the caller.
+func (s *restObjStore) get(tenant, path string) map[string]interface{} {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	obj, ok := s.objs[restObjKey(tenant, path)]
+	if !ok {
+		return nil
+	}
+	objCopy := make(map[string]interface{}, len(obj))
+	for field, val := range obj {
+		objCopy[field] = val
+	}
+	return objCopy
+}
+
+// set stores the object of a rest response, or removes the stored object if the response
+// doesn't carry one.
+func (s *restObjStore) set(tenant, path string, response interface{}) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	obj, _ := response.(map[string]interface{})
+	if _, ok := obj["uuid"].(string); !ok {
+		delete(s.objs, restObjKey(tenant, path))
+		return
+	}
+	s.objs[restObjKey(tenant, path)] = obj
+}
+
+func (s *restObjStore) delete(tenant, path string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.objs, restObjKey(tenant, path))
+}
+
+// ResetRestOpJournal drops the objects kept for the transactional mode, they may go stale while
+// the mode is disabled.
+func ResetRestOpJournal() {
+	restObjs.lock.Lock()
+	defer restObjs.lock.Unlock()
+	if len(restObjs.objs) != 0 {
+		restObjs.objs = make(map[string]map[string]interface{})
+	}
+}
+
+// Snapshot returns the current state of the object that op is about to update or delete.
+// It must be called before op is executed, and only if op may have to be rolled back, ops
+// that create objects need no snapshot. The state of an updated object is the one last
+// written by AKO if known, a deleted object is always fetched, as its snapshot is recreated.
+func (j *RestOpJournal) Snapshot(c *clients.AviClient, op *RestOp, key string) map[string]interface{} {
+	if op.Method != RestPut && op.Method != RestPatch && op.Method != RestDelete {
+		return nil
+	}
+	if op.Method != RestDelete {
+		if prior := restObjs.get(op.Tenant, op.Path); prior != nil {
+			return prior
+		}
+	}
+	var prior map[string]interface{}
+	if err := c.AviSession.Get(op.Path, &prior); err != nil {
+		AviLog.Warnf("key: %s, msg: failed to snapshot %s %s before %v, it can't be rolled back, err: %v", key, op.Model, op.ObjName, op.Method, err)
+		return nil
+	}
+	if _, ok := prior["uuid"].(string); !ok {
+		AviLog.Warnf("key: %s, msg: unexpected snapshot of %s %s before %v, it can't be rolled back: %v", key, op.Model, op.ObjName, op.Method, Stringify(prior))
+		return nil
+	}
+	return prior
+}
+
+// Record stores the inverse of a successfully executed op, given the snapshot of the
+// object taken before op was executed, if any.
+func (j *RestOpJournal) Record(op *RestOp, prior map[string]interface{}, key string) {
+	inverse := &RestOp{
+		Tenant:  op.Tenant,
+		Version: op.Version,
+		Model:   op.Model,
+		ObjName: op.ObjName,
+	}
+	switch op.Method {
+	case RestPost:
+		resp, _ := op.Response.(map[string]interface{})
+		uuid, _ := resp["uuid"].(string)
+		if uuid == "" {
+			AviLog.Warnf("key: %s, msg: uuid not present in response of %s %s, it can't be rolled back", key, op.Model, op.ObjName)
+			return
+		}
+		inverse.Method = RestDelete
+		inverse.Path = strings.TrimSuffix(op.Path, "/") + "/" + uuid
+		restObjs.set(op.Tenant, inverse.Path, resp)
+	case RestPut, RestPatch:
+		restObjs.set(op.Tenant, op.Path, op.Response)
+		if prior == nil {
+			return
+		}
+		// The controller rejects a PUT with a stale _last_modified, and the op has just modified the object.
+		delete(prior, "_last_modified")
+		inverse.Method = RestPut
+		inverse.Path = op.Path
+		inverse.Obj = prior
+	case RestDelete:
+		restObjs.delete(op.Tenant, op.Path)
+		if prior == nil {
+			return
+		}
+		// The object is recreated with its previous uuid, so that the references to it stay valid.
+		// Its own references are the ones of the snapshot, the objects it refers to which are
+		// updated or deleted by the earlier ops of the batch are restored before it, as the ops
+		// are rolled back in the reverse order. If a referent was deleted otherwise, the controller
+		// rejects the recreation, the rollback fails, and the next sync of the model recreates
+		// the object as the delete is kept.
+		delete(prior, "_last_modified")
+		inverse.Method = RestPost
+		inverse.Path = op.Path[:strings.LastIndex(op.Path, "/")]
+		inverse.Obj = prior
+	default:
+		return
+	}
+	j.entries = append(j.entries, restOpJournalEntry{op: op, inverse: inverse})
+}
+
+// Rollback executes the recorded inverse ops in the reverse order of the ops. The ops
+// which are rolled back are marked as failed, so that they are not added to the cache.
+// It returns the last error hit while rolling back, after attempting every inverse op.
+func (j *RestOpJournal) Rollback(c *clients.AviClient, key string) error {
+	var rollbackErr error
+	for i := len(j.entries) - 1; i >= 0; i-- {
+		op, inverse := j.entries[i].op, j.entries[i].inverse
+		SetTenant := session.SetTenant(inverse.Tenant)
+		SetTenant(c.AviSession)
+		if inverse.Version != "" {
+			SetVersion := session.SetVersion(inverse.Version)
+			SetVersion(c.AviSession)
+		}
+		switch inverse.Method {
+		case RestPost:
+			inverse.Err = c.AviSession.Post(inverse.Path, inverse.Obj, &inverse.Response)
+		case RestPut:
+			inverse.Err = c.AviSession.Put(inverse.Path, inverse.Obj, &inverse.Response)
+		case RestDelete:
+			inverse.Err = c.AviSession.Delete(inverse.Path)
+		}
+		if inverse.Err != nil {
+			AviLog.Warnf("key: %s, msg: failed to roll back %v of %s %s with %v %s, err: %v",
+				key, op.Method, op.Model, op.ObjName, inverse.Method, inverse.Path, inverse.Err)
+			rollbackErr = inverse.Err
+			continue
+		}
+		// The objects are kept as the inverse ops restored them.
+		switch inverse.Method {
+		case RestPost:
+			restObjs.set(inverse.Tenant, op.Path, inverse.Response)
+		case RestPut:
+			restObjs.set(inverse.Tenant, inverse.Path, inverse.Response)
+		case RestDelete:
+			restObjs.delete(inverse.Tenant, inverse.Path)
+		}
+		AviLog.Infof("key: %s, msg: rolled back %v of %s %s with %v %s", key, op.Method, op.Model, op.ObjName, inverse.Method, inverse.Path)
+		op.Err = errors.New(RolledBackRestOpError)
+	}
+	j.entries = nil
+	return rollbackErr
+}
+
+// Len returns the number of ops that can be rolled back.
+func (j *RestOpJournal) Len() int {
+	return len(j.entries)
+}
@@ -16,13 +16,27 @@ package utils
 
 import (
 	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/workqueue"
 )
 
+const (
+	// DefaultMaxAttempts is the number of times a key is synced before it is dead-lettered,
+	// when the SyncFunc of the queue keeps returning an error for it.
+	DefaultMaxAttempts = 5
+	// The backoff between the attempts doubles from RetryBaseDelay up to RetryMaxDelay, and a
+	// jitter of up to half of the backoff is added to it.
+	RetryBaseDelay    = 1 * time.Second
+	RetryMaxDelay     = 5 * time.Minute
+	retryJitterFactor = 0.5
+)
+
 var queuewrapper sync.Once
 var queueInstance *WorkQueueWrapper
 var fixedQueues = [...]*WorkerQueue{{NumWorkers: NumWorkersIngestion, WorkqueueName: ObjectIngestionLayer}, {NumWorkers: NumWorkersGraph, WorkqueueName: GraphLayer}}
@@ -37,6 +51,22 @@ func (w *WorkQueueWrapper) GetQueueByName(queueName string) *WorkerQueue {
 	return workqueue
 }
 
+// GetQueueNames returns the names of all the queues managed by the wrapper, sorted.
+func (w *WorkQueueWrapper) GetQueueNames() []string {
+	var queueNames []string
+	for queueName := range w.queueCollection {
+		queueNames = append(queueNames, queueName)
+	}
+	sort.Strings(queueNames)
+	return queueNames
+}
+
+// GetSharedWorkQueue returns the shared queues without initializing them, nil is returned
+// if SharedWorkQueue has not been called yet.
+func GetSharedWorkQueue() *WorkQueueWrapper {
+	return queueInstance
+}
+
 func SharedWorkQueue(queueParams ...*WorkerQueue) *WorkQueueWrapper {
 	queuewrapper.Do(func() {
 		queueInstance = &WorkQueueWrapper{}
@@ -44,6 +74,7 @@ func SharedWorkQueue(queueParams ...*WorkerQueue) *WorkQueueWrapper {
 		if len(queueParams) != 0 {
 			for _, queue := range queueParams {
 				workqueue := NewWorkQueue(queue.NumWorkers, queue.WorkqueueName, queue.SlowSyncTime)
+				workqueue.RequeueFunc = queue.RequeueFunc
 				queueInstance.queueCollection[queue.WorkqueueName] = workqueue
 			}
 		} else {
@@ -65,6 +96,101 @@ type WorkerQueue struct {
 	workerId      uint32
 	SyncFunc      func(interface{}, *sync.WaitGroup) error
 	SlowSyncTime  int
+	inFlightLock  sync.RWMutex
+	inFlightKeys  map[uint32]inFlightKey
+	// maxAttempts is the number of times a key is synced, when SyncFunc returns an error for
+	// it, before the key is moved to the dead-letter set of the queue, see SetMaxAttempts.
+	maxAttempts atomic.Int32
+	// RequeueFunc is called when a key is added back to the queue after an error, so that the
+	// counters of the keys in the queue can be kept up to date.
+	RequeueFunc func(interface{})
+	// failures is the number of consecutive failed syncs of the keys in retry, and of the
+	// dead-lettered keys.
+	failuresLock   sync.Mutex
+	failures       map[interface{}]int
+	deadLetterLock sync.RWMutex
+	deadLetterKeys map[interface{}]DeadLetterKey
+}
+
+// DeadLetterKey is a key for which SyncFunc returned an error in all the attempts. It is synced
+// again only when it is added to the queue after ResetRetries is called for it.
+type DeadLetterKey struct {
+	Key         string    `json:"key"`
+	LastError   string    `json:"last_error"`
+	Attempts    int       `json:"attempts"`
+	LastFailure time.Time `json:"last_failure"`
+}
+
+// retryBackoff returns the delay before the retry of a key that failed to sync the given number
+// of times. The backoff doubles from RetryBaseDelay up to RetryMaxDelay, and a random jitter is
+// added to it, so that the keys that failed together are not retried together.
+func retryBackoff(failures int) time.Duration {
+	backoff := RetryBaseDelay
+	for i := 1; i < failures && backoff < RetryMaxDelay; i++ {
+		backoff *= 2
+	}
+	if backoff > RetryMaxDelay {
+		backoff = RetryMaxDelay
+	}
+	return wait.Jitter(backoff, retryJitterFactor)
+}
+
+type inFlightKey struct {
+	key     interface{}
+	addTime time.Time
+}
+
+// timedQueue records the time at which a key is added to the queue. A key that is added again
+// before a worker picks it up keeps the time of the first add, so that the time is the one of
+// the oldest change pending for the key.
+type timedQueue struct {
+	workqueue.RateLimitingInterface
+	addTimeLock sync.Mutex
+	addTimes    map[interface{}]time.Time
+}
+
+func newTimedQueue(queue workqueue.RateLimitingInterface) *timedQueue {
+	return &timedQueue{
+		RateLimitingInterface: queue,
+		addTimes:              make(map[interface{}]time.Time),
+	}
+}
+
+func (q *timedQueue) recordAddTime(item interface{}) {
+	q.addTimeLock.Lock()
+	defer q.addTimeLock.Unlock()
+	if _, ok := q.addTimes[item]; !ok {
+		q.addTimes[item] = time.Now()
+	}
+}
+
+func (q *timedQueue) popAddTime(item interface{}) (time.Time, bool) {
+	q.addTimeLock.Lock()
+	defer q.addTimeLock.Unlock()
+	addTime, ok := q.addTimes[item]
+	delete(q.addTimes, item)
+	return addTime, ok
+}
+
+func (q *timedQueue) pending() int {
+	q.addTimeLock.Lock()
+	defer q.addTimeLock.Unlock()
+	return len(q.addTimes)
+}
+
+func (q *timedQueue) Add(item interface{}) {
+	q.recordAddTime(item)
+	q.RateLimitingInterface.Add(item)
+}
+
+func (q *timedQueue) AddAfter(item interface{}, duration time.Duration) {
+	q.recordAddTime(item)
+	q.RateLimitingInterface.AddAfter(item, duration)
+}
+
+func (q *timedQueue) AddRateLimited(item interface{}) {
+	q.recordAddTime(item)
+	q.RateLimitingInterface.AddRateLimited(item)
 }
 
 func NewWorkQueue(num_workers uint32, workerQueueName string, slowSyncTime ...int) *WorkerQueue {
@@ -73,15 +199,24 @@ func NewWorkQueue(num_workers uint32, workerQueueName string, slowSyncTime ...in
 	queue.workerId = (uint32(1) << num_workers) - 1
 	queue.NumWorkers = num_workers
 	queue.WorkqueueName = workerQueueName
+	queue.SetMaxAttempts(DefaultMaxAttempts)
+	queue.failures = make(map[interface{}]int)
+	queue.deadLetterKeys = make(map[interface{}]DeadLetterKey)
 	if len(slowSyncTime) > 0 {
 		queue.SlowSyncTime = slowSyncTime[0]
 	}
 	for i := uint32(0); i < num_workers; i++ {
-		queue.Workqueue[i] = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), fmt.Sprintf("avi-%s", workerQueueName))
+		queue.Workqueue[i] = newTimedQueue(workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), fmt.Sprintf("avi-%s", workerQueueName)))
 	}
 	return queue
 }
 
+// SetMaxAttempts sets the number of times a key is synced, when SyncFunc returns an error for it,
+// before it is dead-lettered. It can be called while the workers of the queue are running.
+func (c *WorkerQueue) SetMaxAttempts(maxAttempts int) {
+	c.maxAttempts.Store(int32(maxAttempts))
+}
+
 func (c *WorkerQueue) Run(stopCh <-chan struct{}, wg *sync.WaitGroup) error {
 	AviLog.Infof("Starting workers to drain the %s layer queues", c.WorkqueueName)
 	if c.SyncFunc == nil {
@@ -140,22 +275,28 @@ func (c *WorkerQueue) processSingleWorkItem(worker_id uint32, wg *sync.WaitGroup
 	if shutdown {
 		return false
 	}
-	var ev string
 	// We wrap this block in a func so we can defer c.workqueue.Done.
 	err := func(obj interface{}) error {
 		// We call Done here so the workqueue knows we have finished
-		// processing this item. We also must remember to call Forget if we
-		// do not want this work item being re-queued. For example, we do
-		// not call Forget if a transient error occurs, instead the item is
-		// put back on the workqueue and attempted again after a back-off
-		// period.
+		// processing this item. Forget is called in any case, as the keys
+		// are added with AddRateLimited by the producers. The failures of a
+		// key are instead counted by the queue, and the key is put back on
+		// the workqueue after a back-off period.
 		defer c.Workqueue[worker_id].Done(obj)
+		var addTime time.Time
+		if queue, ok := c.Workqueue[worker_id].(*timedQueue); ok {
+			addTime, _ = queue.popAddTime(obj)
+		}
+		c.setInFlightKey(worker_id, obj, addTime)
+		defer c.setInFlightKey(worker_id, nil, time.Time{})
 		// Run the syncToAvi, passing it the ev resource to be synced.
 		err := c.SyncFunc(obj, wg)
+		c.Workqueue[worker_id].Forget(obj)
 		if err != nil {
-			AviLog.Errorf("There was an error while syncing the key: %s", ev)
+			c.retryKey(worker_id, obj, err)
+		} else {
+			c.ResetRetries(obj)
 		}
-		c.Workqueue[worker_id].Forget(obj)
 
 		return nil
 	}(obj)
@@ -166,6 +307,126 @@ func (c *WorkerQueue) processSingleWorkItem(worker_id uint32, wg *sync.WaitGroup
 	return true
 }
 
+// retryKey adds a key that failed to sync back to the queue of the worker after a backoff, or
+// moves it to the dead-letter set once it has failed maxAttempts times.
+func (c *WorkerQueue) retryKey(worker_id uint32, obj interface{}, err error) {
+	c.failuresLock.Lock()
+	c.failures[obj]++
+	attempts := c.failures[obj]
+	c.failuresLock.Unlock()
+	maxAttempts := int(c.maxAttempts.Load())
+	if attempts < maxAttempts {
+		delay := retryBackoff(attempts)
+		AviLog.Warnf("There was an error while syncing the key: %v, attempt %d of %d, will retry in %v, err: %v", obj, attempts, maxAttempts, delay, err)
+		c.Workqueue[worker_id].AddAfter(obj, delay)
+		if c.RequeueFunc != nil {
+			c.RequeueFunc(obj)
+		}
+		return
+	}
+	// The failures of a dead-lettered key are kept, so that ResetRetries finds it.
+	AviLog.Errorf("There was an error while syncing the key: %v, giving up after %d attempts, err: %v", obj, attempts, err)
+	c.deadLetterLock.Lock()
+	defer c.deadLetterLock.Unlock()
+	c.deadLetterKeys[obj] = DeadLetterKey{
+		Key:         fmt.Sprint(obj),
+		LastError:   err.Error(),
+		Attempts:    attempts,
+		LastFailure: time.Now(),
+	}
+}
+
+// ResetRetries clears the failed attempts of a key, and removes it from the dead-letter set. It
+// is called when a key is synced, and when a change to a related object is published for the
+// key, so that a dead-lettered key is synced again.
+func (c *WorkerQueue) ResetRetries(key interface{}) {
+	c.failuresLock.Lock()
+	_, failed := c.failures[key]
+	delete(c.failures, key)
+	c.failuresLock.Unlock()
+	// Only the keys which failed to sync can be in the dead-letter set.
+	if !failed {
+		return
+	}
+	c.deadLetterLock.Lock()
+	defer c.deadLetterLock.Unlock()
+	delete(c.deadLetterKeys, key)
+}
+
+// DeadLetterKeys returns the keys that failed to sync in all the attempts, sorted by key.
+func (c *WorkerQueue) DeadLetterKeys() []DeadLetterKey {
+	c.deadLetterLock.RLock()
+	defer c.deadLetterLock.RUnlock()
+	keys := make([]DeadLetterKey, 0, len(c.deadLetterKeys))
+	for _, deadLetterKey := range c.deadLetterKeys {
+		keys = append(keys, deadLetterKey)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Key < keys[j].Key
+	})
+	return keys
+}
+
+func (c *WorkerQueue) setInFlightKey(worker_id uint32, obj interface{}, addTime time.Time) {
+	c.inFlightLock.Lock()
+	defer c.inFlightLock.Unlock()
+	if c.inFlightKeys == nil {
+		c.inFlightKeys = make(map[uint32]inFlightKey)
+	}
+	if obj == nil {
+		delete(c.inFlightKeys, worker_id)
+		return
+	}
+	c.inFlightKeys[worker_id] = inFlightKey{key: obj, addTime: addTime}
+}
+
+// InFlightKeys returns the keys that are being synced at the moment, by worker id.
+func (c *WorkerQueue) InFlightKeys() map[uint32]string {
+	c.inFlightLock.RLock()
+	defer c.inFlightLock.RUnlock()
+	keys := make(map[uint32]string, len(c.inFlightKeys))
+	for workerId, inFlight := range c.inFlightKeys {
+		keys[workerId] = fmt.Sprint(inFlight.key)
+	}
+	return keys
+}
+
+// InFlightKeyAddTime returns the time at which a key that is being synced at the moment was
+// added to the queue. It is meant to be called from the SyncFunc of the queue.
+func (c *WorkerQueue) InFlightKeyAddTime(key interface{}) (time.Time, bool) {
+	c.inFlightLock.RLock()
+	defer c.inFlightLock.RUnlock()
+	for _, inFlight := range c.inFlightKeys {
+		if inFlight.key == key {
+			return inFlight.addTime, !inFlight.addTime.IsZero()
+		}
+	}
+	return time.Time{}, false
+}
+
+// PendingKeys returns the number of keys added to the queue that are yet to be picked up by a
+// worker, including the keys which wait for their rate limited add.
+func (c *WorkerQueue) PendingKeys() int {
+	pending := 0
+	for _, queue := range c.Workqueue {
+		if queue, ok := queue.(*timedQueue); ok {
+			pending += queue.pending()
+		} else {
+			pending += queue.Len()
+		}
+	}
+	return pending
+}
+
+// Depth returns the number of keys waiting in the queue of each worker, indexed by worker id.
+func (c *WorkerQueue) Depth() []int {
+	depth := make([]int, len(c.Workqueue))
+	for i, queue := range c.Workqueue {
+		depth[i] = queue.Len()
+	}
+	return depth
+}
+
 func (c *WorkerQueue) processBatchedItems(worker_id uint32, wg *sync.WaitGroup) bool {
 	length := c.Workqueue[worker_id].Len()
 	var overallStatus bool
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package avisimulator
+
+import (
+	"net/http"
+	"time"
+)
+
+// Fault fails the requests which match it, before they are applied to the objects of the simulator.
+// The empty fields match all the requests.
+type Fault struct {
+	// Method is the http method of the requests, e.g. POST.
+	Method string
+	// ObjectType is the object type of the requests, e.g. pool.
+	ObjectType string
+	// Name is the name of the object of the requests, the one in the body of the POST and PUT
+	// requests, and the one of the object with the uuid in the path of the other requests.
+	Name string
+	// StatusCode is the status code of the failed requests, http.StatusInternalServerError when not set.
+	StatusCode int
+	// Message is the error in the response of the failed requests.
+	Message string
+	// Delay delays the response of the matching requests, the requests are not failed when the
+	// Delay is set and the StatusCode is not.
+	Delay time.Duration
+	// Count is the number of requests the fault applies to, 0 applies it to all the requests.
+	Count int
+
+	applied int
+}
+
+// AddFault adds a fault, which applies to the requests received after it is added.
+func (s *Simulator) AddFault(fault Fault) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.faults = append(s.faults, &fault)
+}
+
+// ClearFaults removes all the faults.
+func (s *Simulator) ClearFaults() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.faults = nil
+}
+
+func (f *Fault) matches(method, objType, name string) bool {
+	if f.Count > 0 && f.applied >= f.Count {
+		return false
+	}
+	return (f.Method == "" || f.Method == method) &&
+		(f.ObjectType == "" || f.ObjectType == objType) &&
+		(f.Name == "" || f.Name == name)
+}
+
+// injectFault applies the first fault which matches a request, and returns the delay of the response
+// along with the error the request should fail with.
+func (s *Simulator) injectFault(method, objType, tenant, uuid string, body map[string]interface{}) (time.Duration, *apiError) {
+	name, _ := body["name"].(string)
+	if name == "" && uuid != "" {
+		if obj, err := s.getByUUID(objType, tenant, uuid); err == nil {
+			name = obj.name()
+		}
+	}
+	for _, fault := range s.faults {
+		if !fault.matches(method, objType, name) {
+			continue
+		}
+		fault.applied++
+		if fault.Delay > 0 && fault.StatusCode == 0 {
+			return fault.Delay, nil
+		}
+		status := fault.StatusCode
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		message := fault.Message
+		if message == "" {
+			message = http.StatusText(status)
+		}
+		return fault.Delay, &apiError{status, message}
+	}
+	return 0, nil
+}
@@ -0,0 +1,408 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package avisimulator
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	vipAddrPrefix         = "10.250.250."
+	floatingIPAddrPrefix  = "35.250.250."
+	vipV6AddrPrefix       = "2001:db8::"
+	lastModifiedFieldName = "_last_modified"
+)
+
+// cloudScopedTypes are the object types which belong to a cloud, Default-Cloud unless the cloud_ref
+// of the object says otherwise.
+var cloudScopedTypes = map[string]bool{
+	"virtualservice": true,
+	"vsvip":          true,
+	"pool":           true,
+	"poolgroup":      true,
+	"vrfcontext":     true,
+	"network":        true,
+}
+
+func (s *Simulator) newUUID(objType string) string {
+	s.seq++
+	return fmt.Sprintf("%s-%08x-0000-4000-8000-%012x", objType, s.seq, s.seq)
+}
+
+func objectURL(host, objType, uuid, name string) string {
+	return fmt.Sprintf("https://%s/api/%s/%s#%s", host, objType, uuid, name)
+}
+
+// parseRef returns the uuid and the name of the object a ref refers to. The refs are either by
+// name, /api/pool/?name=foo, or by uuid, https://host/api/pool/pool-uuid#foo, where the name is
+// present only when the ref was returned with include_name.
+func parseRef(ref string) (string, string) {
+	idx := strings.Index(ref, "/api/")
+	if idx < 0 {
+		return "", ""
+	}
+	ref = ref[idx+len("/api/"):]
+	var name string
+	if idx := strings.Index(ref, "#"); idx >= 0 {
+		name, ref = ref[idx+1:], ref[:idx]
+	}
+	if idx := strings.Index(ref, "?"); idx >= 0 {
+		query, _ := url.ParseQuery(ref[idx+1:])
+		return "", query.Get("name")
+	}
+	segments := strings.Split(strings.Trim(ref, "/"), "/")
+	if len(segments) < 2 {
+		return "", name
+	}
+	return segments[1], name
+}
+
+func refObjectType(ref string) string {
+	idx := strings.Index(ref, "/api/")
+	if idx < 0 {
+		return ""
+	}
+	return strings.FieldsFunc(ref[idx+len("/api/"):], func(r rune) bool { return r == '/' || r == '?' || r == '#' })[0]
+}
+
+func (s *Simulator) list(objType, tenant string) []*aviObject {
+	var objs []*aviObject
+	for _, obj := range s.objects[objType] {
+		if obj.tenant == tenant || adminScopedTypes[objType] {
+			objs = append(objs, obj)
+		}
+	}
+	sort.Slice(objs, func(i, j int) bool { return objs[i].seq < objs[j].seq })
+	return objs
+}
+
+func (s *Simulator) getByName(objType, tenant, name string) *aviObject {
+	for _, obj := range s.objects[objType] {
+		if obj.name() == name && (obj.tenant == tenant || adminScopedTypes[objType]) {
+			return obj
+		}
+	}
+	return nil
+}
+
+func (s *Simulator) getByUUID(objType, tenant, uuid string) (*aviObject, *apiError) {
+	obj, ok := s.objects[objType][uuid]
+	if !ok || (obj.tenant != tenant && !adminScopedTypes[objType]) {
+		return nil, &apiError{http.StatusNotFound, fmt.Sprintf("No %s matches the given query.", objectKinds[objType])}
+	}
+	return obj, nil
+}
+
+func (s *Simulator) create(host, objType, tenant string, data map[string]interface{}) (*aviObject, *apiError) {
+	name, _ := data["name"].(string)
+	if name == "" {
+		return nil, &apiError{http.StatusBadRequest, "name: This field is required."}
+	}
+	if adminScopedTypes[objType] {
+		tenant = DefaultTenant
+	}
+	if s.getByName(objType, tenant, name) != nil {
+		return nil, &apiError{http.StatusConflict, fmt.Sprintf("%s with this Name, Tenant already exist.", objectKinds[objType])}
+	}
+	// The uuid of an object can be chosen on its creation, as with the controller.
+	uuid, _ := data["uuid"].(string)
+	if uuid == "" {
+		uuid = s.newUUID(objType)
+	} else if _, ok := s.objects[objType][uuid]; ok {
+		return nil, &apiError{http.StatusConflict, fmt.Sprintf("%s with this Uuid already exist.", objectKinds[objType])}
+	} else {
+		s.seq++
+	}
+	obj := &aviObject{objType: objType, tenant: tenant, uuid: uuid, data: data}
+	obj.seq = s.seq
+	if err := s.prepare(host, obj); err != nil {
+		return nil, err
+	}
+	s.objects[objType][obj.uuid] = obj
+	return obj, nil
+}
+
+func (s *Simulator) update(host, objType, tenant, uuid string, data map[string]interface{}) (*aviObject, *apiError) {
+	obj, err := s.getByUUID(objType, tenant, uuid)
+	if err != nil {
+		return nil, err
+	}
+	name, _ := data["name"].(string)
+	if name == "" {
+		return nil, &apiError{http.StatusBadRequest, "name: This field is required."}
+	}
+	if existing := s.getByName(objType, tenant, name); existing != nil && existing.uuid != uuid {
+		return nil, &apiError{http.StatusConflict, fmt.Sprintf("%s with this Name, Tenant already exist.", objectKinds[objType])}
+	}
+	// Like the controller, the updates based on an older version of the object are rejected.
+	if lastModified, ok := data[lastModifiedFieldName]; ok && lastModified != obj.data[lastModifiedFieldName] {
+		return nil, &apiError{http.StatusPreconditionFailed, "Concurrent Update Error: the object has been modified since it was read."}
+	}
+	updated := &aviObject{objType: objType, tenant: obj.tenant, uuid: uuid, seq: obj.seq, data: data}
+	if err := s.prepare(host, updated); err != nil {
+		return nil, err
+	}
+	s.objects[objType][uuid] = updated
+	return updated, nil
+}
+
+// patch applies the add, replace and delete operations of a PATCH. add appends to the list fields
+// and sets the other ones, replace sets the fields, and delete removes the items of the list fields,
+// matched by their name or route_id when present.
+func (s *Simulator) patch(host, objType, tenant, uuid string, body map[string]interface{}) (*aviObject, *apiError) {
+	obj, err := s.getByUUID(objType, tenant, uuid)
+	if err != nil {
+		return nil, err
+	}
+	data := deepCopy(obj.data)
+	for op, val := range body {
+		fields, ok := val.(map[string]interface{})
+		if !ok {
+			return nil, &apiError{http.StatusBadRequest, fmt.Sprintf("invalid patch operation %s", op)}
+		}
+		for field, fieldVal := range fields {
+			switch op {
+			case "add":
+				items, isList := fieldVal.([]interface{})
+				existing, wasList := data[field].([]interface{})
+				if isList && (wasList || data[field] == nil) {
+					data[field] = append(existing, items...)
+				} else {
+					data[field] = fieldVal
+				}
+			case "replace":
+				data[field] = fieldVal
+			case "delete":
+				items, _ := fieldVal.([]interface{})
+				existing, _ := data[field].([]interface{})
+				var remaining []interface{}
+				for _, item := range existing {
+					if !containsPatchItem(items, item) {
+						remaining = append(remaining, item)
+					}
+				}
+				data[field] = remaining
+			default:
+				return nil, &apiError{http.StatusBadRequest, fmt.Sprintf("invalid patch operation %s", op)}
+			}
+		}
+	}
+	return s.update(host, objType, tenant, uuid, data)
+}
+
+func containsPatchItem(items []interface{}, item interface{}) bool {
+	itemMap, _ := item.(map[string]interface{})
+	for _, candidate := range items {
+		candidateMap, _ := candidate.(map[string]interface{})
+		for _, key := range []string{"route_id", "name"} {
+			if itemMap != nil && candidateMap != nil && itemMap[key] != nil && reflect.DeepEqual(itemMap[key], candidateMap[key]) {
+				return true
+			}
+		}
+		if reflect.DeepEqual(candidate, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// delete removes an object, unless other objects refer to it.
+func (s *Simulator) delete(objType, tenant, uuid string) *apiError {
+	obj, err := s.getByUUID(objType, tenant, uuid)
+	if err != nil {
+		return err
+	}
+	var referrers []string
+	for _, objs := range s.objects {
+		for _, other := range objs {
+			if other.uuid == uuid {
+				continue
+			}
+			for _, ref := range collectRefs(other.data) {
+				if refUUID, _ := parseRef(ref); refUUID == uuid {
+					referrers = append(referrers, fmt.Sprintf("'%s %s'", objectKinds[other.objType], other.name()))
+					break
+				}
+			}
+		}
+	}
+	if len(referrers) > 0 {
+		sort.Strings(referrers)
+		return &apiError{http.StatusBadRequest, fmt.Sprintf("Cannot delete, object is referred by: [%s]", strings.Join(referrers, ", "))}
+	}
+	delete(s.objects[obj.objType], uuid)
+	return nil
+}
+
+// prepare sets the read only fields of an object, resolves its refs and allocates the vips of a vsvip.
+func (s *Simulator) prepare(host string, obj *aviObject) *apiError {
+	if host == "" {
+		host = "localhost"
+	}
+	obj.data["uuid"] = obj.uuid
+	obj.data["url"] = objectURL(host, obj.objType, obj.uuid, obj.name())
+	obj.data[lastModifiedFieldName] = fmt.Sprint(time.Now().UnixMicro())
+	if _, ok := obj.data["tenant_ref"]; !ok {
+		obj.data["tenant_ref"] = "/api/tenant/?name=" + obj.tenant
+	}
+	if _, ok := obj.data["cloud_ref"]; !ok && cloudScopedTypes[obj.objType] {
+		obj.data["cloud_ref"] = "/api/cloud/?name=" + DefaultCloud
+	}
+	if err := s.resolveRefs(host, obj.tenant, obj.data); err != nil {
+		return err
+	}
+	if obj.objType == "vsvip" {
+		s.allocateVips(obj.data)
+	}
+	return nil
+}
+
+// resolveRefs replaces the refs of the fields named *_ref and *_refs, at any depth, with the url of
+// the object they refer to. The refs to simulated objects which don't exist fail the request, the
+// refs to the object types which are not simulated are given a stable uuid.
+func (s *Simulator) resolveRefs(host, tenant string, val interface{}) *apiError {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		for key, fieldVal := range v {
+			switch {
+			case strings.HasSuffix(key, "_ref"):
+				if ref, ok := fieldVal.(string); ok {
+					resolved, err := s.resolveRef(host, tenant, ref)
+					if err != nil {
+						return err
+					}
+					v[key] = resolved
+					continue
+				}
+			case strings.HasSuffix(key, "_refs"):
+				if refs, ok := fieldVal.([]interface{}); ok {
+					for i := range refs {
+						ref, ok := refs[i].(string)
+						if !ok {
+							continue
+						}
+						resolved, err := s.resolveRef(host, tenant, ref)
+						if err != nil {
+							return err
+						}
+						refs[i] = resolved
+					}
+					continue
+				}
+			}
+			if err := s.resolveRefs(host, tenant, fieldVal); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if err := s.resolveRefs(host, tenant, item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Simulator) resolveRef(host, tenant, ref string) (string, *apiError) {
+	objType := refObjectType(ref)
+	if objType == "" {
+		return ref, nil
+	}
+	uuid, name := parseRef(ref)
+	if !IsSimulated(objType) {
+		if uuid == "" {
+			key := objType + "/" + name
+			if _, ok := s.externalRefs[key]; !ok {
+				s.externalRefs[key] = s.newUUID(objType)
+			}
+			uuid = s.externalRefs[key]
+		}
+		return objectURL(host, objType, uuid, name), nil
+	}
+	if uuid != "" {
+		if obj, err := s.getByUUID(objType, tenant, uuid); err == nil {
+			return objectURL(host, objType, obj.uuid, obj.name()), nil
+		}
+	}
+	if name != "" {
+		if obj := s.getByName(objType, tenant, name); obj != nil {
+			return objectURL(host, objType, obj.uuid, obj.name()), nil
+		}
+	}
+	return "", &apiError{http.StatusBadRequest, fmt.Sprintf("Cannot find object of type %s with ref %s", objType, ref)}
+}
+
+// collectRefs returns the refs of an object, of the fields named *_ref and *_refs at any depth.
+func collectRefs(val interface{}) []string {
+	var refs []string
+	switch v := val.(type) {
+	case map[string]interface{}:
+		for key, fieldVal := range v {
+			if key == "url" {
+				continue
+			}
+			if ref, ok := fieldVal.(string); ok && strings.HasSuffix(key, "_ref") {
+				refs = append(refs, ref)
+			} else if items, ok := fieldVal.([]interface{}); ok && strings.HasSuffix(key, "_refs") {
+				for _, item := range items {
+					if ref, ok := item.(string); ok {
+						refs = append(refs, ref)
+					}
+				}
+			} else {
+				refs = append(refs, collectRefs(fieldVal)...)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			refs = append(refs, collectRefs(item)...)
+		}
+	}
+	return refs
+}
+
+// allocateVips allocates the addresses of the vips of a vsvip which have auto_allocate_ip set and
+// don't have one already.
+func (s *Simulator) allocateVips(data map[string]interface{}) {
+	vips, _ := data["vip"].([]interface{})
+	for _, vipVal := range vips {
+		vip, ok := vipVal.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if autoAllocate, _ := vip["auto_allocate_ip"].(bool); !autoAllocate {
+			continue
+		}
+		ipType, _ := vip["auto_allocate_ip_type"].(string)
+		if _, ok := vip["ip_address"]; !ok && ipType != "V6_ONLY" {
+			s.vipSeq++
+			vip["ip_address"] = map[string]interface{}{"addr": fmt.Sprintf("%s%d", vipAddrPrefix, s.vipSeq), "type": "V4"}
+			if floating, _ := vip["auto_allocate_floating_ip"].(bool); floating {
+				vip["floating_ip"] = map[string]interface{}{"addr": fmt.Sprintf("%s%d", floatingIPAddrPrefix, s.vipSeq), "type": "V4"}
+			}
+		}
+		if _, ok := vip["ip6_address"]; !ok && (ipType == "V6_ONLY" || ipType == "V4_V6") {
+			s.vipSeq++
+			vip["ip6_address"] = map[string]interface{}{"addr": fmt.Sprintf("%s%x", vipV6AddrPrefix, s.vipSeq), "type": "V6"}
+		}
+	}
+}
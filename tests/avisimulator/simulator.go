@@ -0,0 +1,507 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+// Package avisimulator simulates the Avi controller REST API for the objects AKO manages. Unlike the
+// canned responses of the integration tests, the simulator keeps the objects it is sent in memory, so
+// that the AKO syncs can be verified against the state of the controller.
+//
+// The Simulator is an http.Handler. In the integration tests it is installed with
+// integrationtest.AddMiddleware, with the canned responses as its Fallback for the object types it
+// doesn't simulate. For offline development it can be served on its own, e.g. with
+// httptest.NewTLSServer, and AKO pointed to it with CTRL_IPADDRESS.
+package avisimulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+const (
+	// DefaultTenant is the tenant of the requests which don't specify one.
+	DefaultTenant = "admin"
+	// DefaultCloud is the cloud present on the simulated controller.
+	DefaultCloud = "Default-Cloud"
+	// DefaultPageSize is the page size of the collection GETs which don't specify one.
+	DefaultPageSize = 25
+	// ControllerVersion is the version the simulator reports in /api/initial-data.
+	ControllerVersion = "22.1.2"
+)
+
+// objectKinds maps the object types the simulator keeps in memory to their kind in the controller
+// error messages. The requests for other object types are sent to the Fallback handler.
+var objectKinds = map[string]string{
+	"virtualservice":                "VirtualService",
+	"vsvip":                         "VsVip",
+	"pool":                          "Pool",
+	"poolgroup":                     "PoolGroup",
+	"httppolicyset":                 "HTTPPolicySet",
+	"sslkeyandcertificate":          "SSLKeyAndCertificate",
+	"vsdatascriptset":               "VSDataScriptSet",
+	"l4policyset":                   "L4PolicySet",
+	"networksecuritypolicy":         "NetworkSecurityPolicy",
+	"applicationpersistenceprofile": "ApplicationPersistenceProfile",
+	"stringgroup":                   "StringGroup",
+	"pkiprofile":                    "PKIProfile",
+	"vrfcontext":                    "VrfContext",
+	"cloud":                         "Cloud",
+	"network":                       "Network",
+}
+
+// adminScopedTypes are the object types which are created in the admin tenant and are visible from
+// all the tenants.
+var adminScopedTypes = map[string]bool{
+	"cloud":      true,
+	"network":    true,
+	"vrfcontext": true,
+}
+
+// IsSimulated returns whether the objects of a type are kept in memory by the simulator.
+func IsSimulated(objType string) bool {
+	_, ok := objectKinds[objType]
+	return ok
+}
+
+type aviObject struct {
+	objType string
+	tenant  string
+	uuid    string
+	seq     uint64
+	data    map[string]interface{}
+}
+
+func (o *aviObject) name() string {
+	name, _ := o.data["name"].(string)
+	return name
+}
+
+// Simulator is an in-memory Avi controller. It implements http.Handler, and serves the login, the
+// controller version and cluster runtime APIs along with the CRUD APIs of the simulated object types.
+type Simulator struct {
+	// Fallback serves the requests for the object types and APIs which are not simulated. The
+	// simulator returns 404 for them when Fallback is nil.
+	Fallback http.Handler
+
+	lock sync.RWMutex
+	// object type -> uuid -> object
+	objects map[string]map[string]*aviObject
+	// uuids generated for the refs to the object types which are not simulated, type/name -> uuid
+	externalRefs map[string]string
+	faults       []*Fault
+	seq          uint64
+	vipSeq       int
+}
+
+// NewSimulator returns a simulator with the Default-Cloud cloud and the global vrfcontext, which
+// are present on every controller.
+func NewSimulator() *Simulator {
+	s := &Simulator{}
+	s.Reset()
+	return s
+}
+
+// Reset removes all the objects and faults, and seeds the default objects again.
+func (s *Simulator) Reset() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.objects = make(map[string]map[string]*aviObject)
+	for objType := range objectKinds {
+		s.objects[objType] = make(map[string]*aviObject)
+	}
+	s.externalRefs = make(map[string]string)
+	s.faults = nil
+	s.seq = 0
+	s.vipSeq = 0
+	s.create("localhost", "cloud", DefaultTenant, map[string]interface{}{
+		"name":  DefaultCloud,
+		"vtype": "CLOUD_VCENTER",
+	})
+	s.create("localhost", "vrfcontext", DefaultTenant, map[string]interface{}{
+		"name":      utils.GlobalVRF,
+		"cloud_ref": "/api/cloud/?name=" + DefaultCloud,
+	})
+}
+
+// Create creates an object as a POST would, resolving its refs, and returns the stored object.
+func (s *Simulator) Create(objType, tenant string, data map[string]interface{}) (map[string]interface{}, error) {
+	if !IsSimulated(objType) {
+		return nil, fmt.Errorf("object type %s is not simulated", objType)
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	obj, apiErr := s.create("localhost", objType, tenant, deepCopy(data))
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return deepCopy(obj.data), nil
+}
+
+// Get returns a copy of the object of a type with the given name in the tenant, nil when there is
+// no such object.
+func (s *Simulator) Get(objType, tenant, name string) map[string]interface{} {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if obj := s.getByName(objType, tenant, name); obj != nil {
+		return deepCopy(obj.data)
+	}
+	return nil
+}
+
+// List returns copies of the objects of a type in the tenant, in the order of their creation.
+func (s *Simulator) List(objType, tenant string) []map[string]interface{} {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	var objs []map[string]interface{}
+	for _, obj := range s.list(objType, tenant) {
+		objs = append(objs, deepCopy(obj.data))
+	}
+	return objs
+}
+
+// LoadFixtures creates the objects in the <type>_mock.json collection files of a directory, e.g.
+// tests/avimockobjects, for the simulated object types. The objects are created in the order of the
+// files, hence the fixtures should not refer to objects of the files which come later. The fixtures
+// are snapshots of different controllers, so the refs to the clouds are rebased on the default cloud
+// and the refs to the simulated objects which are not part of the fixtures are dropped. Only the
+// fixtures of the given object types are loaded, when there are any.
+func (s *Simulator) LoadFixtures(dir string, objTypes ...string) error {
+	for _, objType := range []string{"cloud", "vrfcontext", "network", "pkiprofile", "sslkeyandcertificate", "vsdatascriptset",
+		"pool", "poolgroup", "httppolicyset", "l4policyset", "vsvip", "virtualservice"} {
+		if len(objTypes) > 0 && !utils.HasElem(objTypes, objType) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, objType+"_mock.json"))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		var collection struct {
+			Results []map[string]interface{} `json:"results"`
+		}
+		if err := json.Unmarshal(data, &collection); err != nil {
+			return fmt.Errorf("unable to parse the fixtures of %s: %v", objType, err)
+		}
+		for _, result := range collection.Results {
+			tenant := DefaultTenant
+			if tenantRef, ok := result["tenant_ref"].(string); ok {
+				if _, name := parseRef(tenantRef); name != "" {
+					tenant = name
+				}
+			}
+			// The fixtures keep their uuids, as the canned responses of the other handlers refer to them.
+			delete(result, "url")
+			s.lock.RLock()
+			s.rebaseFixtureRefs(result, tenant)
+			s.lock.RUnlock()
+			if existing := s.Get(objType, tenant, fmt.Sprint(result["name"])); existing != nil {
+				continue
+			}
+			if _, err := s.Create(objType, tenant, result); err != nil {
+				return fmt.Errorf("unable to load the fixture %v of %s: %v", result["name"], objType, err)
+			}
+		}
+	}
+	return nil
+}
+
+// rebaseFixtureRefs rewrites the refs of a fixture, at any depth, to the refs of the simulator. The
+// refs to the simulated objects which don't exist are dropped.
+func (s *Simulator) rebaseFixtureRefs(val interface{}, tenant string) {
+	rebase := func(ref string) (string, bool) {
+		objType := refObjectType(ref)
+		if objType == "" || !IsSimulated(objType) {
+			return ref, true
+		}
+		uuid, name := parseRef(ref)
+		if _, err := s.getByUUID(objType, tenant, uuid); uuid != "" && err == nil {
+			return "/api/" + objType + "/" + uuid, true
+		}
+		if objType == "cloud" {
+			return "/api/cloud/?name=" + DefaultCloud, true
+		}
+		if name != "" && s.getByName(objType, tenant, name) != nil {
+			return "/api/" + objType + "/?name=" + name, true
+		}
+		return "", false
+	}
+	switch v := val.(type) {
+	case map[string]interface{}:
+		for key, fieldVal := range v {
+			if ref, ok := fieldVal.(string); ok && strings.HasSuffix(key, "_ref") {
+				if rebased, ok := rebase(ref); ok {
+					v[key] = rebased
+				} else {
+					delete(v, key)
+				}
+			} else if items, ok := fieldVal.([]interface{}); ok && strings.HasSuffix(key, "_refs") {
+				var refs []interface{}
+				for _, item := range items {
+					ref, ok := item.(string)
+					if !ok {
+						refs = append(refs, item)
+					} else if rebased, ok := rebase(ref); ok {
+						refs = append(refs, rebased)
+					}
+				}
+				v[key] = refs
+			} else {
+				s.rebaseFixtureRefs(fieldVal, tenant)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			s.rebaseFixtureRefs(item, tenant)
+		}
+	}
+}
+
+// ServeHTTP serves the Avi REST API requests.
+func (s *Simulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	path := strings.Trim(r.URL.EscapedPath(), "/")
+	switch {
+	case path == "login" || path == "logout":
+		writeJSON(w, http.StatusOK, map[string]interface{}{"success": "true"})
+		return
+	case path == "api/initial-data":
+		writeJSON(w, http.StatusOK, map[string]interface{}{"version": map[string]interface{}{"Version": ControllerVersion}})
+		return
+	case path == "api/cluster/runtime":
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"node_states":   []interface{}{map[string]interface{}{"name": "127.0.0.1", "role": "CLUSTER_LEADER"}},
+			"cluster_state": map[string]interface{}{"state": "CLUSTER_UP_NO_HA"},
+		})
+		return
+	}
+
+	segments := strings.Split(path, "/")
+	if len(segments) < 2 || len(segments) > 3 || segments[0] != "api" || !IsSimulated(segments[1]) {
+		if s.Fallback != nil {
+			s.Fallback.ServeHTTP(w, r)
+			return
+		}
+		writeError(w, &apiError{http.StatusNotFound, fmt.Sprintf("%s is not simulated", r.URL.Path)})
+		return
+	}
+	objType := segments[1]
+	var uuid string
+	if len(segments) == 3 {
+		uuid = segments[2]
+	}
+	tenant := r.Header.Get("X-Avi-Tenant")
+	if tenant == "" {
+		tenant = DefaultTenant
+	}
+
+	var body map[string]interface{}
+	if r.Method == http.MethodPost || r.Method == http.MethodPut || r.Method == http.MethodPatch {
+		data, err := io.ReadAll(r.Body)
+		if err == nil {
+			err = json.Unmarshal(data, &body)
+		}
+		if err != nil || body == nil {
+			writeError(w, &apiError{http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err)})
+			return
+		}
+	}
+
+	s.lock.Lock()
+	delay, faultErr := s.injectFault(r.Method, objType, tenant, uuid, body)
+	if delay > 0 {
+		s.lock.Unlock()
+		time.Sleep(delay)
+		s.lock.Lock()
+	}
+	defer s.lock.Unlock()
+	if faultErr != nil {
+		writeError(w, faultErr)
+		return
+	}
+
+	var resp interface{}
+	var apiErr *apiError
+	status := http.StatusOK
+	switch {
+	case r.Method == http.MethodGet && uuid == "":
+		resp = s.getCollection(r, objType, tenant)
+	case r.Method == http.MethodGet:
+		var obj *aviObject
+		if obj, apiErr = s.getByUUID(objType, tenant, uuid); apiErr == nil {
+			resp = obj.data
+		}
+	case r.Method == http.MethodPost && uuid == "":
+		var obj *aviObject
+		if obj, apiErr = s.create(r.Host, objType, tenant, body); apiErr == nil {
+			resp, status = obj.data, http.StatusCreated
+		}
+	case r.Method == http.MethodPut && uuid != "":
+		var obj *aviObject
+		if obj, apiErr = s.update(r.Host, objType, tenant, uuid, body); apiErr == nil {
+			resp = obj.data
+		}
+	case r.Method == http.MethodPatch && uuid != "":
+		var obj *aviObject
+		if obj, apiErr = s.patch(r.Host, objType, tenant, uuid, body); apiErr == nil {
+			resp = obj.data
+		}
+	case r.Method == http.MethodDelete && uuid != "":
+		if apiErr = s.delete(objType, tenant, uuid); apiErr == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	default:
+		apiErr = &apiError{http.StatusMethodNotAllowed, fmt.Sprintf("method %s is not allowed on %s", r.Method, r.URL.Path)}
+	}
+	if apiErr != nil {
+		utils.AviLog.Infof("[avisimulator]: %s %s failed: %d %s", r.Method, r.URL, apiErr.status, apiErr.message)
+		writeError(w, apiErr)
+		return
+	}
+	writeJSON(w, status, resp)
+}
+
+// getCollection serves the collection GETs, filtered by the name, name.contains, name.in, uuid,
+// created_by and <field>.name query parameters, and paginated by the page and page_size ones.
+func (s *Simulator) getCollection(r *http.Request, objType, tenant string) map[string]interface{} {
+	query := r.URL.Query()
+	var matched []*aviObject
+	for _, obj := range s.list(objType, tenant) {
+		if matchesQuery(obj, query) {
+			matched = append(matched, obj)
+		}
+	}
+
+	pageSize, page := DefaultPageSize, 1
+	fmt.Sscan(query.Get("page_size"), &pageSize)
+	fmt.Sscan(query.Get("page"), &page)
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if page <= 0 {
+		page = 1
+	}
+	start, end := (page-1)*pageSize, page*pageSize
+	if start > len(matched) {
+		start = len(matched)
+	}
+	if end > len(matched) {
+		end = len(matched)
+	}
+
+	var fields []string
+	if val := query.Get("fields"); val != "" {
+		fields = append(strings.Split(val, ","), "uuid", "url", "name")
+	}
+	results := []interface{}{}
+	for _, obj := range matched[start:end] {
+		results = append(results, selectFields(obj.data, fields))
+	}
+	resp := map[string]interface{}{
+		"count":   len(matched),
+		"results": results,
+	}
+	if end < len(matched) {
+		query.Set("page", fmt.Sprint(page+1))
+		resp["next"] = fmt.Sprintf("https://%s/api/%s?%s", r.Host, objType, query.Encode())
+	}
+	return resp
+}
+
+func matchesQuery(obj *aviObject, query url.Values) bool {
+	for param, values := range query {
+		val := values[0]
+		switch {
+		case param == "name":
+			if obj.name() != val {
+				return false
+			}
+		case param == "name.contains":
+			if !strings.Contains(obj.name(), val) {
+				return false
+			}
+		case param == "name.in":
+			if !utils.HasElem(strings.Split(val, ","), obj.name()) {
+				return false
+			}
+		case param == "uuid":
+			if obj.uuid != val {
+				return false
+			}
+		case param == "created_by":
+			if createdBy, _ := obj.data["created_by"].(string); createdBy != val {
+				return false
+			}
+		case strings.HasSuffix(param, "_ref.name"):
+			// e.g. cloud_ref.name, the refs of the stored objects carry the name of the object they
+			// refer to.
+			ref, _ := obj.data[strings.TrimSuffix(param, ".name")].(string)
+			if _, name := parseRef(ref); name != val {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func selectFields(data map[string]interface{}, fields []string) map[string]interface{} {
+	if len(fields) == 0 {
+		return data
+	}
+	selected := make(map[string]interface{})
+	for _, field := range fields {
+		if val, ok := data[field]; ok {
+			selected[field] = val
+		}
+	}
+	return selected
+}
+
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%d: %s", e.status, e.message)
+}
+
+func writeError(w http.ResponseWriter, err *apiError) {
+	writeJSON(w, err.status, map[string]interface{}{"error": err.message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, resp interface{}) {
+	data, _ := json.Marshal(resp)
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+func deepCopy(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+	raw, _ := json.Marshal(data)
+	var copied map[string]interface{}
+	json.Unmarshal(raw, &copied)
+	return copied
+}
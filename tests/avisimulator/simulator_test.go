@@ -0,0 +1,235 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package avisimulator
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"github.com/vmware/alb-sdk/go/clients"
+	"github.com/vmware/alb-sdk/go/session"
+)
+
+func setUpSimulator(t *testing.T) (*Simulator, *clients.AviClient) {
+	sim := NewSimulator()
+	server := httptest.NewTLSServer(sim)
+	t.Cleanup(server.Close)
+	client, err := clients.NewAviClient(strings.TrimPrefix(server.URL, "https://"), "admin",
+		session.SetPassword("admin"), session.SetInsecure, session.DisableControllerStatusCheckOnFailure(true))
+	if err != nil {
+		t.Fatalf("error in creating the avi client: %v", err)
+	}
+	return sim, client
+}
+
+func statusCode(err error) int {
+	if aviErr, ok := err.(session.AviError); ok {
+		return aviErr.HttpStatusCode
+	}
+	return 0
+}
+
+func TestSimulatorRefs(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	sim, client := setUpSimulator(t)
+
+	var pool map[string]interface{}
+	err := client.AviSession.Post("api/pool", map[string]interface{}{"name": "pool1", "created_by": "ako-cluster"}, &pool)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(pool["uuid"]).To(gomega.HavePrefix("pool-"))
+	g.Expect(pool["cloud_ref"]).To(gomega.HaveSuffix("#" + DefaultCloud))
+
+	// Duplicate names are rejected.
+	err = client.AviSession.Post("api/pool", map[string]interface{}{"name": "pool1"}, &pool)
+	g.Expect(statusCode(err)).To(gomega.Equal(http.StatusConflict))
+
+	// The refs by name are resolved to the refs by uuid.
+	var pg map[string]interface{}
+	err = client.AviSession.Post("api/poolgroup", map[string]interface{}{
+		"name":    "pg1",
+		"members": []interface{}{map[string]interface{}{"pool_ref": "/api/pool/?name=pool1"}},
+	}, &pg)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	memberRef := pg["members"].([]interface{})[0].(map[string]interface{})["pool_ref"]
+	g.Expect(memberRef).To(gomega.ContainSubstring("/api/pool/" + pool["uuid"].(string)))
+
+	// The refs to objects which don't exist are rejected.
+	err = client.AviSession.Post("api/poolgroup", map[string]interface{}{
+		"name":    "pg2",
+		"members": []interface{}{map[string]interface{}{"pool_ref": "/api/pool/?name=pool2"}},
+	}, &pg)
+	g.Expect(statusCode(err)).To(gomega.Equal(http.StatusBadRequest))
+	g.Expect(sim.Get("poolgroup", DefaultTenant, "pg2")).To(gomega.BeNil())
+
+	// The objects which are referred by other objects can't be deleted.
+	err = client.AviSession.Delete("api/pool/" + pool["uuid"].(string))
+	g.Expect(statusCode(err)).To(gomega.Equal(http.StatusBadRequest))
+	g.Expect(err.Error()).To(gomega.ContainSubstring("PoolGroup pg1"))
+
+	g.Expect(client.AviSession.Delete("api/poolgroup/" + sim.Get("poolgroup", DefaultTenant, "pg1")["uuid"].(string))).To(gomega.Succeed())
+	g.Expect(client.AviSession.Delete("api/pool/" + pool["uuid"].(string))).To(gomega.Succeed())
+	err = client.AviSession.Get("api/pool/"+pool["uuid"].(string), &pool)
+	g.Expect(statusCode(err)).To(gomega.Equal(http.StatusNotFound))
+}
+
+func TestSimulatorCollection(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	sim, client := setUpSimulator(t)
+
+	for i := 0; i < 5; i++ {
+		createdBy := "ako-cluster"
+		if i == 4 {
+			createdBy = "someone-else"
+		}
+		_, err := sim.Create("pool", DefaultTenant, map[string]interface{}{"name": fmt.Sprintf("pool%d", i), "created_by": createdBy})
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+	}
+	_, err := sim.Create("pool", "tenant1", map[string]interface{}{"name": "pool0", "created_by": "ako-cluster"})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	// The collection is filtered and paginated, and the next pages are followed the way AKO does.
+	var names []string
+	uri := "api/pool/?include_name=true&cloud_ref.name=" + DefaultCloud + "&created_by=ako-cluster&page_size=3"
+	for uri != "" {
+		var resp map[string]interface{}
+		g.Expect(client.AviSession.Get(uri, &resp)).To(gomega.Succeed())
+		g.Expect(resp["count"]).To(gomega.BeEquivalentTo(4))
+		for _, result := range resp["results"].([]interface{}) {
+			names = append(names, result.(map[string]interface{})["name"].(string))
+		}
+		uri = ""
+		if next, ok := resp["next"].(string); ok {
+			uri = "api/pool" + strings.Split(next, "/api/pool")[1]
+		}
+	}
+	g.Expect(names).To(gomega.Equal([]string{"pool0", "pool1", "pool2", "pool3"}))
+
+	// The objects of the other tenants are not listed.
+	session.SetTenant("tenant1")(client.AviSession)
+	var resp map[string]interface{}
+	g.Expect(client.AviSession.Get("api/pool/?name=pool0", &resp)).To(gomega.Succeed())
+	g.Expect(resp["count"]).To(gomega.BeEquivalentTo(1))
+	// The clouds are visible from all the tenants.
+	g.Expect(client.AviSession.Get("api/cloud/?include_name&name="+DefaultCloud, &resp)).To(gomega.Succeed())
+	g.Expect(resp["count"]).To(gomega.BeEquivalentTo(1))
+}
+
+func TestSimulatorVsVipAndPatch(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	_, client := setUpSimulator(t)
+
+	var vsvip map[string]interface{}
+	err := client.AviSession.Post("api/vsvip", map[string]interface{}{
+		"name":                "vsvip1",
+		"vrf_context_ref":     "/api/vrfcontext/?name=global",
+		"vip":                 []interface{}{map[string]interface{}{"vip_id": "0", "auto_allocate_ip": true, "auto_allocate_floating_ip": true}},
+		"east_west_placement": false,
+	}, &vsvip)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	vip := vsvip["vip"].([]interface{})[0].(map[string]interface{})
+	g.Expect(vip["ip_address"]).To(gomega.HaveKeyWithValue("addr", vipAddrPrefix+"1"))
+	g.Expect(vip["floating_ip"]).To(gomega.HaveKeyWithValue("addr", floatingIPAddrPrefix+"1"))
+
+	var vs map[string]interface{}
+	err = client.AviSession.Post("api/virtualservice", map[string]interface{}{
+		"name":                    "vs1",
+		"vsvip_ref":               "/api/vsvip/?name=vsvip1",
+		"application_profile_ref": "/api/applicationprofile/?name=System-HTTP",
+	}, &vs)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(vs["vsvip_ref"]).To(gomega.ContainSubstring(vsvip["uuid"].(string)))
+	g.Expect(vs["application_profile_ref"]).To(gomega.HaveSuffix("#System-HTTP"))
+
+	// The PATCH of the static routes of a vrfcontext.
+	var vrfs map[string]interface{}
+	g.Expect(client.AviSession.Get("api/vrfcontext/?name=global", &vrfs)).To(gomega.Succeed())
+	vrfUUID := vrfs["results"].([]interface{})[0].(map[string]interface{})["uuid"].(string)
+	route := func(id string) map[string]interface{} {
+		return map[string]interface{}{"route_id": id, "prefix": map[string]interface{}{"ip_addr": map[string]interface{}{"addr": "10.1.0.0", "type": "V4"}, "mask": 24}}
+	}
+	var vrf map[string]interface{}
+	g.Expect(client.AviSession.Patch("api/vrfcontext/"+vrfUUID, map[string]interface{}{"static_routes": []interface{}{route("r1"), route("r2")}}, "add", &vrf)).To(gomega.Succeed())
+	g.Expect(vrf["static_routes"]).To(gomega.HaveLen(2))
+	g.Expect(client.AviSession.Patch("api/vrfcontext/"+vrfUUID, map[string]interface{}{"static_routes": []interface{}{map[string]interface{}{"route_id": "r1"}}}, "delete", &vrf)).To(gomega.Succeed())
+	g.Expect(vrf["static_routes"]).To(gomega.HaveLen(1))
+}
+
+func TestSimulatorFaults(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	sim, client := setUpSimulator(t)
+
+	sim.AddFault(Fault{Method: http.MethodPost, ObjectType: "pool", Name: "pool1", StatusCode: http.StatusBadRequest, Message: "injected", Count: 1})
+	var pool map[string]interface{}
+	err := client.AviSession.Post("api/pool", map[string]interface{}{"name": "pool1"}, &pool)
+	g.Expect(statusCode(err)).To(gomega.Equal(http.StatusBadRequest))
+	g.Expect(err.Error()).To(gomega.ContainSubstring("injected"))
+	g.Expect(sim.Get("pool", DefaultTenant, "pool1")).To(gomega.BeNil())
+
+	// The fault applies to a single request.
+	g.Expect(client.AviSession.Post("api/pool", map[string]interface{}{"name": "pool1"}, &pool)).To(gomega.Succeed())
+	g.Expect(sim.Get("pool", DefaultTenant, "pool1")).NotTo(gomega.BeNil())
+
+	sim.AddFault(Fault{Method: http.MethodDelete, ObjectType: "pool", StatusCode: http.StatusForbidden})
+	g.Expect(statusCode(client.AviSession.Delete("api/pool/" + pool["uuid"].(string)))).To(gomega.Equal(http.StatusForbidden))
+	g.Expect(statusCode(client.AviSession.Delete("api/pool/" + pool["uuid"].(string)))).To(gomega.Equal(http.StatusForbidden))
+	sim.ClearFaults()
+	g.Expect(client.AviSession.Delete("api/pool/" + pool["uuid"].(string))).To(gomega.Succeed())
+}
+
+func TestSimulatorLastModified(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	_, client := setUpSimulator(t)
+
+	var pool map[string]interface{}
+	g.Expect(client.AviSession.Post("api/pool", map[string]interface{}{"name": "pool1"}, &pool)).To(gomega.Succeed())
+	g.Expect(pool).To(gomega.HaveKey(lastModifiedFieldName))
+	uuid := pool["uuid"].(string)
+
+	// The update which carries the current _last_modified of the object is applied.
+	var updated map[string]interface{}
+	pool["description"] = "first"
+	g.Expect(client.AviSession.Put("api/pool/"+uuid, pool, &updated)).To(gomega.Succeed())
+	g.Expect(updated[lastModifiedFieldName]).NotTo(gomega.Equal(pool[lastModifiedFieldName]))
+
+	// The one based on the older version of the object is rejected.
+	pool["description"] = "second"
+	err := client.AviSession.Put("api/pool/"+uuid, pool, &updated)
+	g.Expect(statusCode(err)).To(gomega.Equal(http.StatusPreconditionFailed))
+
+	// The updates without a _last_modified are always applied.
+	delete(pool, lastModifiedFieldName)
+	g.Expect(client.AviSession.Put("api/pool/"+uuid, pool, &updated)).To(gomega.Succeed())
+	g.Expect(updated["description"]).To(gomega.Equal("second"))
+}
+
+func TestSimulatorLoadFixtures(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	sim := NewSimulator()
+	g.Expect(sim.LoadFixtures("../avimockobjects", "vrfcontext", "network")).To(gomega.Succeed())
+	g.Expect(sim.List("vrfcontext", DefaultTenant)).NotTo(gomega.BeEmpty())
+	g.Expect(sim.List("pool", DefaultTenant)).To(gomega.BeEmpty())
+
+	// The fixtures keep their uuids, and their refs to the other clouds are rebased on the default cloud.
+	network := sim.Get("network", DefaultTenant, "vxw-dvs-34-virtualwire-182-sid-2200181-wdc-02-vc20-avi-dev178")
+	g.Expect(network).To(gomega.HaveKeyWithValue("uuid", "dvportgroup-1356-cloud-2b1f2c1c-6020-498d-9356-107b72779ae3"))
+	g.Expect(network["cloud_ref"]).To(gomega.HaveSuffix("#" + DefaultCloud))
+
+	g.Expect(sim.LoadFixtures("../avimockobjects")).To(gomega.Succeed())
+	g.Expect(sim.List("pool", DefaultTenant)).NotTo(gomega.BeEmpty())
+}
@@ -0,0 +1,409 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package endpointslicetests
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/fake"
+	v1beta1crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1beta1/clientset/versioned/fake"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+)
+
+var KubeClient *k8sfake.Clientset
+var CRDClient *crdfake.Clientset
+var V1beta1CRDClient *v1beta1crdfake.Clientset
+var ctrl *k8s.AviController
+
+func TestMain(m *testing.M) {
+	os.Setenv("ENABLE_ENDPOINTSLICE", "true")
+	os.Setenv("VIP_NETWORK_LIST", `[{"networkName":"net123"}]`)
+	os.Setenv("CLUSTER_NAME", "cluster")
+	os.Setenv("CLOUD_NAME", "CLOUD_VCENTER")
+	os.Setenv("SEG_NAME", "Default-Group")
+	os.Setenv("NODE_NETWORK_LIST", `[{"networkName":"net123","cidrs":["10.79.168.0/22"]}]`)
+	os.Setenv("SERVICE_TYPE", "ClusterIP")
+	os.Setenv("AUTO_L4_FQDN", "disable")
+	os.Setenv("POD_NAMESPACE", utils.AKO_DEFAULT_NS)
+	os.Setenv("SHARD_VS_SIZE", "LARGE")
+	os.Setenv("POD_NAME", "ako-0")
+
+	akoControlConfig := lib.AKOControlConfig()
+	KubeClient = k8sfake.NewSimpleClientset()
+	CRDClient = crdfake.NewSimpleClientset()
+	V1beta1CRDClient = v1beta1crdfake.NewSimpleClientset()
+	akoControlConfig.SetCRDClientset(CRDClient)
+	akoControlConfig.Setv1beta1CRDClientset(V1beta1CRDClient)
+	akoControlConfig.SetEventRecorder(lib.AKOEventComponent, KubeClient, true)
+	akoControlConfig.SetDefaultLBController(true)
+	akoControlConfig.SetAKOInstanceFlag(true)
+	data := map[string][]byte{
+		"username": []byte("admin"),
+		"password": []byte("admin"),
+	}
+	object := metav1.ObjectMeta{Name: "avi-secret", Namespace: utils.GetAKONamespace()}
+	secret := &corev1.Secret{Data: data, ObjectMeta: object}
+	KubeClient.CoreV1().Secrets(utils.GetAKONamespace()).Create(context.TODO(), secret, metav1.CreateOptions{})
+
+	registeredInformers := []string{
+		utils.ServiceInformer,
+		utils.EndpointSlicesInformer,
+		utils.IngressInformer,
+		utils.IngressClassInformer,
+		utils.SecretInformer,
+		utils.NSInformer,
+		utils.NodeInformer,
+		utils.ConfigMapInformer,
+	}
+	utils.NewInformers(utils.KubeClientIntf{ClientSet: KubeClient}, registeredInformers)
+	informers := k8s.K8sinformers{Cs: KubeClient}
+	k8s.NewCRDInformers()
+
+	integrationtest.InitializeFakeAKOAPIServer()
+	integrationtest.NewAviFakeClientInstance(KubeClient)
+	defer integrationtest.AviFakeClientInstance.Close()
+
+	ctrl = k8s.SharedAviController()
+	stopCh := utils.SetupSignalHandler()
+	ctrlCh := make(chan struct{})
+	quickSyncCh := make(chan struct{})
+	waitGroupMap := make(map[string]*sync.WaitGroup)
+	wgIngestion := &sync.WaitGroup{}
+	waitGroupMap["ingestion"] = wgIngestion
+	wgFastRetry := &sync.WaitGroup{}
+	waitGroupMap["fastretry"] = wgFastRetry
+	wgSlowRetry := &sync.WaitGroup{}
+	waitGroupMap["slowretry"] = wgSlowRetry
+	wgGraph := &sync.WaitGroup{}
+	waitGroupMap["graph"] = wgGraph
+	wgStatus := &sync.WaitGroup{}
+	waitGroupMap["status"] = wgStatus
+	wgLeaderElection := &sync.WaitGroup{}
+	waitGroupMap["leaderElection"] = wgLeaderElection
+
+	integrationtest.AddConfigMap(KubeClient)
+	ctrl.SetSEGroupCloudNameFromNSAnnotations()
+	integrationtest.PollForSyncStart(ctrl, 10)
+
+	ctrl.HandleConfigMap(informers, ctrlCh, stopCh, quickSyncCh)
+	integrationtest.KubeClient = KubeClient
+	integrationtest.AddDefaultIngressClass()
+	integrationtest.AddDefaultNamespace()
+	integrationtest.AddDefaultNamespace(integrationtest.NAMESPACE)
+
+	go ctrl.InitController(informers, registeredInformers, ctrlCh, stopCh, quickSyncCh, waitGroupMap)
+	os.Exit(m.Run())
+}
+
+func setUpTestForSvcLBWithSlices(t *testing.T, epSlices ...*discoveryv1.EndpointSlice) {
+	objects.SharedAviGraphLister().Delete(integrationtest.SINGLEPORTMODEL)
+	integrationtest.CreateSVC(t, integrationtest.NAMESPACE, integrationtest.SINGLEPORTSVC, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false)
+	for _, epSlice := range epSlices {
+		integrationtest.CreateEPS(t, epSlice)
+	}
+	integrationtest.PollForCompletion(t, integrationtest.SINGLEPORTMODEL, 5)
+}
+
+func tearDownTestForSvcLBWithSlices(t *testing.T, g *gomega.GomegaWithT, epSliceNames ...string) {
+	objects.SharedAviGraphLister().Delete(integrationtest.SINGLEPORTMODEL)
+	integrationtest.DelSVC(t, integrationtest.NAMESPACE, integrationtest.SINGLEPORTSVC)
+	for _, epSliceName := range epSliceNames {
+		integrationtest.DelEPS(t, integrationtest.NAMESPACE, epSliceName)
+	}
+	mcache := cache.SharedAviObjCache()
+	vsKey := cache.NamespaceName{Namespace: integrationtest.AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s", integrationtest.NAMESPACE, integrationtest.SINGLEPORTSVC)}
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(false))
+}
+
+func getPoolServers() []string {
+	found, aviModel := objects.SharedAviGraphLister().Get(integrationtest.SINGLEPORTMODEL)
+	if !found || aviModel == nil {
+		return nil
+	}
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	if len(nodes) != 1 || len(nodes[0].PoolRefs) != 1 {
+		return nil
+	}
+	var servers []string
+	for _, server := range nodes[0].PoolRefs[0].Servers {
+		servers = append(servers, *server.Ip.Addr)
+	}
+	return servers
+}
+
+func TestEndpointSlicesMergedPerService(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	svcName := integrationtest.SINGLEPORTSVC
+	slice1 := integrationtest.ConstructEndpointSlice(integrationtest.NAMESPACE, svcName+"-abc", svcName, "foo0", 8080, []discoveryv1.Endpoint{
+		integrationtest.ConstructSliceEndpoint("1.1.1.2", true, true, false),
+		integrationtest.ConstructSliceEndpoint("1.1.1.1", true, true, false),
+	})
+	slice2 := integrationtest.ConstructEndpointSlice(integrationtest.NAMESPACE, svcName+"-xyz", svcName, "foo0", 8080, []discoveryv1.Endpoint{
+		integrationtest.ConstructSliceEndpoint("1.1.1.3", true, true, false),
+		integrationtest.ConstructSliceEndpoint("1.1.1.1", true, true, false),
+	})
+	// The legacy Endpoints object must not be used when EndpointSlices are enabled.
+	integrationtest.CreateEP(t, integrationtest.NAMESPACE, svcName, false, false, "2.2.2")
+	setUpTestForSvcLBWithSlices(t, slice1, slice2)
+
+	g.Eventually(getPoolServers, 10*time.Second).Should(gomega.Equal([]string{"1.1.1.1", "1.1.1.2", "1.1.1.3"}))
+	_, aviModel := objects.SharedAviGraphLister().Get(integrationtest.SINGLEPORTMODEL)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes[0].PoolRefs[0].Port).To(gomega.Equal(int32(8080)))
+
+	integrationtest.DelEPS(t, integrationtest.NAMESPACE, slice1.Name)
+	g.Eventually(getPoolServers, 10*time.Second).Should(gomega.Equal([]string{"1.1.1.1", "1.1.1.3"}))
+
+	// A slice that belongs to another service is not merged.
+	otherSlice := integrationtest.ConstructEndpointSlice(integrationtest.NAMESPACE, "othersvc-abc", "othersvc", "foo0", 8080, []discoveryv1.Endpoint{
+		integrationtest.ConstructSliceEndpoint("1.1.1.4", true, true, false),
+	})
+	integrationtest.CreateEPS(t, otherSlice)
+	g.Consistently(getPoolServers, 3*time.Second).Should(gomega.Equal([]string{"1.1.1.1", "1.1.1.3"}))
+
+	integrationtest.DelEPS(t, integrationtest.NAMESPACE, otherSlice.Name)
+	integrationtest.DelEP(t, integrationtest.NAMESPACE, svcName)
+	tearDownTestForSvcLBWithSlices(t, g, slice2.Name)
+}
+
+func TestEndpointSliceConditions(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	svcName := integrationtest.SINGLEPORTSVC
+	epSlice := integrationtest.ConstructEndpointSlice(integrationtest.NAMESPACE, svcName+"-abc", svcName, "foo0", 8080, []discoveryv1.Endpoint{
+		integrationtest.ConstructSliceEndpoint("1.1.1.1", true, true, false),
+		integrationtest.ConstructSliceEndpoint("1.1.1.2", false, false, false),
+		integrationtest.ConstructSliceEndpoint("1.1.1.3", false, true, true),
+	})
+	setUpTestForSvcLBWithSlices(t, epSlice)
+
+	// Only ready endpoints are added while at least one endpoint is ready.
+	g.Eventually(getPoolServers, 10*time.Second).Should(gomega.Equal([]string{"1.1.1.1"}))
+
+	// With no ready endpoints, the serving but terminating endpoints are used.
+	epSlice.Endpoints = []discoveryv1.Endpoint{
+		integrationtest.ConstructSliceEndpoint("1.1.1.1", false, true, true),
+		integrationtest.ConstructSliceEndpoint("1.1.1.2", false, false, false),
+		integrationtest.ConstructSliceEndpoint("1.1.1.3", false, true, true),
+	}
+	integrationtest.UpdateEPS(t, epSlice)
+	g.Eventually(getPoolServers, 10*time.Second).Should(gomega.Equal([]string{"1.1.1.1", "1.1.1.3"}))
+
+	// Endpoints that are neither ready nor serving are never added.
+	epSlice.Endpoints = []discoveryv1.Endpoint{
+		integrationtest.ConstructSliceEndpoint("1.1.1.2", false, false, false),
+	}
+	integrationtest.UpdateEPS(t, epSlice)
+	g.Eventually(getPoolServers, 10*time.Second).Should(gomega.BeEmpty())
+
+	tearDownTestForSvcLBWithSlices(t, g, epSlice.Name)
+}
+
+func TestEndpointSliceTopologyHints(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	os.Setenv("TOPOLOGY_ZONE", "zone-a")
+	defer os.Unsetenv("TOPOLOGY_ZONE")
+
+	svcName := integrationtest.SINGLEPORTSVC
+	epSlice := integrationtest.ConstructEndpointSlice(integrationtest.NAMESPACE, svcName+"-abc", svcName, "foo0", 8080, []discoveryv1.Endpoint{
+		integrationtest.ConstructSliceEndpoint("1.1.1.1", true, true, false, "zone-a"),
+		integrationtest.ConstructSliceEndpoint("1.1.1.2", true, true, false, "zone-b"),
+		integrationtest.ConstructSliceEndpoint("1.1.1.3", true, true, false, "zone-a", "zone-b"),
+	})
+	setUpTestForSvcLBWithSlices(t, epSlice)
+	g.Eventually(getPoolServers, 10*time.Second).Should(gomega.Equal([]string{"1.1.1.1", "1.1.1.3"}))
+
+	// Hints are ignored if any endpoint is missing them.
+	epSlice.Endpoints = append(epSlice.Endpoints, integrationtest.ConstructSliceEndpoint("1.1.1.4", true, true, false))
+	integrationtest.UpdateEPS(t, epSlice)
+	g.Eventually(getPoolServers, 10*time.Second).Should(gomega.Equal([]string{"1.1.1.1", "1.1.1.2", "1.1.1.3", "1.1.1.4"}))
+
+	// Hints are ignored if no endpoint is hinted for the zone.
+	epSlice.Endpoints = []discoveryv1.Endpoint{
+		integrationtest.ConstructSliceEndpoint("1.1.1.2", true, true, false, "zone-b"),
+		integrationtest.ConstructSliceEndpoint("1.1.1.5", true, true, false, "zone-c"),
+	}
+	integrationtest.UpdateEPS(t, epSlice)
+	g.Eventually(getPoolServers, 10*time.Second).Should(gomega.Equal([]string{"1.1.1.2", "1.1.1.5"}))
+
+	tearDownTestForSvcLBWithSlices(t, g, epSlice.Name)
+}
+
+func TestEndpointSliceTerminatingEndpointsDrained(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	os.Setenv("GRACEFUL_DRAIN_PERIOD", "5")
+	defer os.Unsetenv("GRACEFUL_DRAIN_PERIOD")
+
+	getDrainingServers := func() map[string]bool {
+		servers := make(map[string]bool)
+		found, aviModel := objects.SharedAviGraphLister().Get(integrationtest.SINGLEPORTMODEL)
+		if !found || aviModel == nil {
+			return servers
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) != 1 || len(nodes[0].PoolRefs) != 1 {
+			return servers
+		}
+		for _, server := range nodes[0].PoolRefs[0].Servers {
+			servers[*server.Ip.Addr] = server.Draining
+		}
+		return servers
+	}
+
+	svcName := integrationtest.SINGLEPORTSVC
+	epSlice := integrationtest.ConstructEndpointSlice(integrationtest.NAMESPACE, svcName+"-abc", svcName, "foo0", 8080, []discoveryv1.Endpoint{
+		integrationtest.ConstructSliceEndpoint("1.1.1.1", true, true, false),
+		integrationtest.ConstructSliceEndpoint("1.1.1.2", true, true, false),
+		integrationtest.ConstructSliceEndpoint("1.1.1.3", true, true, false),
+	})
+	setUpTestForSvcLBWithSlices(t, epSlice)
+	g.Eventually(getDrainingServers, 10*time.Second).Should(gomega.Equal(map[string]bool{"1.1.1.1": false, "1.1.1.2": false, "1.1.1.3": false}))
+
+	// The pool carries the graceful_disable_timeout covering the drain period, and its checksum
+	// changes with the period.
+	_, aviModel := objects.SharedAviGraphLister().Get(integrationtest.SINGLEPORTMODEL)
+	pool := aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].PoolRefs[0]
+	g.Expect(*pool.GetGracefulDisableTimeout()).To(gomega.Equal(int32(1)))
+	checksum := pool.GetCheckSum()
+	os.Setenv("GRACEFUL_DRAIN_PERIOD", "120")
+	g.Expect(*pool.GetGracefulDisableTimeout()).To(gomega.Equal(int32(2)))
+	g.Expect(pool.GetCheckSum()).NotTo(gomega.Equal(checksum))
+	os.Setenv("GRACEFUL_DRAIN_PERIOD", "5")
+
+	// Terminating endpoints that are still serving are kept as draining servers.
+	epSlice.Endpoints = []discoveryv1.Endpoint{
+		integrationtest.ConstructSliceEndpoint("1.1.1.1", true, true, false),
+		integrationtest.ConstructSliceEndpoint("1.1.1.2", false, true, true),
+		integrationtest.ConstructSliceEndpoint("1.1.1.3", false, true, true),
+	}
+	integrationtest.UpdateEPS(t, epSlice)
+	g.Eventually(getDrainingServers, 5*time.Second).Should(gomega.Equal(map[string]bool{"1.1.1.1": false, "1.1.1.2": true, "1.1.1.3": true}))
+
+	// A draining server is removed as soon as its endpoint disappears.
+	epSlice.Endpoints = []discoveryv1.Endpoint{
+		integrationtest.ConstructSliceEndpoint("1.1.1.1", true, true, false),
+		integrationtest.ConstructSliceEndpoint("1.1.1.3", false, true, true),
+	}
+	integrationtest.UpdateEPS(t, epSlice)
+	g.Eventually(getDrainingServers, 5*time.Second).Should(gomega.Equal(map[string]bool{"1.1.1.1": false, "1.1.1.3": true}))
+
+	// The remaining draining server is removed once the drain period expires.
+	g.Eventually(getDrainingServers, 15*time.Second).Should(gomega.Equal(map[string]bool{"1.1.1.1": false}))
+
+	tearDownTestForSvcLBWithSlices(t, g, epSlice.Name)
+}
+
+func TestEndpointSliceTerminatingEndpointsDrainedMultiPort(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	os.Setenv("GRACEFUL_DRAIN_PERIOD", "5")
+	defer os.Unsetenv("GRACEFUL_DRAIN_PERIOD")
+
+	getDrainingServers := func(portName string) map[string]bool {
+		servers := make(map[string]bool)
+		found, aviModel := objects.SharedAviGraphLister().Get(integrationtest.MULTIPORTMODEL)
+		if !found || aviModel == nil {
+			return servers
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) != 1 {
+			return servers
+		}
+		for _, pool := range nodes[0].PoolRefs {
+			if pool.PortName != portName {
+				continue
+			}
+			for _, server := range pool.Servers {
+				servers[*server.Ip.Addr] = server.Draining
+			}
+		}
+		return servers
+	}
+
+	svcName := integrationtest.MULTIPORTSVC
+	objects.SharedAviGraphLister().Delete(integrationtest.MULTIPORTMODEL)
+	integrationtest.CreateSVC(t, integrationtest.NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, true)
+	epSlice0 := integrationtest.ConstructEndpointSlice(integrationtest.NAMESPACE, svcName+"-foo0", svcName, "foo0", 8080, []discoveryv1.Endpoint{
+		integrationtest.ConstructSliceEndpoint("1.1.1.1", true, true, false),
+		integrationtest.ConstructSliceEndpoint("1.1.1.2", true, true, false),
+	})
+	epSlice1 := integrationtest.ConstructEndpointSlice(integrationtest.NAMESPACE, svcName+"-foo1", svcName, "foo1", 8081, []discoveryv1.Endpoint{
+		integrationtest.ConstructSliceEndpoint("1.1.1.1", true, true, false),
+		integrationtest.ConstructSliceEndpoint("1.1.1.2", true, true, false),
+	})
+	integrationtest.CreateEPS(t, epSlice0)
+	integrationtest.CreateEPS(t, epSlice1)
+	integrationtest.PollForCompletion(t, integrationtest.MULTIPORTMODEL, 5)
+	g.Eventually(func() map[string]bool { return getDrainingServers("foo0") }, 10*time.Second).Should(gomega.Equal(map[string]bool{"1.1.1.1": false, "1.1.1.2": false}))
+	g.Eventually(func() map[string]bool { return getDrainingServers("foo1") }, 10*time.Second).Should(gomega.Equal(map[string]bool{"1.1.1.1": false, "1.1.1.2": false}))
+
+	// The endpoint terminates only for the first port, the pool of the other port does not
+	// reset the drain of the first pool.
+	epSlice0.Endpoints = []discoveryv1.Endpoint{
+		integrationtest.ConstructSliceEndpoint("1.1.1.1", true, true, false),
+		integrationtest.ConstructSliceEndpoint("1.1.1.2", false, true, true),
+	}
+	integrationtest.UpdateEPS(t, epSlice0)
+	g.Eventually(func() map[string]bool { return getDrainingServers("foo0") }, 5*time.Second).Should(gomega.Equal(map[string]bool{"1.1.1.1": false, "1.1.1.2": true}))
+	g.Expect(getDrainingServers("foo1")).To(gomega.Equal(map[string]bool{"1.1.1.1": false, "1.1.1.2": false}))
+
+	// The endpoint then terminates for the second port as well, its drain starts then, while the
+	// drain of the first pool keeps its start time.
+	time.Sleep(3 * time.Second)
+	epSlice1.Endpoints = []discoveryv1.Endpoint{
+		integrationtest.ConstructSliceEndpoint("1.1.1.1", true, true, false),
+		integrationtest.ConstructSliceEndpoint("1.1.1.2", false, true, true),
+	}
+	integrationtest.UpdateEPS(t, epSlice1)
+	g.Eventually(func() map[string]bool { return getDrainingServers("foo1") }, 5*time.Second).Should(gomega.Equal(map[string]bool{"1.1.1.1": false, "1.1.1.2": true}))
+
+	// Each draining server is removed once the drain period of its pool expires.
+	g.Eventually(func() map[string]bool { return getDrainingServers("foo0") }, 4*time.Second).Should(gomega.Equal(map[string]bool{"1.1.1.1": false}))
+	g.Expect(getDrainingServers("foo1")).To(gomega.Equal(map[string]bool{"1.1.1.1": false, "1.1.1.2": true}))
+	g.Eventually(func() map[string]bool { return getDrainingServers("foo1") }, 10*time.Second).Should(gomega.Equal(map[string]bool{"1.1.1.1": false}))
+
+	objects.SharedAviGraphLister().Delete(integrationtest.MULTIPORTMODEL)
+	integrationtest.DelSVC(t, integrationtest.NAMESPACE, svcName)
+	integrationtest.DelEPS(t, integrationtest.NAMESPACE, epSlice0.Name)
+	integrationtest.DelEPS(t, integrationtest.NAMESPACE, epSlice1.Name)
+	vsKey := cache.NamespaceName{Namespace: integrationtest.AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s", integrationtest.NAMESPACE, svcName)}
+	g.Eventually(func() bool {
+		_, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(false))
+}
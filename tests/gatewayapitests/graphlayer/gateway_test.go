@@ -92,6 +92,7 @@ func TestMain(m *testing.M) {
 
 	ctrl = akogatewayapik8s.SharedGatewayController()
 	ctrl.DisableSync = false
+	tests.EnableExperimentalRouteResources()
 	ctrl.InitGatewayAPIInformers(tests.GatewayClient)
 	akoControlConfig.SetGatewayAPIClientset(tests.GatewayClient)
 
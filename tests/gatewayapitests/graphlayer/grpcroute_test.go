@@ -0,0 +1,219 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package graphlayer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	akogatewayapitests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+)
+
+/* Test cases
+ * - GRPCRoute CRUD with service and method match
+ * - GRPCRoute with method only match and without matches
+ */
+func TestGRPCRouteCRUD(t *testing.T) {
+
+	gatewayName := "gateway-gr-01"
+	gatewayClassName := "gateway-class-gr-01"
+	grpcRouteName := "grpc-route-gr-01"
+	svcName := "avisvc-gr-01"
+	ports := []int32{8080}
+	modelName, parentVSName := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	integrationtest.CreateSVC(t, DEFAULT_NAMESPACE, svcName, "TCP", corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, DEFAULT_NAMESPACE, svcName, false, false, "1.2.3")
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	match := akogatewayapitests.GetGRPCRouteMatchV1alpha2("helloworld.Greeter", "SayHello", "Exact", []string{"version"})
+	rule := akogatewayapitests.GetGRPCRouteRuleV1alpha2([]gatewayv1alpha2.GRPCRouteMatch{match},
+		[][]string{{svcName, DEFAULT_NAMESPACE, "8080", "1"}})
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupGRPCRoute(t, grpcRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, []gatewayv1alpha2.GRPCRouteRule{rule})
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		return len(nodes[0].EvhNodes)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	g.Expect(nodes[0].PortProto).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].PortProto[0].EnableHTTP2).To(gomega.BeTrue())
+
+	childNode := nodes[0].EvhNodes[0]
+	g.Expect(childNode.VHParentName).To(gomega.Equal(parentVSName))
+	g.Expect(*childNode.VHMatches[0].Host).To(gomega.Equal("foo-8080.com"))
+	g.Expect(childNode.VHMatches[0].Rules[0].Matches.Path.MatchStr).To(gomega.ContainElement("/helloworld.Greeter/SayHello"))
+	g.Expect(*childNode.VHMatches[0].Rules[0].Matches.Path.MatchCriteria).To(gomega.Equal("EQUALS"))
+	g.Expect(childNode.VHMatches[0].Rules[0].Matches.Hdrs).To(gomega.HaveLen(1))
+	g.Expect(*childNode.VHMatches[0].Rules[0].Matches.Hdrs[0].Hdr).To(gomega.Equal("version"))
+	g.Expect(*childNode.VHMatches[0].Rules[0].Matches.Hdrs[0].MatchCriteria).To(gomega.Equal("HDR_EQUALS"))
+	g.Expect(childNode.PoolRefs).To(gomega.HaveLen(1))
+	g.Expect(childNode.PoolRefs[0].EnableHttp2).To(gomega.BeTrue())
+	g.Expect(childNode.PoolRefs[0].Servers).To(gomega.HaveLen(1))
+	g.Expect(childNode.PoolGroupRefs).To(gomega.HaveLen(1))
+
+	// match all the methods of the service
+	match = akogatewayapitests.GetGRPCRouteMatchV1alpha2("helloworld.Greeter", "", "Exact", nil)
+	rule = akogatewayapitests.GetGRPCRouteRuleV1alpha2([]gatewayv1alpha2.GRPCRouteMatch{match},
+		[][]string{{svcName, DEFAULT_NAMESPACE, "8080", "1"}})
+	akogatewayapitests.UpdateGRPCRoute(t, grpcRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, []gatewayv1alpha2.GRPCRouteRule{rule})
+
+	g.Eventually(func() string {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return ""
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 || len(nodes[0].EvhNodes[0].VHMatches) == 0 {
+			return ""
+		}
+		return nodes[0].EvhNodes[0].VHMatches[0].Rules[0].Matches.Path.MatchStr[0]
+	}, 25*time.Second).Should(gomega.Equal("/helloworld.Greeter/"))
+
+	_, aviModel = objects.SharedAviGraphLister().Get(modelName)
+	nodes = aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	childNode = nodes[0].EvhNodes[0]
+	g.Expect(*childNode.VHMatches[0].Rules[0].Matches.Path.MatchCriteria).To(gomega.Equal("BEGINS_WITH"))
+	g.Expect(childNode.VHMatches[0].Rules[0].Matches.Hdrs).To(gomega.HaveLen(0))
+
+	// delete grpcroute
+	akogatewayapitests.TeardownGRPCRoute(t, grpcRouteName, DEFAULT_NAMESPACE)
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return -1
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		return len(nodes[0].EvhNodes)
+	}, 25*time.Second).Should(gomega.Equal(0))
+
+	_, aviModel = objects.SharedAviGraphLister().Get(modelName)
+	nodes = aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	g.Expect(nodes[0].PortProto[0].EnableHTTP2).To(gomega.BeFalse())
+
+	integrationtest.DelSVC(t, DEFAULT_NAMESPACE, svcName)
+	integrationtest.DelEP(t, DEFAULT_NAMESPACE, svcName)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestGRPCRouteWithMethodOnlyAndWithoutMatches(t *testing.T) {
+
+	gatewayName := "gateway-gr-02"
+	gatewayClassName := "gateway-class-gr-02"
+	grpcRouteName := "grpc-route-gr-02"
+	svcName := "avisvc-gr-02"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	integrationtest.CreateSVC(t, DEFAULT_NAMESPACE, svcName, "TCP", corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, DEFAULT_NAMESPACE, svcName, false, false, "1.2.3")
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	match := akogatewayapitests.GetGRPCRouteMatchV1alpha2("", "SayHello", "Exact", nil)
+	rule := akogatewayapitests.GetGRPCRouteRuleV1alpha2([]gatewayv1alpha2.GRPCRouteMatch{match},
+		[][]string{{svcName, DEFAULT_NAMESPACE, "8080", "1"}})
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupGRPCRoute(t, grpcRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, []gatewayv1alpha2.GRPCRouteRule{rule})
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		return len(nodes[0].EvhNodes)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	childNode := nodes[0].EvhNodes[0]
+	g.Expect(childNode.VHMatches[0].Rules[0].Matches.Path.MatchStr).To(gomega.ContainElement("/SayHello"))
+	g.Expect(*childNode.VHMatches[0].Rules[0].Matches.Path.MatchCriteria).To(gomega.Equal("ENDS_WITH"))
+
+	// a rule without matches matches all the requests
+	rule = akogatewayapitests.GetGRPCRouteRuleV1alpha2(nil, [][]string{{svcName, DEFAULT_NAMESPACE, "8080", "1"}})
+	akogatewayapitests.UpdateGRPCRoute(t, grpcRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, []gatewayv1alpha2.GRPCRouteRule{rule})
+
+	g.Eventually(func() string {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return ""
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 || len(nodes[0].EvhNodes[0].VHMatches) == 0 {
+			return ""
+		}
+		return *nodes[0].EvhNodes[0].VHMatches[0].Rules[0].Matches.Path.MatchCriteria
+	}, 25*time.Second).Should(gomega.Equal("BEGINS_WITH"))
+
+	_, aviModel = objects.SharedAviGraphLister().Get(modelName)
+	nodes = aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	childNode = nodes[0].EvhNodes[0]
+	g.Expect(childNode.VHMatches[0].Rules[0].Matches.Path.MatchStr).To(gomega.ContainElement("/"))
+	g.Expect(childNode.PoolRefs).To(gomega.HaveLen(1))
+	g.Expect(childNode.PoolRefs[0].EnableHttp2).To(gomega.BeTrue())
+
+	akogatewayapitests.TeardownGRPCRoute(t, grpcRouteName, DEFAULT_NAMESPACE)
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return -1
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		return len(nodes[0].EvhNodes)
+	}, 25*time.Second).Should(gomega.Equal(0))
+
+	integrationtest.DelSVC(t, DEFAULT_NAMESPACE, svcName)
+	integrationtest.DelEP(t, DEFAULT_NAMESPACE, svcName)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
@@ -530,6 +530,324 @@ func TestHTTPRouteFilterWithRequestRedirect(t *testing.T) {
 	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
 }
 
+func TestHTTPRouteFilterWithRequestRedirectSchemePathPort(t *testing.T) {
+
+	gatewayName := "gateway-hrf-05"
+	gatewayClassName := "gateway-class-hrf-05"
+	httpRouteName := "http-route-hrf-05"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{},
+		map[string][]string{"RequestRedirect": {}},
+		[][]string{{"avisvc", "default", "8080", "1"}})
+	scheme := "https"
+	statusCode301 := 301
+	prefix := "/bar"
+	rule.Filters[0].RequestRedirect.Scheme = &scheme
+	rule.Filters[0].RequestRedirect.StatusCode = &statusCode301
+	rule.Filters[0].RequestRedirect.Path = &gatewayv1.HTTPPathModifier{
+		Type:               gatewayv1.PrefixMatchHTTPPathModifier,
+		ReplacePrefixMatch: &prefix,
+	}
+	rules := []gatewayv1.HTTPRouteRule{rule}
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 || len(nodes[0].EvhNodes[0].HttpPolicyRefs) != 1 {
+			return -1
+		}
+		return len(nodes[0].EvhNodes[0].HttpPolicyRefs[0].RequestRules)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	redirectAction := nodes[0].EvhNodes[0].HttpPolicyRefs[0].RequestRules[0].RedirectAction
+	g.Expect(redirectAction).ShouldNot(gomega.BeNil())
+	g.Expect(*redirectAction.Protocol).To(gomega.Equal("HTTPS"))
+	g.Expect(*redirectAction.Port).To(gomega.Equal(uint32(443)))
+	g.Expect(*redirectAction.Host.Tokens[0].StrValue).To(gomega.Equal("redirect.com"))
+	g.Expect(*redirectAction.StatusCode).To(gomega.Equal("HTTP_REDIRECT_STATUS_CODE_301"))
+	g.Expect(*redirectAction.KeepQuery).To(gomega.BeTrue())
+	g.Expect(redirectAction.Path.Tokens).To(gomega.HaveLen(2))
+	g.Expect(*redirectAction.Path.Tokens[0].Type).To(gomega.Equal("URI_TOKEN_TYPE_STRING"))
+	g.Expect(*redirectAction.Path.Tokens[0].StrValue).To(gomega.Equal("bar"))
+	g.Expect(*redirectAction.Path.Tokens[1].Type).To(gomega.Equal("URI_TOKEN_TYPE_PATH"))
+	g.Expect(*redirectAction.Path.Tokens[1].StartIndex).To(gomega.Equal(uint32(1)))
+
+	// update the redirect with a full path and an explicit port
+	fullPath := "/login"
+	port := gatewayv1.PortNumber(8443)
+	rule.Filters[0].RequestRedirect.Path = &gatewayv1.HTTPPathModifier{
+		Type:            gatewayv1.FullPathHTTPPathModifier,
+		ReplaceFullPath: &fullPath,
+	}
+	rule.Filters[0].RequestRedirect.Port = &port
+	rules = []gatewayv1.HTTPRouteRule{rule}
+	akogatewayapitests.UpdateHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() uint32 {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		childVS := nodes[0].EvhNodes[0]
+		if len(childVS.HttpPolicyRefs) != 1 || len(childVS.HttpPolicyRefs[0].RequestRules) != 1 ||
+			childVS.HttpPolicyRefs[0].RequestRules[0].RedirectAction == nil ||
+			childVS.HttpPolicyRefs[0].RequestRules[0].RedirectAction.Port == nil {
+			return 0
+		}
+		return *childVS.HttpPolicyRefs[0].RequestRules[0].RedirectAction.Port
+	}, 25*time.Second).Should(gomega.Equal(uint32(8443)))
+
+	_, aviModel = objects.SharedAviGraphLister().Get(modelName)
+	nodes = aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	redirectAction = nodes[0].EvhNodes[0].HttpPolicyRefs[0].RequestRules[0].RedirectAction
+	g.Expect(redirectAction.Path.Tokens).To(gomega.HaveLen(1))
+	g.Expect(*redirectAction.Path.Tokens[0].StrValue).To(gomega.Equal("login"))
+
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestHTTPRouteFilterWithURLRewrite(t *testing.T) {
+
+	gatewayName := "gateway-hrf-06"
+	gatewayClassName := "gateway-class-hrf-06"
+	httpRouteName := "http-route-hrf-06"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo/v1"}, []string{},
+		map[string][]string{
+			"URLRewrite":            {"ReplacePrefixMatch", "/"},
+			"RequestHeaderModifier": {"add"},
+		},
+		[][]string{{"avisvc", "default", "8080", "1"}})
+	rules := []gatewayv1.HTTPRouteRule{rule}
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 || len(nodes[0].EvhNodes[0].HttpPolicyRefs) != 1 {
+			return -1
+		}
+		return len(nodes[0].EvhNodes[0].HttpPolicyRefs[0].RequestRules)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	requestRule := nodes[0].EvhNodes[0].HttpPolicyRefs[0].RequestRules[0]
+	g.Expect(requestRule.HdrAction).To(gomega.HaveLen(1))
+	g.Expect(requestRule.RewriteURLAction).ShouldNot(gomega.BeNil())
+	g.Expect(*requestRule.RewriteURLAction.HostHdr.Tokens[0].StrValue).To(gomega.Equal("rewrite.com"))
+	// the prefix /foo/v1 is removed from the path
+	g.Expect(requestRule.RewriteURLAction.Path.Tokens).To(gomega.HaveLen(1))
+	g.Expect(*requestRule.RewriteURLAction.Path.Tokens[0].Type).To(gomega.Equal("URI_TOKEN_TYPE_PATH"))
+	g.Expect(*requestRule.RewriteURLAction.Path.Tokens[0].StartIndex).To(gomega.Equal(uint32(2)))
+
+	// remove the filters
+	rule = akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo/v1"}, []string{},
+		map[string][]string{},
+		[][]string{{"avisvc", "default", "8080", "1"}})
+	rules = []gatewayv1.HTTPRouteRule{rule}
+	akogatewayapitests.UpdateHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		return len(nodes[0].EvhNodes[0].HttpPolicyRefs)
+	}, 25*time.Second).Should(gomega.Equal(0))
+
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestHTTPRouteFilterWithRequestMirror(t *testing.T) {
+
+	gatewayName := "gateway-hrf-07"
+	gatewayClassName := "gateway-class-hrf-07"
+	httpRouteName := "http-route-hrf-07"
+	svcName := "avisvc-hrf-07"
+	mirrorSvcName := "avisvc-mirror-hrf-07"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	integrationtest.CreateSVC(t, DEFAULT_NAMESPACE, svcName, "TCP", corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, DEFAULT_NAMESPACE, svcName, false, false, "1.2.3")
+	integrationtest.CreateSVC(t, DEFAULT_NAMESPACE, mirrorSvcName, "TCP", corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, DEFAULT_NAMESPACE, mirrorSvcName, false, false, "2.3.4")
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{},
+		map[string][]string{"RequestMirror": {mirrorSvcName, DEFAULT_NAMESPACE, "8080"}},
+		[][]string{{svcName, DEFAULT_NAMESPACE, "8080", "1"}})
+	rules := []gatewayv1.HTTPRouteRule{rule}
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 {
+			return -1
+		}
+		return len(nodes[0].EvhNodes[0].TrafficCloneRefs)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	childNode := nodes[0].EvhNodes[0]
+	cloneProfile := childNode.TrafficCloneRefs[0]
+	g.Expect(cloneProfile.CloneServers).To(gomega.HaveLen(1))
+	g.Expect(cloneProfile.CloneServers[0]).To(gomega.HavePrefix("2.3.4"))
+	g.Expect(*childNode.TrafficCloneProfileRef).To(gomega.Equal("/api/trafficcloneprofile?name=" + cloneProfile.Name))
+	// the mirror filter alone doesn't need a httppolicyset
+	g.Expect(childNode.HttpPolicyRefs).To(gomega.HaveLen(0))
+	g.Expect(childNode.PoolGroupRefs).To(gomega.HaveLen(1))
+
+	// remove the mirror filter
+	rule = akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{},
+		map[string][]string{},
+		[][]string{{svcName, DEFAULT_NAMESPACE, "8080", "1"}})
+	rules = []gatewayv1.HTTPRouteRule{rule}
+	akogatewayapitests.UpdateHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() bool {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		childNode := nodes[0].EvhNodes[0]
+		return len(childNode.TrafficCloneRefs) == 0 && childNode.TrafficCloneProfileRef == nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	integrationtest.DelSVC(t, DEFAULT_NAMESPACE, svcName)
+	integrationtest.DelEP(t, DEFAULT_NAMESPACE, svcName)
+	integrationtest.DelSVC(t, DEFAULT_NAMESPACE, mirrorSvcName)
+	integrationtest.DelEP(t, DEFAULT_NAMESPACE, mirrorSvcName)
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestHTTPRouteWithQueryParamAndMethodMatch(t *testing.T) {
+
+	gatewayName := "gateway-hrm-01"
+	gatewayClassName := "gateway-class-hrm-01"
+	httpRouteName := "http-route-hrm-01"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{},
+		map[string][]string{},
+		[][]string{{"avisvc", "default", "8080", "1"}})
+	method := gatewayv1.HTTPMethodGet
+	rule.Matches[0].Method = &method
+	rule.Matches[0].QueryParams = []gatewayv1.HTTPQueryParamMatch{{Name: "version", Value: "v1"}}
+	// the exact path match takes precedence over the path prefix match
+	rule.Matches = append(rule.Matches, akogatewayapitests.GetHTTPRouteMatchV1("/foo", "Exact", []string{}))
+	rules := []gatewayv1.HTTPRouteRule{rule}
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 || len(nodes[0].EvhNodes[0].VHMatches) != 1 {
+			return -1
+		}
+		return len(nodes[0].EvhNodes[0].VHMatches[0].Rules)
+	}, 25*time.Second).Should(gomega.Equal(5))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	vhMatchRules := nodes[0].EvhNodes[0].VHMatches[0].Rules
+	g.Expect(*vhMatchRules[0].Matches.Path.MatchCriteria).To(gomega.Equal("EQUALS"))
+	g.Expect(vhMatchRules[0].Matches.Query).To(gomega.BeNil())
+	g.Expect(vhMatchRules[0].Matches.Method).To(gomega.BeNil())
+
+	// the query param is matched on its boundaries in the query string, by alternative rules
+	queryMatches := make(map[string]string)
+	for _, vhMatchRule := range vhMatchRules[1:] {
+		g.Expect(*vhMatchRule.Matches.Path.MatchCriteria).To(gomega.Equal("BEGINS_WITH"))
+		g.Expect(vhMatchRule.Matches.Query).ShouldNot(gomega.BeNil())
+		g.Expect(vhMatchRule.Matches.Query.MatchStr).To(gomega.HaveLen(1))
+		queryMatches[*vhMatchRule.Matches.Query.MatchCriteria] = vhMatchRule.Matches.Query.MatchStr[0]
+		g.Expect(vhMatchRule.Matches.Method).ShouldNot(gomega.BeNil())
+		g.Expect(*vhMatchRule.Matches.Method.MatchCriteria).To(gomega.Equal("IS_IN"))
+		g.Expect(vhMatchRule.Matches.Method.Methods).To(gomega.ContainElement("HTTP_METHOD_GET"))
+	}
+	g.Expect(queryMatches).To(gomega.Equal(map[string]string{
+		"QUERY_MATCH_EQUALS":      "version=v1",
+		"QUERY_MATCH_BEGINS_WITH": "version=v1&",
+		"QUERY_MATCH_ENDS_WITH":   "&version=v1",
+		"QUERY_MATCH_CONTAINS":    "&version=v1&",
+	}))
+
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
 func TestHTTPRouteWithValidConfig(t *testing.T) {
 	gatewayClassName := "gateway-class-hr-01"
 	gatewayName := "gateway-hr-01"
@@ -1013,3 +1331,64 @@ func TestHTTPRouteInvalidHostname(t *testing.T) {
 	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
 	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
 }
+
+func TestHTTPRouteWithWildcardAndEmptyListenerHostnames(t *testing.T) {
+
+	gatewayName := "gateway-hrh-01"
+	gatewayClassName := "gateway-class-hrh-01"
+	httpRouteName := "http-route-hrh-01"
+	ports := []int32{8080, 8081}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	// the listener without hostname takes the hostnames from the routes
+	listeners[0].Hostname = nil
+	wildcardHostname := gatewayv1.Hostname("*.apps.example.com")
+	listeners[1].Hostname = &wildcardHostname
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{},
+		map[string][]string{},
+		[][]string{{"avisvc", "default", "8080", "1"}})
+	rules := []gatewayv1.HTTPRouteRule{rule}
+	hostnames := []gatewayv1.Hostname{"foo.example.com", "bar.apps.example.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	getChildVSHosts := func() []string {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return nil
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes) != 1 || len(nodes[0].EvhNodes) != 1 {
+			return nil
+		}
+		var hosts []string
+		for _, vhMatch := range nodes[0].EvhNodes[0].VHMatches {
+			hosts = append(hosts, *vhMatch.Host)
+		}
+		return hosts
+	}
+	g.Eventually(getChildVSHosts, 25*time.Second).Should(gomega.ConsistOf("foo.example.com", "bar.apps.example.com"))
+
+	// the route without hostnames inherits the wildcard hostname of the listener
+	parentRefs = akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, []int32{8081})
+	akogatewayapitests.UpdateHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, nil, rules)
+	g.Eventually(getChildVSHosts, 25*time.Second).Should(gomega.ConsistOf("*.apps.example.com"))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	g.Expect(nodes[0].VSVIPRefs[0].FQDNs).NotTo(gomega.ContainElement("*.apps.example.com"))
+
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
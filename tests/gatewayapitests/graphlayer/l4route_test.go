@@ -0,0 +1,326 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package graphlayer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	akogatewayapitests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+)
+
+/* Test cases
+ * - Gateway with TCP and UDP listeners
+ * - TCPRoute CRUD
+ * - TCPRoute conflict on the same listener
+ * - UDPRoute
+ */
+func TestGatewayWithTCPAndUDPListeners(t *testing.T) {
+
+	gatewayName := "gateway-l4r-01"
+	gatewayClassName := "gateway-class-l4r-01"
+	modelName, vsName := akogatewayapitests.GetL4ModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetL4ListenersV1([]int32{8081}, gatewayv1.TCPProtocolType)
+	listeners = append(listeners, akogatewayapitests.GetL4ListenersV1([]int32{5353}, gatewayv1.UDPProtocolType)...)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].Name).To(gomega.Equal(vsName))
+	g.Expect(nodes[0].ApplicationProfile).To(gomega.Equal(utils.DEFAULT_L4_APP_PROFILE))
+	g.Expect(nodes[0].NetworkProfile).To(gomega.Equal(utils.MIXED_NET_PROFILE))
+	g.Expect(nodes[0].PortProto).To(gomega.ConsistOf(
+		avinodes.AviPortHostProtocol{Port: 8081, Protocol: utils.TCP},
+		avinodes.AviPortHostProtocol{Port: 5353, Protocol: utils.UDP},
+	))
+	g.Expect(nodes[0].HTTPDSrefs).To(gomega.HaveLen(0))
+	g.Expect(nodes[0].L4PolicyRefs).To(gomega.HaveLen(0))
+	g.Expect(nodes[0].VSVIPRefs).To(gomega.HaveLen(1))
+
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	g.Eventually(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		return found && aviModel == nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestTCPRouteCRUD(t *testing.T) {
+
+	gatewayName := "gateway-l4r-02"
+	gatewayClassName := "gateway-class-l4r-02"
+	tcpRouteName := "tcp-route-l4r-02"
+	svcName1 := "avisvc-l4r-02a"
+	svcName2 := "avisvc-l4r-02b"
+	ports := []int32{8081}
+	modelName, vsName := akogatewayapitests.GetL4ModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetL4ListenersV1(ports, gatewayv1.TCPProtocolType)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	integrationtest.CreateSVC(t, DEFAULT_NAMESPACE, svcName1, "TCP", corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, DEFAULT_NAMESPACE, svcName1, false, false, "1.2.3")
+	integrationtest.CreateSVC(t, DEFAULT_NAMESPACE, svcName2, "TCP", corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, DEFAULT_NAMESPACE, svcName2, false, false, "1.2.4")
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetTCPRouteRuleV1alpha2([][]string{
+		{svcName1, DEFAULT_NAMESPACE, "8080", "1"},
+		{svcName2, DEFAULT_NAMESPACE, "8080", "3"},
+	})
+	akogatewayapitests.SetupTCPRoute(t, tcpRouteName, DEFAULT_NAMESPACE, parentRefs, []gatewayv1alpha2.TCPRouteRule{rule})
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found || aviModel == nil {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		return len(nodes[0].PoolRefs)
+	}, 25*time.Second).Should(gomega.Equal(2))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	pgName := akogatewayapilib.GetL4PoolGroupName(DEFAULT_NAMESPACE, gatewayName, "listener-8081")
+	g.Expect(nodes[0].PoolGroupRefs).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].PoolGroupRefs[0].Name).To(gomega.Equal(pgName))
+	g.Expect(nodes[0].PoolGroupRefs[0].Members).To(gomega.HaveLen(2))
+	g.Expect(*nodes[0].PoolGroupRefs[0].Members[0].Ratio).To(gomega.Equal(uint32(1)))
+	g.Expect(*nodes[0].PoolGroupRefs[0].Members[1].Ratio).To(gomega.Equal(uint32(3)))
+	for _, pool := range nodes[0].PoolRefs {
+		g.Expect(pool.Protocol).To(gomega.Equal(utils.TCP))
+		g.Expect(pool.Servers).To(gomega.HaveLen(1))
+	}
+	g.Expect(nodes[0].L4PolicyRefs).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].L4PolicyRefs[0].Name).To(gomega.Equal(vsName))
+	g.Expect(nodes[0].L4PolicyRefs[0].PortPool).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].L4PolicyRefs[0].PortPool[0].Port).To(gomega.Equal(uint32(8081)))
+	g.Expect(nodes[0].L4PolicyRefs[0].PortPool[0].Protocol).To(gomega.Equal(utils.TCP))
+	g.Expect(nodes[0].L4PolicyRefs[0].PortPool[0].PoolGroup).To(gomega.Equal(fmt.Sprintf("/api/poolgroup?name=%s", pgName)))
+
+	// remove a backend of the route
+	rule = akogatewayapitests.GetTCPRouteRuleV1alpha2([][]string{{svcName1, DEFAULT_NAMESPACE, "8080", "1"}})
+	akogatewayapitests.UpdateTCPRoute(t, tcpRouteName, DEFAULT_NAMESPACE, parentRefs, []gatewayv1alpha2.TCPRouteRule{rule})
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found || aviModel == nil {
+			return -1
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		return len(nodes[0].PoolRefs)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel = objects.SharedAviGraphLister().Get(modelName)
+	nodes = aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes[0].PoolRefs[0].ServiceMetadata.NamespaceServiceName).To(gomega.ConsistOf(DEFAULT_NAMESPACE + "/" + svcName1))
+	g.Expect(nodes[0].PoolGroupRefs[0].Members).To(gomega.HaveLen(1))
+
+	// delete the route
+	akogatewayapitests.TeardownTCPRoute(t, tcpRouteName, DEFAULT_NAMESPACE)
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found || aviModel == nil {
+			return -1
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		return len(nodes[0].L4PolicyRefs)
+	}, 25*time.Second).Should(gomega.Equal(0))
+
+	_, aviModel = objects.SharedAviGraphLister().Get(modelName)
+	nodes = aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes[0].PoolRefs).To(gomega.HaveLen(0))
+	g.Expect(nodes[0].PoolGroupRefs).To(gomega.HaveLen(0))
+
+	integrationtest.DelSVC(t, DEFAULT_NAMESPACE, svcName1)
+	integrationtest.DelEP(t, DEFAULT_NAMESPACE, svcName1)
+	integrationtest.DelSVC(t, DEFAULT_NAMESPACE, svcName2)
+	integrationtest.DelEP(t, DEFAULT_NAMESPACE, svcName2)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestTCPRouteListenerConflict(t *testing.T) {
+
+	gatewayName := "gateway-l4r-03"
+	gatewayClassName := "gateway-class-l4r-03"
+	tcpRouteName1 := "tcp-route-l4r-03a"
+	tcpRouteName2 := "tcp-route-l4r-03b"
+	svcName1 := "avisvc-l4r-03a"
+	svcName2 := "avisvc-l4r-03b"
+	ports := []int32{8081}
+	modelName, _ := akogatewayapitests.GetL4ModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetL4ListenersV1(ports, gatewayv1.TCPProtocolType)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	integrationtest.CreateSVC(t, DEFAULT_NAMESPACE, svcName1, "TCP", corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, DEFAULT_NAMESPACE, svcName1, false, false, "1.2.3")
+	integrationtest.CreateSVC(t, DEFAULT_NAMESPACE, svcName2, "TCP", corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, DEFAULT_NAMESPACE, svcName2, false, false, "1.2.4")
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rules := []gatewayv1alpha2.TCPRouteRule{akogatewayapitests.GetTCPRouteRuleV1alpha2([][]string{{svcName1, DEFAULT_NAMESPACE, "8080", "1"}})}
+	akogatewayapitests.SetupTCPRoute(t, tcpRouteName1, DEFAULT_NAMESPACE, parentRefs, rules)
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found || aviModel == nil {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		return len(nodes[0].PoolRefs)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	// the second route is attached to the same listener, the first route keeps it
+	rules = []gatewayv1alpha2.TCPRouteRule{akogatewayapitests.GetTCPRouteRuleV1alpha2([][]string{{svcName2, DEFAULT_NAMESPACE, "8080", "1"}})}
+	akogatewayapitests.SetupTCPRoute(t, tcpRouteName2, DEFAULT_NAMESPACE, parentRefs, rules)
+
+	g.Consistently(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found || aviModel == nil {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		return len(nodes[0].PoolRefs)
+	}, 5*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes[0].PoolRefs[0].ServiceMetadata.NamespaceServiceName).To(gomega.ConsistOf(DEFAULT_NAMESPACE + "/" + svcName1))
+
+	// once the first route is deleted, the listener moves to the second route
+	akogatewayapitests.TeardownTCPRoute(t, tcpRouteName1, DEFAULT_NAMESPACE)
+
+	g.Eventually(func() string {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found || aviModel == nil {
+			return ""
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes[0].PoolRefs) != 1 {
+			return ""
+		}
+		return nodes[0].PoolRefs[0].ServiceMetadata.NamespaceServiceName[0]
+	}, 25*time.Second).Should(gomega.Equal(DEFAULT_NAMESPACE + "/" + svcName2))
+
+	integrationtest.DelSVC(t, DEFAULT_NAMESPACE, svcName1)
+	integrationtest.DelEP(t, DEFAULT_NAMESPACE, svcName1)
+	integrationtest.DelSVC(t, DEFAULT_NAMESPACE, svcName2)
+	integrationtest.DelEP(t, DEFAULT_NAMESPACE, svcName2)
+	akogatewayapitests.TeardownTCPRoute(t, tcpRouteName2, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestUDPRoute(t *testing.T) {
+
+	gatewayName := "gateway-l4r-04"
+	gatewayClassName := "gateway-class-l4r-04"
+	udpRouteName := "udp-route-l4r-04"
+	svcName := "avisvc-l4r-04"
+	ports := []int32{5353}
+	modelName, _ := akogatewayapitests.GetL4ModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetL4ListenersV1(ports, gatewayv1.UDPProtocolType)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	integrationtest.CreateSVC(t, DEFAULT_NAMESPACE, svcName, "UDP", corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, DEFAULT_NAMESPACE, svcName, false, false, "1.2.3")
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rules := []gatewayv1alpha2.UDPRouteRule{akogatewayapitests.GetUDPRouteRuleV1alpha2([][]string{{svcName, DEFAULT_NAMESPACE, "8080", "1"}})}
+	akogatewayapitests.SetupUDPRoute(t, udpRouteName, DEFAULT_NAMESPACE, parentRefs, rules)
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found || aviModel == nil {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		return len(nodes[0].L4PolicyRefs)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes[0].NetworkProfile).To(gomega.Equal(utils.SYSTEM_UDP_FAST_PATH))
+	g.Expect(nodes[0].PoolRefs).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].PoolRefs[0].Protocol).To(gomega.Equal(utils.UDP))
+	g.Expect(nodes[0].L4PolicyRefs[0].PortPool).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].L4PolicyRefs[0].PortPool[0].Port).To(gomega.Equal(uint32(5353)))
+	g.Expect(nodes[0].L4PolicyRefs[0].PortPool[0].Protocol).To(gomega.Equal(utils.UDP))
+
+	akogatewayapitests.TeardownUDPRoute(t, udpRouteName, DEFAULT_NAMESPACE)
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found || aviModel == nil {
+			return -1
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		return len(nodes[0].PoolRefs)
+	}, 25*time.Second).Should(gomega.Equal(0))
+
+	integrationtest.DelSVC(t, DEFAULT_NAMESPACE, svcName)
+	integrationtest.DelEP(t, DEFAULT_NAMESPACE, svcName)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
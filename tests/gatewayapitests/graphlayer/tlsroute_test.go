@@ -0,0 +1,244 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package graphlayer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	akogatewayapitests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+)
+
+/* Test cases
+ * - Gateway with TLS Passthrough listener
+ * - TLSRoute CRUD
+ * - TLSRoute hostname conflict between routes
+ */
+func TestGatewayWithPassthroughListener(t *testing.T) {
+
+	gatewayName := "gateway-tr-01"
+	gatewayClassName := "gateway-class-tr-01"
+	ports := []int32{8443}
+	modelName, vsName := akogatewayapitests.GetL4ModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetPassthroughListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].Name).To(gomega.Equal(vsName))
+	g.Expect(nodes[0].SharedVS).To(gomega.BeTrue())
+	g.Expect(nodes[0].PortProto).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].PortProto[0].Port).To(gomega.Equal(int32(8443)))
+	g.Expect(nodes[0].HTTPDSrefs).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].HTTPDSrefs[0].PoolGroupRefs).To(gomega.HaveLen(0))
+	g.Expect(nodes[0].VSVIPRefs).To(gomega.HaveLen(1))
+
+	// no EVH model is built for the passthrough gateway
+	evhModelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+	found, _ := objects.SharedAviGraphLister().Get(evhModelName)
+	g.Expect(found).To(gomega.BeFalse())
+
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	g.Eventually(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		return found && aviModel == nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestTLSRouteCRUD(t *testing.T) {
+
+	gatewayName := "gateway-tr-02"
+	gatewayClassName := "gateway-class-tr-02"
+	tlsRouteName := "tls-route-tr-02"
+	svcName := "avisvc-tr-02"
+	ports := []int32{8443}
+	modelName, _ := akogatewayapitests.GetL4ModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetPassthroughListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	integrationtest.CreateSVC(t, DEFAULT_NAMESPACE, svcName, "TCP", corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, DEFAULT_NAMESPACE, svcName, false, false, "1.2.3")
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetTLSRouteRuleV1alpha2([][]string{{svcName, DEFAULT_NAMESPACE, "8080", "1"}})
+	rules := []gatewayv1alpha2.TLSRouteRule{rule}
+	hostnames := []gatewayv1.Hostname{"foo-8443.com"}
+	akogatewayapitests.SetupTLSRoute(t, tlsRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found || aviModel == nil {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		return len(nodes[0].PoolGroupRefs)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	pgName := akogatewayapilib.GetPassthroughPGName(DEFAULT_NAMESPACE, gatewayName, "foo-8443.com")
+	g.Expect(nodes[0].PoolGroupRefs[0].Name).To(gomega.Equal(pgName))
+	g.Expect(nodes[0].PoolGroupRefs[0].Members).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].PoolRefs).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].PoolRefs[0].Servers).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].HTTPDSrefs[0].PoolGroupRefs).To(gomega.ConsistOf(pgName))
+	g.Expect(nodes[0].VSVIPRefs[0].FQDNs).To(gomega.ConsistOf("foo-8443.com"))
+
+	// remove the backends of the route
+	rules = []gatewayv1alpha2.TLSRouteRule{akogatewayapitests.GetTLSRouteRuleV1alpha2(nil)}
+	akogatewayapitests.UpdateTLSRoute(t, tlsRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found || aviModel == nil {
+			return -1
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		return len(nodes[0].PoolRefs)
+	}, 25*time.Second).Should(gomega.Equal(0))
+
+	// delete the route
+	akogatewayapitests.TeardownTLSRoute(t, tlsRouteName, DEFAULT_NAMESPACE)
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found || aviModel == nil {
+			return -1
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		return len(nodes[0].PoolGroupRefs)
+	}, 25*time.Second).Should(gomega.Equal(0))
+
+	_, aviModel = objects.SharedAviGraphLister().Get(modelName)
+	nodes = aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes[0].HTTPDSrefs[0].PoolGroupRefs).To(gomega.HaveLen(0))
+	g.Expect(nodes[0].VSVIPRefs[0].FQDNs).To(gomega.HaveLen(0))
+
+	integrationtest.DelSVC(t, DEFAULT_NAMESPACE, svcName)
+	integrationtest.DelEP(t, DEFAULT_NAMESPACE, svcName)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestTLSRouteHostnameConflict(t *testing.T) {
+
+	gatewayName := "gateway-tr-03"
+	gatewayClassName := "gateway-class-tr-03"
+	tlsRouteName1 := "tls-route-tr-03a"
+	tlsRouteName2 := "tls-route-tr-03b"
+	svcName1 := "avisvc-tr-03a"
+	svcName2 := "avisvc-tr-03b"
+	ports := []int32{8443}
+	modelName, _ := akogatewayapitests.GetL4ModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetPassthroughListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	integrationtest.CreateSVC(t, DEFAULT_NAMESPACE, svcName1, "TCP", corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, DEFAULT_NAMESPACE, svcName1, false, false, "1.2.3")
+	integrationtest.CreateSVC(t, DEFAULT_NAMESPACE, svcName2, "TCP", corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, DEFAULT_NAMESPACE, svcName2, false, false, "1.2.4")
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	hostnames := []gatewayv1.Hostname{"foo-8443.com"}
+	rules := []gatewayv1alpha2.TLSRouteRule{akogatewayapitests.GetTLSRouteRuleV1alpha2([][]string{{svcName1, DEFAULT_NAMESPACE, "8080", "1"}})}
+	akogatewayapitests.SetupTLSRoute(t, tlsRouteName1, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found || aviModel == nil {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		return len(nodes[0].PoolRefs)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	// the second route claims the same hostname, the first route keeps it
+	rules = []gatewayv1alpha2.TLSRouteRule{akogatewayapitests.GetTLSRouteRuleV1alpha2([][]string{{svcName2, DEFAULT_NAMESPACE, "8080", "1"}})}
+	akogatewayapitests.SetupTLSRoute(t, tlsRouteName2, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Consistently(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found || aviModel == nil {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		return len(nodes[0].PoolRefs)
+	}, 5*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes[0].PoolRefs[0].ServiceMetadata.NamespaceServiceName).To(gomega.ConsistOf(DEFAULT_NAMESPACE + "/" + svcName1))
+
+	// once the first route is deleted, the hostname moves to the second route
+	akogatewayapitests.TeardownTLSRoute(t, tlsRouteName1, DEFAULT_NAMESPACE)
+
+	g.Eventually(func() string {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found || aviModel == nil {
+			return ""
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes[0].PoolRefs) != 1 {
+			return ""
+		}
+		return nodes[0].PoolRefs[0].ServiceMetadata.NamespaceServiceName[0]
+	}, 25*time.Second).Should(gomega.Equal(DEFAULT_NAMESPACE + "/" + svcName2))
+
+	integrationtest.DelSVC(t, DEFAULT_NAMESPACE, svcName1)
+	integrationtest.DelEP(t, DEFAULT_NAMESPACE, svcName1)
+	integrationtest.DelSVC(t, DEFAULT_NAMESPACE, svcName2)
+	integrationtest.DelEP(t, DEFAULT_NAMESPACE, svcName2)
+	akogatewayapitests.TeardownTLSRoute(t, tlsRouteName2, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
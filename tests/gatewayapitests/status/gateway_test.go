@@ -88,6 +88,7 @@ func TestMain(m *testing.M) {
 
 	ctrl = akogatewayapik8s.SharedGatewayController()
 	ctrl.DisableSync = false
+	tests.EnableExperimentalRouteResources()
 	ctrl.InitGatewayAPIInformers(tests.GatewayClient)
 	akoControlConfig.SetGatewayAPIClientset(tests.GatewayClient)
 
@@ -823,7 +824,7 @@ func TestGatewayWithInvalidAllowedRoute(t *testing.T) {
 	}
 	expectedStatus.Listeners[0].Conditions[0].Reason = string(gatewayv1.ListenerReasonInvalidRouteKinds)
 	expectedStatus.Listeners[0].Conditions[0].Status = metav1.ConditionFalse
-	expectedStatus.Listeners[0].Conditions[0].Message = "AllowedRoute kind is invalid. Only HTTPRoute is supported currently"
+	expectedStatus.Listeners[0].Conditions[0].Message = "AllowedRoute kind is invalid. Only HTTPRoute, GRPCRoute is supported currently"
 	expectedStatus.Listeners[0].Conditions[0].Type = string(gatewayv1.ListenerConditionResolvedRefs)
 
 	gateway, err := tests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
@@ -1222,3 +1223,44 @@ func TestMultipleGatewayOverlappingHostname(t *testing.T) {
 	tests.TeardownGateway(t, gatewayName1, DEFAULT_NAMESPACE)
 	tests.TeardownGatewayClass(t, gatewayClassName)
 }
+
+func TestGatewayWithCrossNamespaceCertificateRef(t *testing.T) {
+	gatewayName := "gateway-neg-12"
+	gatewayClassName := "gateway-class-neg-12"
+	grantName := "referencegrant-neg-12"
+	secretNamespace := "red"
+	ports := []int32{8080}
+	tests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := tests.GetListenersV1(ports, "secret-neg-12")
+	secretNamespaceRef := gatewayv1.Namespace(secretNamespace)
+	listeners[0].TLS.CertificateRefs[0].Namespace = &secretNamespaceRef
+	tests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	listenerCondition := func(conditionType string) *metav1.Condition {
+		gateway, err := tests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil || len(gateway.Status.Listeners) != len(ports) {
+			return nil
+		}
+		return apimeta.FindStatusCondition(gateway.Status.Listeners[0].Conditions, conditionType)
+	}
+
+	// the secret in the other namespace is not permitted without a ReferenceGrant
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() string {
+		condition := listenerCondition(string(gatewayv1.ListenerConditionResolvedRefs))
+		if condition == nil || condition.Status != metav1.ConditionFalse {
+			return ""
+		}
+		return condition.Reason
+	}, 30*time.Second).Should(gomega.Equal(string(gatewayv1.ListenerReasonRefNotPermitted)))
+
+	tests.SetupReferenceGrant(t, grantName, secretNamespace, lib.Gateway, DEFAULT_NAMESPACE, utils.Secret)
+	g.Eventually(func() bool {
+		condition := listenerCondition(string(gatewayv1.ListenerConditionAccepted))
+		return condition != nil && condition.Status == metav1.ConditionTrue
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	tests.TeardownReferenceGrant(t, grantName, secretNamespace)
+	tests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	tests.TeardownGatewayClass(t, gatewayClassName)
+}
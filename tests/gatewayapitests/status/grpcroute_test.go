@@ -0,0 +1,146 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	tests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+)
+
+/* Test cases
+ * - GRPCRoute with valid configurations
+ * - GRPCRoute with regular expression method match
+ */
+func TestGRPCRouteWithValidConfig(t *testing.T) {
+	gatewayClassName := "gateway-class-gr-01"
+	gatewayName := "gateway-gr-01"
+	grpcRouteName := "grpcroute-01"
+	ports := []int32{8080}
+
+	tests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := tests.GetListenersV1(ports)
+	tests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		gateway, err := tests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil {
+			t.Logf("Couldn't get the gateway, err: %+v", err)
+			return false
+		}
+		return apimeta.IsStatusConditionTrue(gateway.Status.Conditions, string(gatewayv1.GatewayConditionAccepted))
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := tests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	match := tests.GetGRPCRouteMatchV1alpha2("helloworld.Greeter", "SayHello", "Exact", nil)
+	rule := tests.GetGRPCRouteRuleV1alpha2([]gatewayv1alpha2.GRPCRouteMatch{match}, nil)
+	tests.SetupGRPCRoute(t, grpcRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, []gatewayv1alpha2.GRPCRouteRule{rule})
+
+	g.Eventually(func() bool {
+		grpcRoute, err := tests.GatewayClient.GatewayV1alpha2().GRPCRoutes(DEFAULT_NAMESPACE).Get(context.TODO(), grpcRouteName, metav1.GetOptions{})
+		if err != nil || grpcRoute == nil {
+			t.Logf("Couldn't get the GRPCRoute, err: %+v", err)
+			return false
+		}
+		if len(grpcRoute.Status.Parents) != len(ports) {
+			return false
+		}
+		return apimeta.FindStatusCondition(grpcRoute.Status.Parents[0].Conditions, string(gatewayv1.RouteConditionAccepted)) != nil
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	conditionMap := make(map[string][]metav1.Condition)
+	for _, port := range ports {
+		conditionMap[fmt.Sprintf("%s-%d", gatewayName, port)] = []metav1.Condition{
+			{
+				Type:    string(gatewayv1.RouteConditionAccepted),
+				Reason:  string(gatewayv1.RouteReasonAccepted),
+				Status:  metav1.ConditionTrue,
+				Message: "Parent reference is valid",
+			},
+		}
+	}
+	expectedRouteStatus := tests.GetRouteStatusV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports, conditionMap)
+
+	grpcRoute, err := tests.GatewayClient.GatewayV1alpha2().GRPCRoutes(DEFAULT_NAMESPACE).Get(context.TODO(), grpcRouteName, metav1.GetOptions{})
+	if err != nil || grpcRoute == nil {
+		t.Fatalf("Couldn't get the GRPCRoute, err: %+v", err)
+	}
+	tests.ValidateHTTPRouteStatus(t, &gatewayv1.HTTPRouteStatus{RouteStatus: grpcRoute.Status.RouteStatus}, &gatewayv1.HTTPRouteStatus{RouteStatus: *expectedRouteStatus})
+
+	// the listener reports the attached GRPCRoute
+	g.Eventually(func() int32 {
+		gateway, err := tests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil || len(gateway.Status.Listeners) == 0 {
+			return -1
+		}
+		return gateway.Status.Listeners[0].AttachedRoutes
+	}, 30*time.Second).Should(gomega.Equal(int32(1)))
+
+	tests.TeardownGRPCRoute(t, grpcRouteName, DEFAULT_NAMESPACE)
+	tests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	tests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestGRPCRouteWithRegularExpressionMethodMatch(t *testing.T) {
+	gatewayClassName := "gateway-class-gr-02"
+	gatewayName := "gateway-gr-02"
+	grpcRouteName := "grpcroute-02"
+	ports := []int32{8080}
+
+	tests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := tests.GetListenersV1(ports)
+	tests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		gateway, err := tests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil {
+			t.Logf("Couldn't get the gateway, err: %+v", err)
+			return false
+		}
+		return apimeta.IsStatusConditionTrue(gateway.Status.Conditions, string(gatewayv1.GatewayConditionAccepted))
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := tests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	match := tests.GetGRPCRouteMatchV1alpha2("helloworld.*", "", "RegularExpression", nil)
+	rule := tests.GetGRPCRouteRuleV1alpha2([]gatewayv1alpha2.GRPCRouteMatch{match}, nil)
+	tests.SetupGRPCRoute(t, grpcRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, []gatewayv1alpha2.GRPCRouteRule{rule})
+
+	g.Consistently(func() int {
+		grpcRoute, err := tests.GatewayClient.GatewayV1alpha2().GRPCRoutes(DEFAULT_NAMESPACE).Get(context.TODO(), grpcRouteName, metav1.GetOptions{})
+		if err != nil || grpcRoute == nil {
+			t.Logf("Couldn't get the GRPCRoute, err: %+v", err)
+			return -1
+		}
+		return len(grpcRoute.Status.Parents)
+	}, 10*time.Second).Should(gomega.Equal(0))
+
+	tests.TeardownGRPCRoute(t, grpcRouteName, DEFAULT_NAMESPACE)
+	tests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	tests.TeardownGatewayClass(t, gatewayClassName)
+}
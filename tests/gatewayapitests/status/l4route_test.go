@@ -0,0 +1,191 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	tests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+)
+
+/* Test cases
+ * - TCPRoute with valid configurations
+ * - UDPRoute with valid configurations
+ * - Gateway with TCP listener combined with TLS Passthrough listener
+ */
+func TestTCPRouteWithValidConfig(t *testing.T) {
+	gatewayClassName := "gateway-class-l4r-01"
+	gatewayName := "gateway-l4r-01"
+	tcpRouteName := "tcproute-01"
+	ports := []int32{8081}
+
+	tests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := tests.GetL4ListenersV1(ports, gatewayv1.TCPProtocolType)
+	tests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		gateway, err := tests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil {
+			t.Logf("Couldn't get the gateway, err: %+v", err)
+			return false
+		}
+		return apimeta.IsStatusConditionTrue(gateway.Status.Conditions, string(gatewayv1.GatewayConditionAccepted))
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := tests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	tests.SetupTCPRoute(t, tcpRouteName, DEFAULT_NAMESPACE, parentRefs, nil)
+
+	g.Eventually(func() bool {
+		tcpRoute, err := tests.GatewayClient.GatewayV1alpha2().TCPRoutes(DEFAULT_NAMESPACE).Get(context.TODO(), tcpRouteName, metav1.GetOptions{})
+		if err != nil || tcpRoute == nil {
+			t.Logf("Couldn't get the TCPRoute, err: %+v", err)
+			return false
+		}
+		if len(tcpRoute.Status.Parents) != len(ports) {
+			return false
+		}
+		return apimeta.FindStatusCondition(tcpRoute.Status.Parents[0].Conditions, string(gatewayv1.RouteConditionAccepted)) != nil
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	conditionMap := make(map[string][]metav1.Condition)
+	for _, port := range ports {
+		conditionMap[fmt.Sprintf("%s-%d", gatewayName, port)] = []metav1.Condition{
+			{
+				Type:    string(gatewayv1.RouteConditionAccepted),
+				Reason:  string(gatewayv1.RouteReasonAccepted),
+				Status:  metav1.ConditionTrue,
+				Message: "Parent reference is valid",
+			},
+		}
+	}
+	expectedRouteStatus := tests.GetRouteStatusV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports, conditionMap)
+
+	tcpRoute, err := tests.GatewayClient.GatewayV1alpha2().TCPRoutes(DEFAULT_NAMESPACE).Get(context.TODO(), tcpRouteName, metav1.GetOptions{})
+	if err != nil || tcpRoute == nil {
+		t.Fatalf("Couldn't get the TCPRoute, err: %+v", err)
+	}
+	tests.ValidateHTTPRouteStatus(t, &gatewayv1.HTTPRouteStatus{RouteStatus: tcpRoute.Status.RouteStatus}, &gatewayv1.HTTPRouteStatus{RouteStatus: *expectedRouteStatus})
+
+	// the listener reports the attached TCPRoute
+	g.Eventually(func() int32 {
+		gateway, err := tests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil || len(gateway.Status.Listeners) == 0 {
+			return -1
+		}
+		return gateway.Status.Listeners[0].AttachedRoutes
+	}, 30*time.Second).Should(gomega.Equal(int32(1)))
+
+	tests.TeardownTCPRoute(t, tcpRouteName, DEFAULT_NAMESPACE)
+	tests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	tests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestUDPRouteWithValidConfig(t *testing.T) {
+	gatewayClassName := "gateway-class-l4r-02"
+	gatewayName := "gateway-l4r-02"
+	udpRouteName := "udproute-02"
+	ports := []int32{5353}
+
+	tests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := tests.GetL4ListenersV1(ports, gatewayv1.UDPProtocolType)
+	tests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		gateway, err := tests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil {
+			t.Logf("Couldn't get the gateway, err: %+v", err)
+			return false
+		}
+		return apimeta.IsStatusConditionTrue(gateway.Status.Conditions, string(gatewayv1.GatewayConditionAccepted))
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := tests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	tests.SetupUDPRoute(t, udpRouteName, DEFAULT_NAMESPACE, parentRefs, nil)
+
+	g.Eventually(func() bool {
+		udpRoute, err := tests.GatewayClient.GatewayV1alpha2().UDPRoutes(DEFAULT_NAMESPACE).Get(context.TODO(), udpRouteName, metav1.GetOptions{})
+		if err != nil || udpRoute == nil {
+			t.Logf("Couldn't get the UDPRoute, err: %+v", err)
+			return false
+		}
+		if len(udpRoute.Status.Parents) != len(ports) {
+			return false
+		}
+		return apimeta.IsStatusConditionTrue(udpRoute.Status.Parents[0].Conditions, string(gatewayv1.RouteConditionAccepted))
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	tests.TeardownUDPRoute(t, udpRouteName, DEFAULT_NAMESPACE)
+	tests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	tests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestGatewayWithTCPAndPassthroughListeners(t *testing.T) {
+	gatewayClassName := "gateway-class-l4r-03"
+	gatewayName := "gateway-l4r-03"
+	ports := []int32{8443, 8081}
+
+	tests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := tests.GetPassthroughListenersV1(ports[:1])
+	listeners = append(listeners, tests.GetL4ListenersV1(ports[1:], gatewayv1.TCPProtocolType)...)
+	tests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		gateway, err := tests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil {
+			t.Logf("Couldn't get the gateway, err: %+v", err)
+			return false
+		}
+		return apimeta.FindStatusCondition(gateway.Status.Conditions, string(gatewayv1.GatewayConditionAccepted)) != nil
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	expectedStatus := &gatewayv1.GatewayStatus{
+		Conditions: []metav1.Condition{
+			{
+				Type:               string(gatewayv1.GatewayConditionAccepted),
+				Status:             metav1.ConditionFalse,
+				Message:            "Gateway contains 1 invalid listener(s)",
+				ObservedGeneration: 1,
+				Reason:             string(gatewayv1.GatewayReasonListenersNotValid),
+			},
+		},
+		Listeners: tests.GetListenerStatusV1(ports, []int32{0, 0}),
+	}
+	expectedStatus.Listeners[0].SupportedKinds = akogatewayapilib.SupportedKinds[gatewayv1.TLSProtocolType]
+	expectedStatus.Listeners[1].SupportedKinds = akogatewayapilib.SupportedKinds[gatewayv1.TCPProtocolType]
+	expectedStatus.Listeners[1].Conditions[0].Reason = string(gatewayv1.ListenerReasonProtocolConflict)
+	expectedStatus.Listeners[1].Conditions[0].Status = metav1.ConditionFalse
+	expectedStatus.Listeners[1].Conditions[0].Message = "TCP and UDP listeners can not be combined with TLS listeners in a Gateway"
+
+	gateway, err := tests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+	if err != nil || gateway == nil {
+		t.Fatalf("Couldn't get the gateway, err: %+v", err)
+	}
+
+	tests.ValidateGatewayStatus(t, &gateway.Status, expectedStatus)
+	tests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	tests.TeardownGatewayClass(t, gatewayClassName)
+}
@@ -0,0 +1,188 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	tests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+)
+
+/* Test cases
+ * - TLSRoute with valid configurations
+ * - TLSRoute without hostnames
+ * - Gateway with TLS Passthrough listener combined with HTTPS listener
+ */
+func TestTLSRouteWithValidConfig(t *testing.T) {
+	gatewayClassName := "gateway-class-tr-01"
+	gatewayName := "gateway-tr-01"
+	tlsRouteName := "tlsroute-01"
+	ports := []int32{8443}
+
+	tests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := tests.GetPassthroughListenersV1(ports)
+	tests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		gateway, err := tests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil {
+			t.Logf("Couldn't get the gateway, err: %+v", err)
+			return false
+		}
+		return apimeta.IsStatusConditionTrue(gateway.Status.Conditions, string(gatewayv1.GatewayConditionAccepted))
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := tests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	hostnames := []gatewayv1.Hostname{"foo-8443.com"}
+	tests.SetupTLSRoute(t, tlsRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, nil)
+
+	g.Eventually(func() bool {
+		tlsRoute, err := tests.GatewayClient.GatewayV1alpha2().TLSRoutes(DEFAULT_NAMESPACE).Get(context.TODO(), tlsRouteName, metav1.GetOptions{})
+		if err != nil || tlsRoute == nil {
+			t.Logf("Couldn't get the TLSRoute, err: %+v", err)
+			return false
+		}
+		if len(tlsRoute.Status.Parents) != len(ports) {
+			return false
+		}
+		return apimeta.FindStatusCondition(tlsRoute.Status.Parents[0].Conditions, string(gatewayv1.RouteConditionAccepted)) != nil
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	conditionMap := make(map[string][]metav1.Condition)
+	for _, port := range ports {
+		conditionMap[fmt.Sprintf("%s-%d", gatewayName, port)] = []metav1.Condition{
+			{
+				Type:    string(gatewayv1.RouteConditionAccepted),
+				Reason:  string(gatewayv1.RouteReasonAccepted),
+				Status:  metav1.ConditionTrue,
+				Message: "Parent reference is valid",
+			},
+		}
+	}
+	expectedRouteStatus := tests.GetRouteStatusV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports, conditionMap)
+
+	tlsRoute, err := tests.GatewayClient.GatewayV1alpha2().TLSRoutes(DEFAULT_NAMESPACE).Get(context.TODO(), tlsRouteName, metav1.GetOptions{})
+	if err != nil || tlsRoute == nil {
+		t.Fatalf("Couldn't get the TLSRoute, err: %+v", err)
+	}
+	tests.ValidateHTTPRouteStatus(t, &gatewayv1.HTTPRouteStatus{RouteStatus: tlsRoute.Status.RouteStatus}, &gatewayv1.HTTPRouteStatus{RouteStatus: *expectedRouteStatus})
+
+	// the listener reports the attached TLSRoute
+	g.Eventually(func() int32 {
+		gateway, err := tests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil || len(gateway.Status.Listeners) == 0 {
+			return -1
+		}
+		return gateway.Status.Listeners[0].AttachedRoutes
+	}, 30*time.Second).Should(gomega.Equal(int32(1)))
+
+	tests.TeardownTLSRoute(t, tlsRouteName, DEFAULT_NAMESPACE)
+	tests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	tests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestTLSRouteWithoutHostnames(t *testing.T) {
+	gatewayClassName := "gateway-class-tr-02"
+	gatewayName := "gateway-tr-02"
+	tlsRouteName := "tlsroute-02"
+	ports := []int32{8443}
+
+	tests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := tests.GetPassthroughListenersV1(ports)
+	tests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		gateway, err := tests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil {
+			t.Logf("Couldn't get the gateway, err: %+v", err)
+			return false
+		}
+		return apimeta.IsStatusConditionTrue(gateway.Status.Conditions, string(gatewayv1.GatewayConditionAccepted))
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := tests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	tests.SetupTLSRoute(t, tlsRouteName, DEFAULT_NAMESPACE, parentRefs, nil, nil)
+
+	g.Consistently(func() int {
+		tlsRoute, err := tests.GatewayClient.GatewayV1alpha2().TLSRoutes(DEFAULT_NAMESPACE).Get(context.TODO(), tlsRouteName, metav1.GetOptions{})
+		if err != nil || tlsRoute == nil {
+			t.Logf("Couldn't get the TLSRoute, err: %+v", err)
+			return -1
+		}
+		return len(tlsRoute.Status.Parents)
+	}, 10*time.Second).Should(gomega.Equal(0))
+
+	tests.TeardownTLSRoute(t, tlsRouteName, DEFAULT_NAMESPACE)
+	tests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	tests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestGatewayWithPassthroughAndHTTPSListeners(t *testing.T) {
+	gatewayClassName := "gateway-class-tr-03"
+	gatewayName := "gateway-tr-03"
+	ports := []int32{8080, 8443}
+
+	tests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := tests.GetListenersV1(ports[:1])
+	listeners = append(listeners, tests.GetPassthroughListenersV1(ports[1:])...)
+	tests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		gateway, err := tests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil {
+			t.Logf("Couldn't get the gateway, err: %+v", err)
+			return false
+		}
+		return apimeta.FindStatusCondition(gateway.Status.Conditions, string(gatewayv1.GatewayConditionAccepted)) != nil
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	expectedStatus := &gatewayv1.GatewayStatus{
+		Conditions: []metav1.Condition{
+			{
+				Type:               string(gatewayv1.GatewayConditionAccepted),
+				Status:             metav1.ConditionFalse,
+				Message:            "Gateway contains 1 invalid listener(s)",
+				ObservedGeneration: 1,
+				Reason:             string(gatewayv1.GatewayReasonListenersNotValid),
+			},
+		},
+		Listeners: tests.GetListenerStatusV1(ports, []int32{0, 0}),
+	}
+	expectedStatus.Listeners[0].Conditions[0].Reason = string(gatewayv1.ListenerReasonProtocolConflict)
+	expectedStatus.Listeners[0].Conditions[0].Status = metav1.ConditionFalse
+	expectedStatus.Listeners[0].Conditions[0].Message = "TLS, TCP and UDP listeners can not be combined with HTTP or HTTPS listeners in a Gateway"
+	expectedStatus.Listeners[1].SupportedKinds = akogatewayapilib.SupportedKinds[gatewayv1.TLSProtocolType]
+
+	gateway, err := tests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+	if err != nil || gateway == nil {
+		t.Fatalf("Couldn't get the gateway, err: %+v", err)
+	}
+
+	tests.ValidateGatewayStatus(t, &gateway.Status, expectedStatus)
+	tests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	tests.TeardownGatewayClass(t, gatewayClassName)
+}
@@ -30,6 +30,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 	gatewayfake "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned/fake"
 
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
@@ -73,6 +75,20 @@ func GetModelName(namespace, name string) (string, string) {
 	return "admin/" + vsName, vsName
 }
 
+func GetL4ModelName(namespace, name string) (string, string) {
+	vsName := akogatewayapilib.Prefix + "cluster--" + namespace + "-" + name + "-L4"
+	return "admin/" + vsName, vsName
+}
+
+// EnableExperimentalRouteResources makes the fake discovery client serve the experimental
+// TLSRoute, TCPRoute and UDPRoute resources, so that their informers get initialised.
+func EnableExperimentalRouteResources() {
+	GatewayClient.Resources = append(GatewayClient.Resources, &metav1.APIResourceList{
+		GroupVersion: gatewayv1alpha2.GroupVersion.String(),
+		APIResources: []metav1.APIResource{{Name: "tlsroutes"}, {Name: "tcproutes"}, {Name: "udproutes"}, {Name: "grpcroutes"}},
+	})
+}
+
 func SetGatewayName(gw *gatewayv1.Gateway, name string) {
 	gw.Name = name
 }
@@ -153,6 +169,40 @@ func GetListenersV1(ports []int32, secrets ...string) []gatewayv1.Listener {
 	return listeners
 }
 
+func GetPassthroughListenersV1(ports []int32) []gatewayv1.Listener {
+	listeners := make([]gatewayv1.Listener, 0, len(ports))
+	for _, port := range ports {
+		hostname := fmt.Sprintf("foo-%d.com", port)
+		tlsMode := gatewayv1.TLSModePassthrough
+		listener := gatewayv1.Listener{
+			Name:     gatewayv1.SectionName(fmt.Sprintf("listener-%d", port)),
+			Port:     gatewayv1.PortNumber(port),
+			Protocol: gatewayv1.TLSProtocolType,
+			Hostname: (*gatewayv1.Hostname)(&hostname),
+			TLS: &gatewayv1.GatewayTLSConfig{
+				Mode: &tlsMode,
+			},
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners
+}
+
+func GetL4ListenersV1(ports []int32, protocol gatewayv1.ProtocolType) []gatewayv1.Listener {
+	listeners := make([]gatewayv1.Listener, 0, len(ports))
+	for _, port := range ports {
+		hostname := fmt.Sprintf("foo-%d.com", port)
+		listener := gatewayv1.Listener{
+			Name:     gatewayv1.SectionName(fmt.Sprintf("listener-%d", port)),
+			Port:     gatewayv1.PortNumber(port),
+			Protocol: protocol,
+			Hostname: (*gatewayv1.Hostname)(&hostname),
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners
+}
+
 func GetListenerStatusV1(ports []int32, attachedRoutes []int32) []gatewayv1.ListenerStatus {
 	listeners := make([]gatewayv1.ListenerStatus, 0, len(ports))
 	for i, port := range ports {
@@ -411,6 +461,31 @@ func GetHTTPRouteFilterV1(filterType string, actions []string) gatewayv1.HTTPRou
 			Hostname:   (*gatewayv1.PreciseHostname)(&host),
 			StatusCode: &statusCode302,
 		}
+	case "URLRewrite":
+		host := "rewrite.com"
+		routeFilter.URLRewrite = &gatewayv1.HTTPURLRewriteFilter{
+			Hostname: (*gatewayv1.PreciseHostname)(&host),
+		}
+		// actions hold the path modifier type and its value
+		if len(actions) == 2 {
+			routeFilter.URLRewrite.Path = &gatewayv1.HTTPPathModifier{Type: gatewayv1.HTTPPathModifierType(actions[0])}
+			if routeFilter.URLRewrite.Path.Type == gatewayv1.FullPathHTTPPathModifier {
+				routeFilter.URLRewrite.Path.ReplaceFullPath = &actions[1]
+			} else {
+				routeFilter.URLRewrite.Path.ReplacePrefixMatch = &actions[1]
+			}
+		}
+	case "RequestMirror":
+		// actions hold the name, namespace and port of the mirror backend
+		port, _ := strconv.Atoi(actions[2])
+		servicePort := gatewayv1.PortNumber(port)
+		routeFilter.RequestMirror = &gatewayv1.HTTPRequestMirrorFilter{
+			BackendRef: gatewayv1.BackendObjectReference{
+				Name:      gatewayv1.ObjectName(actions[0]),
+				Namespace: (*gatewayv1.Namespace)(&actions[1]),
+				Port:      &servicePort,
+			},
+		}
 	}
 	return routeFilter
 }
@@ -533,6 +608,333 @@ func TeardownHTTPRoute(t *testing.T, name, namespace string) {
 	hr.Delete(t)
 }
 
+type TLSRoute struct {
+	*gatewayv1alpha2.TLSRoute
+}
+
+func (tr *TLSRoute) TLSRouteV1alpha2(name, namespace string, parentRefs []gatewayv1.ParentReference, hostnames []gatewayv1.Hostname, rules []gatewayv1alpha2.TLSRouteRule) *gatewayv1alpha2.TLSRoute {
+	tlsRoute := &gatewayv1alpha2.TLSRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			ResourceVersion: time.Now().Local().String(),
+		},
+		Spec: gatewayv1alpha2.TLSRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: parentRefs,
+			},
+			Hostnames: hostnames,
+			Rules:     rules,
+		},
+	}
+	return tlsRoute
+}
+
+func GetTLSRouteRuleV1alpha2(backendRefs [][]string) gatewayv1alpha2.TLSRouteRule {
+	backends := make([]gatewayv1.BackendRef, 0, len(backendRefs))
+	for _, backendRef := range backendRefs {
+		backend := GetHTTPRouteBackendV1(backendRef)
+		backends = append(backends, backend.BackendRef)
+	}
+	return gatewayv1alpha2.TLSRouteRule{BackendRefs: backends}
+}
+
+func (tr *TLSRoute) Create(t *testing.T) {
+	_, err := GatewayClient.GatewayV1alpha2().TLSRoutes(tr.Namespace).Create(context.TODO(), tr.TLSRoute, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't create the TLSRoute, err: %+v", err)
+	}
+	t.Logf("Created TLSRoute %s", tr.Name)
+}
+
+func (tr *TLSRoute) Update(t *testing.T) {
+	_, err := GatewayClient.GatewayV1alpha2().TLSRoutes(tr.Namespace).Update(context.TODO(), tr.TLSRoute, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't update the TLSRoute, err: %+v", err)
+	}
+	t.Logf("Updated TLSRoute %s", tr.Name)
+}
+
+func (tr *TLSRoute) Delete(t *testing.T) {
+	err := GatewayClient.GatewayV1alpha2().TLSRoutes(tr.Namespace).Delete(context.TODO(), tr.Name, metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't delete the TLSRoute, err: %+v", err)
+	}
+	t.Logf("Deleted TLSRoute %s", tr.Name)
+}
+
+func SetupTLSRoute(t *testing.T, name, namespace string, parentRefs []gatewayv1.ParentReference, hostnames []gatewayv1.Hostname, rules []gatewayv1alpha2.TLSRouteRule) {
+	tr := &TLSRoute{}
+	tr.TLSRoute = tr.TLSRouteV1alpha2(name, namespace, parentRefs, hostnames, rules)
+	tr.Create(t)
+}
+
+func UpdateTLSRoute(t *testing.T, name, namespace string, parentRefs []gatewayv1.ParentReference, hostnames []gatewayv1.Hostname, rules []gatewayv1alpha2.TLSRouteRule) {
+	tr := &TLSRoute{}
+	tr.TLSRoute = tr.TLSRouteV1alpha2(name, namespace, parentRefs, hostnames, rules)
+	tr.Update(t)
+}
+
+func TeardownTLSRoute(t *testing.T, name, namespace string) {
+	tr := &TLSRoute{}
+	tr.TLSRoute = tr.TLSRouteV1alpha2(name, namespace, nil, nil, nil)
+	tr.Delete(t)
+}
+
+type GRPCRoute struct {
+	*gatewayv1alpha2.GRPCRoute
+}
+
+func (gr *GRPCRoute) GRPCRouteV1alpha2(name, namespace string, parentRefs []gatewayv1.ParentReference, hostnames []gatewayv1.Hostname, rules []gatewayv1alpha2.GRPCRouteRule) *gatewayv1alpha2.GRPCRoute {
+	grpcRoute := &gatewayv1alpha2.GRPCRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			ResourceVersion: time.Now().Local().String(),
+		},
+		Spec: gatewayv1alpha2.GRPCRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: parentRefs,
+			},
+			Hostnames: hostnames,
+			Rules:     rules,
+		},
+	}
+	return grpcRoute
+}
+
+// GetGRPCRouteMatchV1alpha2 returns a method match for the given service and method,
+// an empty service or method is left unset.
+func GetGRPCRouteMatchV1alpha2(service, method, methodMatchType string, headers []string) gatewayv1alpha2.GRPCRouteMatch {
+	routeMatch := gatewayv1alpha2.GRPCRouteMatch{}
+	routeMatch.Method = &gatewayv1alpha2.GRPCMethodMatch{}
+	routeMatch.Method.Type = (*gatewayv1alpha2.GRPCMethodMatchType)(proto.String(methodMatchType))
+	if service != "" {
+		routeMatch.Method.Service = proto.String(service)
+	}
+	if method != "" {
+		routeMatch.Method.Method = proto.String(method)
+	}
+	for _, header := range headers {
+		headerMatch := gatewayv1alpha2.GRPCHeaderMatch{}
+		headerMatch.Type = (*gatewayv1.HeaderMatchType)(proto.String("Exact"))
+		headerMatch.Name = gatewayv1alpha2.GRPCHeaderName(header)
+		headerMatch.Value = "some-value"
+		routeMatch.Headers = append(routeMatch.Headers, headerMatch)
+	}
+	return routeMatch
+}
+
+func GetGRPCRouteRuleV1alpha2(matches []gatewayv1alpha2.GRPCRouteMatch, backendRefs [][]string) gatewayv1alpha2.GRPCRouteRule {
+	backends := make([]gatewayv1alpha2.GRPCBackendRef, 0, len(backendRefs))
+	for _, backendRef := range backendRefs {
+		backend := GetHTTPRouteBackendV1(backendRef)
+		backends = append(backends, gatewayv1alpha2.GRPCBackendRef{BackendRef: backend.BackendRef})
+	}
+	return gatewayv1alpha2.GRPCRouteRule{Matches: matches, BackendRefs: backends}
+}
+
+func (gr *GRPCRoute) Create(t *testing.T) {
+	_, err := GatewayClient.GatewayV1alpha2().GRPCRoutes(gr.Namespace).Create(context.TODO(), gr.GRPCRoute, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't create the GRPCRoute, err: %+v", err)
+	}
+	t.Logf("Created GRPCRoute %s", gr.Name)
+}
+
+func (gr *GRPCRoute) Update(t *testing.T) {
+	_, err := GatewayClient.GatewayV1alpha2().GRPCRoutes(gr.Namespace).Update(context.TODO(), gr.GRPCRoute, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't update the GRPCRoute, err: %+v", err)
+	}
+	t.Logf("Updated GRPCRoute %s", gr.Name)
+}
+
+func (gr *GRPCRoute) Delete(t *testing.T) {
+	err := GatewayClient.GatewayV1alpha2().GRPCRoutes(gr.Namespace).Delete(context.TODO(), gr.Name, metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't delete the GRPCRoute, err: %+v", err)
+	}
+	t.Logf("Deleted GRPCRoute %s", gr.Name)
+}
+
+func SetupGRPCRoute(t *testing.T, name, namespace string, parentRefs []gatewayv1.ParentReference, hostnames []gatewayv1.Hostname, rules []gatewayv1alpha2.GRPCRouteRule) {
+	gr := &GRPCRoute{}
+	gr.GRPCRoute = gr.GRPCRouteV1alpha2(name, namespace, parentRefs, hostnames, rules)
+	gr.Create(t)
+}
+
+func UpdateGRPCRoute(t *testing.T, name, namespace string, parentRefs []gatewayv1.ParentReference, hostnames []gatewayv1.Hostname, rules []gatewayv1alpha2.GRPCRouteRule) {
+	gr := &GRPCRoute{}
+	gr.GRPCRoute = gr.GRPCRouteV1alpha2(name, namespace, parentRefs, hostnames, rules)
+	gr.Update(t)
+}
+
+func TeardownGRPCRoute(t *testing.T, name, namespace string) {
+	gr := &GRPCRoute{}
+	gr.GRPCRoute = gr.GRPCRouteV1alpha2(name, namespace, nil, nil, nil)
+	gr.Delete(t)
+}
+
+type TCPRoute struct {
+	*gatewayv1alpha2.TCPRoute
+}
+
+func (tr *TCPRoute) TCPRouteV1alpha2(name, namespace string, parentRefs []gatewayv1.ParentReference, rules []gatewayv1alpha2.TCPRouteRule) *gatewayv1alpha2.TCPRoute {
+	tcpRoute := &gatewayv1alpha2.TCPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			ResourceVersion: time.Now().Local().String(),
+		},
+		Spec: gatewayv1alpha2.TCPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: parentRefs,
+			},
+			Rules: rules,
+		},
+	}
+	return tcpRoute
+}
+
+func GetTCPRouteRuleV1alpha2(backendRefs [][]string) gatewayv1alpha2.TCPRouteRule {
+	return gatewayv1alpha2.TCPRouteRule{BackendRefs: GetTLSRouteRuleV1alpha2(backendRefs).BackendRefs}
+}
+
+func (tr *TCPRoute) Create(t *testing.T) {
+	_, err := GatewayClient.GatewayV1alpha2().TCPRoutes(tr.Namespace).Create(context.TODO(), tr.TCPRoute, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't create the TCPRoute, err: %+v", err)
+	}
+	t.Logf("Created TCPRoute %s", tr.Name)
+}
+
+func (tr *TCPRoute) Update(t *testing.T) {
+	_, err := GatewayClient.GatewayV1alpha2().TCPRoutes(tr.Namespace).Update(context.TODO(), tr.TCPRoute, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't update the TCPRoute, err: %+v", err)
+	}
+	t.Logf("Updated TCPRoute %s", tr.Name)
+}
+
+func (tr *TCPRoute) Delete(t *testing.T) {
+	err := GatewayClient.GatewayV1alpha2().TCPRoutes(tr.Namespace).Delete(context.TODO(), tr.Name, metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't delete the TCPRoute, err: %+v", err)
+	}
+	t.Logf("Deleted TCPRoute %s", tr.Name)
+}
+
+func SetupTCPRoute(t *testing.T, name, namespace string, parentRefs []gatewayv1.ParentReference, rules []gatewayv1alpha2.TCPRouteRule) {
+	tr := &TCPRoute{}
+	tr.TCPRoute = tr.TCPRouteV1alpha2(name, namespace, parentRefs, rules)
+	tr.Create(t)
+}
+
+func UpdateTCPRoute(t *testing.T, name, namespace string, parentRefs []gatewayv1.ParentReference, rules []gatewayv1alpha2.TCPRouteRule) {
+	tr := &TCPRoute{}
+	tr.TCPRoute = tr.TCPRouteV1alpha2(name, namespace, parentRefs, rules)
+	tr.Update(t)
+}
+
+func TeardownTCPRoute(t *testing.T, name, namespace string) {
+	tr := &TCPRoute{}
+	tr.TCPRoute = tr.TCPRouteV1alpha2(name, namespace, nil, nil)
+	tr.Delete(t)
+}
+
+type UDPRoute struct {
+	*gatewayv1alpha2.UDPRoute
+}
+
+func (ur *UDPRoute) UDPRouteV1alpha2(name, namespace string, parentRefs []gatewayv1.ParentReference, rules []gatewayv1alpha2.UDPRouteRule) *gatewayv1alpha2.UDPRoute {
+	udpRoute := &gatewayv1alpha2.UDPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			ResourceVersion: time.Now().Local().String(),
+		},
+		Spec: gatewayv1alpha2.UDPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: parentRefs,
+			},
+			Rules: rules,
+		},
+	}
+	return udpRoute
+}
+
+func GetUDPRouteRuleV1alpha2(backendRefs [][]string) gatewayv1alpha2.UDPRouteRule {
+	return gatewayv1alpha2.UDPRouteRule{BackendRefs: GetTLSRouteRuleV1alpha2(backendRefs).BackendRefs}
+}
+
+func (ur *UDPRoute) Create(t *testing.T) {
+	_, err := GatewayClient.GatewayV1alpha2().UDPRoutes(ur.Namespace).Create(context.TODO(), ur.UDPRoute, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't create the UDPRoute, err: %+v", err)
+	}
+	t.Logf("Created UDPRoute %s", ur.Name)
+}
+
+func (ur *UDPRoute) Delete(t *testing.T) {
+	err := GatewayClient.GatewayV1alpha2().UDPRoutes(ur.Namespace).Delete(context.TODO(), ur.Name, metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't delete the UDPRoute, err: %+v", err)
+	}
+	t.Logf("Deleted UDPRoute %s", ur.Name)
+}
+
+func SetupUDPRoute(t *testing.T, name, namespace string, parentRefs []gatewayv1.ParentReference, rules []gatewayv1alpha2.UDPRouteRule) {
+	ur := &UDPRoute{}
+	ur.UDPRoute = ur.UDPRouteV1alpha2(name, namespace, parentRefs, rules)
+	ur.Create(t)
+}
+
+func TeardownUDPRoute(t *testing.T, name, namespace string) {
+	ur := &UDPRoute{}
+	ur.UDPRoute = ur.UDPRouteV1alpha2(name, namespace, nil, nil)
+	ur.Delete(t)
+}
+
+// SetupReferenceGrant creates a ReferenceGrant in the namespace, which permits the fromKind
+// objects in the fromNamespace to refer to all the toKind objects in the namespace.
+func SetupReferenceGrant(t *testing.T, name, namespace, fromKind, fromNamespace, toKind string) {
+	grant := &gatewayv1beta1.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: gatewayv1beta1.ReferenceGrantSpec{
+			From: []gatewayv1beta1.ReferenceGrantFrom{
+				{
+					Group:     gatewayv1.GroupName,
+					Kind:      gatewayv1.Kind(fromKind),
+					Namespace: gatewayv1.Namespace(fromNamespace),
+				},
+			},
+			To: []gatewayv1beta1.ReferenceGrantTo{
+				{
+					Group: "",
+					Kind:  gatewayv1.Kind(toKind),
+				},
+			},
+		},
+	}
+	_, err := GatewayClient.GatewayV1beta1().ReferenceGrants(namespace).Create(context.TODO(), grant, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't create the ReferenceGrant, err: %+v", err)
+	}
+	t.Logf("Created ReferenceGrant %s", name)
+}
+
+func TeardownReferenceGrant(t *testing.T, name, namespace string) {
+	err := GatewayClient.GatewayV1beta1().ReferenceGrants(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't delete the ReferenceGrant, err: %+v", err)
+	}
+	t.Logf("Deleted ReferenceGrant %s", name)
+}
+
 func ValidateGatewayStatus(t *testing.T, actualStatus, expectedStatus *gatewayv1.GatewayStatus) {
 
 	g := gomega.NewGomegaWithT(t)
@@ -15,21 +15,34 @@
 package ingresstests
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"sort"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/webhook"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1beta1"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
 
 	"github.com/onsi/gomega"
+	"google.golang.org/protobuf/proto"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 func TestCreateDeleteHostRule(t *testing.T) {
@@ -506,6 +519,69 @@ func TestGoodToBadHostRule(t *testing.T) {
 	TearDownIngressForCacheSyncCheck(t, modelName)
 }
 
+func TestHostRuleStatusConditions(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	hrname := "samplehr-foo"
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+	integrationtest.SetupHostRule(t, hrname, "foo.com", true)
+
+	sniVSKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--foo.com"}
+	integrationtest.VerifyMetadataHostRule(t, g, sniVSKey, "default/samplehr-foo", true)
+
+	conditionStatus := func(conditionType string) string {
+		hostrule, err := v1beta1CRDClient.AkoV1beta1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+		if err != nil {
+			return ""
+		}
+		condition := meta.FindStatusCondition(hostrule.Status.Conditions, conditionType)
+		if condition == nil || condition.ObservedGeneration != hostrule.Generation {
+			return ""
+		}
+		return string(condition.Status) + "/" + condition.Reason
+	}
+	g.Eventually(func() string {
+		return conditionStatus(lib.CRDConditionAccepted)
+	}, 10*time.Second).Should(gomega.Equal("True/Accepted"))
+	g.Expect(conditionStatus(lib.CRDConditionResolvedRefs)).To(gomega.Equal("True/ResolvedRefs"))
+	g.Eventually(func() string {
+		return conditionStatus(lib.CRDConditionProgrammed)
+	}, 10*time.Second).Should(gomega.Equal("True/Programmed"))
+
+	hostrule, _ := v1beta1CRDClient.AkoV1beta1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+	g.Expect(hostrule.Status.ObservedGeneration).To(gomega.Equal(hostrule.Generation))
+	g.Expect(hostrule.Status.AppliedTo).To(gomega.HaveLen(1))
+	g.Expect(hostrule.Status.AppliedTo[0].Type).To(gomega.Equal("VirtualService"))
+	g.Expect(hostrule.Status.AppliedTo[0].Name).To(gomega.Equal("cluster--foo.com"))
+	g.Expect(hostrule.Status.AppliedTo[0].UUID).NotTo(gomega.BeEmpty())
+
+	// update hostrule with bad ref
+	hrUpdate := integrationtest.FakeHostRule{
+		Name:               hrname,
+		Namespace:          "default",
+		Fqdn:               "foo.com",
+		WafPolicy:          "thisisBADaviref",
+		ApplicationProfile: "thisisaviref-appprof",
+	}.HostRule()
+	hrUpdate.ResourceVersion = "2"
+	hrUpdate.Generation = 2
+	if _, err := v1beta1CRDClient.AkoV1beta1().HostRules("default").Update(context.TODO(), hrUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HostRule: %v", err)
+	}
+
+	g.Eventually(func() string {
+		return conditionStatus(lib.CRDConditionAccepted)
+	}, 10*time.Second).Should(gomega.Equal("False/Invalid"))
+	g.Expect(conditionStatus(lib.CRDConditionResolvedRefs)).To(gomega.Equal("False/InvalidRef"))
+	hostrule, _ = v1beta1CRDClient.AkoV1beta1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+	g.Expect(hostrule.Status.Status).To(gomega.Equal("Rejected"))
+	g.Expect(hostrule.Status.ObservedGeneration).To(gomega.Equal(int64(2)))
+
+	integrationtest.TeardownHostRule(t, g, sniVSKey, hrname)
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
 func TestInsecureHostAndHostrule(t *testing.T) {
 	// create insecure ingress, insecure hostrule, nothing should be applied
 	g := gomega.NewGomegaWithT(t)
@@ -1501,3 +1577,353 @@ func TestHTTPRuleWithInvalidPath(t *testing.T) {
 
 	TearDownIngressForCacheSyncCheck(t, modelName)
 }
+
+func TestHTTPRulePoolSettings(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	rrname := "samplerr-foo"
+
+	SetupDomain()
+	SetUpTestForIngress(t, modelName)
+	integrationtest.AddSecret("my-secret", "default", "tlsCert", "tlsKey")
+	integrationtest.PollForCompletion(t, modelName, 5)
+	ingressObject := integrationtest.FakeIngress{
+		Name:        "foo-with-targets",
+		Namespace:   "default",
+		DnsNames:    []string{"foo.com"},
+		Ips:         []string{"8.8.8.8"},
+		HostNames:   []string{"v1"},
+		Paths:       []string{"/foo"},
+		ServiceName: "avisvc",
+		TlsSecretDNS: map[string][]string{
+			"my-secret": {"foo.com"},
+		},
+	}
+
+	ingrFake := ingressObject.Ingress(true)
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), ingrFake, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	integrationtest.PollForCompletion(t, modelName, 5)
+
+	poolFooKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--default-foo.com_foo-foo-with-targets"}
+	httpRulePath := "/foo"
+	rrCreate := integrationtest.FakeHTTPRule{
+		Name:      rrname,
+		Namespace: "default",
+		Fqdn:      "foo.com",
+		PathProperties: []integrationtest.FakeHTTPRulePath{{
+			Path:       httpRulePath,
+			SslProfile: "thisisaviref-sslprofile",
+		}},
+	}.HTTPRule()
+	rrCreate.Spec.Paths[0].PoolSettings = v1beta1.HTTPRulePoolSettings{
+		ServerTimeout:                     proto.Uint32(30000),
+		MaxConcurrentConnectionsPerServer: proto.Int32(100),
+		ConnectionRampDuration:            proto.Int32(5),
+		GracefulDisableTimeout:            proto.Int32(-1),
+		RequestQueueEnabled:               proto.Bool(true),
+		RequestQueueDepth:                 proto.Uint32(256),
+	}
+	if _, err := v1beta1CRDClient.AkoV1beta1().HTTPRules("default").Create(context.TODO(), rrCreate, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding HTTPRule: %v", err)
+	}
+	integrationtest.VerifyMetadataHTTPRule(t, g, poolFooKey, "default/"+rrname+"/"+httpRulePath, true)
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	pool := nodes[0].SniNodes[0].PoolRefs[0]
+	g.Expect(*pool.ServerTimeout).To(gomega.Equal(uint32(30000)))
+	g.Expect(*pool.MaxConcurrentConnectionsPerServer).To(gomega.Equal(int32(100)))
+	g.Expect(*pool.ConnectionRampDuration).To(gomega.Equal(int32(5)))
+	g.Expect(*pool.GracefulDisableTimeout).To(gomega.Equal(int32(-1)))
+	g.Expect(*pool.RequestQueueEnabled).To(gomega.BeTrue())
+	g.Expect(*pool.RequestQueueDepth).To(gomega.Equal(uint32(256)))
+
+	// a connection ramp beyond 300 minutes rejects the httprule
+	rrUpdate := rrCreate.DeepCopy()
+	rrUpdate.Spec.Paths[0].PoolSettings.ConnectionRampDuration = proto.Int32(301)
+	rrUpdate.ResourceVersion = "2"
+	rrUpdate.Generation = 2
+	if _, err := v1beta1CRDClient.AkoV1beta1().HTTPRules("default").Update(context.TODO(), rrUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HTTPRule: %v", err)
+	}
+	g.Eventually(func() string {
+		httprule, _ := v1beta1CRDClient.AkoV1beta1().HTTPRules("default").Get(context.TODO(), rrname, metav1.GetOptions{})
+		return httprule.Status.Status
+	}, 10*time.Second).Should(gomega.Equal("Rejected"))
+	httprule, _ := v1beta1CRDClient.AkoV1beta1().HTTPRules("default").Get(context.TODO(), rrname, metav1.GetOptions{})
+	g.Expect(httprule.Status.Error).To(gomega.ContainSubstring("connectionRampDuration"))
+
+	// a request queue depth without the request queue enabled rejects the httprule
+	rrUpdate = rrCreate.DeepCopy()
+	rrUpdate.Spec.Paths[0].PoolSettings.RequestQueueEnabled = nil
+	rrUpdate.ResourceVersion = "3"
+	rrUpdate.Generation = 3
+	if _, err := v1beta1CRDClient.AkoV1beta1().HTTPRules("default").Update(context.TODO(), rrUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HTTPRule: %v", err)
+	}
+	g.Eventually(func() string {
+		httprule, _ := v1beta1CRDClient.AkoV1beta1().HTTPRules("default").Get(context.TODO(), rrname, metav1.GetOptions{})
+		return httprule.Status.Error
+	}, 10*time.Second).Should(gomega.ContainSubstring("requestQueueDepth"))
+
+	// delete httprule unsets the pool settings
+	integrationtest.TeardownHTTPRule(t, rrname)
+	integrationtest.VerifyMetadataHTTPRule(t, g, poolFooKey, "default/"+rrname+"/"+httpRulePath, false)
+	_, aviModel = objects.SharedAviGraphLister().Get(modelName)
+	nodes = aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	pool = nodes[0].SniNodes[0].PoolRefs[0]
+	g.Expect(pool.ServerTimeout).To(gomega.BeNil())
+	g.Expect(pool.MaxConcurrentConnectionsPerServer).To(gomega.BeNil())
+	g.Expect(pool.ConnectionRampDuration).To(gomega.BeNil())
+	g.Expect(pool.GracefulDisableTimeout).To(gomega.BeNil())
+	g.Expect(pool.RequestQueueEnabled).To(gomega.BeNil())
+	g.Expect(pool.RequestQueueDepth).To(gomega.BeNil())
+
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
+func TestHTTPRuleAlternateBackends(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	rrname := "samplerr-foo"
+
+	SetupDomain()
+	SetUpTestForIngress(t, modelName)
+	integrationtest.AddSecret("my-secret", "default", "tlsCert", "tlsKey")
+	integrationtest.CreateSVC(t, "default", "avisvc2", corev1.ProtocolTCP, corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, "default", "avisvc2", false, false, "2.2.2")
+	integrationtest.PollForCompletion(t, modelName, 5)
+	ingressObject := integrationtest.FakeIngress{
+		Name:        "foo-with-targets",
+		Namespace:   "default",
+		DnsNames:    []string{"foo.com"},
+		Ips:         []string{"8.8.8.8"},
+		HostNames:   []string{"v1"},
+		Paths:       []string{"/foo"},
+		ServiceName: "avisvc",
+		TlsSecretDNS: map[string][]string{
+			"my-secret": {"foo.com"},
+		},
+	}
+
+	ingrFake := ingressObject.Ingress(true)
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), ingrFake, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	integrationtest.PollForCompletion(t, modelName, 5)
+
+	poolName := "cluster--default-foo.com_foo-foo-with-targets"
+	alternatePoolName := poolName + "-avisvc2"
+	poolRatios := func() map[string]uint32 {
+		ratios := make(map[string]uint32)
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) == 0 || len(nodes[0].SniNodes) == 0 {
+			return ratios
+		}
+		pools := make(map[string]bool)
+		for _, pool := range nodes[0].SniNodes[0].PoolRefs {
+			pools[pool.Name] = true
+		}
+		for _, pg := range nodes[0].SniNodes[0].PoolGroupRefs {
+			if pg.Name != poolName {
+				continue
+			}
+			for _, member := range pg.Members {
+				name := strings.TrimPrefix(*member.PoolRef, "/api/pool?name=")
+				if pools[name] {
+					ratios[name] = *member.Ratio
+				}
+			}
+		}
+		return ratios
+	}
+
+	rrCreate := integrationtest.FakeHTTPRule{
+		Name:      rrname,
+		Namespace: "default",
+		Fqdn:      "foo.com",
+		PathProperties: []integrationtest.FakeHTTPRulePath{{
+			Path:       "/foo",
+			SslProfile: "thisisaviref-sslprofile",
+		}},
+	}.HTTPRule()
+	rrCreate.Spec.Paths[0].AlternateBackends = []v1beta1.HTTPRuleAlternateBackend{{ServiceName: "avisvc2", Weight: 20}}
+	if _, err := v1beta1CRDClient.AkoV1beta1().HTTPRules("default").Create(context.TODO(), rrCreate, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding HTTPRule: %v", err)
+	}
+	g.Eventually(poolRatios, 10*time.Second).Should(gomega.Equal(map[string]uint32{poolName: 80, alternatePoolName: 20}))
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	for _, pool := range nodes[0].SniNodes[0].PoolRefs {
+		if pool.Name == alternatePoolName {
+			g.Expect(pool.Servers).To(gomega.HaveLen(1))
+			g.Expect(*pool.Servers[0].Ip.Addr).To(gomega.Equal("2.2.2.1"))
+			// the settings of the path apply to the pools of the alternate backends as well
+			g.Expect(*pool.SslProfileRef).To(gomega.ContainSubstring("thisisaviref-sslprofile"))
+		}
+	}
+	g.Eventually(func() []v1beta1.HTTPRuleTrafficSplit {
+		httprule, _ := v1beta1CRDClient.AkoV1beta1().HTTPRules("default").Get(context.TODO(), rrname, metav1.GetOptions{})
+		return httprule.Status.TrafficSplit
+	}, 10*time.Second).Should(gomega.Equal([]v1beta1.HTTPRuleTrafficSplit{{
+		Target:  "/foo",
+		Ingress: "default/foo-with-targets",
+		Backends: []v1beta1.HTTPRuleBackendWeight{
+			{ServiceName: "avisvc", Weight: 80},
+			{ServiceName: "avisvc2", Weight: 20},
+		},
+	}}))
+
+	// weights adding up to more than 100 reject the httprule
+	rrUpdate := rrCreate.DeepCopy()
+	rrUpdate.Spec.Paths[0].AlternateBackends = []v1beta1.HTTPRuleAlternateBackend{
+		{ServiceName: "avisvc2", Weight: 60},
+		{ServiceName: "avisvc3", Weight: 50},
+	}
+	rrUpdate.ResourceVersion = "2"
+	rrUpdate.Generation = 2
+	if _, err := v1beta1CRDClient.AkoV1beta1().HTTPRules("default").Update(context.TODO(), rrUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HTTPRule: %v", err)
+	}
+	g.Eventually(func() string {
+		httprule, _ := v1beta1CRDClient.AkoV1beta1().HTTPRules("default").Get(context.TODO(), rrname, metav1.GetOptions{})
+		return httprule.Status.Error
+	}, 10*time.Second).Should(gomega.ContainSubstring("add up to 110"))
+
+	// removing the alternate backends removes their pools, and the traffic split
+	rrUpdate = rrCreate.DeepCopy()
+	rrUpdate.Spec.Paths[0].AlternateBackends = nil
+	rrUpdate.ResourceVersion = "3"
+	rrUpdate.Generation = 3
+	if _, err := v1beta1CRDClient.AkoV1beta1().HTTPRules("default").Update(context.TODO(), rrUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HTTPRule: %v", err)
+	}
+	g.Eventually(poolRatios, 10*time.Second).Should(gomega.Equal(map[string]uint32{poolName: 100}))
+	_, aviModel = objects.SharedAviGraphLister().Get(modelName)
+	nodes = aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	for _, pool := range nodes[0].SniNodes[0].PoolRefs {
+		g.Expect(pool.Name).NotTo(gomega.Equal(alternatePoolName))
+	}
+	g.Eventually(func() int {
+		httprule, _ := v1beta1CRDClient.AkoV1beta1().HTTPRules("default").Get(context.TODO(), rrname, metav1.GetOptions{})
+		return len(httprule.Status.TrafficSplit)
+	}, 10*time.Second).Should(gomega.Equal(0))
+
+	integrationtest.TeardownHTTPRule(t, rrname)
+	integrationtest.DelSVC(t, "default", "avisvc2")
+	integrationtest.DelEP(t, "default", "avisvc2")
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
+func crdAdmissionResponse(t *testing.T, kind string, obj interface{}) *admissionv1.AdmissionResponse {
+	raw, _ := json.Marshal(obj)
+	review := admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request: &admissionv1.AdmissionRequest{
+			UID:       "test-uid",
+			Kind:      metav1.GroupVersionKind{Group: "ako.vmware.com", Version: "v1beta1", Kind: kind},
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	}
+	body, _ := json.Marshal(review)
+	req := httptest.NewRequest(http.MethodPost, webhook.ValidateCRDRoute, bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	webhook.ValidateCRD(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected admission webhook response code %d: %s", rec.Code, rec.Body.String())
+	}
+	result := admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil || result.Response == nil {
+		t.Fatalf("invalid admission webhook response %s: %v", rec.Body.String(), err)
+	}
+	if result.Response.UID != "test-uid" {
+		t.Fatalf("unexpected uid %s in admission webhook response", result.Response.UID)
+	}
+	return result.Response
+}
+
+func TestHostRuleAdmissionWebhook(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	hrname := "samplehr-foo"
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+	g.Eventually(k8s.IsCRDAdmissionReady, 20*time.Second).Should(gomega.BeTrue())
+
+	integrationtest.SetupHostRule(t, hrname, "foo.com", true)
+	g.Eventually(func() string {
+		hostrule, _ := v1beta1CRDClient.AkoV1beta1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+		return hostrule.Status.Status
+	}, 20*time.Second).Should(gomega.Equal("Accepted"))
+	hrUpdate := integrationtest.FakeHostRule{
+		Name:      hrname,
+		Namespace: "default",
+		Fqdn:      "foo.com",
+	}.HostRule()
+	hrUpdate.Spec.VirtualHost.FqdnType = v1beta1.Exact
+	hrUpdate.Spec.VirtualHost.Aliases = []string{"alias1.com"}
+	hrUpdate.ResourceVersion = "2"
+	if _, err := v1beta1CRDClient.AkoV1beta1().HostRules("default").Update(context.TODO(), hrUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HostRule: %v", err)
+	}
+	g.Eventually(func() []string {
+		_, aliases := objects.SharedCRDLister().GetFQDNToAliasesMapping("foo.com")
+		return aliases
+	}, 20*time.Second).Should(gomega.ContainElement("alias1.com"))
+
+	// The refs of the accepted HostRule are in the ref index, admitting them must not call the controller.
+	var controllerRefChecks int32
+	integrationtest.AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.Contains(r.URL.RawQuery, "thisisaviref") {
+			atomic.AddInt32(&controllerRefChecks, 1)
+		}
+		integrationtest.NormalControllerServer(w, r)
+	})
+	defer integrationtest.ResetMiddleware()
+
+	// HostRule with the FQDN of an existing HostRule.
+	duplicate := integrationtest.FakeHostRule{
+		Name:       "duplicate-hr-foo",
+		Namespace:  "default",
+		Fqdn:       "foo.com",
+		WafPolicy:  "thisisaviref-waf",
+		SslProfile: "thisisaviref-sslprof",
+	}.HostRule()
+	resp := crdAdmissionResponse(t, lib.HostRule, duplicate)
+	g.Expect(resp.Allowed).To(gomega.BeFalse())
+	g.Expect(resp.Result.Message).To(gomega.Equal("duplicate fqdn foo.com found in default/" + hrname))
+
+	// HostRule with an alias in use by another HostRule.
+	aliasClash := integrationtest.FakeHostRule{
+		Name:      "alias-hr-baz",
+		Namespace: "default",
+		Fqdn:      "baz.com",
+	}.HostRule()
+	aliasClash.Spec.VirtualHost.FqdnType = v1beta1.Exact
+	aliasClash.Spec.VirtualHost.Aliases = []string{"alias1.com"}
+	resp = crdAdmissionResponse(t, lib.HostRule, aliasClash)
+	g.Expect(resp.Allowed).To(gomega.BeFalse())
+	g.Expect(resp.Result.Message).To(gomega.Equal("alias1.com is already in use by hostrule foo.com"))
+
+	// Valid HostRule.
+	valid := integrationtest.FakeHostRule{
+		Name:       "valid-hr-baz",
+		Namespace:  "default",
+		Fqdn:       "baz.com",
+		WafPolicy:  "thisisaviref-waf",
+		SslProfile: "thisisaviref-sslprof",
+	}.HostRule()
+	resp = crdAdmissionResponse(t, lib.HostRule, valid)
+	g.Expect(resp.Allowed).To(gomega.BeTrue())
+	g.Expect(atomic.LoadInt32(&controllerRefChecks)).To(gomega.BeZero())
+
+	integrationtest.ResetMiddleware()
+	sniVSKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--foo.com"}
+	integrationtest.TeardownHostRule(t, g, sniVSKey, hrname)
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
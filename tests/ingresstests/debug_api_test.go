@@ -0,0 +1,250 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package ingresstests
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/debugapi"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+)
+
+const debugApiToken = "debug-token"
+
+func debugApiGet(t *testing.T, handler http.Handler, uri, token string, response interface{}) int {
+	req := httptest.NewRequest(http.MethodGet, uri, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if response != nil && rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), response); err != nil {
+			t.Fatalf("error in decoding the response of %s: %v", uri, err)
+		}
+	}
+	return rec.Code
+}
+
+func TestDebugApi(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpTestForIngress(t, modelName)
+	tlsKey := "debug-api-tls-private-key"
+	integrationtest.AddSecret("my-secret", "default", "tlsCert", tlsKey)
+	apiServer := &api.ApiServer{Models: []models.ApiModel{&debugapi.DebugModel{}}}
+	handler := apiServer.SetRouter(false, nil)
+
+	ingrFake := (integrationtest.FakeIngress{
+		Name:      "foo-with-targets",
+		Namespace: "default",
+		DnsNames:  []string{"foo.com", "noo.com"},
+		Ips:       []string{"8.8.8.8"},
+		Paths:     []string{"/foo/bar"},
+		HostNames: []string{"v1"},
+		TlsSecretDNS: map[string][]string{
+			"my-secret": {"foo.com"},
+		},
+		ServiceName: "avisvc",
+	}).IngressMultiPath()
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), ingrFake, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	integrationtest.PollForCompletion(t, modelName, 5)
+	g.Eventually(func() bool {
+		_, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(cache.NamespaceName{Namespace: "admin", Name: "cluster--foo.com"})
+		return found
+	}, 30*time.Second).Should(gomega.BeTrue())
+
+	// The requests without the token from the avi-secret are rejected.
+	uri := debugapi.ModelRoute + "?name=" + modelName
+	g.Expect(debugApiGet(t, handler, uri, "", nil)).To(gomega.Equal(http.StatusUnauthorized))
+	g.Expect(debugApiGet(t, handler, uri, "wrong-token", nil)).To(gomega.Equal(http.StatusUnauthorized))
+	g.Expect(debugApiGet(t, handler, debugapi.ModelRoute+"?name=admin/unknown", debugApiToken, nil)).To(gomega.Equal(http.StatusNotFound))
+
+	var modelNames []string
+	g.Expect(debugApiGet(t, handler, debugapi.ModelsRoute, debugApiToken, &modelNames)).To(gomega.Equal(http.StatusOK))
+	g.Expect(modelNames).To(gomega.ContainElement(modelName))
+
+	var model debugapi.ModelResponse
+	g.Expect(debugApiGet(t, handler, uri, debugApiToken, &model)).To(gomega.Equal(http.StatusOK))
+	g.Expect(model.Name).To(gomega.Equal(modelName))
+	var vsNode map[string]interface{}
+	for _, node := range model.Nodes {
+		if node.Type == "VirtualServiceNode" {
+			g.Expect(json.Unmarshal(node.Node, &vsNode)).To(gomega.Succeed())
+		}
+	}
+	g.Expect(vsNode["Name"]).To(gomega.Equal("cluster--Shared-L7-0"))
+	g.Expect(vsNode["SniNodes"]).To(gomega.HaveLen(1))
+
+	// The private key of the certificate of the SNI child is redacted.
+	sniNode := vsNode["SniNodes"].([]interface{})[0].(map[string]interface{})
+	g.Expect(sniNode["SSLKeyCertRefs"]).To(gomega.HaveLen(1))
+	g.Expect(sniNode["SSLKeyCertRefs"].([]interface{})[0]).To(gomega.HaveKeyWithValue("Key", "<redacted>"))
+	req := httptest.NewRequest(http.MethodGet, uri, nil)
+	req.Header.Set("Authorization", "Bearer "+debugApiToken)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	g.Expect(rec.Body.String()).NotTo(gomega.ContainSubstring(tlsKey))
+	g.Expect(rec.Body.String()).NotTo(gomega.ContainSubstring(base64.StdEncoding.EncodeToString([]byte(tlsKey))))
+
+	var cacheResp debugapi.CacheResponse
+	g.Expect(debugApiGet(t, handler, debugapi.CacheRoute+"?name="+modelName, debugApiToken, &cacheResp)).To(gomega.Equal(http.StatusOK))
+	var vsNames []string
+	for _, vsCache := range cacheResp.VirtualServices {
+		g.Expect(vsCache.Uuid).NotTo(gomega.BeEmpty())
+		g.Expect(vsCache.CloudConfigCksum).NotTo(gomega.BeEmpty())
+		vsNames = append(vsNames, vsCache.Name)
+	}
+	g.Expect(vsNames).To(gomega.ConsistOf("cluster--Shared-L7-0", "cluster--foo.com"))
+	g.Expect(cacheResp.VSVips).To(gomega.HaveLen(1))
+	g.Expect(cacheResp.Pools).NotTo(gomega.BeEmpty())
+	g.Expect(cacheResp.SSLKeyCerts).To(gomega.HaveLen(1))
+
+	var k8sObjects []debugapi.K8sObject
+	g.Expect(debugApiGet(t, handler, debugapi.K8sRoute+"?name="+modelName, debugApiToken, &k8sObjects)).To(gomega.Equal(http.StatusOK))
+	g.Expect(k8sObjects).To(gomega.ContainElements(
+		debugapi.K8sObject{Kind: utils.Ingress, Namespace: "default", Name: "foo-with-targets"},
+		debugapi.K8sObject{Kind: utils.Service, Namespace: "default", Name: "avisvc"},
+	))
+
+	var queues []debugapi.QueueStatus
+	g.Expect(debugApiGet(t, handler, debugapi.QueuesRoute, debugApiToken, &queues)).To(gomega.Equal(http.StatusOK))
+	queueNames := make(map[string]int)
+	for _, queue := range queues {
+		queueNames[queue.Name] = len(queue.Workers)
+	}
+	g.Expect(queueNames).To(gomega.HaveKey(utils.ObjectIngestionLayer))
+	g.Expect(queueNames).To(gomega.HaveKey(utils.GraphLayer))
+	g.Expect(queueNames[utils.GraphLayer]).To(gomega.BeNumerically(">", 0))
+
+	if err := KubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), "foo-with-targets", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	g.Eventually(func() bool {
+		_, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(cache.NamespaceName{Namespace: "admin", Name: "cluster--foo.com"})
+		return found
+	}, 30*time.Second).Should(gomega.BeFalse())
+	KubeClient.CoreV1().Secrets("default").Delete(context.TODO(), "my-secret", metav1.DeleteOptions{})
+	TearDownTestForIngress(t, modelName)
+}
+
+func TestDebugApiDeadLetterKeys(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpTestForIngress(t, modelName)
+	apiServer := &api.ApiServer{Models: []models.ApiModel{&debugapi.DebugModel{}}}
+	handler := apiServer.SetRouter(false, nil)
+	graphQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
+	graphQueue.SetMaxAttempts(2)
+	defer graphQueue.SetMaxAttempts(utils.DefaultMaxAttempts)
+
+	// The controller rejects all the changes, with an error that is not retried by the retry layers.
+	integrationtest.AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && !strings.Contains(r.URL.EscapedPath(), "login") {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintln(w, `{"error": "bad request"}`)
+			return
+		}
+		integrationtest.NormalControllerServer(w, r)
+	})
+	defer integrationtest.ResetMiddleware()
+
+	ingrFake := (integrationtest.FakeIngress{
+		Name:        "foo-with-targets",
+		Namespace:   "default",
+		DnsNames:    []string{"foo.com"},
+		Ips:         []string{"8.8.8.8"},
+		Paths:       []string{"/foo"},
+		ServiceName: "avisvc",
+	}).Ingress()
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), ingrFake, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	integrationtest.PollForCompletion(t, modelName, 5)
+
+	graphLayerDeadLetterKeys := func() []utils.DeadLetterKey {
+		var queues []debugapi.QueueStatus
+		g.Expect(debugApiGet(t, handler, debugapi.QueuesRoute, debugApiToken, &queues)).To(gomega.Equal(http.StatusOK))
+		for _, queue := range queues {
+			if queue.Name == utils.GraphLayer {
+				return queue.DeadLetterKeys
+			}
+		}
+		return nil
+	}
+	var deadLetterKey utils.DeadLetterKey
+	g.Eventually(func() bool {
+		for _, key := range graphLayerDeadLetterKeys() {
+			if key.Key == modelName {
+				deadLetterKey = key
+				return true
+			}
+		}
+		return false
+	}, 30*time.Second).Should(gomega.BeTrue())
+	g.Expect(deadLetterKey.Attempts).To(gomega.Equal(2))
+	g.Expect(deadLetterKey.LastError).NotTo(gomega.BeEmpty())
+	g.Expect(deadLetterKey.LastFailure.IsZero()).To(gomega.BeFalse())
+
+	// Once the controller accepts the changes, the model is synced again on a change to the ingress.
+	integrationtest.ResetMiddleware()
+	ingrFake = (integrationtest.FakeIngress{
+		Name:        "foo-with-targets",
+		Namespace:   "default",
+		DnsNames:    []string{"foo.com"},
+		Ips:         []string{"8.8.8.8"},
+		Paths:       []string{"/bar"},
+		ServiceName: "avisvc",
+	}).Ingress()
+	ingrFake.ResourceVersion = "2"
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Update(context.TODO(), ingrFake, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Ingress: %v", err)
+	}
+	g.Eventually(func() bool {
+		_, found := cache.SharedAviObjCache().PoolCache.AviCacheGet(cache.NamespaceName{Namespace: "admin", Name: "cluster--foo.com_bar-default-foo-with-targets"})
+		return found
+	}, 30*time.Second).Should(gomega.BeTrue())
+	g.Eventually(func() []utils.DeadLetterKey {
+		return graphLayerDeadLetterKeys()
+	}, 10*time.Second).Should(gomega.BeEmpty())
+
+	if err := KubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), "foo-with-targets", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	g.Eventually(func() bool {
+		_, found := cache.SharedAviObjCache().PoolCache.AviCacheGet(cache.NamespaceName{Namespace: "admin", Name: "cluster--foo.com_bar-default-foo-with-targets"})
+		return found
+	}, 30*time.Second).Should(gomega.BeFalse())
+	TearDownTestForIngress(t, modelName)
+}
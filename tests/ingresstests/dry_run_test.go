@@ -0,0 +1,194 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package ingresstests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"github.com/vmware/alb-sdk/go/clients"
+	"github.com/vmware/alb-sdk/go/session"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/debugapi"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/rest"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/avisimulator"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+)
+
+// saveCacheSnapshot saves the cache snapshot served by the debug API to a file, and returns the
+// cache read back from it.
+func saveCacheSnapshot(t *testing.T, handler http.Handler, edit func(*cache.CacheSnapshot)) *cache.AviObjCache {
+	var snapshot cache.CacheSnapshot
+	if code := debugApiGet(t, handler, debugapi.SnapshotRoute, debugApiToken, &snapshot); code != http.StatusOK {
+		t.Fatalf("error in getting the cache snapshot, status code: %d", code)
+	}
+	if edit != nil {
+		edit(&snapshot)
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("error in encoding the cache snapshot: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("error in saving the cache snapshot: %v", err)
+	}
+	aviObjCache, err := cache.ReadCacheSnapshot(path)
+	if err != nil {
+		t.Fatalf("error in reading the cache snapshot: %v", err)
+	}
+	return aviObjCache
+}
+
+func planEntries(planner *rest.RestOperations, plan *rest.Plan, modelName string) []rest.PlanEntry {
+	planner.DequeueNodes(modelName)
+	var entries []rest.PlanEntry
+	for _, entry := range plan.Entries() {
+		if entry.Model == modelName {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func TestDryRunPlan(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpTestForIngress(t, modelName)
+	apiServer := &api.ApiServer{Models: []models.ApiModel{&debugapi.DebugModel{}}}
+	handler := apiServer.SetRouter(false, nil)
+	initialCache := saveCacheSnapshot(t, handler, nil)
+
+	ingrFake := (integrationtest.FakeIngress{
+		Name:        "foo-with-targets",
+		Namespace:   "default",
+		DnsNames:    []string{"foo.com"},
+		Ips:         []string{"8.8.8.8"},
+		HostNames:   []string{"v1"},
+		Paths:       []string{"/foo"},
+		ServiceName: "avisvc",
+	}).Ingress()
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), ingrFake, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	integrationtest.PollForCompletion(t, modelName, 5)
+	var poolName string
+	g.Eventually(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found || aviModel == nil {
+			return false
+		}
+		vs := aviModel.(*nodes.AviObjectGraph).GetAviVS()
+		if len(vs) == 0 || len(vs[0].PoolRefs) == 0 {
+			return false
+		}
+		poolName = vs[0].PoolRefs[0].Name
+		_, found = cache.SharedAviObjCache().PoolCache.AviCacheGet(cache.NamespaceName{Namespace: "admin", Name: poolName})
+		return found
+	}, 30*time.Second).Should(gomega.BeTrue())
+
+	// Against the cache saved before the ingress was added, the objects for the ingress are to be
+	// created, and nothing is to be deleted.
+	plan := rest.NewPlan()
+	entries := planEntries(rest.NewRestPlanner(initialCache, nil, plan), plan, modelName)
+	poolCreated := false
+	for _, entry := range entries {
+		g.Expect(entry.Action).NotTo(gomega.Equal(rest.PlanDelete))
+		if entry.Action == rest.PlanCreate && entry.ObjectType == "Pool" && entry.Name == poolName {
+			poolCreated = true
+		}
+	}
+	g.Expect(poolCreated).To(gomega.BeTrue())
+
+	// Against the cache saved after the ingress was synced, there is nothing to create or delete.
+	// The datascript is updated on every sync with the mock controller, whose responses do not
+	// carry the poolgroups of the datascript.
+	plan = rest.NewPlan()
+	for _, entry := range planEntries(rest.NewRestPlanner(saveCacheSnapshot(t, handler, nil), nil, plan), plan, modelName) {
+		g.Expect(entry.Action).To(gomega.Equal(rest.PlanUpdate))
+		g.Expect(entry.ObjectType).To(gomega.Equal("VSDataScriptSet"))
+	}
+
+	// When the checksum of the pool does not match, the pool is to be updated, and the diff is
+	// computed against the pool in the controller.
+	var poolUuid string
+	syncedCache := saveCacheSnapshot(t, handler, func(snapshot *cache.CacheSnapshot) {
+		for _, pool := range snapshot.Pools {
+			if pool.Name == poolName {
+				pool.CloudConfigCksum = "0"
+				poolUuid = pool.Uuid
+			}
+		}
+	})
+	g.Expect(poolUuid).NotTo(gomega.BeEmpty())
+	sim := avisimulator.NewSimulator()
+	_, err := sim.Create("pool", avisimulator.DefaultTenant, map[string]interface{}{
+		"name":    poolName,
+		"uuid":    poolUuid,
+		"servers": []interface{}{map[string]interface{}{"ip": map[string]interface{}{"addr": "10.0.0.1", "type": "V4"}}},
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	server := httptest.NewTLSServer(sim)
+	defer server.Close()
+	client, err := clients.NewAviClient(strings.TrimPrefix(server.URL, "https://"), "admin",
+		session.SetPassword("admin"), session.SetInsecure, session.DisableControllerStatusCheckOnFailure(true))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	plan = rest.NewPlan()
+	entries = planEntries(rest.NewRestPlanner(syncedCache, &utils.AviRestClientPool{AviClient: []*clients.AviClient{client}}, plan), plan, modelName)
+	var poolEntries []rest.PlanEntry
+	for _, entry := range entries {
+		if entry.ObjectType == "Pool" {
+			poolEntries = append(poolEntries, entry)
+		}
+	}
+	g.Expect(poolEntries).To(gomega.HaveLen(1))
+	g.Expect(poolEntries[0].Action).To(gomega.Equal(rest.PlanUpdate))
+	g.Expect(poolEntries[0].Uuid).To(gomega.Equal(poolUuid))
+	diffs := make(map[string]rest.FieldDiff)
+	for _, diff := range poolEntries[0].Diff {
+		diffs[diff.Field] = diff
+	}
+	g.Expect(diffs).To(gomega.HaveKey("servers[0].ip.addr"))
+	g.Expect(diffs["servers[0].ip.addr"].Current).To(gomega.Equal("10.0.0.1"))
+	// The fields which match are not reported.
+	g.Expect(diffs).NotTo(gomega.HaveKey("name"))
+
+	// Nothing is applied by the planner.
+	g.Expect(sim.Get("pool", avisimulator.DefaultTenant, poolName)["servers"]).To(gomega.HaveLen(1))
+	poolCache, _ := cache.SharedAviObjCache().PoolCache.AviCacheGet(cache.NamespaceName{Namespace: "admin", Name: poolName})
+	g.Expect(poolCache.(*cache.AviPoolCache).CloudConfigCksum).NotTo(gomega.Equal("0"))
+
+	if err := KubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), "foo-with-targets", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	TearDownTestForIngress(t, modelName)
+}
@@ -40,6 +40,7 @@ import (
 	"github.com/onsi/gomega"
 	"github.com/vmware/alb-sdk/go/models"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
@@ -74,8 +75,9 @@ func TestMain(m *testing.M) {
 	akoControlConfig.SetEventRecorder(lib.AKOEventComponent, KubeClient, true)
 	akoControlConfig.SetDefaultLBController(true)
 	data := map[string][]byte{
-		"username": []byte("admin"),
-		"password": []byte("admin"),
+		"username":      []byte("admin"),
+		"password":      []byte("admin"),
+		"debugApiToken": []byte(debugApiToken),
 	}
 	object := metav1.ObjectMeta{Name: "avi-secret", Namespace: utils.GetAKONamespace()}
 	secret := &corev1.Secret{Data: data, ObjectMeta: object}
@@ -2964,3 +2966,182 @@ func TestDualStackMultipleBackendService(t *testing.T) {
 
 	TearDownTestForIngress(t, modelName)
 }
+
+func TestL7ModelDefaultBackend(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	modelName := "admin/cluster--Shared-L7-0"
+	vsName := "cluster--Shared-L7-0"
+	SetUpTestForIngress(t, modelName)
+
+	defaultBackend := &networkingv1.IngressBackend{
+		Service: &networkingv1.IngressServiceBackend{
+			Name: "avisvc",
+			Port: networkingv1.ServiceBackendPort{Number: 8080},
+		},
+	}
+	ingrFake := (integrationtest.FakeIngress{
+		Name:        "foo-with-default",
+		Namespace:   "default",
+		DnsNames:    []string{"foo.com"},
+		Ips:         []string{"8.8.8.8"},
+		HostNames:   []string{"v1"},
+		Paths:       []string{"/foo"},
+		ServiceName: "avisvc",
+	}).Ingress()
+	ingrFake.Spec.DefaultBackend = defaultBackend
+	ingrFake.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), ingrFake, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	integrationtest.PollForCompletion(t, modelName, 5)
+
+	g.Eventually(func() string {
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			if nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS(); len(nodes) > 0 {
+				return nodes[0].DefaultPoolGroup
+			}
+		}
+		return ""
+	}, 10*time.Second).Should(gomega.Equal(lib.GetL7DefaultBackendPGName(vsName)))
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes[0].PoolGroupRefs).To(gomega.HaveLen(2))
+	g.Expect(nodes[0].PoolRefs).To(gomega.HaveLen(2))
+	for _, pool := range nodes[0].PoolRefs {
+		if pool.Name == lib.GetL7DefaultBackendPoolName(vsName) {
+			g.Expect(pool.Servers).To(gomega.HaveLen(1))
+			g.Expect(pool.Port).To(gomega.Equal(int32(8080)))
+			g.Expect(pool.PriorityLabel).To(gomega.BeEmpty())
+		}
+	}
+	// The default backend pool is not a member of the shared poolgroup.
+	for _, pg := range nodes[0].PoolGroupRefs {
+		if pg.Name == lib.GetL7SharedPGName(vsName) {
+			g.Expect(pg.Members).To(gomega.HaveLen(1))
+		}
+	}
+
+	// The default backend of a newer Ingress doesn't override the one of the older Ingress.
+	newerIngrFake := (integrationtest.FakeIngress{
+		Name:        "bar-with-default",
+		Namespace:   "default",
+		DnsNames:    []string{"bar.com"},
+		Ips:         []string{"8.8.8.8"},
+		HostNames:   []string{"v1"},
+		Paths:       []string{"/bar"},
+		ServiceName: "avisvc",
+	}).Ingress()
+	newerIngrFake.Spec.DefaultBackend = defaultBackend
+	newerIngrFake.CreationTimestamp = metav1.NewTime(time.Now())
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), newerIngrFake, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	integrationtest.PollForCompletion(t, modelName, 5)
+	getDefaultBackendIngress := func() []string {
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			if nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS(); len(nodes) > 0 {
+				for _, pool := range nodes[0].PoolRefs {
+					if pool.Name == lib.GetL7DefaultBackendPoolName(vsName) {
+						return pool.AviMarkers.IngressName
+					}
+				}
+			}
+		}
+		return nil
+	}
+	g.Consistently(getDefaultBackendIngress, 2*time.Second).Should(gomega.Equal([]string{"foo-with-default"}))
+
+	// The default backend of the newer Ingress is picked once the older Ingress is deleted.
+	if err := KubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), "foo-with-default", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	g.Eventually(getDefaultBackendIngress, 10*time.Second).Should(gomega.Equal([]string{"bar-with-default"}))
+
+	if err := KubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), "bar-with-default", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	g.Eventually(func() string {
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			if nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS(); len(nodes) > 0 {
+				return nodes[0].DefaultPoolGroup
+			}
+		}
+		return "not-found"
+	}, 10*time.Second).Should(gomega.BeEmpty())
+	_, aviModel = objects.SharedAviGraphLister().Get(modelName)
+	nodes = aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes[0].PoolRefs).To(gomega.HaveLen(0))
+
+	TearDownTestForIngress(t, modelName)
+}
+
+func TestL7ModelSNIRegexPath(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	integrationtest.AddSecret("my-secret", "default", "tlsCert", "tlsKey")
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpTestForIngress(t, modelName)
+
+	ingrFake := (integrationtest.FakeIngress{
+		Name:        "foo-with-regex",
+		Namespace:   "default",
+		DnsNames:    []string{"foo.com"},
+		Paths:       []string{"/foo/[0-9]+$"},
+		Ips:         []string{"8.8.8.8"},
+		HostNames:   []string{"v1"},
+		ServiceName: "avisvc",
+		TlsSecretDNS: map[string][]string{
+			"my-secret": {"foo.com"},
+		},
+	}).Ingress()
+	exact := networkingv1.PathTypeExact
+	backend := ingrFake.Spec.Rules[0].HTTP.Paths[0].Backend
+	ingrFake.Spec.Rules[0].HTTP.Paths = append(ingrFake.Spec.Rules[0].HTTP.Paths,
+		networkingv1.HTTPIngressPath{Path: "/exact", PathType: &exact, Backend: backend},
+		networkingv1.HTTPIngressPath{Path: "/bar/(", Backend: backend},
+	)
+	ingrFake.Annotations = map[string]string{lib.UseRegexAnnotation: "true"}
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), ingrFake, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	integrationtest.PollForCompletion(t, modelName, 5)
+
+	getMatchCriteria := func() map[string]string {
+		matchCriteria := make(map[string]string)
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+			if len(nodes) == 0 || len(nodes[0].SniNodes) == 0 || len(nodes[0].SniNodes[0].HttpPolicyRefs) == 0 {
+				return matchCriteria
+			}
+			for _, hppMap := range nodes[0].SniNodes[0].HttpPolicyRefs[0].HppMap {
+				matchCriteria[hppMap.Path[0]] = hppMap.MatchCriteria
+			}
+		}
+		return matchCriteria
+	}
+	// The invalid regular expression is skipped.
+	g.Eventually(getMatchCriteria, 10*time.Second).Should(gomega.Equal(map[string]string{
+		"/foo/[0-9]+$": "REGEX_MATCH",
+		"/exact":       "EQUALS",
+	}))
+
+	// Without the annotation, the ImplementationSpecific paths are prefix matched.
+	ingrFake.Annotations = nil
+	ingrFake.ResourceVersion = "2"
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Update(context.TODO(), ingrFake, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Ingress: %v", err)
+	}
+	g.Eventually(getMatchCriteria, 10*time.Second).Should(gomega.Equal(map[string]string{
+		"/foo/[0-9]+$": "BEGINS_WITH",
+		"/exact":       "EQUALS",
+		"/bar/(":       "BEGINS_WITH",
+	}))
+
+	if err := KubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), "foo-with-regex", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	KubeClient.CoreV1().Secrets("default").Delete(context.TODO(), "my-secret", metav1.DeleteOptions{})
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	VerifySNIIngressDeletion(t, g, aviModel, 0)
+
+	TearDownTestForIngress(t, modelName)
+}
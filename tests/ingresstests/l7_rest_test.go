@@ -15,16 +15,19 @@
 package ingresstests
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
@@ -1016,3 +1019,115 @@ func TestProfilesAttachedToVS(t *testing.T) {
 
 	integrationtest.ResetMiddleware()
 }
+
+func TestSNIRegexPathWithStringGroups(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	var lock sync.Mutex
+	var stringGroupDeletes int
+	var regexPathMatches []map[string]interface{}
+	integrationtest.AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		url := r.URL.EscapedPath()
+		if (r.Method == http.MethodPost || r.Method == http.MethodPut) && strings.Contains(url, "/api/httppolicyset") {
+			var resp map[string]interface{}
+			data, _ := io.ReadAll(r.Body)
+			json.Unmarshal(data, &resp)
+			r.Body = io.NopCloser(bytes.NewReader(data))
+			if requestPolicy, ok := resp["http_request_policy"].(map[string]interface{}); ok {
+				rules, _ := requestPolicy["rules"].([]interface{})
+				for _, rule := range rules {
+					pathMatch, _ := rule.(map[string]interface{})["match"].(map[string]interface{})["path"].(map[string]interface{})
+					if pathMatch != nil && pathMatch["match_criteria"] == "REGEX_MATCH" {
+						lock.Lock()
+						regexPathMatches = append(regexPathMatches, pathMatch)
+						lock.Unlock()
+					}
+				}
+			}
+		} else if r.Method == http.MethodDelete && strings.Contains(url, "/api/stringgroup") {
+			lock.Lock()
+			stringGroupDeletes++
+			lock.Unlock()
+		}
+		integrationtest.NormalControllerServer(w, r)
+	})
+	defer integrationtest.ResetMiddleware()
+
+	integrationtest.AddSecret("my-secret", "default", "tlsCert", "tlsKey")
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpTestForIngress(t, modelName)
+
+	ingrFake := (integrationtest.FakeIngress{
+		Name:        "foo-with-regex",
+		Namespace:   "default",
+		DnsNames:    []string{"foo.com"},
+		Paths:       []string{"/foo/[0-9]+$"},
+		Ips:         []string{"8.8.8.8"},
+		HostNames:   []string{"v1"},
+		ServiceName: "avisvc",
+		TlsSecretDNS: map[string][]string{
+			"my-secret": {"foo.com"},
+		},
+	}).Ingress()
+	ingrFake.Annotations = map[string]string{lib.UseRegexAnnotation: "true"}
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), ingrFake, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	integrationtest.PollForCompletion(t, modelName, 5)
+
+	// The regular expression is held by a string group referred to by the path match.
+	mcache := cache.SharedAviObjCache()
+	var httpPolicyName string
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) == 0 || len(nodes[0].SniNodes) == 0 || len(nodes[0].SniNodes[0].HttpPolicyRefs) == 0 {
+			return 0
+		}
+		httpPolicyName = nodes[0].SniNodes[0].HttpPolicyRefs[0].Name
+		return len(nodes[0].SniNodes[0].HttpPolicyRefs[0].GetRegexStringGroups())
+	}, 10*time.Second).Should(gomega.Equal(1))
+	sgName := lib.GetRegexStringGroupName(httpPolicyName, "/foo/[0-9]+$")
+	sgKey := cache.NamespaceName{Namespace: "admin", Name: sgName}
+	g.Eventually(func() bool {
+		_, found := mcache.StringGroupCache.AviCacheGet(sgKey)
+		return found
+	}, 10*time.Second).Should(gomega.BeTrue())
+	g.Eventually(func() []cache.NamespaceName {
+		httpCache, found := mcache.HTTPPolicyCache.AviCacheGet(cache.NamespaceName{Namespace: "admin", Name: httpPolicyName})
+		if !found {
+			return nil
+		}
+		return httpCache.(*cache.AviHTTPPolicyCache).StringGroupCollection
+	}, 10*time.Second).Should(gomega.ConsistOf(sgKey))
+	lock.Lock()
+	g.Expect(regexPathMatches).NotTo(gomega.BeEmpty())
+	for _, pathMatch := range regexPathMatches {
+		g.Expect(pathMatch).NotTo(gomega.HaveKey("match_str"))
+		g.Expect(pathMatch["string_group_refs"]).To(gomega.ConsistOf("/api/stringgroup/?name=" + sgName))
+	}
+	lock.Unlock()
+
+	// The string group is deleted once the path is no longer matched as a regular expression.
+	ingrFake.Annotations = nil
+	ingrFake.ResourceVersion = "2"
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Update(context.TODO(), ingrFake, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Ingress: %v", err)
+	}
+	g.Eventually(func() bool {
+		_, found := mcache.StringGroupCache.AviCacheGet(sgKey)
+		return found
+	}, 10*time.Second).Should(gomega.BeFalse())
+	lock.Lock()
+	g.Expect(stringGroupDeletes).To(gomega.Equal(1))
+	lock.Unlock()
+
+	if err := KubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), "foo-with-regex", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	KubeClient.CoreV1().Secrets("default").Delete(context.TODO(), "my-secret", metav1.DeleteOptions{})
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	VerifySNIIngressDeletion(t, g, aviModel, 0)
+
+	TearDownTestForIngress(t, modelName)
+}
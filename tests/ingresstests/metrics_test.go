@@ -0,0 +1,118 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package ingresstests
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+)
+
+var registerMetricsOnce sync.Once
+
+// gatherMetric returns the samples of the metric whose name ends with name, the metric names
+// are prefixed with the pod name and namespace.
+func gatherMetric(t *testing.T, name string) []*dto.Metric {
+	families, err := lib.GetPrometheusRegistry().Gather()
+	if err != nil {
+		t.Fatalf("error in gathering the metrics: %v", err)
+	}
+	for _, family := range families {
+		if strings.HasSuffix(family.GetName(), "_"+name) {
+			return family.GetMetric()
+		}
+	}
+	return nil
+}
+
+func histogramCount(t *testing.T, name string, labels map[string]string) uint64 {
+	var count uint64
+	for _, metric := range gatherMetric(t, name) {
+		matched := 0
+		for _, label := range metric.GetLabel() {
+			if value, ok := labels[label.GetName()]; ok && value == label.GetValue() {
+				matched++
+			}
+		}
+		if matched == len(labels) {
+			count += metric.GetHistogram().GetSampleCount()
+		}
+	}
+	return count
+}
+
+func TestSyncMetrics(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	registerMetricsOnce.Do(func() {
+		lib.SetPrometheusRegistry()
+		lib.RegisterPromMetrics()
+	})
+	lib.AKOControlConfig().SetAKOPrometheusFlag(true)
+	defer lib.AKOControlConfig().SetAKOPrometheusFlag(false)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpTestForIngress(t, modelName)
+	syncLatencyCount := histogramCount(t, "sync_latency_seconds", nil)
+	restLatencyCount := histogramCount(t, "avi_rest_latency_seconds", map[string]string{"object_type": "VirtualService"})
+
+	ingrFake := (integrationtest.FakeIngress{
+		Name:        "foo-with-targets",
+		Namespace:   "default",
+		DnsNames:    []string{"foo.com"},
+		Ips:         []string{"8.8.8.8"},
+		HostNames:   []string{"v1"},
+		Paths:       []string{"/foo"},
+		ServiceName: "avisvc",
+	}).Ingress()
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), ingrFake, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	integrationtest.PollForCompletion(t, modelName, 5)
+	g.Eventually(func() bool {
+		vsCache, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(cache.NamespaceName{Namespace: "admin", Name: "cluster--Shared-L7-0"})
+		return found && vsCache.(*cache.AviVsCache).Uuid != ""
+	}, 30*time.Second).Should(gomega.BeTrue())
+
+	// The ingress event is applied in the controller through all the layers.
+	g.Eventually(func() uint64 {
+		return histogramCount(t, "sync_latency_seconds", nil)
+	}, 30*time.Second).Should(gomega.BeNumerically(">", syncLatencyCount))
+	for _, layer := range []string{lib.MetricLayerIngestion, lib.MetricLayerGraph, lib.MetricLayerRest, lib.MetricLayerStatus} {
+		g.Eventually(func() uint64 {
+			return histogramCount(t, "layer_processing_seconds", map[string]string{"layer": layer})
+		}, 30*time.Second).Should(gomega.BeNumerically(">", 0), "layer "+layer)
+	}
+	g.Expect(histogramCount(t, "avi_rest_latency_seconds", map[string]string{"object_type": "VirtualService"})).
+		To(gomega.BeNumerically(">", restLatencyCount))
+
+	g.Expect(gatherMetric(t, "keys_in_retry_layer")).To(gomega.HaveLen(2))
+	g.Expect(gatherMetric(t, "seconds_since_last_full_sync")).To(gomega.HaveLen(1))
+
+	if err := KubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), "foo-with-targets", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	TearDownTestForIngress(t, modelName)
+}
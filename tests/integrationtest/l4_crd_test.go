@@ -1738,3 +1738,77 @@ func TestCreateDeleteL4RuleInOtherNS(t *testing.T) {
 	TearDownTestForSvcLB(t, g)
 	TeardownL4Rule(t, L4RuleName, "default")
 }
+
+func TestL4RuleNetworkSecurityPolicyWithLoadBalancerSourceRanges(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	L4RuleName := "test-l4rule-nsp"
+	ports := []int{8080}
+
+	SetUpTestForSvcLB(t)
+	SetupL4Rule(t, L4RuleName, NAMESPACE, ports)
+	g.Eventually(func() string {
+		l4Rule, _ := lib.AKOControlConfig().V1alpha2CRDClientset().AkoV1alpha2().L4Rules(NAMESPACE).Get(context.TODO(), L4RuleName, metav1.GetOptions{})
+		return l4Rule.Status.Status
+	}, 30*time.Second).Should(gomega.Equal("Accepted"))
+
+	svcObj := (FakeService{
+		Name:         SINGLEPORTSVC,
+		Namespace:    NAMESPACE,
+		Type:         corev1.ServiceTypeLoadBalancer,
+		ServicePorts: []Serviceport{{PortName: "foo1", Protocol: "TCP", PortNumber: 8080, TargetPort: intstr.FromInt(8080)}},
+	}).Service()
+	svcObj.Spec.LoadBalancerSourceRanges = []string{"10.10.0.0/16"}
+	svcObj.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcObj, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+
+	vsName := fmt.Sprintf("cluster--%s-%s", NAMESPACE, SINGLEPORTSVC)
+	g.Eventually(func() int {
+		if found, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL); found && aviModel != nil {
+			return len(aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].NetworkSecurityPolicyRefs)
+		}
+		return 0
+	}, 30*time.Second).Should(gomega.Equal(1))
+	_, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(*nodes[0].NetworkSecurityPolicyRef).To(gomega.Equal("/api/networksecuritypolicy?name=" + vsName))
+
+	// The source ranges take precedence over the networkSecurityPolicyRef of the L4Rule, so the
+	// clients stay restricted.
+	svcObj.Annotations = map[string]string{lib.L4RuleAnnotation: L4RuleName}
+	svcObj.ResourceVersion = "3"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcObj, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	g.Eventually(func() string {
+		if found, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL); found && aviModel != nil {
+			return aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].ServiceMetadata.CRDStatus.Value
+		}
+		return ""
+	}, 30*time.Second).Should(gomega.Equal(NAMESPACE + "/" + L4RuleName))
+	_, aviModel = objects.SharedAviGraphLister().Get(SINGLEPORTMODEL)
+	nodes = aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes[0].NetworkSecurityPolicyRefs).To(gomega.HaveLen(1))
+	g.Expect(*nodes[0].NetworkSecurityPolicyRef).To(gomega.Equal("/api/networksecuritypolicy?name=" + vsName))
+
+	// The L4Rule is rejected when it is validated with the Service attached.
+	TeardownL4Rule(t, L4RuleName, NAMESPACE)
+	SetupL4Rule(t, L4RuleName, NAMESPACE, ports)
+	g.Eventually(func() string {
+		l4Rule, _ := lib.AKOControlConfig().V1alpha2CRDClientset().AkoV1alpha2().L4Rules(NAMESPACE).Get(context.TODO(), L4RuleName, metav1.GetOptions{})
+		return l4Rule.Status.Status
+	}, 30*time.Second).Should(gomega.Equal("Rejected"))
+	l4Rule, _ := lib.AKOControlConfig().V1alpha2CRDClientset().AkoV1alpha2().L4Rules(NAMESPACE).Get(context.TODO(), L4RuleName, metav1.GetOptions{})
+	g.Expect(l4Rule.Status.Error).To(gomega.ContainSubstring("loadBalancerSourceRanges"))
+	g.Consistently(func() string {
+		if found, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL); found && aviModel != nil {
+			return *aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].NetworkSecurityPolicyRef
+		}
+		return ""
+	}, 2*time.Second).Should(gomega.Equal("/api/networksecuritypolicy?name=" + vsName))
+
+	TearDownTestForSvcLB(t, g)
+	TeardownL4Rule(t, L4RuleName, NAMESPACE)
+}
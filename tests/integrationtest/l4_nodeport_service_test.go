@@ -272,3 +272,122 @@ func TestMultiPortL4SvcNodePort(t *testing.T) {
 
 	TearDownTestForSvcLBMultiport(t, g)
 }
+
+// TestL4SvcNodePortDrainTerminatingNode tests that a node marked for deletion is kept as a
+// disabled server for the graceful drain period, and is removed after it expires.
+func TestL4SvcNodePortDrainTerminatingNode(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	SetNodePortMode()
+	defer SetClusterIPMode()
+	os.Setenv("GRACEFUL_DRAIN_PERIOD", "5")
+	defer os.Unsetenv("GRACEFUL_DRAIN_PERIOD")
+	nodeIP1, nodeIP2 := "10.1.1.2", "10.1.1.3"
+	CreateNode(t, "testNode1", nodeIP1)
+	defer DeleteNode(t, "testNode1")
+	CreateNode(t, "testNode2", nodeIP2)
+	defer DeleteNode(t, "testNode2")
+
+	SetUpTestForSvcLB(t)
+	getServers := func() map[string]bool {
+		servers := make(map[string]bool)
+		_, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL)
+		if aviModel == nil {
+			return servers
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) != 1 || len(nodes[0].PoolRefs) != 1 {
+			return servers
+		}
+		for _, server := range nodes[0].PoolRefs[0].Servers {
+			servers[*server.Ip.Addr] = server.Draining
+		}
+		return servers
+	}
+	g.Eventually(getServers, 10*time.Second).Should(gomega.Equal(map[string]bool{nodeIP1: false, nodeIP2: false}))
+
+	nodeExample := (FakeNode{
+		Name:     "testNode2",
+		PodCIDR:  "10.244.0.0/24",
+		PodCIDRs: []string{"10.244.0.0/24"},
+		Version:  "2",
+		NodeIP:   nodeIP2,
+	}).Node()
+	nodeExample.Spec.Taints = []corev1.Taint{{Key: "ToBeDeletedByClusterAutoscaler", Effect: corev1.TaintEffectNoSchedule}}
+	if _, err := KubeClient.CoreV1().Nodes().Update(context.TODO(), nodeExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Node: %v", err)
+	}
+	g.Eventually(getServers, 5*time.Second).Should(gomega.Equal(map[string]bool{nodeIP1: false, nodeIP2: true}))
+
+	// The draining server is removed once the drain period expires.
+	g.Eventually(getServers, 15*time.Second).Should(gomega.Equal(map[string]bool{nodeIP1: false}))
+
+	TearDownTestForSvcLB(t, g)
+}
+
+// TestL4SvcNodePortExternalTrafficPolicyLocal tests that only the nodes hosting the endpoints
+// of a service with the Local external traffic policy are added as pool servers.
+func TestL4SvcNodePortExternalTrafficPolicyLocal(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	SetNodePortMode()
+	defer SetClusterIPMode()
+	nodeIP1, nodeIP2 := "10.1.1.2", "10.1.1.3"
+	CreateNode(t, "testNode1", nodeIP1)
+	defer DeleteNode(t, "testNode1")
+	CreateNode(t, "testNode2", nodeIP2)
+	defer DeleteNode(t, "testNode2")
+
+	objects.SharedAviGraphLister().Delete(SINGLEPORTMODEL)
+	svcObj := ConstructService(NAMESPACE, SINGLEPORTSVC, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string), "")
+	svcObj.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyLocal
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcObj, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Service: %v", err)
+	}
+	nodeName := "testNode2"
+	epExample := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: NAMESPACE, Name: SINGLEPORTSVC},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: "1.1.1.1", NodeName: &nodeName}},
+			Ports:     []corev1.EndpointPort{{Name: "foo0", Port: 8080, Protocol: corev1.ProtocolTCP}},
+		}},
+	}
+	if _, err := KubeClient.CoreV1().Endpoints(NAMESPACE).Create(context.TODO(), epExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in creating Endpoint: %v", err)
+	}
+
+	getServers := func() []string {
+		var servers []string
+		_, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL)
+		if aviModel == nil {
+			return servers
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) != 1 || len(nodes[0].PoolRefs) != 1 {
+			return servers
+		}
+		for _, server := range nodes[0].PoolRefs[0].Servers {
+			servers = append(servers, *server.Ip.Addr)
+		}
+		return servers
+	}
+	g.Eventually(getServers, 10*time.Second).Should(gomega.ConsistOf(nodeIP2))
+
+	// The pool servers follow the endpoints moving between the nodes.
+	nodeName = "testNode1"
+	epExample.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Endpoints(NAMESPACE).Update(context.TODO(), epExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Endpoint: %v", err)
+	}
+	g.Eventually(getServers, 10*time.Second).Should(gomega.ConsistOf(nodeIP1))
+
+	// All the nodes are added back with the Cluster external traffic policy.
+	svcObj.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyCluster
+	svcObj.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcObj, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	g.Eventually(getServers, 10*time.Second).Should(gomega.ConsistOf(nodeIP1, nodeIP2))
+
+	TearDownTestForSvcLB(t, g)
+}
@@ -15,11 +15,13 @@
 package integrationtest
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"sync"
@@ -31,11 +33,13 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/rest"
 	crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/fake"
 	v1beta1crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1beta1/clientset/versioned/fake"
 
 	v1alpha2crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha2/clientset/versioned/fake"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/avisimulator"
 
 	"github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -722,6 +726,228 @@ func TestCreateServiceLBWithFaultCacheSync(t *testing.T) {
 	TearDownTestForSvcLB(t, g)
 }
 
+// TestCreateServiceLBTransactionalRollback fails the virtualservice creation once, and verifies that
+// the objects created before it in the same batch are deleted from the controller before the retry.
+func TestCreateServiceLBTransactionalRollback(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	os.Setenv("TRANSACTIONAL_REST_APPLY", "true")
+	defer os.Unsetenv("TRANSACTIONAL_REST_APPLY")
+
+	vsName := fmt.Sprintf("cluster--%s-%s", NAMESPACE, SINGLEPORTSVC)
+	var lock sync.Mutex
+	var deletedObjects []string
+	injectFault := true
+	AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		url := r.URL.EscapedPath()
+		lock.Lock()
+		defer lock.Unlock()
+		if (r.Method == http.MethodPost || r.Method == http.MethodPut) && strings.Contains(url, "virtualservice") && injectFault {
+			data, _ := io.ReadAll(r.Body)
+			var resp map[string]interface{}
+			json.Unmarshal(data, &resp)
+			if resp["name"] == vsName {
+				injectFault = false
+				w.WriteHeader(http.StatusInternalServerError)
+				fmt.Fprintln(w, `{"error": "internal server error"}`)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(data))
+		}
+		if r.Method == http.MethodDelete {
+			deletedObjects = append(deletedObjects, url)
+		}
+		NormalControllerServer(w, r)
+	})
+	defer ResetMiddleware()
+
+	SetUpTestForSvcLB(t)
+
+	mcache := cache.SharedAviObjCache()
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: vsName}
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 15*time.Second).Should(gomega.Equal(true))
+
+	// The pool created before the failed virtualservice call must have been deleted.
+	lock.Lock()
+	g.Expect(injectFault).To(gomega.BeFalse())
+	g.Expect(deletedObjects).To(gomega.ContainElement(gomega.ContainSubstring("/api/pool/pool-" + vsName + "-TCP-8080")))
+	lock.Unlock()
+
+	vsCache, _ := mcache.VsCacheMeta.AviCacheGet(vsKey)
+	vsCacheObj, ok := vsCache.(*cache.AviVsCache)
+	if !ok {
+		t.Fatalf("Invalid VS object. Cannot cast.")
+	}
+	g.Expect(vsCacheObj.PoolKeyCollection).To(gomega.HaveLen(1))
+	g.Expect(vsCacheObj.L4PolicyCollection).To(gomega.HaveLen(1))
+
+	TearDownTestForSvcLB(t, g)
+}
+
+// TestUpdateServiceLBTransactionalRollbackWithSimulator fails the virtualservice update with an error
+// that is not retried, and verifies that the l4policyset updated before it in the same batch is restored
+// on a controller which rejects the updates based on an older version of the object.
+func TestUpdateServiceLBTransactionalRollbackWithSimulator(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	os.Setenv("TRANSACTIONAL_REST_APPLY", "true")
+	defer os.Unsetenv("TRANSACTIONAL_REST_APPLY")
+
+	sim := avisimulator.NewSimulator()
+	sim.Fallback = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		NormalControllerServer(w, r)
+	})
+	g.Expect(sim.LoadFixtures(defaultMockFilePath, "network")).To(gomega.Succeed())
+	l4PolicyPorts := func(l4Policy map[string]interface{}) interface{} {
+		rules := l4Policy["l4_connection_policy"].(map[string]interface{})["rules"].([]interface{})
+		return rules[0].(map[string]interface{})["match"].(map[string]interface{})["port"].(map[string]interface{})["ports"]
+	}
+	// The ports and the response status of the l4policyset updates.
+	type l4PolicyPut struct {
+		ports  interface{}
+		status int
+	}
+	var lock sync.Mutex
+	var l4PolicyPuts []l4PolicyPut
+	AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || !strings.Contains(r.URL.EscapedPath(), "l4policyset") {
+			sim.ServeHTTP(w, r)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		var l4Policy map[string]interface{}
+		json.Unmarshal(data, &l4Policy)
+		r.Body = io.NopCloser(bytes.NewReader(data))
+		rec := httptest.NewRecorder()
+		sim.ServeHTTP(rec, r)
+		lock.Lock()
+		l4PolicyPuts = append(l4PolicyPuts, l4PolicyPut{ports: l4PolicyPorts(l4Policy), status: rec.Code})
+		lock.Unlock()
+		for header, values := range rec.Header() {
+			w.Header()[header] = values
+		}
+		w.WriteHeader(rec.Code)
+		w.Write(rec.Body.Bytes())
+	})
+	defer ResetMiddleware()
+
+	SetUpTestForSvcLB(t)
+
+	vsName := fmt.Sprintf("cluster--%s-%s", NAMESPACE, SINGLEPORTSVC)
+	mcache := cache.SharedAviObjCache()
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: vsName}
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 15*time.Second).Should(gomega.BeTrue())
+	g.Expect(l4PolicyPorts(sim.Get("l4policyset", AVINAMESPACE, vsName))).To(gomega.ConsistOf(float64(8080)))
+
+	// The port change updates the l4policyset before the virtualservice, which is rejected.
+	sim.AddFault(avisimulator.Fault{Method: http.MethodPut, ObjectType: "virtualservice", Name: vsName, StatusCode: http.StatusBadRequest, Message: "injected", Count: 1})
+	lock.Lock()
+	l4PolicyPuts = nil
+	lock.Unlock()
+	svcObj := ConstructService(NAMESPACE, SINGLEPORTSVC, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string), "")
+	svcObj.Spec.Ports[0].Port = 8081
+	svcObj.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcObj, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+
+	// The update of the l4policyset is rolled back with a PUT the controller accepts, before the
+	// model is synced again.
+	g.Eventually(func() int {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(l4PolicyPuts)
+	}, 15*time.Second).Should(gomega.BeNumerically(">=", 2))
+	lock.Lock()
+	g.Expect(l4PolicyPuts[0].ports).To(gomega.ConsistOf(float64(8081)))
+	g.Expect(l4PolicyPuts[0].status).To(gomega.Equal(http.StatusOK))
+	g.Expect(l4PolicyPuts[1].ports).To(gomega.ConsistOf(float64(8080)))
+	g.Expect(l4PolicyPuts[1].status).To(gomega.Equal(http.StatusOK))
+	lock.Unlock()
+	g.Eventually(func() interface{} {
+		return l4PolicyPorts(sim.Get("l4policyset", AVINAMESPACE, vsName))
+	}, 15*time.Second).Should(gomega.ConsistOf(float64(8081)))
+
+	TearDownTestForSvcLB(t, g)
+	g.Eventually(func() int {
+		return len(sim.List("virtualservice", AVINAMESPACE)) + len(sim.List("l4policyset", AVINAMESPACE)) + len(sim.List("pool", AVINAMESPACE))
+	}, 15*time.Second).Should(gomega.Equal(0))
+}
+
+// TestTransactionalRollbackWithChangedReferentsWithSimulator fails a batch of rest operations after
+// an update and a delete, and verifies that the update is rolled back from the object AKO last wrote,
+// without fetching it, and that the recreation of the deleted object, whose referent was deleted
+// meanwhile, is rejected, leaving the delete in place for the next sync.
+func TestTransactionalRollbackWithChangedReferentsWithSimulator(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	os.Setenv("TRANSACTIONAL_REST_APPLY", "true")
+	defer os.Unsetenv("TRANSACTIONAL_REST_APPLY")
+
+	sim := avisimulator.NewSimulator()
+	pool, err := sim.Create("pool", AVINAMESPACE, map[string]interface{}{"name": "rollback-pool"})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	pg, err := sim.Create("poolgroup", AVINAMESPACE, map[string]interface{}{
+		"name":    "rollback-pg",
+		"members": []interface{}{map[string]interface{}{"pool_ref": "/api/pool/?name=rollback-pool"}},
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	var lock sync.Mutex
+	var getPaths []string
+	AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			lock.Lock()
+			getPaths = append(getPaths, r.URL.EscapedPath())
+			lock.Unlock()
+		}
+		if r.Method == http.MethodPost && strings.HasSuffix(r.URL.EscapedPath(), "/api/pool/") {
+			data, _ := io.ReadAll(r.Body)
+			r.Body = io.NopCloser(bytes.NewReader(data))
+			if strings.Contains(string(data), "rollback-failing-pool") {
+				// The pool referred by the deleted poolgroup is deleted before the batch fails.
+				sim.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/api/pool/"+pool["uuid"].(string), nil))
+			}
+		}
+		sim.ServeHTTP(w, r)
+	})
+	defer ResetMiddleware()
+
+	aviClient := cache.SharedAVIClients().AviClient[0]
+	restOperator := rest.NewRestOperator(&rest.RestOperations{}, true)
+	newOp := func(method utils.RestMethod, model, path string, obj interface{}) *utils.RestOp {
+		return &utils.RestOp{Method: method, Model: model, Path: path, Obj: obj, Tenant: AVINAMESPACE}
+	}
+
+	// The pool written by AKO is known without fetching it.
+	createOp := newOp(utils.RestPost, "Pool", "/api/pool/", map[string]interface{}{"name": "rollback-updated-pool", "description": "created"})
+	g.Expect(restOperator.AviRestOperate(aviClient, []*utils.RestOp{createOp}, "rollback")).To(gomega.Succeed())
+	updatedPoolPath := "/api/pool/" + createOp.Response.(map[string]interface{})["uuid"].(string)
+
+	sim.AddFault(avisimulator.Fault{Method: http.MethodPost, ObjectType: "pool", Name: "rollback-failing-pool", StatusCode: http.StatusBadRequest, Message: "injected", Count: 1})
+	lock.Lock()
+	getPaths = nil
+	lock.Unlock()
+	restOps := []*utils.RestOp{
+		newOp(utils.RestPut, "Pool", updatedPoolPath, map[string]interface{}{"name": "rollback-updated-pool", "description": "updated"}),
+		newOp(utils.RestDelete, "PoolGroup", "/api/poolgroup/"+pg["uuid"].(string), nil),
+		newOp(utils.RestPost, "Pool", "/api/pool/", map[string]interface{}{"name": "rollback-failing-pool"}),
+	}
+	g.Expect(restOperator.AviRestOperate(aviClient, restOps, "rollback")).NotTo(gomega.Succeed())
+
+	lock.Lock()
+	g.Expect(getPaths).To(gomega.ContainElement(gomega.HaveSuffix("/api/poolgroup/" + pg["uuid"].(string))))
+	g.Expect(getPaths).NotTo(gomega.ContainElement(gomega.HaveSuffix(updatedPoolPath)))
+	lock.Unlock()
+	g.Expect(restOps[0].Err).To(gomega.MatchError(utils.RolledBackRestOpError))
+	g.Expect(sim.Get("pool", AVINAMESPACE, "rollback-updated-pool")["description"]).To(gomega.Equal("created"))
+	g.Expect(restOps[1].Err).NotTo(gomega.HaveOccurred())
+	g.Expect(sim.Get("poolgroup", AVINAMESPACE, "rollback-pg")).To(gomega.BeNil())
+	g.Expect(sim.Get("pool", AVINAMESPACE, "rollback-pool")).To(gomega.BeNil())
+}
+
 func TestCreateMultiportServiceLBCacheSync(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 	MULTIPORTSVC, NAMESPACE, AVINAMESPACE := "testsvcmulti", "red-ns", "admin"
@@ -1098,6 +1324,19 @@ func TestInfraSettingDelete(t *testing.T) {
 	g.Expect(nodes[0].VSVIPRefs[0].VipNetworks[0].NetworkName).Should(gomega.Equal("thisisaviref-" + settingName + "-networkName"))
 	g.Expect(*nodes[0].EnableRhi).Should(gomega.Equal(true))
 
+	// Only the Virtual Service is listed in the status of the AviInfraSetting, not its pools.
+	g.Eventually(func() []string {
+		setting, err := v1beta1CRDClient.AkoV1beta1().AviInfraSettings().Get(context.TODO(), settingName, metav1.GetOptions{})
+		if err != nil {
+			return nil
+		}
+		var appliedTo []string
+		for _, ref := range setting.Status.AppliedTo {
+			appliedTo = append(appliedTo, ref.Type+"/"+ref.Name)
+		}
+		return appliedTo
+	}, 20*time.Second).Should(gomega.Equal([]string{"VirtualService/cluster--red-ns-testsvc"}))
+
 	TeardownAviInfraSetting(t, settingName)
 
 	// defaults to global seGroup and networkName.
@@ -1796,3 +2035,528 @@ func TestLBSvcWithExtDNSAndAutoFQDNAsFlat(t *testing.T) {
 	TearDownTestForSvcLBWithExtDNS(t, g)
 	os.Setenv("AUTO_L4_FQDN", "disable")
 }
+
+func TestCreateDeleteServiceLBWithSimulator(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sim := avisimulator.NewSimulator()
+	sim.Fallback = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		NormalControllerServer(w, r)
+	})
+	// The networks of the canned cloud and network responses are referred by the vsvip and the pool.
+	g.Expect(sim.LoadFixtures(defaultMockFilePath, "network")).To(gomega.Succeed())
+	AddMiddleware(sim.ServeHTTP)
+	defer ResetMiddleware()
+
+	SetUpTestForSvcLB(t)
+
+	vsName := fmt.Sprintf("cluster--%s-%s", NAMESPACE, SINGLEPORTSVC)
+	g.Eventually(func() map[string]interface{} {
+		return sim.Get("virtualservice", AVINAMESPACE, vsName)
+	}, 15*time.Second).ShouldNot(gomega.BeNil())
+
+	// The objects on the controller refer to each other by uuid.
+	vs := sim.Get("virtualservice", AVINAMESPACE, vsName)
+	vsVip := sim.Get("vsvip", AVINAMESPACE, vsName)
+	g.Expect(vsVip).NotTo(gomega.BeNil())
+	g.Expect(vs["vsvip_ref"]).To(gomega.ContainSubstring(vsVip["uuid"].(string)))
+	pools := sim.List("pool", AVINAMESPACE)
+	g.Expect(pools).To(gomega.HaveLen(1))
+	g.Expect(pools[0]["servers"]).To(gomega.HaveLen(1))
+	l4Policy := sim.Get("l4policyset", AVINAMESPACE, vsName)
+	g.Expect(l4Policy).NotTo(gomega.BeNil())
+	g.Expect(vs["l4_policies"]).To(gomega.ContainElement(gomega.HaveKeyWithValue("l4_policy_set_ref", gomega.ContainSubstring(l4Policy["uuid"].(string)))))
+
+	// The cache is built from the responses of the controller.
+	mcache := cache.SharedAviObjCache()
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: vsName}
+	g.Eventually(func() string {
+		if vsCache, found := mcache.VsCacheMeta.AviCacheGet(vsKey); found {
+			return vsCache.(*cache.AviVsCache).Uuid
+		}
+		return ""
+	}, 15*time.Second).Should(gomega.Equal(vs["uuid"]))
+
+	TearDownTestForSvcLB(t, g)
+	g.Eventually(func() int {
+		return len(sim.List("virtualservice", AVINAMESPACE)) + len(sim.List("vsvip", AVINAMESPACE)) +
+			len(sim.List("pool", AVINAMESPACE)) + len(sim.List("l4policyset", AVINAMESPACE))
+	}, 15*time.Second).Should(gomega.Equal(0))
+}
+
+func TestLBSvcWithLoadBalancerSourceRangesWithSimulator(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sim := avisimulator.NewSimulator()
+	sim.Fallback = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		NormalControllerServer(w, r)
+	})
+	g.Expect(sim.LoadFixtures(defaultMockFilePath, "network")).To(gomega.Succeed())
+	AddMiddleware(sim.ServeHTTP)
+	defer ResetMiddleware()
+
+	objects.SharedAviGraphLister().Delete(SINGLEPORTMODEL)
+	svcObj := ConstructService(NAMESPACE, SINGLEPORTSVC, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string), "")
+	svcObj.Spec.LoadBalancerSourceRanges = []string{"10.10.0.0/16", "192.168.1.10/32"}
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcObj, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, SINGLEPORTSVC, false, false, "1.1.1")
+
+	vsName := fmt.Sprintf("cluster--%s-%s", NAMESPACE, SINGLEPORTSVC)
+	g.Eventually(func() map[string]interface{} {
+		return sim.Get("networksecuritypolicy", AVINAMESPACE, vsName)
+	}, 15*time.Second).ShouldNot(gomega.BeNil())
+	g.Eventually(func() interface{} {
+		if vs := sim.Get("virtualservice", AVINAMESPACE, vsName); vs != nil {
+			return vs["network_security_policy_ref"]
+		}
+		return nil
+	}, 15*time.Second).Should(gomega.ContainSubstring(sim.Get("networksecuritypolicy", AVINAMESPACE, vsName)["uuid"].(string)))
+
+	// The clients in the source ranges are allowed on the service port, the others are denied.
+	nsp := sim.Get("networksecuritypolicy", AVINAMESPACE, vsName)
+	rules := nsp["rules"].([]interface{})
+	g.Expect(rules).To(gomega.HaveLen(2))
+	allowRule := rules[0].(map[string]interface{})
+	g.Expect(allowRule["action"]).To(gomega.Equal(lib.NSP_ACTION_ALLOW))
+	g.Expect(allowRule["match"].(map[string]interface{})["client_ip"].(map[string]interface{})["prefixes"]).To(gomega.HaveLen(2))
+	g.Expect(allowRule["match"].(map[string]interface{})["vs_port"].(map[string]interface{})["ports"]).To(gomega.ConsistOf(float64(8080)))
+	g.Expect(rules[1].(map[string]interface{})["action"]).To(gomega.Equal(lib.NSP_ACTION_DENY))
+
+	// The policy follows the changes of the source ranges.
+	svcObj.Spec.LoadBalancerSourceRanges = []string{"10.10.0.0/16"}
+	svcObj.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcObj, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	g.Eventually(func() int {
+		nsp := sim.Get("networksecuritypolicy", AVINAMESPACE, vsName)
+		if nsp == nil {
+			return 0
+		}
+		allowRule := nsp["rules"].([]interface{})[0].(map[string]interface{})
+		return len(allowRule["match"].(map[string]interface{})["client_ip"].(map[string]interface{})["prefixes"].([]interface{}))
+	}, 15*time.Second).Should(gomega.Equal(1))
+
+	// The policy is removed from the VS and deleted along with the source ranges.
+	svcObj.Spec.LoadBalancerSourceRanges = nil
+	svcObj.ResourceVersion = "3"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcObj, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	g.Eventually(func() map[string]interface{} {
+		return sim.Get("networksecuritypolicy", AVINAMESPACE, vsName)
+	}, 15*time.Second).Should(gomega.BeNil())
+	g.Expect(sim.Get("virtualservice", AVINAMESPACE, vsName)).NotTo(gomega.HaveKey("network_security_policy_ref"))
+
+	// The policy is deleted along with the Service.
+	svcObj.Spec.LoadBalancerSourceRanges = []string{"10.10.0.0/16"}
+	svcObj.ResourceVersion = "4"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcObj, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	g.Eventually(func() map[string]interface{} {
+		return sim.Get("networksecuritypolicy", AVINAMESPACE, vsName)
+	}, 15*time.Second).ShouldNot(gomega.BeNil())
+	TearDownTestForSvcLB(t, g)
+	g.Eventually(func() int {
+		return len(sim.List("virtualservice", AVINAMESPACE)) + len(sim.List("networksecuritypolicy", AVINAMESPACE))
+	}, 15*time.Second).Should(gomega.Equal(0))
+	mcache := cache.SharedAviObjCache()
+	_, found := mcache.NetworkSecurityPolicyCache.AviCacheGet(cache.NamespaceName{Namespace: AVINAMESPACE, Name: vsName})
+	g.Expect(found).To(gomega.BeFalse())
+}
+
+func TestSharedVIPSvcWithLoadBalancerSourceRanges(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	SetUpTestForSharedVIPSvcLB(t, corev1.ProtocolTCP, corev1.ProtocolUDP)
+
+	svcObj, err := KubeClient.CoreV1().Services(NAMESPACE).Get(context.TODO(), SHAREDVIPSVC01, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("error in getting Service: %v", err)
+	}
+	svcObj.Spec.LoadBalancerSourceRanges = []string{"10.10.10.0/24"}
+	svcObj.ResourceVersion = "2"
+	if _, err = KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcObj, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+
+	// Only the ports of the Service with the source ranges are restricted.
+	modelName := "admin/cluster--red-ns-" + SHAREDVIPKEY
+	g.Eventually(func() int {
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+			if len(nodes) == 1 {
+				return len(nodes[0].NetworkSecurityPolicyRefs)
+			}
+		}
+		return 0
+	}, 15*time.Second).Should(gomega.Equal(1))
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes[0].NetworkSecurityPolicyRefs[0].SourceRanges).To(gomega.Equal(map[int64][]string{8080: {"10.10.10.0/24"}}))
+	g.Expect(*nodes[0].NetworkSecurityPolicyRef).To(gomega.Equal("/api/networksecuritypolicy?name=" + nodes[0].Name))
+
+	mcache := cache.SharedAviObjCache()
+	nspKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: nodes[0].Name}
+	g.Eventually(func() bool {
+		_, found := mcache.NetworkSecurityPolicyCache.AviCacheGet(nspKey)
+		return found
+	}, 15*time.Second).Should(gomega.BeTrue())
+
+	TearDownTestForSharedVIPSvcLB(t, g)
+	g.Eventually(func() bool {
+		_, found := mcache.NetworkSecurityPolicyCache.AviCacheGet(nspKey)
+		return found
+	}, 15*time.Second).Should(gomega.BeFalse())
+}
+
+func TestLBSvcWithClientIPSessionAffinityWithSimulator(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sim := avisimulator.NewSimulator()
+	sim.Fallback = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		NormalControllerServer(w, r)
+	})
+	g.Expect(sim.LoadFixtures(defaultMockFilePath, "network")).To(gomega.Succeed())
+	AddMiddleware(sim.ServeHTTP)
+	defer ResetMiddleware()
+
+	objects.SharedAviGraphLister().Delete(SINGLEPORTMODEL)
+	svcObj := ConstructService(NAMESPACE, SINGLEPORTSVC, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string), "")
+	timeoutSeconds := int32(600)
+	svcObj.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+	svcObj.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: &timeoutSeconds}}
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcObj, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, SINGLEPORTSVC, false, false, "1.1.1")
+
+	poolName := lib.GetL4PoolName(SINGLEPORTSVC, NAMESPACE, "TCP", 8080)
+	getPoolProfile := func() interface{} {
+		if pool := sim.Get("pool", AVINAMESPACE, poolName); pool != nil {
+			return pool["application_persistence_profile_ref"]
+		}
+		return nil
+	}
+	profileName := lib.GetClientIPPersistenceProfileName(10)
+	g.Expect(profileName).To(gomega.Equal("cluster--clientip-persistence-10"))
+	g.Eventually(func() map[string]interface{} {
+		return sim.Get("applicationpersistenceprofile", AVINAMESPACE, profileName)
+	}, 15*time.Second).ShouldNot(gomega.BeNil())
+	profile := sim.Get("applicationpersistenceprofile", AVINAMESPACE, profileName)
+	g.Expect(profile["persistence_type"]).To(gomega.Equal(lib.PERSISTENCE_TYPE_CLIENT_IP))
+	g.Expect(profile["ip_persistence_profile"].(map[string]interface{})["ip_persistent_timeout"]).To(gomega.Equal(float64(10)))
+	g.Eventually(getPoolProfile, 15*time.Second).Should(gomega.ContainSubstring(profile["uuid"].(string)))
+
+	// The pool moves to the profile of the new affinity timeout, and the previous profile is deleted.
+	timeoutSeconds = 3601
+	svcObj.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcObj, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	profileName = lib.GetClientIPPersistenceProfileName(61)
+	g.Eventually(func() map[string]interface{} {
+		return sim.Get("applicationpersistenceprofile", AVINAMESPACE, profileName)
+	}, 15*time.Second).ShouldNot(gomega.BeNil())
+	profile = sim.Get("applicationpersistenceprofile", AVINAMESPACE, profileName)
+	g.Expect(profile["ip_persistence_profile"].(map[string]interface{})["ip_persistent_timeout"]).To(gomega.Equal(float64(61)))
+	g.Eventually(getPoolProfile, 15*time.Second).Should(gomega.ContainSubstring(profile["uuid"].(string)))
+	g.Eventually(func() map[string]interface{} {
+		return sim.Get("applicationpersistenceprofile", AVINAMESPACE, lib.GetClientIPPersistenceProfileName(10))
+	}, 15*time.Second).Should(gomega.BeNil())
+
+	// The profile is removed from the pool and deleted when the affinity is turned off.
+	svcObj.Spec.SessionAffinity = corev1.ServiceAffinityNone
+	svcObj.Spec.SessionAffinityConfig = nil
+	svcObj.ResourceVersion = "3"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcObj, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	g.Eventually(func() int {
+		return len(sim.List("applicationpersistenceprofile", AVINAMESPACE))
+	}, 15*time.Second).Should(gomega.Equal(0))
+	g.Expect(sim.Get("pool", AVINAMESPACE, poolName)).NotTo(gomega.HaveKey("application_persistence_profile_ref"))
+
+	// The profile is deleted along with the Service.
+	svcObj.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+	svcObj.ResourceVersion = "4"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcObj, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	profileName = lib.GetClientIPPersistenceProfileName(180)
+	g.Eventually(func() map[string]interface{} {
+		return sim.Get("applicationpersistenceprofile", AVINAMESPACE, profileName)
+	}, 15*time.Second).ShouldNot(gomega.BeNil())
+	TearDownTestForSvcLB(t, g)
+	g.Eventually(func() int {
+		return len(sim.List("pool", AVINAMESPACE)) + len(sim.List("applicationpersistenceprofile", AVINAMESPACE))
+	}, 15*time.Second).Should(gomega.Equal(0))
+	mcache := cache.SharedAviObjCache()
+	_, found := mcache.PersistenceProfileCache.AviCacheGet(cache.NamespaceName{Namespace: AVINAMESPACE, Name: profileName})
+	g.Expect(found).To(gomega.BeFalse())
+}
+
+func TestLBSvcsSharingClientIPPersistenceProfileWithSimulator(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sim := avisimulator.NewSimulator()
+	sim.Fallback = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		NormalControllerServer(w, r)
+	})
+	g.Expect(sim.LoadFixtures(defaultMockFilePath, "network")).To(gomega.Succeed())
+	// The controller refuses to delete a profile which a pool refers to, hence no delete should fail.
+	var lock sync.Mutex
+	var failedProfileDeletes []string
+	AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || !strings.Contains(r.URL.EscapedPath(), "applicationpersistenceprofile") {
+			sim.ServeHTTP(w, r)
+			return
+		}
+		rec := httptest.NewRecorder()
+		sim.ServeHTTP(rec, r)
+		if rec.Code != http.StatusOK && rec.Code != http.StatusNoContent {
+			lock.Lock()
+			failedProfileDeletes = append(failedProfileDeletes, rec.Body.String())
+			lock.Unlock()
+		}
+		for header, values := range rec.Header() {
+			w.Header()[header] = values
+		}
+		w.WriteHeader(rec.Code)
+		w.Write(rec.Body.Bytes())
+	})
+	defer ResetMiddleware()
+
+	timeoutSeconds := int32(600)
+	objects.SharedAviGraphLister().Delete(SINGLEPORTMODEL)
+	objects.SharedAviGraphLister().Delete(MULTIPORTMODEL)
+	singlePortSvc := ConstructService(NAMESPACE, SINGLEPORTSVC, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string), "")
+	multiPortSvc := ConstructService(NAMESPACE, MULTIPORTSVC, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, true, make(map[string]string), "")
+	for _, svcObj := range []*corev1.Service{singlePortSvc, multiPortSvc} {
+		svcObj.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+		svcObj.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: &timeoutSeconds}}
+		if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcObj, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("error in adding Service: %v", err)
+		}
+	}
+	CreateEP(t, NAMESPACE, SINGLEPORTSVC, false, false, "1.1.1")
+	CreateEP(t, NAMESPACE, MULTIPORTSVC, true, true, "1.1.1")
+
+	// All the pools of both the Services refer to the one profile of the timeout.
+	profileName := lib.GetClientIPPersistenceProfileName(10)
+	poolNames := []string{lib.GetL4PoolName(SINGLEPORTSVC, NAMESPACE, "TCP", 8080)}
+	for port := int32(8080); port < 8083; port++ {
+		poolNames = append(poolNames, lib.GetL4PoolName(MULTIPORTSVC, NAMESPACE, "TCP", port))
+	}
+	poolsReferTo := func(pools []string, profileName string) func() bool {
+		return func() bool {
+			profile := sim.Get("applicationpersistenceprofile", AVINAMESPACE, profileName)
+			if profile == nil {
+				return false
+			}
+			for _, poolName := range pools {
+				pool := sim.Get("pool", AVINAMESPACE, poolName)
+				if pool == nil || !strings.Contains(fmt.Sprint(pool["application_persistence_profile_ref"]), profile["uuid"].(string)) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	g.Eventually(poolsReferTo(poolNames, profileName), 15*time.Second).Should(gomega.BeTrue())
+	g.Expect(sim.List("applicationpersistenceprofile", AVINAMESPACE)).To(gomega.HaveLen(1))
+
+	// The profile is kept while the pools of the multiport Service still refer to it.
+	timeoutSeconds = 1200
+	singlePortSvc.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), singlePortSvc, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	g.Eventually(poolsReferTo(poolNames[:1], lib.GetClientIPPersistenceProfileName(20)), 15*time.Second).Should(gomega.BeTrue())
+	g.Consistently(poolsReferTo(poolNames[1:], profileName), 3*time.Second).Should(gomega.BeTrue())
+
+	// The profile is deleted along with the last Service referring to it.
+	TearDownTestForSvcLBMultiport(t, g)
+	g.Eventually(func() map[string]interface{} {
+		return sim.Get("applicationpersistenceprofile", AVINAMESPACE, profileName)
+	}, 15*time.Second).Should(gomega.BeNil())
+	g.Expect(sim.List("applicationpersistenceprofile", AVINAMESPACE)).To(gomega.HaveLen(1))
+
+	TearDownTestForSvcLB(t, g)
+	g.Eventually(func() int {
+		return len(sim.List("pool", AVINAMESPACE)) + len(sim.List("applicationpersistenceprofile", AVINAMESPACE))
+	}, 15*time.Second).Should(gomega.Equal(0))
+	lock.Lock()
+	defer lock.Unlock()
+	g.Expect(failedProfileDeletes).To(gomega.BeEmpty())
+}
+
+// TestLBSvcsSharingClientIPPersistenceProfileDeleteAndCreateWithSimulator deletes a Service while another
+// Service with the same affinity timeout is being created, and verifies that the shared profile is not
+// deleted under the pool of the created Service.
+func TestLBSvcsSharingClientIPPersistenceProfileDeleteAndCreateWithSimulator(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sim := avisimulator.NewSimulator()
+	sim.Fallback = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		NormalControllerServer(w, r)
+	})
+	g.Expect(sim.LoadFixtures(defaultMockFilePath, "network")).To(gomega.Succeed())
+	var lock sync.Mutex
+	var failedRequests []string
+	AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+			sim.ServeHTTP(w, r)
+			return
+		}
+		rec := httptest.NewRecorder()
+		sim.ServeHTTP(rec, r)
+		if rec.Code >= http.StatusBadRequest {
+			lock.Lock()
+			failedRequests = append(failedRequests, r.Method+" "+r.URL.EscapedPath()+": "+rec.Body.String())
+			lock.Unlock()
+		}
+		for header, values := range rec.Header() {
+			w.Header()[header] = values
+		}
+		w.WriteHeader(rec.Code)
+		w.Write(rec.Body.Bytes())
+	})
+	defer ResetMiddleware()
+
+	timeoutSeconds := int32(600)
+	createSvc := func(name string) {
+		svcObj := ConstructService(NAMESPACE, name, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string), "")
+		svcObj.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+		svcObj.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: &timeoutSeconds}}
+		if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcObj, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("error in adding Service: %v", err)
+		}
+		CreateEP(t, NAMESPACE, name, false, false, "1.1.1")
+	}
+	otherSvc := "testsvc-other"
+	profileName := lib.GetClientIPPersistenceProfileName(10)
+	poolReferTo := func(svcName string) func() bool {
+		return func() bool {
+			profile := sim.Get("applicationpersistenceprofile", AVINAMESPACE, profileName)
+			pool := sim.Get("pool", AVINAMESPACE, lib.GetL4PoolName(svcName, NAMESPACE, "TCP", 8080))
+			return profile != nil && pool != nil && strings.Contains(fmt.Sprint(pool["application_persistence_profile_ref"]), profile["uuid"].(string))
+		}
+	}
+	objects.SharedAviGraphLister().Delete(SINGLEPORTMODEL)
+	createSvc(SINGLEPORTSVC)
+	g.Eventually(poolReferTo(SINGLEPORTSVC), 15*time.Second).Should(gomega.BeTrue())
+
+	// The pool of the created Service is posted slowly. The other Service is deleted meanwhile, while
+	// its pool is the only cached one which refers to the profile.
+	sim.AddFault(avisimulator.Fault{Method: http.MethodPost, ObjectType: "pool", Name: lib.GetL4PoolName(otherSvc, NAMESPACE, "TCP", 8080), Delay: 5 * time.Second, Count: 1})
+	createSvc(otherSvc)
+	g.Eventually(func() map[string]interface{} {
+		return sim.Get("vsvip", AVINAMESPACE, "cluster--"+NAMESPACE+"-"+otherSvc)
+	}, 15*time.Second).ShouldNot(gomega.BeNil())
+	DelSVC(t, NAMESPACE, SINGLEPORTSVC)
+	DelEP(t, NAMESPACE, SINGLEPORTSVC)
+
+	g.Eventually(func() map[string]interface{} {
+		return sim.Get("pool", AVINAMESPACE, lib.GetL4PoolName(SINGLEPORTSVC, NAMESPACE, "TCP", 8080))
+	}, 15*time.Second).Should(gomega.BeNil())
+	g.Eventually(poolReferTo(otherSvc), 15*time.Second).Should(gomega.BeTrue())
+	lock.Lock()
+	g.Expect(failedRequests).To(gomega.BeEmpty())
+	lock.Unlock()
+
+	DelSVC(t, NAMESPACE, otherSvc)
+	DelEP(t, NAMESPACE, otherSvc)
+	g.Eventually(func() int {
+		return len(sim.List("pool", AVINAMESPACE)) + len(sim.List("applicationpersistenceprofile", AVINAMESPACE))
+	}, 15*time.Second).Should(gomega.Equal(0))
+}
+
+func TestLBSvcDriftDetectionWithSimulator(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sim := avisimulator.NewSimulator()
+	sim.Fallback = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		NormalControllerServer(w, r)
+	})
+	g.Expect(sim.LoadFixtures(defaultMockFilePath, "network")).To(gomega.Succeed())
+	AddMiddleware(sim.ServeHTTP)
+	defer ResetMiddleware()
+	defer os.Unsetenv(lib.DRIFT_DETECTION)
+
+	SetUpTestForSvcLB(t)
+
+	vsName := fmt.Sprintf("cluster--%s-%s", NAMESPACE, SINGLEPORTSVC)
+	poolName := lib.GetL4PoolName(SINGLEPORTSVC, NAMESPACE, "TCP", 8080)
+	mcache := cache.SharedAviObjCache()
+	poolKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: poolName}
+	getPoolFieldsCksum := func() string {
+		if poolCache, found := mcache.PoolCache.AviCacheGet(poolKey); found {
+			return poolCache.(*cache.AviPoolCache).FieldsCksum
+		}
+		return ""
+	}
+	g.Eventually(getPoolFieldsCksum, 15*time.Second).ShouldNot(gomega.BeEmpty())
+	g.Eventually(func() map[string]interface{} {
+		return sim.Get("virtualservice", AVINAMESPACE, vsName)
+	}, 15*time.Second).ShouldNot(gomega.BeNil())
+	getField := func(objType, name, field string) string {
+		return fmt.Sprint(sim.Get(objType, AVINAMESPACE, name)[field])
+	}
+	lbAlgorithm := getField("pool", poolName, "lb_algorithm")
+
+	// The objects are edited as from the Avi UI, without a change of the cloud_config_cksum.
+	editObject := func(objType, name string, fields map[string]interface{}) {
+		body, _ := json.Marshal(map[string]interface{}{"replace": fields})
+		uuid := sim.Get(objType, AVINAMESPACE, name)["uuid"].(string)
+		req := httptest.NewRequest(http.MethodPatch, "/api/"+objType+"/"+uuid, bytes.NewReader(body))
+		req.Header.Set("X-Avi-Tenant", AVINAMESPACE)
+		recorder := httptest.NewRecorder()
+		sim.ServeHTTP(recorder, req)
+		g.Expect(recorder.Code).To(gomega.Equal(http.StatusOK))
+	}
+
+	// Nothing is detected when the detection is disabled.
+	editObject("pool", poolName, map[string]interface{}{"lb_algorithm": "LB_ALGORITHM_FASTEST_RESPONSE"})
+	oldCksum := getPoolFieldsCksum()
+	ctrl.FullSync()
+	g.Expect(getPoolFieldsCksum()).To(gomega.Equal(oldCksum))
+
+	// The drift is only reported with the report policy, and the edited object is taken as synced.
+	os.Setenv(lib.DRIFT_DETECTION, lib.DriftDetectionReport)
+	ctrl.FullSync()
+	g.Expect(getPoolFieldsCksum()).To(gomega.Equal(cache.PoolDriftChecksum(sim.Get("pool", AVINAMESPACE, poolName))))
+	g.Expect(getPoolFieldsCksum()).NotTo(gomega.Equal(oldCksum))
+	g.Consistently(func() string {
+		return getField("pool", poolName, "lb_algorithm")
+	}, 2*time.Second).Should(gomega.Equal("LB_ALGORITHM_FASTEST_RESPONSE"))
+
+	// The desired state of the drifted pool and virtualservice is applied again with the repair policy.
+	editObject("pool", poolName, map[string]interface{}{"lb_algorithm": "LB_ALGORITHM_LEAST_LOAD"})
+	editObject("virtualservice", vsName, map[string]interface{}{"traffic_enabled": false})
+	os.Setenv(lib.DRIFT_DETECTION, lib.DriftDetectionRepair)
+	ctrl.FullSync()
+	g.Eventually(func() string {
+		return getField("pool", poolName, "lb_algorithm")
+	}, 15*time.Second).Should(gomega.Equal(lbAlgorithm))
+	g.Eventually(func() string {
+		return getField("virtualservice", vsName, "traffic_enabled")
+	}, 15*time.Second).ShouldNot(gomega.Equal("false"))
+	g.Eventually(getPoolFieldsCksum, 15*time.Second).Should(gomega.Equal(cache.PoolDriftChecksum(sim.Get("pool", AVINAMESPACE, poolName))))
+
+	// The pool timeouts are compared as well.
+	gracefulDisableTimeout := getField("pool", poolName, "graceful_disable_timeout")
+	editObject("pool", poolName, map[string]interface{}{"graceful_disable_timeout": 42})
+	ctrl.FullSync()
+	g.Eventually(func() string {
+		return getField("pool", poolName, "graceful_disable_timeout")
+	}, 15*time.Second).Should(gomega.Equal(gracefulDisableTimeout))
+
+	TearDownTestForSvcLB(t, g)
+	g.Eventually(func() int {
+		return len(sim.List("virtualservice", AVINAMESPACE)) + len(sim.List("pool", AVINAMESPACE))
+	}, 15*time.Second).Should(gomega.Equal(0))
+}
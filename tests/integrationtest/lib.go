@@ -49,6 +49,7 @@ import (
 	"google.golang.org/protobuf/proto"
 	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networking "k8s.io/api/networking/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -1059,6 +1060,67 @@ func DelEP(t *testing.T, ns string, Name string) {
 	}
 }
 
+// ConstructEndpointSlice returns an IPv4 EndpointSlice owned by the service svcName,
+// exposing a single port with the given endpoints.
+func ConstructEndpointSlice(ns, name, svcName, portName string, port int32, endpoints []discoveryv1.Endpoint) *discoveryv1.EndpointSlice {
+	protocol := corev1.ProtocolTCP
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      name,
+			Labels:    map[string]string{discoveryv1.LabelServiceName: svcName},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints:   endpoints,
+		Ports: []discoveryv1.EndpointPort{{
+			Name:     &portName,
+			Port:     &port,
+			Protocol: &protocol,
+		}},
+	}
+}
+
+// ConstructSliceEndpoint returns an EndpointSlice endpoint with the given conditions and zone hints.
+func ConstructSliceEndpoint(address string, ready, serving, terminating bool, forZones ...string) discoveryv1.Endpoint {
+	endpoint := discoveryv1.Endpoint{
+		Addresses: []string{address},
+		Conditions: discoveryv1.EndpointConditions{
+			Ready:       &ready,
+			Serving:     &serving,
+			Terminating: &terminating,
+		},
+	}
+	if len(forZones) != 0 {
+		endpoint.Hints = &discoveryv1.EndpointHints{}
+		for _, zone := range forZones {
+			endpoint.Hints.ForZones = append(endpoint.Hints.ForZones, discoveryv1.ForZone{Name: zone})
+		}
+	}
+	return endpoint
+}
+
+func CreateEPS(t *testing.T, epSlice *discoveryv1.EndpointSlice) {
+	_, err := KubeClient.DiscoveryV1().EndpointSlices(epSlice.Namespace).Create(context.TODO(), epSlice, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("error in creating EndpointSlice: %v", err)
+	}
+}
+
+func UpdateEPS(t *testing.T, epSlice *discoveryv1.EndpointSlice) {
+	epSlice.ResourceVersion = "2"
+	_, err := KubeClient.DiscoveryV1().EndpointSlices(epSlice.Namespace).Update(context.TODO(), epSlice, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("error in updating EndpointSlice: %v", err)
+	}
+}
+
+func DelEPS(t *testing.T, ns string, Name string) {
+	err := KubeClient.DiscoveryV1().EndpointSlices(ns).Delete(context.TODO(), Name, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		t.Fatalf("error in deleting EndpointSlice: %v", err)
+	}
+}
+
 func InitializeFakeAKOAPIServer() *api.FakeApiServer {
 	utils.AviLog.Infof("Initializing Fake AKO API server")
 	akoApi := &api.FakeApiServer{
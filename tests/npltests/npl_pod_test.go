@@ -1279,3 +1279,51 @@ func TestIngressAddPodWithMultiportSvc(t *testing.T) {
 	}
 	verifyIngressDeletion(t, g, aviModel, 0)
 }
+
+// TestNPLLBSvcDrainTerminatingPod creates a Service type LB and a Pod with matching label, and then marks
+// the Pod as terminating. It is verified that the Server is kept as draining until the drain period expires.
+func TestNPLLBSvcDrainTerminatingPod(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	os.Setenv("GRACEFUL_DRAIN_PERIOD", "5")
+	defer os.Unsetenv("GRACEFUL_DRAIN_PERIOD")
+
+	selectors := make(map[string]string)
+	selectors["app"] = "npl"
+	objects.SharedAviGraphLister().Delete(defaultLBModel)
+	createPodWithNPLAnnotation(selectors)
+	setUpTestForSvcLB(t)
+
+	getServers := func() map[string]bool {
+		servers := make(map[string]bool)
+		found, aviModel := objects.SharedAviGraphLister().Get(defaultLBModel)
+		if !found || aviModel == nil {
+			return servers
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) != 1 || len(nodes[0].PoolRefs) != 1 {
+			return servers
+		}
+		for _, server := range nodes[0].PoolRefs[0].Servers {
+			servers[*server.Ip.Addr] = server.Draining
+		}
+		return servers
+	}
+	g.Eventually(getServers, 40*time.Second).Should(gomega.Equal(map[string]bool{defaultHostIP: false}))
+
+	// If the Pod starts terminating, the server should be kept as draining.
+	testPod := getTestPod(selectors)
+	testPod.Annotations = map[string]string{
+		lib.NPLPodAnnotation: "[{\"podPort\":8080,\"nodeIP\":\"10.10.10.10\",\"nodePort\":40001}]",
+	}
+	now := metav1.Now()
+	testPod.DeletionTimestamp = &now
+	testPod.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Pods(defaultNS).Update(context.TODO(), &testPod, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Pod: %v", err)
+	}
+	g.Eventually(getServers, 10*time.Second).Should(gomega.Equal(map[string]bool{defaultHostIP: true}))
+
+	// Once the drain period expires, the server should get deleted from model.
+	g.Eventually(getServers, 20*time.Second).Should(gomega.Equal(map[string]bool{}))
+	tearDownTestForSvcLB(t, g)
+}